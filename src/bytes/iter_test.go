@@ -0,0 +1,89 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytes_test
+
+import (
+	. "bytes"
+	"iter"
+	"testing"
+	"unicode"
+)
+
+// collect returns the subslices yielded by seq, checking that
+// appending to each of them does not modify the input.
+func collect(t *testing.T, seq iter.Seq[[]byte]) [][]byte {
+	a := [][]byte{}
+	for b := range seq {
+		if len(b) != cap(b) {
+			t.Errorf("yielded %q with capacity %d beyond its length", b, cap(b)-len(b))
+		}
+		a = append(a, b)
+	}
+	return a
+}
+
+var linesTests = []struct {
+	s string
+	a []string
+}{
+	{"", []string{}},
+	{"\n", []string{"\n"}},
+	{"abc", []string{"abc"}},
+	{"abc\n", []string{"abc\n"}},
+	{"abc\ndef", []string{"abc\n", "def"}},
+	{"abc\n\ndef\n", []string{"abc\n", "\n", "def\n"}},
+	{"a\r\nb\r\n", []string{"a\r\n", "b\r\n"}},
+}
+
+func TestLines(t *testing.T) {
+	for _, tt := range linesTests {
+		a := sliceOfString(collect(t, Lines([]byte(tt.s))))
+		if !eq(a, tt.a) {
+			t.Errorf("Lines(%q) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
+
+func TestSplitSeq(t *testing.T) {
+	for _, tt := range splittests {
+		if tt.n >= 0 {
+			continue
+		}
+		a := sliceOfString(collect(t, SplitSeq([]byte(tt.s), []byte(tt.sep))))
+		if !eq(a, tt.a) {
+			t.Errorf("SplitSeq(%q, %q) = %q; want %q", tt.s, tt.sep, a, tt.a)
+		}
+	}
+}
+
+func TestSplitAfterSeq(t *testing.T) {
+	for _, tt := range splitaftertests {
+		if tt.n >= 0 {
+			continue
+		}
+		a := sliceOfString(collect(t, SplitAfterSeq([]byte(tt.s), []byte(tt.sep))))
+		if !eq(a, tt.a) {
+			t.Errorf("SplitAfterSeq(%q, %q) = %q; want %q", tt.s, tt.sep, a, tt.a)
+		}
+	}
+}
+
+func TestFieldsSeq(t *testing.T) {
+	for _, tt := range fieldstests {
+		a := sliceOfString(collect(t, FieldsSeq([]byte(tt.s))))
+		if !eq(a, tt.a) {
+			t.Errorf("FieldsSeq(%q) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
+
+func TestFieldsFuncSeq(t *testing.T) {
+	for _, tt := range fieldstests {
+		a := sliceOfString(collect(t, FieldsFuncSeq([]byte(tt.s), unicode.IsSpace)))
+		if !eq(a, tt.a) {
+			t.Errorf("FieldsFuncSeq(%q, unicode.IsSpace) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
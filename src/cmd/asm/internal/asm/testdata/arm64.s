@@ -123,6 +123,42 @@ TEXT	foo(SB), DUPOK|NOSPLIT, $-8
 	VFMLA	V1.D2, V12.D2, V1.D2            // 81cd614e
 	VFMLA	V1.S2, V12.S2, V1.S2            // 81cd210e
 	VFMLA	V1.S4, V12.S4, V1.S4            // 81cd214e
+	VFADD	V1.S2, V2.S2, V3.S2             // 43d4210e
+	VFADD	V1.S4, V2.S4, V3.S4             // 43d4214e
+	VFADD	V1.D2, V2.D2, V3.D2             // 43d4614e
+	VFSUB	V1.S2, V2.S2, V3.S2             // 43d4a10e
+	VFSUB	V1.S4, V2.S4, V3.S4             // 43d4a14e
+	VFSUB	V1.D2, V2.D2, V3.D2             // 43d4e14e
+	VFMUL	V1.S2, V2.S2, V3.S2             // 43dc212e
+	VFMUL	V1.S4, V2.S4, V3.S4             // 43dc216e
+	VFMUL	V1.D2, V2.D2, V3.D2             // 43dc616e
+	VFDIV	V1.S2, V2.S2, V3.S2             // 43fc212e
+	VFDIV	V1.S4, V2.S4, V3.S4             // 43fc216e
+	VFDIV	V1.D2, V2.D2, V3.D2             // 43fc616e
+	VFMAX	V1.S2, V2.S2, V3.S2             // 43f4210e
+	VFMAX	V1.S4, V2.S4, V3.S4             // 43f4214e
+	VFMAX	V1.D2, V2.D2, V3.D2             // 43f4614e
+	VFMIN	V1.S2, V2.S2, V3.S2             // 43f4a10e
+	VFMIN	V1.S4, V2.S4, V3.S4             // 43f4a14e
+	VFMIN	V1.D2, V2.D2, V3.D2             // 43f4e14e
+	VFSQRT	V1.S2, V2.S2                    // 22f8a12e
+	VFSQRT	V1.S4, V2.S4                    // 22f8a16e
+	VFSQRT	V1.D2, V2.D2                    // 22f8e16e
+	VMUL	V1.B16, V2.B16, V3.B16          // 439c214e
+	VMUL	V1.H8, V2.H8, V3.H8             // 439c614e
+	VMUL	V1.S4, V2.S4, V3.S4             // 439ca14e
+	VSMAX	V1.B16, V2.B16, V3.B16          // 4364214e
+	VSMAX	V1.H8, V2.H8, V3.H8             // 4364614e
+	VSMAX	V1.S4, V2.S4, V3.S4             // 4364a14e
+	VSMIN	V1.B16, V2.B16, V3.B16          // 436c214e
+	VSMIN	V1.H8, V2.H8, V3.H8             // 436c614e
+	VSMIN	V1.S4, V2.S4, V3.S4             // 436ca14e
+	VCMGT	V1.B16, V2.B16, V3.B16          // 4334214e
+	VCMGT	V1.H8, V2.H8, V3.H8             // 4334614e
+	VCMGT	V1.S4, V2.S4, V3.S4             // 4334a14e
+	VCMGT	V1.D2, V2.D2, V3.D2             // 4334e14e
+	VBIC	V1.B8, V2.B8, V3.B8             // 431c610e
+	VBIC	V1.B16, V2.B16, V3.B16          // 431c614e
 	VFMLS	V1.D2, V12.D2, V1.D2            // 81cde14e
 	VFMLS	V1.S2, V12.S2, V1.S2            // 81cda10e
 	VFMLS	V1.S4, V12.S4, V1.S4            // 81cda14e
@@ -129,6 +129,15 @@ TEXT errors(SB),$0
 	VFMLS	V1.H4, V12.H4, V3.H4                             // ERROR "invalid arrangement"
 	VFMLS	V1.H8, V12.H8, V3.H8                             // ERROR "invalid arrangement"
 	VFMLS	V1.H4, V12.H4, V3.H4                             // ERROR "invalid arrangement"
+	VFADD	V1.B16, V2.B16, V3.B16                           // ERROR "invalid arrangement"
+	VFSUB	V1.H8, V2.H8, V3.H8                              // ERROR "invalid arrangement"
+	VFDIV	V1.S4, V2.S4, V3.D2                              // ERROR "operand mismatch"
+	VFSQRT	V1.B16, V2.B16                                   // ERROR "invalid arrangement"
+	VFSQRT	V1.S4, V2.D2                                     // ERROR "invalid arrangement"
+	VMUL	V1.D2, V2.D2, V3.D2                              // ERROR "invalid arrangement"
+	VSMAX	V1.D2, V2.D2, V3.D2                              // ERROR "invalid arrangement"
+	VBIC	V1.S4, V2.S4, V3.S4                              // ERROR "invalid arrangement"
+	VREV64	V1.D2, V2.D2                                     // ERROR "invalid arrangement"
 	VST1.P	[V4.S4,V5.S4], 48(R1)                            // ERROR "invalid post-increment offset"
 	VST1.P	[V4.S4], 8(R1)                                   // ERROR "invalid post-increment offset"
 	VLD1.P	32(R1), [V8.S4, V9.S4, V10.S4]                   // ERROR "invalid post-increment offset"
@@ -0,0 +1,184 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package amd64
+
+import (
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/ssagen"
+	"cmd/internal/obj"
+	"cmd/internal/obj/x86"
+)
+
+type simdKey struct {
+	kind ssa.SIMDKind
+	elem ssa.SIMDElem
+}
+
+// simdInsts gives the instructions implementing the vector operations
+// on 128-, 256- and 512-bit vectors. Except for SIMDSqrt, each takes a
+// memory operand and a vector register and writes a vector register:
+//
+//	INST mem, Vsrc, Vdst // Vdst = Vsrc op mem
+//
+// Operations with special code sequences are handled in ssaGenSIMD.
+var simdInsts = map[simdKey][3]obj.As{
+	{ssa.SIMDAdd, ssa.SIMDUint8}:    {x86.AVPADDB, x86.AVPADDB, x86.AVPADDB},
+	{ssa.SIMDSub, ssa.SIMDUint8}:    {x86.AVPSUBB, x86.AVPSUBB, x86.AVPSUBB},
+	{ssa.SIMDMin, ssa.SIMDUint8}:    {x86.AVPMINUB, x86.AVPMINUB, x86.AVPMINUB},
+	{ssa.SIMDMax, ssa.SIMDUint8}:    {x86.AVPMAXUB, x86.AVPMAXUB, x86.AVPMAXUB},
+	{ssa.SIMDAnd, ssa.SIMDUint8}:    {x86.AVPAND, x86.AVPAND, x86.AVPANDD},
+	{ssa.SIMDOr, ssa.SIMDUint8}:     {x86.AVPOR, x86.AVPOR, x86.AVPORD},
+	{ssa.SIMDXor, ssa.SIMDUint8}:    {x86.AVPXOR, x86.AVPXOR, x86.AVPXORD},
+	{ssa.SIMDAndNot, ssa.SIMDUint8}: {x86.AVPANDN, x86.AVPANDN, x86.AVPANDND},
+	{ssa.SIMDEqual, ssa.SIMDUint8}:  {x86.AVPCMPEQB, x86.AVPCMPEQB, x86.AVPCMPEQB},
+
+	{ssa.SIMDAdd, ssa.SIMDInt32}:     {x86.AVPADDD, x86.AVPADDD, x86.AVPADDD},
+	{ssa.SIMDSub, ssa.SIMDInt32}:     {x86.AVPSUBD, x86.AVPSUBD, x86.AVPSUBD},
+	{ssa.SIMDMul, ssa.SIMDInt32}:     {x86.AVPMULLD, x86.AVPMULLD, x86.AVPMULLD},
+	{ssa.SIMDMin, ssa.SIMDInt32}:     {x86.AVPMINSD, x86.AVPMINSD, x86.AVPMINSD},
+	{ssa.SIMDMax, ssa.SIMDInt32}:     {x86.AVPMAXSD, x86.AVPMAXSD, x86.AVPMAXSD},
+	{ssa.SIMDAnd, ssa.SIMDInt32}:     {x86.AVPAND, x86.AVPAND, x86.AVPANDD},
+	{ssa.SIMDOr, ssa.SIMDInt32}:      {x86.AVPOR, x86.AVPOR, x86.AVPORD},
+	{ssa.SIMDXor, ssa.SIMDInt32}:     {x86.AVPXOR, x86.AVPXOR, x86.AVPXORD},
+	{ssa.SIMDAndNot, ssa.SIMDInt32}:  {x86.AVPANDN, x86.AVPANDN, x86.AVPANDND},
+	{ssa.SIMDEqual, ssa.SIMDInt32}:   {x86.AVPCMPEQD, x86.AVPCMPEQD, obj.AXXX},
+	{ssa.SIMDGreater, ssa.SIMDInt32}: {x86.AVPCMPGTD, x86.AVPCMPGTD, obj.AXXX},
+
+	{ssa.SIMDAdd, ssa.SIMDFloat32}:    {x86.AVADDPS, x86.AVADDPS, x86.AVADDPS},
+	{ssa.SIMDSub, ssa.SIMDFloat32}:    {x86.AVSUBPS, x86.AVSUBPS, x86.AVSUBPS},
+	{ssa.SIMDMul, ssa.SIMDFloat32}:    {x86.AVMULPS, x86.AVMULPS, x86.AVMULPS},
+	{ssa.SIMDDiv, ssa.SIMDFloat32}:    {x86.AVDIVPS, x86.AVDIVPS, x86.AVDIVPS},
+	{ssa.SIMDMin, ssa.SIMDFloat32}:    {x86.AVMINPS, x86.AVMINPS, x86.AVMINPS},
+	{ssa.SIMDMax, ssa.SIMDFloat32}:    {x86.AVMAXPS, x86.AVMAXPS, x86.AVMAXPS},
+	{ssa.SIMDMulAdd, ssa.SIMDFloat32}: {x86.AVFMADD231PS, x86.AVFMADD231PS, x86.AVFMADD231PS},
+	{ssa.SIMDSqrt, ssa.SIMDFloat32}:   {x86.AVSQRTPS, x86.AVSQRTPS, x86.AVSQRTPS},
+
+	{ssa.SIMDAdd, ssa.SIMDFloat64}:    {x86.AVADDPD, x86.AVADDPD, x86.AVADDPD},
+	{ssa.SIMDSub, ssa.SIMDFloat64}:    {x86.AVSUBPD, x86.AVSUBPD, x86.AVSUBPD},
+	{ssa.SIMDMul, ssa.SIMDFloat64}:    {x86.AVMULPD, x86.AVMULPD, x86.AVMULPD},
+	{ssa.SIMDDiv, ssa.SIMDFloat64}:    {x86.AVDIVPD, x86.AVDIVPD, x86.AVDIVPD},
+	{ssa.SIMDMin, ssa.SIMDFloat64}:    {x86.AVMINPD, x86.AVMINPD, x86.AVMINPD},
+	{ssa.SIMDMax, ssa.SIMDFloat64}:    {x86.AVMAXPD, x86.AVMAXPD, x86.AVMAXPD},
+	{ssa.SIMDMulAdd, ssa.SIMDFloat64}: {x86.AVFMADD231PD, x86.AVFMADD231PD, x86.AVFMADD231PD},
+	{ssa.SIMDSqrt, ssa.SIMDFloat64}:   {x86.AVSQRTPD, x86.AVSQRTPD, x86.AVSQRTPD},
+}
+
+// simdReg returns the register for vector register number n
+// (the n of Xn) holding a vector of width bits.
+func simdReg(n int16, width int) int16 {
+	switch width {
+	case 128:
+		return x86.REG_X0 + n
+	case 256:
+		return x86.REG_Y0 + n
+	case 512:
+		return x86.REG_Z0 + n
+	}
+	panic("bad SIMD width")
+}
+
+// simdMove returns the unaligned move instruction for vectors of op.
+func simdMove(op ssa.SIMDOp) obj.As {
+	switch op.Elem() {
+	case ssa.SIMDFloat32:
+		return x86.AVMOVUPS
+	case ssa.SIMDFloat64:
+		return x86.AVMOVUPD
+	}
+	if op.Width() == 512 {
+		return x86.AVMOVDQU32
+	}
+	return x86.AVMOVDQU
+}
+
+// simdLoad emits a load of the vector at (base) into register reg.
+func simdLoad(s *ssagen.State, op ssa.SIMDOp, base, reg int16) {
+	p := s.Prog(simdMove(op))
+	p.From = obj.Addr{Type: obj.TYPE_MEM, Reg: base}
+	p.To = obj.Addr{Type: obj.TYPE_REG, Reg: reg}
+}
+
+// simdStore emits a store of register reg to the vector at (base).
+func simdStore(s *ssagen.State, op ssa.SIMDOp, reg, base int16) {
+	p := s.Prog(simdMove(op))
+	p.From = obj.Addr{Type: obj.TYPE_REG, Reg: reg}
+	p.To = obj.Addr{Type: obj.TYPE_MEM, Reg: base}
+}
+
+// simdOp3 emits "as (base), src, dst".
+func simdOp3(s *ssagen.State, as obj.As, base, src, dst int16) {
+	p := s.Prog(as)
+	p.From = obj.Addr{Type: obj.TYPE_MEM, Reg: base}
+	p.AddRestSourceReg(src)
+	p.To = obj.Addr{Type: obj.TYPE_REG, Reg: dst}
+}
+
+// ssaGenSIMD emits the code for the LoweredSIMD ops. The operands
+// are loaded into X14 (and X13), or their Y or Z extensions.
+func ssaGenSIMD(s *ssagen.State, v *ssa.Value) {
+	op := ssa.SIMDOp(v.AuxInt)
+	width := op.Width()
+	tmp := simdReg(14, width)
+	insts, ok := simdInsts[simdKey{op.Kind(), op.Elem()}]
+	if op.Kind() == ssa.SIMDMask {
+		ok = op.Elem() == ssa.SIMDUint8
+	} else {
+		ok = ok && insts[width/256] != obj.AXXX
+	}
+	if !ok {
+		v.Fatalf("unsupported SIMD operation %v", op)
+	}
+
+	switch v.Op {
+	case ssa.OpAMD64LoweredSIMDMask:
+		simdLoad(s, op, v.Args[0].Reg(), tmp)
+		if width == 512 {
+			opregreg(s, x86.AVPMOVB2M, x86.REG_K1, tmp)
+			opregreg(s, x86.AKMOVQ, v.Reg(), x86.REG_K1)
+		} else {
+			opregreg(s, x86.AVPMOVMSKB, v.Reg(), tmp)
+		}
+
+	case ssa.OpAMD64LoweredSIMDUnary:
+		p := s.Prog(insts[width/256])
+		p.From = obj.Addr{Type: obj.TYPE_MEM, Reg: v.Args[1].Reg()}
+		p.To = obj.Addr{Type: obj.TYPE_REG, Reg: tmp}
+		simdStore(s, op, tmp, v.Args[0].Reg())
+
+	case ssa.OpAMD64LoweredSIMDBinary:
+		as := insts[width/256]
+		z, x, y := v.Args[0].Reg(), v.Args[1].Reg(), v.Args[2].Reg()
+		switch {
+		case op.Kind() == ssa.SIMDMulAdd:
+			// tmp = x*y + z
+			tmp2 := simdReg(13, width)
+			simdLoad(s, op, z, tmp)
+			simdLoad(s, op, x, tmp2)
+			simdOp3(s, as, y, tmp2, tmp)
+		case op.Kind() == ssa.SIMDAndNot:
+			// VPANDN computes ^src & mem.
+			simdLoad(s, op, y, tmp)
+			simdOp3(s, as, x, tmp, tmp)
+		case op.Kind() == ssa.SIMDEqual && width == 512:
+			// AVX-512 comparisons produce a mask register.
+			simdLoad(s, op, x, tmp)
+			simdOp3(s, as, y, tmp, x86.REG_K1)
+			opregreg(s, x86.AVPMOVM2B, tmp, x86.REG_K1)
+		default:
+			simdLoad(s, op, x, tmp)
+			simdOp3(s, as, y, tmp, tmp)
+		}
+		simdStore(s, op, tmp, z)
+
+	default:
+		v.Fatalf("bad SIMD op %v", v.LongString())
+	}
+
+	if width > 128 {
+		// Clear the upper halves of the vector registers so that
+		// the SSE code that follows does not pay a transition penalty.
+		s.Prog(x86.AVZEROUPPER)
+	}
+}
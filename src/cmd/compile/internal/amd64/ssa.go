@@ -1229,6 +1229,8 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 	case ssa.OpAMD64REPSTOSQ:
 		s.Prog(x86.AREP)
 		s.Prog(x86.ASTOSQ)
+	case ssa.OpAMD64LoweredSIMDUnary, ssa.OpAMD64LoweredSIMDBinary, ssa.OpAMD64LoweredSIMDMask:
+		ssaGenSIMD(s, v)
 	case ssa.OpAMD64REPMOVSQ:
 		s.Prog(x86.AREP)
 		s.Prog(x86.AMOVSQ)
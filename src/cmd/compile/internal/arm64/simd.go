@@ -0,0 +1,189 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package arm64
+
+import (
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/ssagen"
+	"cmd/internal/obj"
+	"cmd/internal/obj/arm64"
+)
+
+type simdKey struct {
+	kind ssa.SIMDKind
+	elem ssa.SIMDElem
+}
+
+// simdInsts gives the Advanced SIMD instructions implementing the
+// vector operations. Except for VFSQRT, each is used as
+//
+//	INST Vm, Vn, Vd // Vd = Vn op Vm
+var simdInsts = map[simdKey]obj.As{
+	{ssa.SIMDAdd, ssa.SIMDUint8}:    arm64.AVADD,
+	{ssa.SIMDSub, ssa.SIMDUint8}:    arm64.AVSUB,
+	{ssa.SIMDMin, ssa.SIMDUint8}:    arm64.AVUMIN,
+	{ssa.SIMDMax, ssa.SIMDUint8}:    arm64.AVUMAX,
+	{ssa.SIMDAnd, ssa.SIMDUint8}:    arm64.AVAND,
+	{ssa.SIMDOr, ssa.SIMDUint8}:     arm64.AVORR,
+	{ssa.SIMDXor, ssa.SIMDUint8}:    arm64.AVEOR,
+	{ssa.SIMDAndNot, ssa.SIMDUint8}: arm64.AVBIC,
+	{ssa.SIMDEqual, ssa.SIMDUint8}:  arm64.AVCMEQ,
+
+	{ssa.SIMDAdd, ssa.SIMDInt32}:     arm64.AVADD,
+	{ssa.SIMDSub, ssa.SIMDInt32}:     arm64.AVSUB,
+	{ssa.SIMDMul, ssa.SIMDInt32}:     arm64.AVMUL,
+	{ssa.SIMDMin, ssa.SIMDInt32}:     arm64.AVSMIN,
+	{ssa.SIMDMax, ssa.SIMDInt32}:     arm64.AVSMAX,
+	{ssa.SIMDAnd, ssa.SIMDInt32}:     arm64.AVAND,
+	{ssa.SIMDOr, ssa.SIMDInt32}:      arm64.AVORR,
+	{ssa.SIMDXor, ssa.SIMDInt32}:     arm64.AVEOR,
+	{ssa.SIMDAndNot, ssa.SIMDInt32}:  arm64.AVBIC,
+	{ssa.SIMDEqual, ssa.SIMDInt32}:   arm64.AVCMEQ,
+	{ssa.SIMDGreater, ssa.SIMDInt32}: arm64.AVCMGT,
+
+	{ssa.SIMDAdd, ssa.SIMDFloat32}:    arm64.AVFADD,
+	{ssa.SIMDSub, ssa.SIMDFloat32}:    arm64.AVFSUB,
+	{ssa.SIMDMul, ssa.SIMDFloat32}:    arm64.AVFMUL,
+	{ssa.SIMDDiv, ssa.SIMDFloat32}:    arm64.AVFDIV,
+	{ssa.SIMDMin, ssa.SIMDFloat32}:    arm64.AVFMIN,
+	{ssa.SIMDMax, ssa.SIMDFloat32}:    arm64.AVFMAX,
+	{ssa.SIMDMulAdd, ssa.SIMDFloat32}: arm64.AVFMLA,
+	{ssa.SIMDSqrt, ssa.SIMDFloat32}:   arm64.AVFSQRT,
+
+	{ssa.SIMDAdd, ssa.SIMDFloat64}:    arm64.AVFADD,
+	{ssa.SIMDSub, ssa.SIMDFloat64}:    arm64.AVFSUB,
+	{ssa.SIMDMul, ssa.SIMDFloat64}:    arm64.AVFMUL,
+	{ssa.SIMDDiv, ssa.SIMDFloat64}:    arm64.AVFDIV,
+	{ssa.SIMDMin, ssa.SIMDFloat64}:    arm64.AVFMIN,
+	{ssa.SIMDMax, ssa.SIMDFloat64}:    arm64.AVFMAX,
+	{ssa.SIMDMulAdd, ssa.SIMDFloat64}: arm64.AVFMLA,
+	{ssa.SIMDSqrt, ssa.SIMDFloat64}:   arm64.AVFSQRT,
+}
+
+// simdArng returns the arrangement of a 128-bit register holding
+// elements of type e.
+func simdArng(e ssa.SIMDElem) int16 {
+	switch e {
+	case ssa.SIMDUint8:
+		return arm64.ARNG_16B
+	case ssa.SIMDInt32, ssa.SIMDFloat32:
+		return arm64.ARNG_4S
+	case ssa.SIMDFloat64:
+		return arm64.ARNG_2D
+	}
+	panic("bad SIMD element type")
+}
+
+// vreg returns Vn.<arng>.
+func vreg(n, arng int16) obj.Addr {
+	return obj.Addr{Type: obj.TYPE_REG, Reg: arm64.REG_ARNG + n&31 + (arng&15)<<5}
+}
+
+// simdLoad emits a load of the 128 bits at off(base) into Vn.
+func simdLoad(s *ssagen.State, base int16, off int64, n int16) {
+	p := s.Prog(arm64.AFMOVQ)
+	p.From = obj.Addr{Type: obj.TYPE_MEM, Reg: base, Offset: off}
+	p.To = obj.Addr{Type: obj.TYPE_REG, Reg: arm64.REG_F0 + n}
+}
+
+// simdStore emits a store of Vn to the 128 bits at off(base).
+func simdStore(s *ssagen.State, n int16, base int16, off int64) {
+	p := s.Prog(arm64.AFMOVQ)
+	p.From = obj.Addr{Type: obj.TYPE_REG, Reg: arm64.REG_F0 + n}
+	p.To = obj.Addr{Type: obj.TYPE_MEM, Reg: base, Offset: off}
+}
+
+// simdOp3 emits "as Vm.<arng>, Vn.<arng>, Vd.<arng>".
+func simdOp3(s *ssagen.State, as obj.As, arng, m, n, d int16) {
+	p := s.Prog(as)
+	p.From = vreg(m, arng)
+	p.Reg = vreg(n, arng).Reg
+	p.To = vreg(d, arng)
+}
+
+// ssaGenSIMD emits the code for the LoweredSIMD ops. Vectors wider
+// than 128 bits are processed 128 bits at a time in V29-V31.
+func ssaGenSIMD(s *ssagen.State, v *ssa.Value) {
+	op := ssa.SIMDOp(v.AuxInt)
+	arng := simdArng(op.Elem())
+	as, ok := simdInsts[simdKey{op.Kind(), op.Elem()}]
+	if op.Kind() == ssa.SIMDMask {
+		ok = op.Elem() == ssa.SIMDUint8
+	}
+	if !ok {
+		v.Fatalf("unsupported SIMD operation %v", op)
+	}
+
+	for i := 0; i < op.Width()/128; i++ {
+		off := int64(i) * 16
+		switch v.Op {
+		case ssa.OpARM64LoweredSIMDMask:
+			simdMask16(s, v, off, i)
+
+		case ssa.OpARM64LoweredSIMDUnary:
+			simdLoad(s, v.Args[1].Reg(), off, 30)
+			p := s.Prog(as)
+			p.From = vreg(30, arng)
+			p.To = vreg(30, arng)
+			simdStore(s, 30, v.Args[0].Reg(), off)
+
+		case ssa.OpARM64LoweredSIMDBinary:
+			z, x, y := v.Args[0].Reg(), v.Args[1].Reg(), v.Args[2].Reg()
+			if op.Kind() == ssa.SIMDMulAdd {
+				// V30 += V31 * V29
+				simdLoad(s, z, off, 30)
+				simdLoad(s, x, off, 31)
+				simdLoad(s, y, off, 29)
+				simdOp3(s, as, arng, 29, 31, 30)
+			} else {
+				simdLoad(s, x, off, 30)
+				simdLoad(s, y, off, 31)
+				simdOp3(s, as, arng, 31, 30, 30)
+			}
+			simdStore(s, 30, z, off)
+
+		default:
+			v.Fatalf("bad SIMD op %v", v.LongString())
+		}
+	}
+}
+
+// simdMask16 emits code that ORs the sign bits of the 16 bytes at
+// off(v.Args[0]) into bits 16*i to 16*i+15 of v's register.
+// If i is 0, it sets the register instead.
+func simdMask16(s *ssagen.State, v *ssa.Value, off int64, i int) {
+	simdLoad(s, v.Args[0].Reg(), off, 30)
+
+	// Reduce the sign bits to the low 8 bits of each 64-bit half by
+	// repeatedly adding each element shifted right into the next
+	// element's position.
+	p := s.Prog(arm64.AVUSHR)
+	p.From = obj.Addr{Type: obj.TYPE_CONST, Offset: 7}
+	p.Reg = vreg(30, arm64.ARNG_16B).Reg
+	p.To = vreg(30, arm64.ARNG_16B)
+	for _, sh := range []struct {
+		n    int64
+		arng int16
+	}{{7, arm64.ARNG_8H}, {14, arm64.ARNG_4S}, {28, arm64.ARNG_2D}} {
+		p := s.Prog(arm64.AVUSRA)
+		p.From = obj.Addr{Type: obj.TYPE_CONST, Offset: sh.n}
+		p.Reg = vreg(30, sh.arng).Reg
+		p.To = vreg(30, sh.arng)
+	}
+
+	out := v.Reg()
+	for half := 0; half < 2; half++ {
+		r := int16(arm64.REGTMP)
+		if i == 0 && half == 0 {
+			r = out
+		}
+		p := s.Prog(arm64.AVMOV)
+		p.From = obj.Addr{Type: obj.TYPE_REG, Reg: arm64.REG_ELEM + 30 + (arm64.ARNG_B&15)<<5, Index: int16(8 * half)}
+		p.To = obj.Addr{Type: obj.TYPE_REG, Reg: r}
+		if r != out {
+			genshift(s, v, arm64.AORR, out, r, out, arm64.SHIFT_LL, int64(16*i+8*half))
+		}
+	}
+}
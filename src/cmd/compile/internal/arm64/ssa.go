@@ -1006,6 +1006,8 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p.To.Name = obj.NAME_EXTERN
 		p.To.Sym = ir.Syms.Duffzero
 		p.To.Offset = v.AuxInt
+	case ssa.OpARM64LoweredSIMDUnary, ssa.OpARM64LoweredSIMDBinary, ssa.OpARM64LoweredSIMDMask:
+		ssaGenSIMD(s, v)
 	case ssa.OpARM64LoweredZero:
 		// STP.P	(ZR,ZR), 16(R16)
 		// CMP	Rarg1, R16
@@ -222,6 +222,9 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 		if err != nil {
 			log.Fatalf("%s: PGO error: %v", base.Flag.PgoProfile, err)
 		}
+		if profile != nil && base.Debug.PGOLayout != 0 {
+			ssagen.PGOProfile = profile
+		}
 	}
 
 	// Interleaved devirtualization and inlining.
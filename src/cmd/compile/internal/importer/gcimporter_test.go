@@ -606,3 +606,47 @@ func lookupObj(t *testing.T, scope *types2.Scope, name string) types2.Object {
 	t.Fatalf("%s not found", name)
 	return nil
 }
+
+func TestGenericAlias(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	// This package only handles gc export data.
+	if runtime.Compiler != "gc" {
+		t.Skipf("gc-built packages not available (compiler = %s)", runtime.Compiler)
+	}
+
+	pkg := compileAndImportPkg(t, "genalias")
+
+	for _, test := range []struct {
+		name, tparams, rhs string
+	}{
+		{"A", "[P any]", "T[P, string]"},
+		{"List", "[E any]", "[]E"},
+	} {
+		obj := lookupObj(t, pkg.Scope(), test.name)
+		alias, ok := obj.Type().(*types2.Alias)
+		if !ok {
+			t.Errorf("%s: got %T, want *types2.Alias", test.name, obj.Type())
+			continue
+		}
+		if got := types2.TypeString(alias, types2.RelativeTo(pkg)); got != test.name {
+			t.Errorf("%s: got type %s", test.name, got)
+		}
+		var tparams []string
+		for i := 0; i < alias.TypeParams().Len(); i++ {
+			tp := alias.TypeParams().At(i)
+			tparams = append(tparams, tp.Obj().Name()+" "+types2.TypeString(tp.Constraint(), nil))
+		}
+		if got := "[" + strings.Join(tparams, ", ") + "]"; got != test.tparams {
+			t.Errorf("%s: got type parameters %s, want %s", test.name, got, test.tparams)
+		}
+		if got := types2.TypeString(types2.Unalias(alias), types2.RelativeTo(pkg)); got != test.rhs {
+			t.Errorf("%s: got aliased type %s, want %s", test.name, got, test.rhs)
+		}
+	}
+
+	// Uses of generic aliases are recorded as the types they denote.
+	if got, want := types2.TypeString(lookupObj(t, pkg.Scope(), "X").Type(), types2.RelativeTo(pkg)), "T[int, string]"; got != want {
+		t.Errorf("X: got type %s, want %s", got, want)
+	}
+}
@@ -409,8 +409,12 @@ func (pr *pkgReader) objIdx(idx pkgbits.Index) (*types2.Package, string) {
 
 		case pkgbits.ObjAlias:
 			pos := r.pos()
+			var tparams []*types2.TypeParam
+			if r.p.AliasTypeParams() {
+				tparams = r.typeParamNames()
+			}
 			typ := r.typ()
-			return newAliasTypeName(pos, objPkg, objName, typ)
+			return newAliasTypeName(pos, objPkg, objName, typ, tparams)
 
 		case pkgbits.ObjConst:
 			pos := r.pos()
@@ -536,12 +540,14 @@ func (r *reader) ident(marker pkgbits.SyncMarker) (*types2.Package, string) {
 }
 
 // newAliasTypeName returns a new TypeName, with a materialized *types2.Alias if supported.
-func newAliasTypeName(pos syntax.Pos, pkg *types2.Package, name string, rhs types2.Type) *types2.TypeName {
+// Aliases with type parameters are always materialized.
+func newAliasTypeName(pos syntax.Pos, pkg *types2.Package, name string, rhs types2.Type, tparams []*types2.TypeParam) *types2.TypeName {
 	// Copied from x/tools/internal/aliases.NewAlias via
 	// GOROOT/src/go/internal/gcimporter/ureader.go.
-	if gotypesalias.Value() == "1" {
+	if len(tparams) > 0 || gotypesalias.Value() == "1" {
 		tname := types2.NewTypeName(pos, pkg, name, nil)
-		_ = types2.NewAlias(tname, rhs) // form TypeName -> Alias cycle
+		alias := types2.NewAlias(tname, rhs) // form TypeName -> Alias cycle
+		alias.SetTypeParams(tparams)
 		return tname
 	}
 	return types2.NewTypeName(pos, pkg, name, rhs)
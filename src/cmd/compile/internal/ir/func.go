@@ -90,15 +90,20 @@ type Func struct {
 
 	Inl *Inline
 
-	// funcLitGen and goDeferGen track how many closures have been
-	// created in this function for function literals and go/defer
-	// wrappers, respectively. Used by closureName for creating unique
-	// function names.
+	// RangeParent, if non-nil, is the first non-range body function containing
+	// the closure for the body of a range function.
+	RangeParent *Func
+
+	// funcLitGen, rangeLitGen and goDeferGen track how many closures have been
+	// created in this function for function literals, range-over-func loop
+	// bodies, and go/defer wrappers, respectively. Used by closureName for
+	// creating unique function names.
 	//
 	// Tracking goDeferGen separately avoids wrappers throwing off
 	// function literal numbering (e.g., runtime/trace_test.TestTraceSymbolize.func11).
-	funcLitGen int32
-	goDeferGen int32
+	funcLitGen  int32
+	rangeLitGen int32
+	goDeferGen  int32
 
 	Label int32 // largest auto-generated label in this function
 
@@ -416,9 +421,17 @@ func IsTrivialClosure(clo *ClosureExpr) bool {
 var globClosgen int32
 
 // closureName generates a new unique name for a closure within outerfn at pos.
+//
+// Range-over-func loop bodies are named outer-rangeN, where outer is the
+// function containing the loop; closures within loop bodies are named as
+// if the loop bodies were part of that function.
 func closureName(outerfn *Func, pos src.XPos, why Op) *types.Sym {
+	if outerfn != nil && outerfn.RangeParent != nil {
+		outerfn = outerfn.RangeParent
+	}
 	pkg := types.LocalPkg
 	outer := "glob."
+	sep := "."
 	var prefix string
 	switch why {
 	default:
@@ -427,6 +440,8 @@ func closureName(outerfn *Func, pos src.XPos, why Op) *types.Sym {
 		if outerfn == nil || outerfn.OClosure == nil {
 			prefix = "func"
 		}
+	case ORANGE:
+		sep, prefix = "-", "range"
 	case OGO:
 		prefix = "gowrap"
 	case ODEFER:
@@ -441,9 +456,12 @@ func closureName(outerfn *Func, pos src.XPos, why Op) *types.Sym {
 		pkg = outerfn.Sym().Pkg
 		outer = FuncName(outerfn)
 
-		if why == OCLOSURE {
+		switch why {
+		case OCLOSURE:
 			gen = &outerfn.funcLitGen
-		} else {
+		case ORANGE:
+			gen = &outerfn.rangeLitGen
+		default:
 			gen = &outerfn.goDeferGen
 		}
 	}
@@ -460,7 +478,7 @@ func closureName(outerfn *Func, pos src.XPos, why Op) *types.Sym {
 	}
 
 	*gen++
-	return pkg.Lookup(fmt.Sprintf("%s.%s%d", outer, prefix, *gen))
+	return pkg.Lookup(fmt.Sprintf("%s%s%s%d", outer, sep, prefix, *gen))
 }
 
 // NewClosureFunc creates a new Func to represent a function literal
@@ -476,11 +494,18 @@ func closureName(outerfn *Func, pos src.XPos, why Op) *types.Sym {
 // appending to pkg.Funcs.
 //
 // why is the reason we're generating this Func. It can be OCLOSURE
-// (for a normal function literal) or OGO or ODEFER (for wrapping a
-// call expression that has parameters or results).
+// (for a normal function literal), ORANGE (for the body of a
+// range-over-func loop) or OGO or ODEFER (for wrapping a call
+// expression that has parameters or results).
 func NewClosureFunc(fpos, cpos src.XPos, why Op, typ *types.Type, outerfn *Func, pkg *Package) *Func {
 	fn := NewFunc(fpos, fpos, closureName(outerfn, cpos, why), typ)
 	fn.SetIsHiddenClosure(outerfn != nil)
+	if why == ORANGE && outerfn != nil {
+		fn.RangeParent = outerfn
+		if outerfn.RangeParent != nil {
+			fn.RangeParent = outerfn.RangeParent
+		}
+	}
 
 	clo := &ClosureExpr{Func: fn}
 	clo.op = OCLOSURE
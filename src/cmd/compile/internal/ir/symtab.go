@@ -62,6 +62,10 @@ type symsStruct struct {
 	Zerobase        *obj.LSym
 	ARM64HasATOMICS *obj.LSym
 	ARMHasVFPv4     *obj.LSym
+	X86HasAVX       *obj.LSym
+	X86HasAVX2      *obj.LSym
+	X86HasAVX512BW  *obj.LSym
+	X86HasAVX512F   *obj.LSym
 	X86HasFMA       *obj.LSym
 	X86HasPOPCNT    *obj.LSym
 	X86HasSSE41     *obj.LSym
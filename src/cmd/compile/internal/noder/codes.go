@@ -62,7 +62,7 @@ const (
 	exprFuncInst
 	exprRecv
 	exprReshape
-	exprRuntimeBuiltin // a reference to a runtime function from transformed syntax. Followed by string name, e.g., "panicrangeexit"
+	exprRuntimeBuiltin // a reference to a runtime function from transformed syntax. Followed by string name, e.g., "panicrangestate"
 )
 
 type codeAssign int
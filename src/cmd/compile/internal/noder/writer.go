@@ -62,9 +62,10 @@ import (
 type pkgWriter struct {
 	pkgbits.PkgEncoder
 
-	m      posMap
-	curpkg *types2.Package
-	info   *types2.Info
+	m         posMap
+	curpkg    *types2.Package
+	info      *types2.Info
+	rangeFunc map[*syntax.FuncLit]bool // closures generated for range-over-func loop bodies
 
 	// Indices for previously written syntax and types2 things.
 
@@ -89,13 +90,14 @@ type pkgWriter struct {
 
 // newPkgWriter returns an initialized pkgWriter for the specified
 // package.
-func newPkgWriter(m posMap, pkg *types2.Package, info *types2.Info) *pkgWriter {
+func newPkgWriter(m posMap, pkg *types2.Package, info *types2.Info, rangeFunc map[*syntax.FuncLit]bool) *pkgWriter {
 	return &pkgWriter{
 		PkgEncoder: pkgbits.NewPkgEncoder(base.Debug.SyncFrames),
 
-		m:      m,
-		curpkg: pkg,
-		info:   info,
+		m:         m,
+		curpkg:    pkg,
+		info:      info,
+		rangeFunc: rangeFunc,
 
 		pkgsIdx: make(map[*types2.Package]pkgbits.Index),
 		objsIdx: make(map[types2.Object]pkgbits.Index),
@@ -488,6 +490,18 @@ func (w *writer) typInfo(info typeInfo) {
 // typIdx also reports whether typ is a derived type; that is, whether
 // its identity depends on type parameters.
 func (pw *pkgWriter) typIdx(typ types2.Type, dict *writerDict) typeInfo {
+	// Strip non-global aliases, because they only appear in inline
+	// bodies anyway. Otherwise, they can cause types.Sym collisions
+	// (e.g., "main.C" for both of the local type aliases in
+	// test/fixedbugs/issue50190.go).
+	for {
+		if alias, ok := typ.(*types2.Alias); ok && !isGlobal(alias.Obj()) {
+			typ = alias.Rhs()
+		} else {
+			break
+		}
+	}
+
 	if idx, ok := pw.typsIdx[typ]; ok {
 		return typeInfo{idx: idx, derived: false}
 	}
@@ -497,6 +511,12 @@ func (pw *pkgWriter) typIdx(typ types2.Type, dict *writerDict) typeInfo {
 		}
 	}
 
+	// Instances of generic type aliases are written as the types they
+	// denote; only the alias declaration itself has type parameters.
+	if alias, ok := typ.(*types2.Alias); ok && alias.TypeArgs().Len() > 0 {
+		return pw.typIdx(types2.Unalias(alias), dict)
+	}
+
 	w := pw.newWriter(pkgbits.RelocType, pkgbits.SyncTypeIdx)
 	w.dict = dict
 
@@ -832,9 +852,12 @@ func (w *writer) doObj(wext *writer, obj types2.Object) pkgbits.CodeObj {
 		if obj.IsAlias() {
 			w.pos(obj)
 			t := obj.Type()
+			var tparams *types2.TypeParamList
 			if alias, ok := t.(*types2.Alias); ok { // materialized alias
 				t = alias.Rhs()
+				tparams = alias.TypeParams()
 			}
+			w.typeParamNames(tparams)
 			w.typ(t)
 			return pkgbits.ObjAlias
 		}
@@ -2320,6 +2343,7 @@ func (w *writer) funcLit(expr *syntax.FuncLit) {
 	w.Sync(pkgbits.SyncFuncLit)
 	w.pos(expr)
 	w.signature(sig)
+	w.Bool(w.p.rangeFunc[expr])
 
 	w.Len(len(closureVars))
 	for _, cv := range closureVars {
@@ -2932,8 +2956,13 @@ func objTypeParams(obj types2.Object) *types2.TypeParamList {
 		}
 		return sig.TypeParams()
 	case *types2.TypeName:
-		if !obj.IsAlias() {
-			return obj.Type().(*types2.Named).TypeParams()
+		switch t := obj.Type().(type) {
+		case *types2.Named:
+			if !obj.IsAlias() {
+				return t.TypeParams()
+			}
+		case *types2.Alias:
+			return t.TypeParams()
 		}
 	}
 	return nil
@@ -115,8 +115,22 @@ type Profile struct {
 	// WeightedCG represents the IRGraph built from profile, which we will
 	// update as part of inlining.
 	WeightedCG *IRGraph
+
+	// funcLines maps the linker symbol name of each function in the
+	// profile to the weights of its source lines, keyed by line offset.
+	funcLines map[string]map[int]int64
+
+	// hotLineWeight is the line weight at or above which a source line
+	// is considered hot.
+	hotLineWeight int64
 }
 
+// hotLineThresholdPercent is the weight at or above which a source line is
+// considered hot, as a percentage of the weight of the hottest line. Since
+// line weights are cumulative, the hottest line is typically the root of
+// all stacks, and this is a percentage of the total weight of the profile.
+const hotLineThresholdPercent = 1
+
 // New generates a profile-graph from the profile or pre-processed profile.
 func New(profileFile string) (*Profile, error) {
 	f, err := os.Open(profileFile)
@@ -151,10 +165,44 @@ func New(profileFile string) (*Profile, error) {
 	// Create package-level call graph with weights from profile and IR.
 	wg := createIRGraph(base.NamedEdgeMap)
 
-	return &Profile{
+	p := &Profile{
 		Profile:    base,
 		WeightedCG: wg,
-	}, nil
+		funcLines:  make(map[string]map[int]int64),
+	}
+	for _, l := range base.NamedLineMap.ByWeight {
+		lines := p.funcLines[l.FuncName]
+		if lines == nil {
+			lines = make(map[int]int64)
+			p.funcLines[l.FuncName] = lines
+		}
+		lines[l.LineOffset] = base.NamedLineMap.Weight[l]
+	}
+	if len(base.NamedLineMap.ByWeight) > 0 {
+		hottest := base.NamedLineMap.Weight[base.NamedLineMap.ByWeight[0]]
+		p.hotLineWeight = hottest * hotLineThresholdPercent / 100
+		if p.hotLineWeight < 1 {
+			p.hotLineWeight = 1
+		}
+	}
+	return p, nil
+}
+
+// FuncLines returns the weights of the source lines of the function with
+// the given linker symbol name, keyed by line offset from the function
+// start line. It returns nil if the function does not appear in the
+// profile, or the profile has no line weights, as is the case for
+// profiles preprocessed by older versions of go tool preprofile.
+//
+// The returned map must not be modified.
+func (p *Profile) FuncLines(name string) map[int]int64 {
+	return p.funcLines[name]
+}
+
+// HotLineWeight returns the line weight at or above which a source line is
+// considered hot.
+func (p *Profile) HotLineWeight() int64 {
+	return p.hotLineWeight
 }
 
 // initializeIRGraph builds the IRGraph by visiting all the ir.Func in decl list
@@ -2,8 +2,6 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build goexperiment.rangefunc
-
 package rangefunc_test
 
 import (
@@ -1295,3 +1293,55 @@ func TestGotoB(t *testing.T) {
 		t.Logf("Saw expected panic '%v'", err)
 	}
 }
+
+// SwallowPanic is a bad iterator that recovers from a panic in the
+// loop body and returns as if iteration had finished normally.
+func SwallowPanic[T any](s []T) Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		defer func() {
+			recover()
+		}()
+		for i, v := range s {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// TestMissingPanic checks that a loop whose body panicked does not
+// silently resume when the iterator swallows the panic.
+func TestMissingPanic(t *testing.T) {
+	var result []int
+	err := func() (err any) {
+		defer func() {
+			err = recover()
+		}()
+		for i := range SwallowPanic([]int{1, 2, 3}) {
+			result = append(result, i)
+			panic("body panic")
+		}
+		result = append(result, 999)
+		return
+	}()
+	if expect := []int{0}; !slices.Equal(expect, result) {
+		t.Errorf("Expected %v, got %v", expect, result)
+	}
+	if e, ok := err.(interface{ RuntimeError() }); !ok {
+		t.Errorf("Expected a runtime error, got %v", err)
+	} else {
+		t.Logf("Saw expected panic '%v'", e)
+	}
+}
+
+// TestNoVars checks that range over func permits omitting
+// the iteration variables.
+func TestNoVars(t *testing.T) {
+	n := 0
+	for range OfSliceIndex([]int{10, 20, 30}) {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 iterations, got %d", n)
+	}
+}
@@ -146,9 +146,22 @@ but maybe it should be?
 To permit checking that an iterator is well-behaved -- that is, that
 it does not call the loop body again after it has returned false or
 after the entire loop has exited (it might retain a copy of the body
-function, or pass it to another goroutine) -- each generated loop has
-its own #exitK flag that is checked before each iteration, and set both
-at any early exit and after the iteration completes.
+function, or pass it to another goroutine), and that it does not
+recover a panic raised by the loop body and carry on -- each generated
+loop has its own #stateK variable that records the state of the loop
+body, using the abi.RF_State values:
+
+	abi.RF_READY         the body is ready to be called
+	abi.RF_PANIC         the body is running, or has panicked
+	abi.RF_DONE          the body has returned false
+	abi.RF_EXHAUSTED     the iterator function has returned
+
+The body checks that the state is abi.RF_READY on entry and sets it to
+abi.RF_PANIC; it sets the state back to abi.RF_READY when it returns
+true, and to abi.RF_DONE when it returns false. If the iterator function
+returns normally while the state is still abi.RF_PANIC, it must have
+recovered a panic from the body, which is reported as
+abi.RF_MISSING_PANIC.
 
 For example:
 
@@ -161,15 +174,18 @@ For example:
 becomes
 
 	{
-		var #exit1 bool
+		var #state1 = abi.RF_READY
 		f(func(x T1) bool {
-			if #exit1 { runtime.panicrangeexit() }
+			if #state1 != abi.RF_READY { runtime.panicrangestate(#state1) }
+			#state1 = abi.RF_PANIC
 			...
-			if ... { #exit1 = true ; return false }
+			if ... { #state1 = abi.RF_DONE ; return false }
 			...
+			#state1 = abi.RF_READY
 			return true
 		})
-		#exit1 = true
+		if #state1 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+		#state1 = abi.RF_EXHAUSTED
 	}
 
 # Nested Loops
@@ -206,30 +222,36 @@ becomes
 			#r1 type1
 			#r2 type2
 		)
-		var #exit1 bool
+		var #state1 = abi.RF_READY
 		f(func() {
-			if #exit1 { runtime.panicrangeexit() }
-			var #exit2 bool
+			if #state1 != abi.RF_READY { runtime.panicrangestate(#state1) }
+			#state1 = abi.RF_PANIC
+			var #state2 = abi.RF_READY
 			g(func() {
-				if #exit2 { runtime.panicrangeexit() }
+				if #state2 != abi.RF_READY { runtime.panicrangestate(#state2) }
+				#state2 = abi.RF_PANIC
 				...
 				{
 					// return a, b
 					#r1, #r2 = a, b
 					#next = -2
-					#exit1, #exit2 = true, true
+					#state1, #state2 = abi.RF_DONE, abi.RF_DONE
 					return false
 				}
 				...
+				#state2 = abi.RF_READY
 				return true
 			})
-			#exit2 = true
+			if #state2 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+			#state2 = abi.RF_EXHAUSTED
 			if #next < 0 {
 				return false
 			}
+			#state1 = abi.RF_READY
 			return true
 		})
-		#exit1 = true
+		if #state1 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+		#state1 = abi.RF_EXHAUSTED
 		if #next == -2 {
 			return #r1, #r2
 		}
@@ -278,52 +300,62 @@ becomes
 
 	{
 		var #next int
-		var #exit1 bool
+		var #state1 = abi.RF_READY
 		f(func() {
-			if #exit1 { runtime.panicrangeexit() }
-			var #exit2 bool
+			if #state1 != abi.RF_READY { runtime.panicrangestate(#state1) }
+			#state1 = abi.RF_PANIC
+			var #state2 = abi.RF_READY
 			g(func() {
-				if #exit2 { runtime.panicrangeexit() }
-				var #exit3 bool
+				if #state2 != abi.RF_READY { runtime.panicrangestate(#state2) }
+				#state2 = abi.RF_PANIC
+				var #state3 = abi.RF_READY
 				h(func() {
-					if #exit3 { runtime.panicrangeexit() }
+					if #state3 != abi.RF_READY { runtime.panicrangestate(#state3) }
+					#state3 = abi.RF_PANIC
 					...
 					{
 						// break F
 						#next = 4
-						#exit1, #exit2, #exit3 = true, true, true
+						#state1, #state2, #state3 = abi.RF_DONE, abi.RF_DONE, abi.RF_DONE
 						return false
 					}
 					...
 					{
 						// continue F
 						#next = 3
-						#exit2, #exit3 = true, true
+						#state2, #state3 = abi.RF_DONE, abi.RF_DONE
 						return false
 					}
 					...
+					#state3 = abi.RF_READY
 					return true
 				})
-				#exit3 = true
+				if #state3 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+				#state3 = abi.RF_EXHAUSTED
 				if #next >= 2 {
 					#next -= 2
 					return false
 				}
+				#state2 = abi.RF_READY
 				return true
 			})
-			#exit2 = true
+			if #state2 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+			#state2 = abi.RF_EXHAUSTED
 			if #next >= 2 {
 				#next -= 2
 				return false
 			}
 			if #next == 1 {
 				#next = 0
+				#state1 = abi.RF_READY
 				return true
 			}
 			...
+			#state1 = abi.RF_READY
 			return true
 		})
-		#exit1 = true
+		if #state1 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+		#state1 = abi.RF_EXHAUSTED
 	}
 
 Note that the post-h checks only consider a break,
@@ -363,39 +395,48 @@ becomes
 	Top: print("start\n")
 	{
 		var #next int
-		var #exit1 bool
+		var #state1 = abi.RF_READY
 		f(func() {
-			if #exit1 { runtime.panicrangeexit() }
-			var #exit2 bool
+			if #state1 != abi.RF_READY { runtime.panicrangestate(#state1) }
+			#state1 = abi.RF_PANIC
+			var #state2 = abi.RF_READY
 			g(func() {
-				if #exit2 { runtime.panicrangeexit() }
+				if #state2 != abi.RF_READY { runtime.panicrangestate(#state2) }
+				#state2 = abi.RF_PANIC
 				...
-				var #exit3 bool
+				var #state3 = abi.RF_READY
 				h(func() {
-				if #exit3 { runtime.panicrangeexit() }
+					if #state3 != abi.RF_READY { runtime.panicrangestate(#state3) }
+					#state3 = abi.RF_PANIC
 					...
 					{
 						// goto Top
 						#next = -3
-						#exit1, #exit2, #exit3 = true, true, true
+						#state1, #state2, #state3 = abi.RF_DONE, abi.RF_DONE, abi.RF_DONE
 						return false
 					}
 					...
+					#state3 = abi.RF_READY
 					return true
 				})
-				#exit3 = true
+				if #state3 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+				#state3 = abi.RF_EXHAUSTED
 				if #next < 0 {
 					return false
 				}
+				#state2 = abi.RF_READY
 				return true
 			})
-			#exit2 = true
+			if #state2 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+			#state2 = abi.RF_EXHAUSTED
 			if #next < 0 {
 				return false
 			}
+			#state1 = abi.RF_READY
 			return true
 		})
-		#exit1 = true
+		if #state1 == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+		#state1 = abi.RF_EXHAUSTED
 		if #next == -3 {
 			#next = 0
 			goto Top
@@ -462,6 +503,7 @@ import (
 	"cmd/compile/internal/types2"
 	"fmt"
 	"go/constant"
+	"internal/abi"
 	"os"
 )
 
@@ -493,11 +535,15 @@ type rewriter struct {
 	rewritten map[*syntax.ForStmt]syntax.Stmt
 
 	// Declared variables in generated code for outermost loop.
-	declStmt     *syntax.DeclStmt
-	nextVar      types2.Object
-	retVars      []types2.Object
-	defers       types2.Object
-	exitVarCount int // exitvars are referenced from their respective loops
+	declStmt      *syntax.DeclStmt
+	nextVar       types2.Object
+	retVars       []types2.Object
+	defers        types2.Object
+	stateVarCount int // stateVars are referenced from their respective loops
+
+	// rangefuncBodyClosures records the func literals
+	// generated for loop bodies, shared by all rewriters.
+	rangefuncBodyClosures map[*syntax.FuncLit]bool
 }
 
 // A branch is a single labeled branch.
@@ -509,8 +555,8 @@ type branch struct {
 // A forLoop describes a single range-over-func loop being processed.
 type forLoop struct {
 	nfor         *syntax.ForStmt // actual syntax
-	exitFlag     *types2.Var     // #exit variable for this loop
-	exitFlagDecl *syntax.VarDecl
+	stateVar     *types2.Var     // #state variable for this loop
+	stateVarDecl *syntax.VarDecl
 
 	checkRet      bool     // add check for "return" after loop
 	checkRetArgs  bool     // add check for "return args" after loop
@@ -520,25 +566,29 @@ type forLoop struct {
 }
 
 // Rewrite rewrites all the range-over-funcs in the files.
-func Rewrite(pkg *types2.Package, info *types2.Info, files []*syntax.File) {
+// It returns the set of function literals generated from
+// range-over-func loop bodies.
+func Rewrite(pkg *types2.Package, info *types2.Info, files []*syntax.File) map[*syntax.FuncLit]bool {
+	ri := make(map[*syntax.FuncLit]bool)
 	for _, file := range files {
 		syntax.Inspect(file, func(n syntax.Node) bool {
 			switch n := n.(type) {
 			case *syntax.FuncDecl:
-				rewriteFunc(pkg, info, n.Type, n.Body)
+				rewriteFunc(pkg, info, n.Type, n.Body, ri)
 				return false
 			case *syntax.FuncLit:
-				rewriteFunc(pkg, info, n.Type, n.Body)
+				rewriteFunc(pkg, info, n.Type, n.Body, ri)
 				return false
 			}
 			return true
 		})
 	}
+	return ri
 }
 
 // rewriteFunc rewrites all the range-over-funcs in a single function (a top-level func or a func literal).
 // The typ and body are the function's type and body.
-func rewriteFunc(pkg *types2.Package, info *types2.Info, typ *syntax.FuncType, body *syntax.BlockStmt) {
+func rewriteFunc(pkg *types2.Package, info *types2.Info, typ *syntax.FuncType, body *syntax.BlockStmt, ri map[*syntax.FuncLit]bool) {
 	if body == nil {
 		return
 	}
@@ -547,6 +597,8 @@ func rewriteFunc(pkg *types2.Package, info *types2.Info, typ *syntax.FuncType, b
 		info:  info,
 		outer: typ,
 		body:  body,
+
+		rangefuncBodyClosures: ri,
 	}
 	syntax.Inspect(body, r.inspect)
 	if (base.Flag.W != 0) && r.forStack != nil {
@@ -566,7 +618,7 @@ func (r *rewriter) checkFuncMisuse() bool {
 func (r *rewriter) inspect(n syntax.Node) bool {
 	switch n := n.(type) {
 	case *syntax.FuncLit:
-		rewriteFunc(r.pkg, r.info, n.Type, n.Body)
+		rewriteFunc(r.pkg, r.info, n.Type, n.Body, r.rangefuncBodyClosures)
 		return false
 
 	default:
@@ -627,8 +679,8 @@ func (r *rewriter) startLoop(loop *forLoop) {
 		r.rewritten = make(map[*syntax.ForStmt]syntax.Stmt)
 	}
 	if r.checkFuncMisuse() {
-		// declare the exit flag for this loop's body
-		loop.exitFlag, loop.exitFlagDecl = r.exitVar(loop.nfor.Pos())
+		// declare the state flag for this loop's body
+		loop.stateVar, loop.stateVarDecl = r.stateVar(loop.nfor.Pos())
 	}
 }
 
@@ -679,17 +731,19 @@ func (r *rewriter) editDefer(x *syntax.CallStmt) syntax.Stmt {
 	return x
 }
 
-func (r *rewriter) exitVar(pos syntax.Pos) (*types2.Var, *syntax.VarDecl) {
-	r.exitVarCount++
+// stateVar declares a new #stateK variable, initialized to abi.RF_READY,
+// for a loop at pos.
+func (r *rewriter) stateVar(pos syntax.Pos) (*types2.Var, *syntax.VarDecl) {
+	r.stateVarCount++
 
-	name := fmt.Sprintf("#exit%d", r.exitVarCount)
-	typ := r.bool.Type()
+	name := fmt.Sprintf("#state%d", r.stateVarCount)
+	typ := r.int.Type()
 	obj := types2.NewVar(pos, r.pkg, name, typ)
 	n := syntax.NewName(pos, name)
 	setValueType(n, typ)
 	r.info.Defs[n] = obj
 
-	return obj, &syntax.VarDecl{NameList: []*syntax.Name{n}}
+	return obj, &syntax.VarDecl{NameList: []*syntax.Name{n}, Values: r.stateConst(abi.RF_READY)}
 }
 
 // editReturn returns the replacement for the return statement x.
@@ -725,7 +779,7 @@ func (r *rewriter) editReturn(x *syntax.ReturnStmt) syntax.Stmt {
 	if r.checkFuncMisuse() {
 		// mark all enclosing loop bodies as exited
 		for i := 0; i < len(r.forStack); i++ {
-			bl.List = append(bl.List, r.setExitedAt(i))
+			bl.List = append(bl.List, r.setStateAt(i, abi.RF_DONE))
 		}
 	}
 	bl.List = append(bl.List, &syntax.ReturnStmt{Results: r.useVar(r.false)})
@@ -814,8 +868,16 @@ func (r *rewriter) editBranch(x *syntax.BranchStmt) syntax.Stmt {
 
 		if depth == 0 && x.Tok == syntax.Continue {
 			ret = &syntax.ReturnStmt{Results: r.useVar(r.true)}
-			setPos(ret, x.Pos())
-			return ret
+			if !r.checkFuncMisuse() {
+				setPos(ret, x.Pos())
+				return ret
+			}
+			// Mark this loop body as ready for the next iteration.
+			bl := &syntax.BlockStmt{
+				List: []syntax.Stmt{r.setState(abi.RF_READY), ret},
+			}
+			setPos(bl, x.Pos())
+			return bl
 		}
 		ret = &syntax.ReturnStmt{Results: r.useVar(r.false)}
 
@@ -824,7 +886,7 @@ func (r *rewriter) editBranch(x *syntax.BranchStmt) syntax.Stmt {
 		if depth == 0 {
 			var stmts []syntax.Stmt
 			if r.checkFuncMisuse() {
-				stmts = []syntax.Stmt{r.setExited(), ret}
+				stmts = []syntax.Stmt{r.setState(abi.RF_DONE), ret}
 			} else {
 				stmts = []syntax.Stmt{ret}
 			}
@@ -864,9 +926,9 @@ func (r *rewriter) editBranch(x *syntax.BranchStmt) syntax.Stmt {
 	}
 
 	if r.checkFuncMisuse() {
-		// Set #exitK for this loop and those exited by the control flow.
+		// Set #stateK for this loop and those exited by the control flow.
 		for i := exitFrom; i < len(r.forStack); i++ {
-			bl.List = append(bl.List, r.setExitedAt(i))
+			bl.List = append(bl.List, r.setStateAt(i, abi.RF_DONE))
 		}
 	}
 
@@ -971,18 +1033,20 @@ func (r *rewriter) endLoop(loop *forLoop) {
 		block.List = append(block.List, r.declStmt)
 	}
 
-	// declare the exitFlag here so it has proper scope and zeroing
+	// declare the state flag here so it has proper scope and initialization
 	if r.checkFuncMisuse() {
-		exitFlagDecl := &syntax.DeclStmt{DeclList: []syntax.Decl{loop.exitFlagDecl}}
-		block.List = append(block.List, exitFlagDecl)
+		stateVarDecl := &syntax.DeclStmt{DeclList: []syntax.Decl{loop.stateVarDecl}}
+		setPos(stateVarDecl, start)
+		block.List = append(block.List, stateVarDecl)
 	}
 
 	// iteratorFunc(bodyFunc)
 	block.List = append(block.List, call)
 
 	if r.checkFuncMisuse() {
-		// iteratorFunc has exited, mark the exit flag for the body
-		block.List = append(block.List, r.setExited())
+		// iteratorFunc has exited; check that it did not swallow a
+		// panic from the body, and mark the sequence exhausted.
+		block.List = append(block.List, r.assertNotMissingPanic(end, loop), r.setState(abi.RF_EXHAUSTED))
 	}
 	block.List = append(block.List, checks...)
 
@@ -996,15 +1060,17 @@ func (r *rewriter) endLoop(loop *forLoop) {
 	r.rewritten[nfor] = block
 }
 
-func (r *rewriter) setExited() *syntax.AssignStmt {
-	return r.setExitedAt(len(r.forStack) - 1)
+// setState returns the statement #stateK = s for the innermost loop.
+func (r *rewriter) setState(s abi.RF_State) *syntax.AssignStmt {
+	return r.setStateAt(len(r.forStack)-1, s)
 }
 
-func (r *rewriter) setExitedAt(index int) *syntax.AssignStmt {
+// setStateAt returns the statement #stateK = s for the loop r.forStack[index].
+func (r *rewriter) setStateAt(index int, s abi.RF_State) *syntax.AssignStmt {
 	loop := r.forStack[index]
 	return &syntax.AssignStmt{
-		Lhs: r.useVar(loop.exitFlag),
-		Rhs: r.useVar(r.true),
+		Lhs: r.useVar(loop.stateVar),
+		Rhs: r.stateConst(s),
 	}
 }
 
@@ -1059,17 +1125,28 @@ func (r *rewriter) bodyFunc(body []syntax.Stmt, lhs []syntax.Expr, def bool, fty
 	}
 	tv.SetIsValue()
 	bodyFunc.SetTypeInfo(tv)
+	r.rangefuncBodyClosures[bodyFunc] = true
 
 	loop := r.forStack[len(r.forStack)-1]
 
 	if r.checkFuncMisuse() {
-		bodyFunc.Body.List = append(bodyFunc.Body.List, r.assertNotExited(start, loop))
+		// The body is running; until it exits normally,
+		// it might panic.
+		bodyFunc.Body.List = append(bodyFunc.Body.List, r.assertReady(start, loop))
+		setState := r.setState(abi.RF_PANIC)
+		setPos(setState, start)
+		bodyFunc.Body.List = append(bodyFunc.Body.List, setState)
 	}
 
 	// Original loop body (already rewritten by editStmt during inspect).
 	bodyFunc.Body.List = append(bodyFunc.Body.List, body...)
 
-	// return true to continue at end of loop body
+	// end of loop body, set state to ready and return true to continue iteration
+	if r.checkFuncMisuse() {
+		setState := r.setState(abi.RF_READY)
+		setPos(setState, end)
+		bodyFunc.Body.List = append(bodyFunc.Body.List, setState)
+	}
 	ret := &syntax.ReturnStmt{Results: r.useVar(r.true)}
 	ret.SetPos(end)
 	bodyFunc.Body.List = append(bodyFunc.Body.List, ret)
@@ -1108,7 +1185,15 @@ func (r *rewriter) checks(loop *forLoop, pos syntax.Pos) []syntax.Stmt {
 			list = append(list, r.ifNext(syntax.Geq, perLoopStep, retStmt(r.useVar(r.false))))
 		}
 		if loop.checkContinue {
-			list = append(list, r.ifNext(syntax.Eql, perLoopStep-1, retStmt(r.useVar(r.true))))
+			// Continuing the enclosing loop returns true from its body,
+			// which must first be marked ready for the next iteration.
+			var cont syntax.Stmt = retStmt(r.useVar(r.true))
+			if r.checkFuncMisuse() {
+				cont = &syntax.BlockStmt{
+					List: []syntax.Stmt{r.setStateAt(len(r.forStack)-2, abi.RF_READY), cont},
+				}
+			}
+			list = append(list, r.ifNext(syntax.Eql, perLoopStep-1, cont))
 		}
 	}
 
@@ -1167,26 +1252,49 @@ func setValueType(x syntax.Expr, typ syntax.Type) {
 	x.SetTypeInfo(tv)
 }
 
-// assertNotExited returns the statement:
+// assertReady returns the statement:
 //
-//	if #exitK { runtime.panicrangeexit() }
+//	if #stateK != abi.RF_READY { runtime.panicrangestate(#stateK) }
 //
-// where #exitK is the exit guard for loop.
-func (r *rewriter) assertNotExited(start syntax.Pos, loop *forLoop) syntax.Stmt {
+// where #stateK is the state variable for loop.
+func (r *rewriter) assertReady(start syntax.Pos, loop *forLoop) syntax.Stmt {
+	nif := r.ifState(syntax.Neq, loop, abi.RF_READY, r.useVar(loop.stateVar))
+	setPos(nif, start)
+	return nif
+}
+
+// assertNotMissingPanic returns the statement:
+//
+//	if #stateK == abi.RF_PANIC { runtime.panicrangestate(abi.RF_MISSING_PANIC) }
+//
+// where #stateK is the state variable for loop.
+func (r *rewriter) assertNotMissingPanic(end syntax.Pos, loop *forLoop) syntax.Stmt {
+	nif := r.ifState(syntax.Eql, loop, abi.RF_PANIC, r.stateConst(abi.RF_MISSING_PANIC))
+	setPos(nif, end)
+	return nif
+}
+
+// ifState returns the statement:
+//
+//	if #stateK op s { runtime.panicrangestate(arg) }
+//
+// where #stateK is the state variable for loop.
+func (r *rewriter) ifState(op syntax.Operator, loop *forLoop, s abi.RF_State, arg syntax.Expr) *syntax.IfStmt {
 	callPanicExpr := &syntax.CallExpr{
-		Fun: runtimeSym(r.info, "panicrangeexit"),
+		Fun:     runtimeSym(r.info, "panicrangestate"),
+		ArgList: []syntax.Expr{arg},
 	}
 	setValueType(callPanicExpr, nil) // no result type
 
 	callPanic := &syntax.ExprStmt{X: callPanicExpr}
 
 	nif := &syntax.IfStmt{
-		Cond: r.useVar(loop.exitFlag),
+		Cond: &syntax.Operation{Op: op, X: r.useVar(loop.stateVar), Y: r.stateConst(s)},
 		Then: &syntax.BlockStmt{
 			List: []syntax.Stmt{callPanic},
 		},
 	}
-	setPos(nif, start)
+	setValueType(nif.Cond, r.bool.Type())
 	return nif
 }
 
@@ -1217,6 +1325,11 @@ func forRangeFunc(n syntax.Node) (*syntax.ForStmt, bool) {
 	return nfor, true
 }
 
+// stateConst returns syntax for an integer literal with the value of s.
+func (r *rewriter) stateConst(s abi.RF_State) *syntax.BasicLit {
+	return r.intConst(int(s))
+}
+
 // intConst returns syntax for an integer literal with the given value.
 func (r *rewriter) intConst(c int) *syntax.BasicLit {
 	lit := &syntax.BasicLit{
@@ -1289,8 +1402,9 @@ var runtimePkg = func() *types2.Package {
 	obj := types2.NewFunc(nopos, pkg, "deferrangefunc", types2.NewSignatureType(nil, nil, nil, nil, types2.NewTuple(types2.NewParam(nopos, pkg, "extra", anyType)), false))
 	pkg.Scope().Insert(obj)
 
-	// func panicrangeexit()
-	obj = types2.NewFunc(nopos, pkg, "panicrangeexit", types2.NewSignatureType(nil, nil, nil, nil, nil, false))
+	// func panicrangestate(state int)
+	intType := types2.Universe.Lookup("int").Type()
+	obj = types2.NewFunc(nopos, pkg, "panicrangestate", types2.NewSignatureType(nil, nil, nil, types2.NewTuple(types2.NewParam(nopos, pkg, "state", intType)), nil, false))
 	pkg.Scope().Insert(obj)
 
 	return pkg
@@ -904,6 +904,13 @@ func init() {
 			faultOnNilArg0: true,
 		},
 
+		// Vector operations for package simd, see the generic SIMD ops.
+		// auxint = ssa.SIMDOp
+		// Uses X13 and X14 (and their Y and Z extensions, and K1) as temporaries.
+		{name: "LoweredSIMDUnary", argLength: 3, reg: regInfo{inputs: []regMask{gpsp, gpsp}, clobbers: buildReg("X14")}, aux: "Int64"},                          // *arg0 = op(*arg1). arg2=mem, returns mem
+		{name: "LoweredSIMDBinary", argLength: 4, reg: regInfo{inputs: []regMask{gpsp, gpsp, gpsp}, clobbers: buildReg("X13 X14")}, aux: "Int64"},               // *arg0 = *arg1 op *arg2. arg3=mem, returns mem
+		{name: "LoweredSIMDMask", argLength: 2, reg: regInfo{inputs: []regMask{gpsp}, outputs: gponly, clobbers: buildReg("X14")}, aux: "Int64", typ: "UInt64"}, // sign bits of *arg0. arg1=mem
+
 		// With a register ABI, the actual register info for these instructions (i.e., what is used in regalloc) is augmented with per-call-site bindings of additional arguments to specific in and out registers.
 		{name: "CALLstatic", argLength: -1, reg: regInfo{clobbers: callerSave}, aux: "CallOff", clobberFlags: true, call: true},                                              // call static function aux.(*obj.LSym).  last arg=mem, auxint=argsize, returns mem
 		{name: "CALLtail", argLength: -1, reg: regInfo{clobbers: callerSave}, aux: "CallOff", clobberFlags: true, call: true, tailCall: true},                                // tail call static function aux.(*obj.LSym).  last arg=mem, auxint=argsize, returns mem
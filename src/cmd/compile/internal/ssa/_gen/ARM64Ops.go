@@ -561,6 +561,13 @@ func init() {
 			faultOnNilArg0: true,
 		},
 
+		// Vector operations for package simd, see the generic SIMD ops.
+		// auxint = ssa.SIMDOp
+		// Uses V29-V31 and REGTMP as temporaries.
+		{name: "LoweredSIMDUnary", argLength: 3, reg: regInfo{inputs: []regMask{gp, gp}, clobbers: buildReg("F30")}, aux: "Int64"},                                   // *arg0 = op(*arg1). arg2=mem, returns mem
+		{name: "LoweredSIMDBinary", argLength: 4, reg: regInfo{inputs: []regMask{gp, gp, gp}, clobbers: buildReg("F29 F30 F31")}, aux: "Int64"},                      // *arg0 = *arg1 op *arg2. arg3=mem, returns mem
+		{name: "LoweredSIMDMask", argLength: 2, reg: regInfo{inputs: []regMask{gp}, outputs: []regMask{gp}, clobbers: buildReg("F30")}, aux: "Int64", typ: "UInt64"}, // sign bits of *arg0. arg1=mem
+
 		// duffcopy
 		// arg0 = address of dst memory (in R21, changed as side effect)
 		// arg1 = address of src memory (in R20, changed as side effect)
@@ -639,6 +639,14 @@ var genericOps = []opData{
 	// Prefetch instruction
 	{name: "PrefetchCache", argLength: 2, hasSideEffects: true},         // Do prefetch arg0 to cache. arg0=addr, arg1=memory.
 	{name: "PrefetchCacheStreamed", argLength: 2, hasSideEffects: true}, // Do non-temporal or streamed prefetch arg0 to cache. arg0=addr, arg1=memory.
+
+	// Vector operations for package simd. The operands are pointers to
+	// vectors in memory. auxint is an ssa.SIMDOp giving the operation,
+	// element type and vector width. SIMDBinary with a MulAdd op also
+	// reads *arg0.
+	{name: "SIMDUnary", argLength: 3, typ: "Mem", aux: "Int64"},   // *arg0 = op(*arg1). arg2=memory. Returns memory.
+	{name: "SIMDBinary", argLength: 4, typ: "Mem", aux: "Int64"},  // *arg0 = *arg1 op *arg2. arg3=memory. Returns memory.
+	{name: "SIMDMask", argLength: 2, typ: "UInt64", aux: "Int64"}, // bit i of result = sign bit of element i of *arg0. arg1=memory.
 }
 
 //     kind          controls        successors   implicit exit
@@ -503,6 +503,7 @@ var passes = [...]pass{
 	{name: "critical", fn: critical, required: true}, // remove critical edges
 	{name: "phi tighten", fn: phiTighten},            // place rematerializable phi args near uses to reduce value lifetimes
 	{name: "likelyadjust", fn: likelyadjust},
+	{name: "pgo branches", fn: pgoBranches},          // predict branches using the PGO profile, if any
 	{name: "layout", fn: layout, required: true},     // schedule blocks
 	{name: "schedule", fn: schedule, required: true}, // schedule values
 	{name: "late nilcheck", fn: nilcheckelim2},
@@ -559,6 +560,9 @@ var passOrder = [...]constraint{
 	{"critical", "phi tighten"},
 	// don't layout blocks until critical edges have been removed
 	{"critical", "layout"},
+	// the profile overrides static branch predictions, and drives layout
+	{"likelyadjust", "pgo branches"},
+	{"pgo branches", "layout"},
 	// regalloc requires the removal of all critical edges
 	{"critical", "regalloc"},
 	// regalloc requires all the values in a block to be scheduled
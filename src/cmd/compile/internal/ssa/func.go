@@ -78,6 +78,15 @@ type Func struct {
 
 	auxmap    auxmap             // map from aux values to opaque ids used by CSE
 	constants map[int64][]*Value // constants cache, keyed by constant value; users must check value's Op and Type
+
+	// ProfileLines maps source lines of the function, as relative line
+	// numbers of outermost positions, to their cumulative weight in the
+	// PGO profile. It is nil if there is no profile or the function does
+	// not appear in it. See pgo.go.
+	ProfileLines map[int]int64
+	// ProfileHotWeight is the weight at or above which a line is
+	// considered hot.
+	ProfileHotWeight int64
 }
 
 type LocalSlotSplitKey struct {
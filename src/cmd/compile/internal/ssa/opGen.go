@@ -1035,6 +1035,9 @@ const (
 	OpAMD64MOVQstoreconstidx8
 	OpAMD64DUFFZERO
 	OpAMD64REPSTOSQ
+	OpAMD64LoweredSIMDUnary
+	OpAMD64LoweredSIMDBinary
+	OpAMD64LoweredSIMDMask
 	OpAMD64CALLstatic
 	OpAMD64CALLtail
 	OpAMD64CALLclosure
@@ -1691,6 +1694,9 @@ const (
 	OpARM64GreaterEqualNoov
 	OpARM64DUFFZERO
 	OpARM64LoweredZero
+	OpARM64LoweredSIMDUnary
+	OpARM64LoweredSIMDBinary
+	OpARM64LoweredSIMDMask
 	OpARM64DUFFCOPY
 	OpARM64LoweredMove
 	OpARM64LoweredGetClosurePtr
@@ -3242,6 +3248,9 @@ const (
 	OpClobberReg
 	OpPrefetchCache
 	OpPrefetchCacheStreamed
+	OpSIMDUnary
+	OpSIMDBinary
+	OpSIMDMask
 )
 
 var opcodeTable = [...]opInfo{
@@ -13664,6 +13673,45 @@ var opcodeTable = [...]opInfo{
 			clobbers: 130, // CX DI
 		},
 	},
+	{
+		name:    "LoweredSIMDUnary",
+		auxType: auxInt64,
+		argLen:  3,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+				{1, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+			},
+			clobbers: 1073741824, // X14
+		},
+	},
+	{
+		name:    "LoweredSIMDBinary",
+		auxType: auxInt64,
+		argLen:  4,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+				{1, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+				{2, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+			},
+			clobbers: 1610612736, // X13 X14
+		},
+	},
+	{
+		name:    "LoweredSIMDMask",
+		auxType: auxInt64,
+		argLen:  2,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 49151}, // AX CX DX BX SP BP SI DI R8 R9 R10 R11 R12 R13 R15
+			},
+			clobbers: 1073741824, // X14
+			outputs: []outputInfo{
+				{0, 49135}, // AX CX DX BX BP SI DI R8 R9 R10 R11 R12 R13 R15
+			},
+		},
+	},
 	{
 		name:         "CALLstatic",
 		auxType:      auxCallOff,
@@ -22634,6 +22682,45 @@ var opcodeTable = [...]opInfo{
 			clobbers: 65536, // R16
 		},
 	},
+	{
+		name:    "LoweredSIMDUnary",
+		auxType: auxInt64,
+		argLen:  3,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+				{1, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+			},
+			clobbers: 2305843009213693952, // F30
+		},
+	},
+	{
+		name:    "LoweredSIMDBinary",
+		auxType: auxInt64,
+		argLen:  4,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+				{1, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+				{2, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+			},
+			clobbers: 8070450532247928832, // F29 F30 F31
+		},
+	},
+	{
+		name:    "LoweredSIMDMask",
+		auxType: auxInt64,
+		argLen:  2,
+		reg: regInfo{
+			inputs: []inputInfo{
+				{0, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+			},
+			clobbers: 2305843009213693952, // F30
+			outputs: []outputInfo{
+				{0, 670826495}, // R0 R1 R2 R3 R4 R5 R6 R7 R8 R9 R10 R11 R12 R13 R14 R15 R16 R17 R19 R20 R21 R22 R23 R24 R25 R26 R30
+			},
+		},
+	},
 	{
 		name:           "DUFFCOPY",
 		auxType:        auxInt64,
@@ -40726,6 +40813,24 @@ var opcodeTable = [...]opInfo{
 		hasSideEffects: true,
 		generic:        true,
 	},
+	{
+		name:    "SIMDUnary",
+		auxType: auxInt64,
+		argLen:  3,
+		generic: true,
+	},
+	{
+		name:    "SIMDBinary",
+		auxType: auxInt64,
+		argLen:  4,
+		generic: true,
+	},
+	{
+		name:    "SIMDMask",
+		auxType: auxInt64,
+		argLen:  2,
+		generic: true,
+	},
 }
 
 func (o Op) Asm() obj.As          { return opcodeTable[o].asm }
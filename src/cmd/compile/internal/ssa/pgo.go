@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "cmd/internal/src"
+
+// Profile-guided optimizations below the call graph level.
+//
+// When compiling with a PGO profile, the frontend sets Func.ProfileLines to
+// the cumulative weight of each source line of the function, as recorded by
+// the profile. The weight of a line is the weight of the samples with that
+// line anywhere on the stack, so it approximates how much time is spent
+// executing the line and the functions it calls, inlined or not.
+//
+// The weight of a block is the largest weight of the lines of its values and
+// control. It is used to
+//   - predict branches towards the hotter successor, which drives block
+//     layout (see pgoBranches), and
+//   - align the first block of hot loops (see Func.HotLoopStarts).
+
+// profileWeight returns the weight of b in the profile, or -1 if none of its
+// values carries a position that is covered by the profile.
+//
+// The profile records lines as adjusted by //line directives, and attributes
+// inlined code to the calls in this function, so positions are mapped to the
+// relative line of their outermost position.
+func (f *Func) profileWeight(b *Block) int64 {
+	w := int64(-1)
+	add := func(pos src.XPos) {
+		if !pos.IsKnown() {
+			return
+		}
+		line := int(f.Config.ctxt.OutermostPos(pos).RelLine())
+		if lw, ok := f.ProfileLines[line]; ok {
+			w = max(w, lw)
+		} else {
+			// The function appears in the profile, so a line
+			// missing from it was never sampled.
+			w = max(w, 0)
+		}
+	}
+	for _, v := range b.Values {
+		add(v.Pos)
+	}
+	if b.Kind != BlockPlain {
+		add(b.Pos)
+	}
+	return w
+}
+
+// profileWeights returns the profile weight of each block, indexed by block
+// ID, or nil if the function has no profile.
+func (f *Func) profileWeights() []int64 {
+	if f.ProfileLines == nil {
+		return nil
+	}
+	weights := make([]int64, f.NumBlocks())
+	for _, b := range f.Blocks {
+		weights[b.ID] = f.profileWeight(b)
+	}
+	return weights
+}
+
+// pgoBranches sets the likely direction of conditional branches whose
+// successors have clearly different weights in the profile. The profile
+// takes precedence over the static heuristics of likelyadjust, which
+// runs before it.
+func pgoBranches(f *Func) {
+	weights := f.profileWeights()
+	if weights == nil {
+		return
+	}
+
+	// succWeight returns the weight of the code reached through b,
+	// skipping over empty blocks like those introduced for critical
+	// edges.
+	succWeight := func(b *Block) int64 {
+		for i := 0; i < 4 && weights[b.ID] < 0 && b.Kind == BlockPlain; i++ {
+			b = b.Succs[0].b
+		}
+		return weights[b.ID]
+	}
+
+	for _, b := range f.Blocks {
+		if b.Kind == BlockDefer || len(b.Succs) != 2 {
+			continue
+		}
+		w0, w1 := succWeight(b.Succs[0].b), succWeight(b.Succs[1].b)
+		if w0 < 0 || w1 < 0 {
+			continue
+		}
+		// Require a clear difference, so that noise in the
+		// profile does not override the static heuristics.
+		likely := b.Likely
+		switch {
+		case w0 > 0 && w0 >= 2*w1:
+			likely = BranchLikely
+		case w1 > 0 && w1 >= 2*w0:
+			likely = BranchUnlikely
+		}
+		if likely != b.Likely {
+			if f.pass.debug > 0 {
+				succ := b.Succs[0].b
+				if likely == BranchUnlikely {
+					succ = b.Succs[1].b
+				}
+				f.Warnl(b.Pos, "PGO predicts branch to %s (weights %d, %d)", succ, w0, w1)
+			}
+			b.Likely = likely
+		}
+	}
+}
+
+// HotLoopStarts reports, for each block ID, whether the block is the first
+// block of a hot loop in the final block order, that is, the target of a
+// backward branch whose weight in the profile is at least
+// ProfileHotWeight. It returns nil if the function has no profile.
+// It must be called after block layout is final.
+func (f *Func) HotLoopStarts() []bool {
+	if f.ProfileLines == nil || f.ProfileHotWeight <= 0 {
+		return nil
+	}
+	order := make([]int, f.NumBlocks())
+	for i, b := range f.Blocks {
+		order[b.ID] = i
+	}
+	var starts []bool
+	for i, b := range f.Blocks {
+		backward := false
+		for _, e := range b.Preds {
+			if order[e.b.ID] >= i {
+				backward = true
+				break
+			}
+		}
+		if !backward || f.profileWeight(b) < f.ProfileHotWeight {
+			continue
+		}
+		if starts == nil {
+			starts = make([]bool, f.NumBlocks())
+		}
+		starts[b.ID] = true
+	}
+	return starts
+}
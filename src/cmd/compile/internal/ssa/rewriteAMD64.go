@@ -1068,6 +1068,15 @@ func rewriteValueAMD64(v *Value) bool {
 		return rewriteValueAMD64_OpRsh8x64(v)
 	case OpRsh8x8:
 		return rewriteValueAMD64_OpRsh8x8(v)
+	case OpSIMDBinary:
+		v.Op = OpAMD64LoweredSIMDBinary
+		return true
+	case OpSIMDMask:
+		v.Op = OpAMD64LoweredSIMDMask
+		return true
+	case OpSIMDUnary:
+		v.Op = OpAMD64LoweredSIMDUnary
+		return true
 	case OpSelect0:
 		return rewriteValueAMD64_OpSelect0(v)
 	case OpSelect1:
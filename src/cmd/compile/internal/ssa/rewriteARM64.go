@@ -1025,6 +1025,15 @@ func rewriteValueARM64(v *Value) bool {
 		return rewriteValueARM64_OpRsh8x64(v)
 	case OpRsh8x8:
 		return rewriteValueARM64_OpRsh8x8(v)
+	case OpSIMDBinary:
+		v.Op = OpARM64LoweredSIMDBinary
+		return true
+	case OpSIMDMask:
+		v.Op = OpARM64LoweredSIMDMask
+		return true
+	case OpSIMDUnary:
+		v.Op = OpARM64LoweredSIMDUnary
+		return true
 	case OpSelect0:
 		return rewriteValueARM64_OpSelect0(v)
 	case OpSelect1:
@@ -0,0 +1,131 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import "fmt"
+
+// A SIMDOp describes the operation performed by a SIMDUnary,
+// SIMDBinary or SIMDMask value, and is stored in the value's AuxInt.
+// It records the operation, the vector element type and the vector
+// width in bits. The backends use it to select instructions.
+type SIMDOp int64
+
+// A SIMDKind is the operation of a SIMDOp.
+type SIMDKind uint8
+
+const (
+	SIMDAdd     SIMDKind = iota + 1 // z = x + y
+	SIMDSub                         // z = x - y
+	SIMDMul                         // z = x * y
+	SIMDDiv                         // z = x / y
+	SIMDMin                         // z = min(x, y)
+	SIMDMax                         // z = max(x, y)
+	SIMDAnd                         // z = x & y
+	SIMDOr                          // z = x | y
+	SIMDXor                         // z = x ^ y
+	SIMDAndNot                      // z = x &^ y
+	SIMDEqual                       // z = x == y ? -1 : 0
+	SIMDGreater                     // z = x > y ? -1 : 0
+	SIMDMulAdd                      // z = x*y + z, fused
+	SIMDSqrt                        // z = sqrt(x)
+	SIMDMask                        // bit i of result = sign bit of x[i]
+)
+
+var simdKindNames = [...]string{
+	SIMDAdd:     "Add",
+	SIMDSub:     "Sub",
+	SIMDMul:     "Mul",
+	SIMDDiv:     "Div",
+	SIMDMin:     "Min",
+	SIMDMax:     "Max",
+	SIMDAnd:     "And",
+	SIMDOr:      "Or",
+	SIMDXor:     "Xor",
+	SIMDAndNot:  "AndNot",
+	SIMDEqual:   "Equal",
+	SIMDGreater: "Greater",
+	SIMDMulAdd:  "MulAdd",
+	SIMDSqrt:    "Sqrt",
+	SIMDMask:    "Mask",
+}
+
+func (k SIMDKind) String() string {
+	if int(k) < len(simdKindNames) && simdKindNames[k] != "" {
+		return simdKindNames[k]
+	}
+	return fmt.Sprintf("SIMDKind(%d)", uint8(k))
+}
+
+// A SIMDElem is the element type of a SIMDOp.
+type SIMDElem uint8
+
+const (
+	SIMDUint8 SIMDElem = iota + 1
+	SIMDInt32
+	SIMDFloat32
+	SIMDFloat64
+)
+
+var simdElemNames = [...]string{
+	SIMDUint8:   "Uint8",
+	SIMDInt32:   "Int32",
+	SIMDFloat32: "Float32",
+	SIMDFloat64: "Float64",
+}
+
+func (e SIMDElem) String() string {
+	if int(e) < len(simdElemNames) && simdElemNames[e] != "" {
+		return simdElemNames[e]
+	}
+	return fmt.Sprintf("SIMDElem(%d)", uint8(e))
+}
+
+// Size returns the size of an element in bytes.
+func (e SIMDElem) Size() int {
+	switch e {
+	case SIMDUint8:
+		return 1
+	case SIMDInt32, SIMDFloat32:
+		return 4
+	case SIMDFloat64:
+		return 8
+	}
+	panic("bad SIMDElem")
+}
+
+// IsFloat reports whether e is a floating-point element type.
+func (e SIMDElem) IsFloat() bool {
+	return e == SIMDFloat32 || e == SIMDFloat64
+}
+
+// MakeSIMDOp returns the SIMDOp for operation k on vectors of width
+// bits with elements of type e.
+func MakeSIMDOp(k SIMDKind, e SIMDElem, width int) SIMDOp {
+	return SIMDOp(int64(k) | int64(e)<<8 | int64(width)<<16)
+}
+
+// Kind returns the operation of o.
+func (o SIMDOp) Kind() SIMDKind {
+	return SIMDKind(o)
+}
+
+// Elem returns the element type of o.
+func (o SIMDOp) Elem() SIMDElem {
+	return SIMDElem(o >> 8)
+}
+
+// Width returns the vector width of o in bits.
+func (o SIMDOp) Width() int {
+	return int(o >> 16)
+}
+
+// Lanes returns the number of elements in a vector of o.
+func (o SIMDOp) Lanes() int {
+	return o.Width() / 8 / o.Elem().Size()
+}
+
+func (o SIMDOp) String() string {
+	return fmt.Sprintf("%v %vx%d", o.Kind(), o.Elem(), o.Lanes())
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"internal/buildcfg"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/sys"
+)
+
+// simdTypes are the vector types of package simd.
+var simdTypes = [...]struct {
+	name  string
+	elem  ssa.SIMDElem
+	width int // in bits
+}{
+	{"Uint8x16", ssa.SIMDUint8, 128},
+	{"Uint8x32", ssa.SIMDUint8, 256},
+	{"Uint8x64", ssa.SIMDUint8, 512},
+	{"Int32x4", ssa.SIMDInt32, 128},
+	{"Int32x8", ssa.SIMDInt32, 256},
+	{"Int32x16", ssa.SIMDInt32, 512},
+	{"Float32x4", ssa.SIMDFloat32, 128},
+	{"Float32x8", ssa.SIMDFloat32, 256},
+	{"Float32x16", ssa.SIMDFloat32, 512},
+	{"Float64x2", ssa.SIMDFloat64, 128},
+	{"Float64x4", ssa.SIMDFloat64, 256},
+	{"Float64x8", ssa.SIMDFloat64, 512},
+}
+
+// simdMethodExists reports whether the vector types with element
+// type e have the method for operation k.
+func simdMethodExists(k ssa.SIMDKind, e ssa.SIMDElem) bool {
+	switch k {
+	case ssa.SIMDAdd, ssa.SIMDSub, ssa.SIMDMin, ssa.SIMDMax:
+		return true
+	case ssa.SIMDMul:
+		return e != ssa.SIMDUint8
+	case ssa.SIMDDiv, ssa.SIMDSqrt, ssa.SIMDMulAdd:
+		return e.IsFloat()
+	case ssa.SIMDAnd, ssa.SIMDOr, ssa.SIMDXor, ssa.SIMDAndNot, ssa.SIMDEqual:
+		return !e.IsFloat()
+	case ssa.SIMDGreater:
+		return e == ssa.SIMDInt32
+	case ssa.SIMDMask:
+		return e == ssa.SIMDUint8
+	}
+	return false
+}
+
+// initSIMDIntrinsics registers the methods of the package simd vector
+// types as intrinsics on the architectures that can lower them.
+// Methods that are not intrinsified use their portable Go code.
+func initSIMDIntrinsics(addF func(pkg, fn string, b intrinsicBuilder, archFamilies ...sys.ArchFamily)) {
+	for _, t := range &simdTypes {
+		for k := ssa.SIMDAdd; k <= ssa.SIMDMask; k++ {
+			if !simdMethodExists(k, t.elem) {
+				continue
+			}
+			op := ssa.MakeSIMDOp(k, t.elem, t.width)
+			fn := "(*" + t.name + ")." + k.String()
+			if amd64SIMDLowered(op) {
+				addF("simd", fn, makeSIMDIntrinsic(op, amd64SIMDFeatures), sys.AMD64)
+			}
+			// Advanced SIMD is mandatory on arm64. Vectors wider
+			// than 128 bits are processed 128 bits at a time.
+			addF("simd", fn, makeSIMDIntrinsic(op, nil), sys.ARM64)
+		}
+	}
+}
+
+// amd64SIMDLowered reports whether the amd64 back end lowers op.
+func amd64SIMDLowered(op ssa.SIMDOp) bool {
+	// Materializing the result of a 512-bit int32 comparison, which
+	// is a mask register, needs AVX-512DQ, which the runtime does
+	// not detect.
+	if op.Width() == 512 && op.Elem() == ssa.SIMDInt32 {
+		return op.Kind() != ssa.SIMDEqual && op.Kind() != ssa.SIMDGreater
+	}
+	return true
+}
+
+// amd64SIMDFeatures returns the runtime CPU feature flags that must
+// all be set to run the lowering of op. It returns nil if the
+// GOAMD64 level guarantees them.
+func amd64SIMDFeatures(op ssa.SIMDOp) []*obj.LSym {
+	var features []*obj.LSym
+	switch op.Width() {
+	case 128:
+		// Use VEX encodings, which do not require alignment
+		// and avoid SSE/AVX transition penalties.
+		if buildcfg.GOAMD64 < 3 {
+			features = append(features, ir.Syms.X86HasAVX)
+		}
+	case 256:
+		if buildcfg.GOAMD64 < 3 {
+			if op.Elem().IsFloat() {
+				features = append(features, ir.Syms.X86HasAVX)
+			} else {
+				features = append(features, ir.Syms.X86HasAVX2)
+			}
+		}
+	case 512:
+		if buildcfg.GOAMD64 < 4 {
+			features = append(features, ir.Syms.X86HasAVX512F)
+			if op.Elem() == ssa.SIMDUint8 {
+				features = append(features, ir.Syms.X86HasAVX512BW)
+			}
+		}
+	}
+	if op.Kind() == ssa.SIMDMulAdd && op.Width() < 512 && buildcfg.GOAMD64 < 3 {
+		features = append(features, ir.Syms.X86HasFMA)
+	}
+	return features
+}
+
+// makeSIMDIntrinsic returns an intrinsic builder for the vector
+// operation op. If features is not nil, it returns the CPU feature
+// flags that are checked at run time before using the lowered
+// operation; if they are not all set, the method is called instead.
+func makeSIMDIntrinsic(op ssa.SIMDOp, features func(ssa.SIMDOp) []*obj.LSym) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		var syms []*obj.LSym
+		if features != nil {
+			syms = features(op)
+		}
+		if len(syms) == 0 {
+			return s.simdOp(op, n, args)
+		}
+
+		var v *ssa.Value
+		for _, sym := range syms {
+			f := s.entryNewValue0A(ssa.OpHasCPUFeature, types.Types[types.TBOOL], sym)
+			if v == nil {
+				v = f
+			} else {
+				v = s.newValue2(ssa.OpAndB, types.Types[types.TBOOL], v, f)
+			}
+		}
+		b := s.endBlock()
+		b.Kind = ssa.BlockIf
+		b.SetControl(v)
+		bTrue := s.f.NewBlock(ssa.BlockPlain)
+		bFalse := s.f.NewBlock(ssa.BlockPlain)
+		bEnd := s.f.NewBlock(ssa.BlockPlain)
+		b.AddEdgeTo(bTrue)
+		b.AddEdgeTo(bFalse)
+		b.Likely = ssa.BranchLikely // code using package simd expects vector units
+
+		// We have the intrinsic - use it directly.
+		s.startBlock(bTrue)
+		s.vars[n] = s.simdOp(op, n, args)
+		s.endBlock().AddEdgeTo(bEnd)
+
+		// Call the pure Go version.
+		s.startBlock(bFalse)
+		s.vars[n] = s.callResult(n, callNormal)
+		s.endBlock().AddEdgeTo(bEnd)
+
+		// Merge results.
+		s.startBlock(bEnd)
+		return s.variable(n, n.Type())
+	}
+}
+
+// simdOp emits the vector operation op for the method call n with
+// arguments args, the receiver followed by the method arguments.
+// It returns the result of the call.
+func (s *state) simdOp(op ssa.SIMDOp, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+	switch op.Kind() {
+	case ssa.SIMDMask:
+		v := s.newValue2I(ssa.OpSIMDMask, types.Types[types.TUINT64], int64(op), args[0], s.mem())
+		switch n.Type().Size() {
+		case 2:
+			return s.newValue1(ssa.OpTrunc64to16, n.Type(), v)
+		case 4:
+			return s.newValue1(ssa.OpTrunc64to32, n.Type(), v)
+		}
+		return v
+	case ssa.SIMDSqrt:
+		s.vars[memVar] = s.newValue3I(ssa.OpSIMDUnary, types.TypeMem, int64(op), args[0], args[1], s.mem())
+	default:
+		s.vars[memVar] = s.newValue4I(ssa.OpSIMDBinary, types.TypeMem, int64(op), args[0], args[1], args[2], s.mem())
+	}
+	// The methods return their receiver.
+	return args[0]
+}
@@ -21,6 +21,7 @@ import (
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/liveness"
 	"cmd/compile/internal/objw"
+	"cmd/compile/internal/pgoir"
 	"cmd/compile/internal/reflectdata"
 	"cmd/compile/internal/rttype"
 	"cmd/compile/internal/ssa"
@@ -38,6 +39,11 @@ import (
 var ssaConfig *ssa.Config
 var ssaCaches []ssa.Cache
 
+// PGOProfile is the profile that guides block layout and loop alignment,
+// or nil. It is only set with -d=pgolayout=1, and it must be set before
+// compiling any functions.
+var PGOProfile *pgoir.Profile
+
 var ssaDump string     // early copy of $GOSSAFUNC; the func name to dump output for
 var ssaDir string      // optional destination for ssa dump file
 var ssaDumpStdout bool // whether to dump to stdout
@@ -146,6 +152,10 @@ func InitConfig() {
 	ir.Syms.X86HasPOPCNT = typecheck.LookupRuntimeVar("x86HasPOPCNT")       // bool
 	ir.Syms.X86HasSSE41 = typecheck.LookupRuntimeVar("x86HasSSE41")         // bool
 	ir.Syms.X86HasFMA = typecheck.LookupRuntimeVar("x86HasFMA")             // bool
+	ir.Syms.X86HasAVX = typecheck.LookupRuntimeVar("x86HasAVX")             // bool
+	ir.Syms.X86HasAVX2 = typecheck.LookupRuntimeVar("x86HasAVX2")           // bool
+	ir.Syms.X86HasAVX512F = typecheck.LookupRuntimeVar("x86HasAVX512F")     // bool
+	ir.Syms.X86HasAVX512BW = typecheck.LookupRuntimeVar("x86HasAVX512BW")   // bool
 	ir.Syms.ARMHasVFPv4 = typecheck.LookupRuntimeVar("armHasVFPv4")         // bool
 	ir.Syms.ARM64HasATOMICS = typecheck.LookupRuntimeVar("arm64HasATOMICS") // bool
 	ir.Syms.Staticuint64s = typecheck.LookupRuntimeVar("staticuint64s")
@@ -367,6 +377,18 @@ func buildssa(fn *ir.Func, worker int) *ssa.Func {
 	s.f.ABIDefault = abiForFunc(nil, ssaConfig.ABI0, ssaConfig.ABI1)
 	s.f.ABISelf = abiSelf
 
+	if PGOProfile != nil {
+		if lines := PGOProfile.FuncLines(ir.LinkFuncName(fn)); lines != nil {
+			// See "A note on line numbers" in package pgoir.
+			startLine := int(base.Ctxt.InnermostPos(fn.Pos()).RelLine())
+			s.f.ProfileLines = make(map[int]int64, len(lines))
+			for off, w := range lines {
+				s.f.ProfileLines[startLine+off] = w
+			}
+			s.f.ProfileHotWeight = PGOProfile.HotLineWeight()
+		}
+	}
+
 	s.panics = map[funcLine]*ssa.Block{}
 	s.softFloat = s.config.SoftFloat
 
@@ -5067,6 +5089,13 @@ func InitTables() {
 
 	/******** math/big ********/
 	alias("math/big", "mulWW", "math/bits", "Mul64", p8...)
+
+	/******** simd ********/
+	// Vector operations read and write memory behind the back of
+	// the race detector and sanitizers, so leave them as calls.
+	if buildcfg.Experiment.SIMD && !base.Flag.Cfg.Instrumenting {
+		initSIMDIntrinsics(addF)
+	}
 }
 
 // findIntrinsic returns a function which builds the SSA equivalent of the
@@ -5105,16 +5134,31 @@ func IsIntrinsicCall(n *ir.CallExpr) bool {
 	if n == nil {
 		return false
 	}
-	name, ok := n.Fun.(*ir.Name)
-	if !ok {
+	sym := calleeSym(n)
+	if sym == nil {
 		return false
 	}
-	return findIntrinsic(name.Sym()) != nil
+	return findIntrinsic(sym) != nil
+}
+
+// calleeSym returns the symbol of the function or method called by n,
+// or nil if n is not a direct call.
+func calleeSym(n *ir.CallExpr) *types.Sym {
+	switch fn := n.Fun; fn.Op() {
+	case ir.ONAME:
+		return fn.(*ir.Name).Sym()
+	case ir.OMETHEXPR:
+		if name := ir.MethodExprName(fn); name != nil {
+			return name.Sym()
+		}
+	}
+	return nil
 }
 
 // intrinsicCall converts a call to a recognized intrinsic function into the intrinsic SSA operation.
 func (s *state) intrinsicCall(n *ir.CallExpr) *ssa.Value {
-	v := findIntrinsic(n.Fun.Sym())(s, n, s.intrinsicArgs(n))
+	sym := calleeSym(n)
+	v := findIntrinsic(sym)(s, n, s.intrinsicArgs(n))
 	if ssa.IntrinsicsDebug > 0 {
 		x := v
 		if x == nil {
@@ -5123,7 +5167,7 @@ func (s *state) intrinsicCall(n *ir.CallExpr) *ssa.Value {
 		if x.Op == ssa.OpSelect0 || x.Op == ssa.OpSelect1 {
 			x = x.Args[0]
 		}
-		base.WarnfAt(n.Pos(), "intrinsic substitution for %v with %s", n.Fun.Sym().Name, x.LongString())
+		base.WarnfAt(n.Pos(), "intrinsic substitution for %v with %s", sym.Name, x.LongString())
 	}
 	return v
 }
@@ -7302,8 +7346,21 @@ func genssa(f *ssa.Func, pp *objw.Progs) {
 
 	var argLiveIdx int = -1 // argument liveness info index
 
+	// Align the start of hot loops, if the profile shows any.
+	var alignLoops []bool
+	if Arch.LinkArch.Family == sys.AMD64 {
+		alignLoops = f.HotLoopStarts()
+	}
+
 	// Emit basic blocks
 	for i, b := range f.Blocks {
+		if alignLoops != nil && alignLoops[b.ID] {
+			// Branches to b target the instruction after the
+			// padding, so only the loop entry executes it.
+			p := s.pp.Prog(obj.APCALIGN)
+			p.From.Type = obj.TYPE_CONST
+			p.From.Offset = 32
+		}
 		s.bstart[b.ID] = s.pp.Next
 		s.lineRunStart = nil
 		s.SetPos(s.pp.Pos.WithNotStmt()) // It needs a non-empty Pos, but cannot be a statement boundary (yet).
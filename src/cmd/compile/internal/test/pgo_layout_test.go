@@ -0,0 +1,157 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"internal/profile"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const layoutSrc = `package main
+
+//go:noinline
+func Cold(n int) int {
+	return n * 3
+}
+
+//go:noinline
+func work(i int) int {
+	return i*i + 1
+}
+
+//go:noinline
+func Hot(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		if i%16 != 0 {
+			s += work(i)
+		} else {
+			s -= i
+		}
+	}
+	return s
+}
+
+func main() {
+	println(Hot(1000), Cold(1))
+}
+`
+
+// writeLayoutProfile writes a CPU profile for layoutSrc in which the call to
+// work in Hot dominates, and Cold and the else branch in Hot never run.
+func writeLayoutProfile(t *testing.T, name string) {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     1,
+	}
+	funcs := make(map[string]*profile.Function)
+	fn := func(name string, start int64) *profile.Function {
+		f := &profile.Function{ID: uint64(len(p.Function) + 1), Name: name, SystemName: name, Filename: "layout.go", StartLine: start}
+		p.Function = append(p.Function, f)
+		funcs[name] = f
+		return f
+	}
+	fn("main.work", 9)
+	fn("main.Hot", 14)
+	fn("main.main", 26)
+	loc := func(name string, line int64) *profile.Location {
+		l := &profile.Location{ID: uint64(len(p.Location) + 1), Address: uint64(len(p.Location) + 1), Line: []profile.Line{{Function: funcs[name], Line: line}}}
+		p.Location = append(p.Location, l)
+		return l
+	}
+	mainCall := loc("main.main", 27)
+	for _, s := range []struct {
+		weight int64
+		stack  []*profile.Location
+	}{
+		{100, []*profile.Location{loc("main.work", 10), loc("main.Hot", 18), mainCall}},
+		{20, []*profile.Location{loc("main.Hot", 18), mainCall}},
+		{30, []*profile.Location{loc("main.Hot", 16), mainCall}},
+		{30, []*profile.Location{loc("main.Hot", 17), mainCall}},
+	} {
+		p.Sample = append(p.Sample, &profile.Sample{Location: s.stack, Value: []int64{s.weight}})
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := p.Write(f); err != nil {
+		t.Fatalf("error writing profile: %v", err)
+	}
+}
+
+// TestPGOLayout tests that with -d=pgolayout=1 the profile drives branch
+// prediction and loop alignment, and that it does not without it.
+func TestPGOLayout(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/pgo/layout\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "layout.go"), []byte(layoutSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pprof := filepath.Join(dir, "layout.pprof")
+	writeLayoutProfile(t, pprof)
+
+	exe := filepath.Join(dir, "layout.exe")
+	build := func(debug string) []byte {
+		gcflag := fmt.Sprintf("-gcflags=-pgoprofile=%s -S -d=%sssa/pgo_branches/debug=1", pprof, debug)
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, testenv.GoToolPath(t), "build", "-o", exe, gcflag, "."))
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("build failed: %v, output:\n%s", err, out)
+		}
+		return out
+	}
+	predicted := regexp.MustCompile(`layout\.go:17:\d+: PGO predicts branch to b\d+`)
+
+	// Profile-guided layout is off by default.
+	if out := build(""); predicted.Match(out) || bytes.Contains(out, []byte("PCALIGN\t$32")) {
+		t.Errorf("profile used for layout without -d=pgolayout=1, output:\n%s", out)
+	}
+
+	out := build("pgolayout=1,")
+
+	// The branch to the call in the loop is predicted taken, even though
+	// branches to calls are statically predicted not taken.
+	if !predicted.Match(out) {
+		t.Errorf("missing PGO branch prediction in Hot, output:\n%s", out)
+	}
+
+	// The hot loop in Hot is aligned.
+	if runtime.GOARCH == "amd64" {
+		aligned := false
+		inHot := false
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.Contains(line, " STEXT ") {
+				inHot = strings.HasPrefix(line, "main.Hot ")
+			}
+			if inHot && strings.Contains(line, "PCALIGN\t$32") {
+				aligned = true
+			}
+		}
+		if !aligned {
+			t.Errorf("hot loop in Hot is not aligned, output:\n%s", out)
+		}
+	}
+}
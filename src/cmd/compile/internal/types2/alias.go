@@ -4,17 +4,29 @@
 
 package types2
 
-import "fmt"
+import (
+	"cmd/compile/internal/syntax"
+	"fmt"
+)
 
 // An Alias represents an alias type.
 // Whether or not Alias types are created is controlled by the
 // gotypesalias setting with the GODEBUG environment variable.
-// For gotypesalias=1, alias declarations produce an Alias type.
-// Otherwise, the alias information is only in the type name,
-// which points directly to the actual (aliased) type.
+// For gotypesalias=1, alias declarations produce an Alias type,
+// and for gotypesalias=0 they do not. If the setting is absent,
+// they produce an Alias type if the package's Go version, as given
+// by Config.GoVersion, is go1.23 or later.
+// Without an Alias type, the alias information is only in the type
+// name, which points directly to the actual (aliased) type.
+//
+// A generic alias has type parameters; an instance of a generic alias
+// has the same type parameters and records the type arguments it was
+// instantiated with. Generic aliases require Alias types.
 type Alias struct {
 	obj     *TypeName      // corresponding declared alias object
+	orig    *Alias         // original, uninstantiated alias
 	tparams *TypeParamList // type parameters, or nil
+	targs   *TypeList      // type arguments, or nil
 	fromRHS Type           // RHS of type alias declaration; may be an alias
 	actual  Type           // actual (aliased) type; never an alias
 }
@@ -28,7 +40,10 @@ func NewAlias(obj *TypeName, rhs Type) *Alias {
 	return alias
 }
 
-func (a *Alias) Obj() *TypeName   { return a.obj }
+// Obj returns the type name for the declaration defining the alias type a.
+// For instantiated types, this is same as the type name of the origin type.
+func (a *Alias) Obj() *TypeName { return a.orig.obj }
+
 func (a *Alias) Underlying() Type { return unalias(a).Underlying() }
 func (a *Alias) String() string   { return TypeString(a, nil) }
 
@@ -38,6 +53,25 @@ func (a *Alias) String() string   { return TypeString(a, nil) }
 // // declaration "type A = R", which may be another alias.
 // func (a *Alias) Rhs() Type { return a.fromRHS }
 
+// Origin returns the generic Alias type of which a is an instance.
+// If a is not an instance of a generic alias, Origin returns a.
+func (a *Alias) Origin() *Alias { return a.orig }
+
+// TypeParams returns the type parameters of the alias type a, or nil.
+// A generic Alias and its instances have the same type parameters.
+func (a *Alias) TypeParams() *TypeParamList { return a.tparams }
+
+// SetTypeParams sets the type parameters of the alias type a.
+// The alias a must not have type arguments.
+func (a *Alias) SetTypeParams(tparams []*TypeParam) {
+	assert(a.targs == nil)
+	a.tparams = bindTParams(tparams)
+}
+
+// TypeArgs returns the type arguments used to instantiate the Alias type.
+// If a is not an instance of a generic alias, the result is nil.
+func (a *Alias) TypeArgs() *TypeList { return a.targs }
+
 // Unalias returns t if it is not an alias type;
 // otherwise it follows t's alias chain until it
 // reaches a non-alias type which is then returned.
@@ -84,7 +118,10 @@ func asNamed(t Type) *Named {
 // rhs must not be nil.
 func (check *Checker) newAlias(obj *TypeName, rhs Type) *Alias {
 	assert(rhs != nil)
-	a := &Alias{obj, nil, rhs, nil}
+	a := new(Alias)
+	a.obj = obj
+	a.orig = a
+	a.fromRHS = rhs
 	if obj.typ == nil {
 		obj.typ = a
 	}
@@ -97,6 +134,20 @@ func (check *Checker) newAlias(obj *TypeName, rhs Type) *Alias {
 	return a
 }
 
+// newAliasInstance creates a new alias instance for the given origin and type
+// arguments, recording pos as the position of its synthetic object (for error
+// reporting).
+func (check *Checker) newAliasInstance(pos syntax.Pos, orig *Alias, targs []Type, expanding *Named, ctxt *Context) *Alias {
+	assert(len(targs) > 0)
+	obj := NewTypeName(pos, orig.obj.pkg, orig.obj.name, nil)
+	rhs := check.subst(pos, orig.fromRHS, makeSubstMap(orig.TypeParams().list(), targs), expanding, ctxt)
+	res := check.newAlias(obj, rhs)
+	res.orig = orig
+	res.tparams = orig.tparams
+	res.targs = newTypeList(targs)
+	return res
+}
+
 func (a *Alias) cleanup() {
 	// Ensure a.actual is set before types are published,
 	// so Unalias is a pure "getter", not a "setter".
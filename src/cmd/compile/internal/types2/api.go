@@ -175,6 +175,11 @@ type Config struct {
 	// of an error message. ErrorURL must be a format string containing
 	// exactly one "%s" format, e.g. "[go.dev/e/%s]".
 	ErrorURL string
+
+	// If EnableAlias is set, alias declarations produce an Alias type,
+	// independent of the gotypesalias GODEBUG setting. This is required
+	// for generic type aliases.
+	EnableAlias bool
 }
 
 func srcimporter_setUsesCgo(conf *Config) {
@@ -446,17 +446,12 @@ func (check *Checker) instantiatedType(x syntax.Expr, xlist []syntax.Expr, def *
 	}
 
 	var cause string
-	gtyp := check.genericType(x, &cause)
+	typ := check.genericType(x, &cause)
 	if cause != "" {
 		check.errorf(x, NotAGenericType, invalidOp+"%s%s (%s)", x, xlist, cause)
 	}
-	if !isValid(gtyp) {
-		return gtyp // error already reported
-	}
-
-	orig := asNamed(gtyp)
-	if orig == nil {
-		panic(fmt.Sprintf("%v: cannot instantiate %v", x.Pos(), gtyp))
+	if !isValid(typ) {
+		return typ // error already reported
 	}
 
 	// evaluate arguments
@@ -466,19 +461,38 @@ func (check *Checker) instantiatedType(x syntax.Expr, xlist []syntax.Expr, def *
 		return Typ[Invalid]
 	}
 
+	// typ must be a generic Alias or Named type, or an alias of a generic Named type
+	var gtyp genericType
+	if alias, _ := typ.(*Alias); alias != nil && isGeneric(alias) {
+		gtyp = alias
+	} else if orig := asNamed(typ); orig != nil {
+		gtyp = orig
+	} else {
+		panic(fmt.Sprintf("%v: cannot instantiate %v", x.Pos(), typ))
+	}
+
 	// create the instance
-	inst := asNamed(check.instance(x.Pos(), orig, targs, nil, check.context()))
+	// The instance is not generic anymore as it has type arguments, but unless
+	// instantiation failed, it still satisfies the genericType interface because
+	// it has type parameters, too.
+	inst, _ := check.instance(x.Pos(), gtyp, targs, nil, check.context()).(genericType)
+	if inst == nil {
+		setDefType(def, Typ[Invalid])
+		return Typ[Invalid] // error already reported
+	}
 	setDefType(def, inst)
 
-	// orig.tparams may not be set up, so we need to do expansion later.
+	// For Named types, orig.tparams may not be set up, so we need to do expansion later.
 	check.later(func() {
 		// This is an instance from the source, not from recursive substitution,
 		// and so it must be resolved during type-checking so that we can report
 		// errors.
-		check.recordInstance(x, inst.TypeArgs().list(), inst)
+		check.recordInstance(x, targs, inst)
 
-		if check.validateTArgLen(x.Pos(), inst.obj.name, inst.TypeParams().Len(), inst.TypeArgs().Len()) {
-			if i, err := check.verify(x.Pos(), inst.TypeParams().list(), inst.TypeArgs().list(), check.context()); err != nil {
+		name := inst.(interface{ Obj() *TypeName }).Obj().name
+		tparams := inst.TypeParams().list()
+		if check.validateTArgLen(x.Pos(), name, len(tparams), len(targs)) {
+			if i, err := check.verify(x.Pos(), tparams, targs, check.context()); err != nil {
 				// best position for error reporting
 				pos := x.Pos()
 				if i < len(xlist) {
@@ -486,19 +500,27 @@ func (check *Checker) instantiatedType(x syntax.Expr, xlist []syntax.Expr, def *
 				}
 				check.softErrorf(pos, InvalidTypeArg, "%s", err)
 			} else {
-				check.mono.recordInstance(check.pkg, x.Pos(), inst.TypeParams().list(), inst.TypeArgs().list(), xlist)
+				check.mono.recordInstance(check.pkg, x.Pos(), tparams, targs, xlist)
 			}
 		}
 
 		// TODO(rfindley): remove this call: we don't need to call validType here,
 		// as cycles can only occur for types used inside a Named type declaration,
 		// and so it suffices to call validType from declared types.
-		check.validType(inst)
+		if named := asNamed(inst); named != nil {
+			check.validType(named)
+		}
 	}).describef(x, "resolve instance %s", inst)
 
 	return inst
 }
 
+// A genericType implements access to its type parameters.
+type genericType interface {
+	Type
+	TypeParams() *TypeParamList
+}
+
 // arrayLength type-checks the array length expression e
 // and returns the constant length >= 0, or a value < 0
 // to indicate an error (and thus an unknown length).
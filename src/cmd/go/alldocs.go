@@ -37,6 +37,7 @@
 // Additional help topics:
 //
 //	buildconstraint build constraints
+//	buildjson       build -json encoding
 //	buildmode       build modes
 //	c               calling between Go and C
 //	cache           build and test caching
@@ -69,7 +70,7 @@
 //
 // Usage:
 //
-//	go build [-o output] [build flags] [packages]
+//	go build [-o output] [-json] [build flags] [packages]
 //
 // Build compiles the packages named by the import paths,
 // along with their dependencies, but it does not install the results.
@@ -100,6 +101,10 @@
 // ends with a slash or backslash, then any resulting executables
 // will be written to that directory.
 //
+// The -json flag prints the progress of the build and the output of the
+// build tools as JSON events on standard output, instead of printing tool
+// output as text on standard error. See 'go help buildjson' for details.
+//
 // The build flags are shared by the build, clean, get, install, list, run,
 // and test commands:
 //
@@ -734,7 +739,7 @@
 //
 // Usage:
 //
-//	go install [build flags] [packages]
+//	go install [-json] [build flags] [packages]
 //
 // Install compiles and installs the packages named by the import paths.
 //
@@ -787,6 +792,9 @@
 // Setting GODEBUG=installgoroot=all restores the use of
 // $GOROOT/pkg/$GOOS_$GOARCH.
 //
+// The -json flag prints the progress of the build as JSON events,
+// as for 'go build'. See 'go help buildjson' for details.
+//
 // For more about build flags, see 'go help build'.
 //
 // For more about specifying packages, see 'go help packages'.
@@ -1003,6 +1011,7 @@
 //	    GoVersion  string        // go version used in module
 //	    Retracted  []string      // retraction information, if any (with -retracted or -u)
 //	    Deprecated string        // deprecation message, if any (with -u)
+//	    Vulns      []*ModuleVuln // known vulnerabilities, if any (with -vuln)
 //	    Error      *ModuleError  // error loading module
 //	    Sum        string        // checksum for path, version (as in go.sum)
 //	    GoModSum   string        // checksum for go.mod (as in go.sum)
@@ -1014,6 +1023,17 @@
 //	    Err string // the error itself
 //	}
 //
+//	type ModuleVuln struct {
+//	    ID       string   // vulnerability ID, such as "GO-2021-0113"
+//	    Aliases  []string // other IDs, such as CVE numbers
+//	    Summary  string   // short description
+//	    Fixed    string   // lowest version with a fix, if any
+//	    Packages []*struct {
+//	        Path    string   // package import path
+//	        Symbols []string // affected functions and methods, if known
+//	    }
+//	}
+//
 // The file GoMod refers to may be outside the module directory if the
 // module is in the module cache or if the -modfile flag is used.
 //
@@ -1065,6 +1085,17 @@
 // versions are listed together with unretracted versions. The -retracted
 // flag may be used with or without -m.
 //
+// The -vuln flag causes list to set the Module's Vulns field to the
+// vulnerabilities known to affect that module version, according to the
+// vulnerability database named by the GOVULNDB environment variable,
+// which must be set (see 'go help environment'). Each vulnerability
+// lists the packages and symbols it affects, when known, and the lowest
+// version that fixes it. A version with known vulnerabilities is followed
+// by the string "(vulnerable)" in the default output. Vulnerabilities are
+// matched by module version only: the packages and symbols are copied from
+// the database, and list does not check whether the build uses them. The
+// -vuln flag may only be used with -m.
+//
 // The arguments to list -m are interpreted as a list of modules, not packages.
 // The main module is the module containing the current directory.
 // The active modules are the main module and its dependencies.
@@ -1120,6 +1151,7 @@
 //	graph       print module requirement graph
 //	init        initialize new module in current directory
 //	tidy        add missing and remove unused modules
+//	upgrade     upgrade retracted dependencies and report deprecated ones
 //	vendor      make vendored copy of dependencies
 //	verify      verify dependencies have expected content
 //	why         explain why packages or modules are needed
@@ -1364,6 +1396,50 @@
 //
 // See https://golang.org/ref/mod#go-mod-tidy for more about 'go mod tidy'.
 //
+// # Upgrade retracted dependencies and report deprecated ones
+//
+// Usage:
+//
+//	go mod upgrade [-diff] [-json] [-x]
+//
+// Upgrade checks every module in the build list for retractions and
+// deprecations published by the module's author, and upgrades each module
+// whose selected version is retracted to the lowest newer version that is
+// not retracted or excluded. The upgrades are applied together using
+// minimal version selection, so that no other module is upgraded beyond what
+// those new versions require. If a newly selected version is itself
+// retracted, upgrade repeats the process until the build list contains no
+// retracted versions that can be upgraded.
+//
+// A deprecation applies to every version of a module, so upgrade reports
+// deprecated modules but cannot fix them by changing versions; they must be
+// replaced by hand, typically with the module named in the deprecation
+// message.
+//
+// Upgrade prints each retracted or deprecated module, and each change to
+// the build list, to standard error.
+//
+// The -diff flag causes upgrade to print the changes it would make to
+// go.mod as a unified diff, without modifying go.mod or go.sum.
+//
+// The -json flag causes upgrade to print a sequence of JSON objects to
+// standard output instead of modifying go.mod, one for each retracted or
+// deprecated module, corresponding to this Go struct:
+//
+//	type Module struct {
+//	    Path       string   // module path
+//	    Version    string   // selected version
+//	    Retracted  []string // retraction rationale, if the version is retracted
+//	    Deprecated string   // deprecation message, if the module is deprecated
+//	    Upgrade    string   // proposed version, if any
+//	    Error      string   // error finding an upgrade, if any
+//	}
+//
+// The -x flag causes upgrade to print the commands download executes.
+//
+// See https://golang.org/ref/mod#go-mod-file-retract for more about
+// retractions and deprecations.
+//
 // # Make vendored copy of dependencies
 //
 // Usage:
@@ -1868,7 +1944,7 @@
 //
 // Usage:
 //
-//	go version [-m] [-v] [file ...]
+//	go version [-m] [-v] [-vuln] [file ...]
 //
 // Version prints the build information for Go binary files.
 //
@@ -1887,6 +1963,16 @@
 // information consists of multiple lines following the version line, each
 // indented by a leading tab character.
 //
+// The -vuln flag, which requires -m, causes go version to consult the
+// vulnerability database named by the GOVULNDB environment variable,
+// which must be set (see 'go help environment'), and print a line for each known vulnerability
+// affecting a module in the binary, of the form
+//
+//	vuln	GO-2021-0113	golang.org/x/text@v0.3.5	fixed in v0.3.7
+//
+// As with 'go list -m -vuln', vulnerabilities are matched by module version
+// only, whether or not the binary contains the affected packages and symbols.
+//
 // See also: go doc runtime/debug.BuildInfo.
 //
 // # Report likely mistakes in packages
@@ -1913,6 +1999,10 @@
 // and execution, such as -C, -n, -x, -v, -tags, and -toolexec.
 // For more about these flags, see 'go help build'.
 //
+// The -json flag, which is passed to the vet tool, also makes go vet print
+// the progress of the build and the output of the build and vet tools as a
+// stream of JSON objects on standard output; see 'go help buildjson'.
+//
 // See also: go fmt, go fix.
 //
 // # Build constraints
@@ -2045,6 +2135,62 @@
 // with a "// +build" prefix. The gofmt command will add an equivalent //go:build
 // constraint when encountering the older syntax.
 //
+// # Build -json encoding
+//
+// The 'go build', 'go install' and 'go vet' commands accept a -json flag,
+// which makes them print the progress of the build and the output of the
+// build tools as a stream of JSON objects on standard output, one per line,
+// instead of printing tool output as text on standard error. Each object
+// is a BuildEvent:
+//
+//	type BuildEvent struct {
+//		Time       time.Time
+//		Action     string
+//		ImportPath string
+//		Output     string
+//		File       string
+//		Line       int
+//		Column     int
+//		Message    string
+//	}
+//
+// The Time field holds the time the event happened. The ImportPath field
+// holds the import path of the package the event is about, if any.
+//
+// The possible values of Action are:
+//
+//	build-start      - the build of the package has started
+//	build-cached     - the package was found in the build cache
+//	build-compiled   - the package was compiled
+//	build-output     - a build tool printed Output for the package
+//	build-diagnostic - a compiler or vet reported a diagnostic
+//	build-fail       - building or linking the package failed
+//
+// A "build-start" event is followed by one of "build-cached",
+// "build-compiled" or "build-fail" for the same package. Events for
+// different packages are interleaved as packages are built in parallel.
+//
+// A "build-diagnostic" event describes one error or warning in the output
+// of a compiler or of vet, giving its position as File, Line and, if known, Column,
+// and its text as Message. The diagnostics of a failed package are reported
+// after the "build-output" event carrying the full output of the failing
+// tool, and before the "build-fail" event.
+//
+// The Action values of build events all begin with "build-" and differ
+// from those of 'go test -json' events (see 'go doc test2json'), whose
+// Time, Action and Output fields have the same meaning, so that the two
+// streams can be read by the same program.
+//
+// Errors that prevent the go command from starting the build, such as
+// a package that cannot be found, are still printed as text on standard
+// error.
+//
+// For 'go vet', the -json flag is also passed to the vet tool, which then
+// reports its analysis results as JSON (see 'go tool vet help'). The output
+// of the vet tool for each package is reported as a "build-output" event,
+// followed by a "build-diagnostic" event for each diagnostic it contains.
+// As without -json, vet findings do not make the package fail.
+//
 // # Build modes
 //
 // The 'go build' and 'go install' commands take a -buildmode argument which
@@ -2142,6 +2288,19 @@
 // Running 'go clean -fuzzcache' removes all cached fuzzing values.
 // This may make fuzzing less effective, temporarily.
 //
+// Setting the GOCACHEPROG environment variable to a command, with optional
+// space-separated flags, makes the go command start that program and
+// delegate the storage of build and test results to it, for example to
+// share them between machines. The go command exchanges JSON messages
+// with the program over its standard input and output; the protocol is
+// documented in package cmd/internal/cacheprog. The cache directory
+// named by GOCACHE is still used for fuzzing values. The reference
+// implementation, cmd/gocacheprog, stores results in a local directory
+// and can share them through an HTTP server:
+//
+//	go build -o gocacheprog cmd/gocacheprog
+//	GOCACHEPROG="$PWD/gocacheprog -url=https://cache.example.com" go build
+//
 // The GODEBUG environment variable can enable printing of debugging
 // information about the state of the cache:
 //
@@ -2186,6 +2345,9 @@
 //	GOCACHE
 //		The directory where the go command will store cached
 //		information for reuse in future builds.
+//	GOCACHEPROG
+//		A command (with optional space-separated flags) that implements an
+//		external go command build cache. See 'go help cache'.
 //	GOMODCACHE
 //		The directory where the go command will store downloaded modules.
 //	GODEBUG
@@ -2235,6 +2397,13 @@
 //	GOVCS
 //		Lists version control commands that may be used with matching servers.
 //		See 'go help vcs'.
+//	GOVULNDB
+//		The vulnerability database consulted by 'go list -m -vuln' and
+//		'go version -m -vuln': an https or file URL, or the absolute path of
+//		a local directory, holding a database in the format served by
+//		https://vuln.go.dev. There is no default; the -vuln flags fail if
+//		GOVULNDB is not set. A database served over HTTP is not consulted
+//		when GOPROXY=off or -mod=vendor disallow network access.
 //	GOWORK
 //		In module aware mode, use the given go.work file as a workspace file.
 //		By default or when GOWORK is "auto", the go command searches for a
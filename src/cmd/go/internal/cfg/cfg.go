@@ -68,6 +68,7 @@ var (
 	BuildBuildmode     string   // -buildmode flag
 	BuildBuildvcs      = "auto" // -buildvcs flag: "true", "false", or "auto"
 	BuildContext       = defaultContext()
+	BuildJSON          bool                    // -json flag (build, install and vet only)
 	BuildMod           string                  // -mod flag
 	BuildModExplicit   bool                    // whether -mod was set explicitly
 	BuildModReason     string                  // reason -mod was set, if set by default
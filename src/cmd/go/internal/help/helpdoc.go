@@ -498,6 +498,9 @@ General-purpose environment variables:
 	GOCACHE
 		The directory where the go command will store cached
 		information for reuse in future builds.
+	GOCACHEPROG
+		A command (with optional space-separated flags) that implements an
+		external go command build cache. See 'go help cache'.
 	GOMODCACHE
 		The directory where the go command will store downloaded modules.
 	GODEBUG
@@ -547,6 +550,13 @@ General-purpose environment variables:
 	GOVCS
 		Lists version control commands that may be used with matching servers.
 		See 'go help vcs'.
+	GOVULNDB
+		The vulnerability database consulted by 'go list -m -vuln' and
+		'go version -m -vuln': an https or file URL, or the absolute path of
+		a local directory, holding a database in the format served by
+		https://vuln.go.dev. There is no default; the -vuln flags fail if
+		GOVULNDB is not set. A database served over HTTP is not consulted
+		when GOPROXY=off or -mod=vendor disallow network access.
 	GOWORK
 		In module aware mode, use the given go.work file as a workspace file.
 		By default or when GOWORK is "auto", the go command searches for a
@@ -809,6 +819,19 @@ testing, but they're stored in a subdirectory of the build cache.
 Running 'go clean -fuzzcache' removes all cached fuzzing values.
 This may make fuzzing less effective, temporarily.
 
+Setting the GOCACHEPROG environment variable to a command, with optional
+space-separated flags, makes the go command start that program and
+delegate the storage of build and test results to it, for example to
+share them between machines. The go command exchanges JSON messages
+with the program over its standard input and output; the protocol is
+documented in package cmd/internal/cacheprog. The cache directory
+named by GOCACHE is still used for fuzzing values. The reference
+implementation, cmd/gocacheprog, stores results in a local directory
+and can share them through an HTTP server:
+
+	go build -o gocacheprog cmd/gocacheprog
+	GOCACHEPROG="$PWD/gocacheprog -url=https://cache.example.com" go build
+
 The GODEBUG environment variable can enable printing of debugging
 information about the state of the cache:
 
@@ -825,6 +848,66 @@ decisions about whether to reuse a cached test result.
 `,
 }
 
+var HelpBuildJSON = &base.Command{
+	UsageLine: "buildjson",
+	Short:     "build -json encoding",
+	Long: `
+The 'go build', 'go install' and 'go vet' commands accept a -json flag,
+which makes them print the progress of the build and the output of the
+build tools as a stream of JSON objects on standard output, one per line,
+instead of printing tool output as text on standard error. Each object
+is a BuildEvent:
+
+	type BuildEvent struct {
+		Time       time.Time
+		Action     string
+		ImportPath string
+		Output     string
+		File       string
+		Line       int
+		Column     int
+		Message    string
+	}
+
+The Time field holds the time the event happened. The ImportPath field
+holds the import path of the package the event is about, if any.
+
+The possible values of Action are:
+
+	build-start      - the build of the package has started
+	build-cached     - the package was found in the build cache
+	build-compiled   - the package was compiled
+	build-output     - a build tool printed Output for the package
+	build-diagnostic - a compiler or vet reported a diagnostic
+	build-fail       - building or linking the package failed
+
+A "build-start" event is followed by one of "build-cached",
+"build-compiled" or "build-fail" for the same package. Events for
+different packages are interleaved as packages are built in parallel.
+
+A "build-diagnostic" event describes one error or warning in the output
+of a compiler or of vet, giving its position as File, Line and, if known, Column,
+and its text as Message. The diagnostics of a failed package are reported
+after the "build-output" event carrying the full output of the failing
+tool, and before the "build-fail" event.
+
+The Action values of build events all begin with "build-" and differ
+from those of 'go test -json' events (see 'go doc test2json'), whose
+Time, Action and Output fields have the same meaning, so that the two
+streams can be read by the same program.
+
+Errors that prevent the go command from starting the build, such as
+a package that cannot be found, are still printed as text on standard
+error.
+
+For 'go vet', the -json flag is also passed to the vet tool, which then
+reports its analysis results as JSON (see 'go tool vet help'). The output
+of the vet tool for each package is reported as a "build-output" event,
+followed by a "build-diagnostic" event for each diagnostic it contains.
+As without -json, vet findings do not make the package fail.
+`,
+}
+
 var HelpBuildConstraint = &base.Command{
 	UsageLine: "buildconstraint",
 	Short:     "build constraints",
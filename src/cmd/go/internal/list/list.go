@@ -31,6 +31,7 @@ import (
 	"cmd/go/internal/modinfo"
 	"cmd/go/internal/modload"
 	"cmd/go/internal/str"
+	"cmd/go/internal/vulndb"
 	"cmd/go/internal/work"
 )
 
@@ -244,6 +245,7 @@ applied to a Go struct, but now a Module struct:
         GoVersion  string        // go version used in module
         Retracted  []string      // retraction information, if any (with -retracted or -u)
         Deprecated string        // deprecation message, if any (with -u)
+        Vulns      []*ModuleVuln // known vulnerabilities, if any (with -vuln)
         Error      *ModuleError  // error loading module
         Sum        string        // checksum for path, version (as in go.sum)
         GoModSum   string        // checksum for go.mod (as in go.sum)
@@ -255,6 +257,17 @@ applied to a Go struct, but now a Module struct:
         Err string // the error itself
     }
 
+    type ModuleVuln struct {
+        ID       string   // vulnerability ID, such as "GO-2021-0113"
+        Aliases  []string // other IDs, such as CVE numbers
+        Summary  string   // short description
+        Fixed    string   // lowest version with a fix, if any
+        Packages []*struct {
+            Path    string   // package import path
+            Symbols []string // affected functions and methods, if known
+        }
+    }
+
 The file GoMod refers to may be outside the module directory if the
 module is in the module cache or if the -modfile flag is used.
 
@@ -306,6 +319,17 @@ module's go.mod file. When -retracted is used with -versions, retracted
 versions are listed together with unretracted versions. The -retracted
 flag may be used with or without -m.
 
+The -vuln flag causes list to set the Module's Vulns field to the
+vulnerabilities known to affect that module version, according to the
+vulnerability database named by the GOVULNDB environment variable,
+which must be set (see 'go help environment'). Each vulnerability
+lists the packages and symbols it affects, when known, and the lowest
+version that fixes it. A version with known vulnerabilities is followed
+by the string "(vulnerable)" in the default output. Vulnerabilities are
+matched by module version only: the packages and symbols are copied from
+the database, and list does not check whether the build uses them. The
+-vuln flag may only be used with -m.
+
 The arguments to list -m are interpreted as a list of modules, not packages.
 The main module is the module containing the current directory.
 The active modules are the main module and its dependencies.
@@ -367,6 +391,7 @@ var (
 	listTest       = CmdList.Flag.Bool("test", false, "")
 	listU          = CmdList.Flag.Bool("u", false, "")
 	listVersions   = CmdList.Flag.Bool("versions", false, "")
+	listVuln       = CmdList.Flag.Bool("vuln", false, "")
 )
 
 // A StringsFlag is a command-line flag that interprets its argument
@@ -567,6 +592,12 @@ func runList(ctx context.Context, cmd *base.Command, args []string) {
 				mode |= modload.ListRetractedVersions
 			}
 		}
+		if *listVuln {
+			if _, err := vulndb.Default(); err != nil {
+				base.Fatalf("go: %v", err)
+			}
+			mode |= modload.ListVulns
+		}
 		if *listReuse != "" && len(args) == 0 {
 			base.Fatalf("go: list -m -reuse only has an effect with module@version arguments")
 		}
@@ -595,6 +626,9 @@ func runList(ctx context.Context, cmd *base.Command, args []string) {
 	if *listVersions {
 		base.Fatalf("go list -versions can only be used with -m")
 	}
+	if *listVuln {
+		base.Fatalf("go list -vuln can only be used with -m")
+	}
 
 	// These pairings make no sense.
 	if *listFind && *listDeps {
@@ -0,0 +1,241 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// go mod upgrade
+
+package modcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internal/diff"
+	"os"
+	"sort"
+
+	"cmd/go/internal/base"
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/gover"
+	"cmd/go/internal/modload"
+
+	"golang.org/x/mod/module"
+)
+
+var cmdUpgrade = &base.Command{
+	UsageLine: "go mod upgrade [-diff] [-json] [-x]",
+	Short:     "upgrade retracted dependencies and report deprecated ones",
+	Long: `
+Upgrade checks every module in the build list for retractions and
+deprecations published by the module's author, and upgrades each module
+whose selected version is retracted to the lowest newer version that is
+not retracted or excluded. The upgrades are applied together using
+minimal version selection, so that no other module is upgraded beyond what
+those new versions require. If a newly selected version is itself
+retracted, upgrade repeats the process until the build list contains no
+retracted versions that can be upgraded.
+
+A deprecation applies to every version of a module, so upgrade reports
+deprecated modules but cannot fix them by changing versions; they must be
+replaced by hand, typically with the module named in the deprecation
+message.
+
+Upgrade prints each retracted or deprecated module, and each change to
+the build list, to standard error.
+
+The -diff flag causes upgrade to print the changes it would make to
+go.mod as a unified diff, without modifying go.mod or go.sum.
+
+The -json flag causes upgrade to print a sequence of JSON objects to
+standard output instead of modifying go.mod, one for each retracted or
+deprecated module, corresponding to this Go struct:
+
+    type Module struct {
+        Path       string   // module path
+        Version    string   // selected version
+        Retracted  []string // retraction rationale, if the version is retracted
+        Deprecated string   // deprecation message, if the module is deprecated
+        Upgrade    string   // proposed version, if any
+        Error      string   // error finding an upgrade, if any
+    }
+
+The -x flag causes upgrade to print the commands download executes.
+
+See https://golang.org/ref/mod#go-mod-file-retract for more about
+retractions and deprecations.
+	`,
+	Run: runUpgrade,
+}
+
+var (
+	upgradeDiff bool // if true, print a diff of go.mod instead of writing it
+	upgradeJSON bool // if true, print a JSON report instead of writing go.mod
+)
+
+func init() {
+	cmdUpgrade.Flag.BoolVar(&upgradeDiff, "diff", false, "")
+	cmdUpgrade.Flag.BoolVar(&upgradeJSON, "json", false, "")
+	cmdUpgrade.Flag.BoolVar(&cfg.BuildX, "x", false, "")
+	base.AddChdirFlag(&cmdUpgrade.Flag)
+	base.AddModCommonFlags(&cmdUpgrade.Flag)
+}
+
+// An upgradeModule describes a retracted or deprecated module in the
+// build list, as printed by 'go mod upgrade -json'.
+type upgradeModule struct {
+	Path       string
+	Version    string
+	Retracted  []string `json:",omitempty"`
+	Deprecated string   `json:",omitempty"`
+	Upgrade    string   `json:",omitempty"`
+	Error      string   `json:",omitempty"`
+}
+
+func runUpgrade(ctx context.Context, cmd *base.Command, args []string) {
+	if len(args) > 0 {
+		base.Fatalf("go: 'go mod upgrade' accepts no arguments")
+	}
+	if upgradeDiff && upgradeJSON {
+		base.Fatalf("go: -diff and -json cannot be used together")
+	}
+	modload.ForceUseModules = true
+	modload.RootMode = modload.NeedRoot
+	modload.ExplicitWriteGoMod = true
+
+	mg, err := modload.LoadModGraph(ctx, "")
+	if err != nil {
+		base.Fatal(err)
+	}
+	initial := make(map[string]string)
+	for _, m := range mg.BuildList() {
+		initial[m.Path] = m.Version
+	}
+
+	// Check every module in the build list, then upgrade the retracted ones
+	// together. An upgrade may raise other modules to versions that are
+	// themselves retracted, so repeat until the set of selected versions
+	// settles. Each round only raises versions, so this terminates.
+	var (
+		reported = make(map[string]*upgradeModule)
+		checked  = make(map[module.Version]bool)
+		order    []*upgradeModule
+	)
+	for {
+		var mustSelect []module.Version
+		for _, m := range mg.BuildList() {
+			if m.Version == "" || gover.IsToolchain(m.Path) || modload.MainModules.Contains(m.Path) || checked[m] {
+				continue
+			}
+			checked[m] = true
+
+			um := reported[m.Path]
+			if um == nil {
+				um = &upgradeModule{Path: m.Path, Version: m.Version}
+				if deprecation, err := modload.CheckDeprecation(ctx, m); err == nil && deprecation != "" {
+					um.Deprecated = modload.ShortMessage(deprecation, "")
+				}
+			}
+			err := modload.CheckRetractions(ctx, m)
+			retractErr := (*modload.ModuleRetractedError)(nil)
+			if !errors.As(err, &retractErr) {
+				if um.Deprecated != "" && reported[m.Path] == nil {
+					reported[m.Path] = um
+					order = append(order, um)
+				}
+				continue
+			}
+			if reported[m.Path] == nil {
+				if len(retractErr.Rationale) == 0 {
+					um.Retracted = []string{"retracted by module author"}
+				} else {
+					um.Retracted = retractErr.Rationale
+				}
+				reported[m.Path] = um
+				order = append(order, um)
+			}
+
+			info, err := modload.Query(ctx, m.Path, ">"+m.Version, "", modload.CheckAllowed)
+			if err != nil {
+				um.Error = err.Error()
+				continue
+			}
+			um.Upgrade = info.Version
+			mustSelect = append(mustSelect, module.Version{Path: m.Path, Version: info.Version})
+		}
+		if len(mustSelect) == 0 {
+			break
+		}
+		if _, err := modload.EditBuildList(ctx, nil, mustSelect); err != nil {
+			base.Fatal(err)
+		}
+		if mg, err = modload.LoadModGraph(ctx, ""); err != nil {
+			base.Fatal(err)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i].Path < order[j].Path })
+
+	if upgradeJSON {
+		for _, um := range order {
+			b, err := json.MarshalIndent(um, "", "\t")
+			if err != nil {
+				base.Fatal(err)
+			}
+			os.Stdout.Write(append(b, '\n'))
+		}
+		return
+	}
+
+	for _, um := range order {
+		if um.Deprecated != "" {
+			fmt.Fprintf(os.Stderr, "go: module %s is deprecated: %s\n", um.Path, um.Deprecated)
+		}
+		if len(um.Retracted) > 0 {
+			fmt.Fprintf(os.Stderr, "go: %s@%s is retracted: %s\n", um.Path, um.Version, modload.ShortMessage(um.Retracted[0], "retracted by module author"))
+		}
+		if um.Error != "" {
+			fmt.Fprintf(os.Stderr, "go: no upgrade for %s@%s: %s\n", um.Path, um.Version, um.Error)
+		}
+	}
+
+	if upgradeDiff {
+		before, after, _, err := modload.UpdateGoModFromReqs(ctx, modload.WriteOpts{})
+		if err != nil {
+			base.Fatal(err)
+		}
+		os.Stdout.Write(diff.Diff("current/go.mod", before, "upgraded/go.mod", after))
+		return
+	}
+
+	if err := modload.WriteGoMod(ctx, modload.WriteOpts{}); err != nil {
+		base.Fatal(err)
+	}
+	if mg, err = modload.LoadModGraph(ctx, ""); err != nil {
+		base.Fatal(err)
+	}
+	for _, m := range mg.BuildList() {
+		if gover.IsToolchain(m.Path) || modload.MainModules.Contains(m.Path) {
+			continue
+		}
+		switch old, ok := initial[m.Path]; {
+		case !ok:
+			fmt.Fprintf(os.Stderr, "go: added %s %s\n", m.Path, m.Version)
+		case old != m.Version:
+			fmt.Fprintf(os.Stderr, "go: upgraded %s %s => %s\n", m.Path, old, m.Version)
+		}
+		delete(initial, m.Path)
+	}
+	for _, m := range modload.MainModules.Versions() {
+		delete(initial, m.Path)
+	}
+	removed := make([]string, 0, len(initial))
+	for path := range initial {
+		if !gover.IsToolchain(path) {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		fmt.Fprintf(os.Stderr, "go: removed %s %s\n", path, initial[path])
+	}
+}
@@ -28,6 +28,7 @@ type ModulePublic struct {
 	GoVersion  string           `json:",omitempty"` // go version used in module
 	Retracted  []string         `json:",omitempty"` // retraction information, if any (with -retracted or -u)
 	Deprecated string           `json:",omitempty"` // deprecation message, if any (with -u)
+	Vulns      []*ModuleVuln    `json:",omitempty"` // known vulnerabilities, if any (with -vuln)
 	Error      *ModuleError     `json:",omitempty"` // error loading module
 	Sum        string           `json:",omitempty"` // checksum for path, version (as in go.sum)
 	GoModSum   string           `json:",omitempty"` // checksum for go.mod (as in go.sum)
@@ -35,6 +36,19 @@ type ModulePublic struct {
 	Reuse      bool             `json:",omitempty"` // reuse of old module info is safe
 }
 
+type ModuleVuln struct {
+	ID       string               // vulnerability ID, such as "GO-2021-0113"
+	Aliases  []string             `json:",omitempty"` // other IDs, such as CVE numbers
+	Summary  string               `json:",omitempty"` // short description
+	Fixed    string               `json:",omitempty"` // lowest version with a fix, if any
+	Packages []*ModuleVulnPackage `json:",omitempty"` // affected packages, if known
+}
+
+type ModuleVulnPackage struct {
+	Path    string   // package import path
+	Symbols []string `json:",omitempty"` // affected functions and methods, if known
+}
+
 type ModuleError struct {
 	Err string // error text
 }
@@ -55,10 +69,13 @@ func (m *ModulePublic) String() string {
 	s := m.Path
 	versionString := func(mm *ModulePublic) string {
 		v := mm.Version
-		if len(mm.Retracted) == 0 {
-			return v
+		if len(mm.Retracted) != 0 {
+			v += " (retracted)"
+		}
+		if len(mm.Vulns) != 0 {
+			v += " (vulnerable)"
 		}
-		return v + " (retracted)"
+		return v
 	}
 
 	if m.Version != "" {
@@ -22,6 +22,7 @@ import (
 	"cmd/go/internal/modindex"
 	"cmd/go/internal/modinfo"
 	"cmd/go/internal/search"
+	"cmd/go/internal/vulndb"
 
 	"golang.org/x/mod/module"
 )
@@ -286,6 +287,35 @@ func addDeprecation(ctx context.Context, m *modinfo.ModulePublic) {
 	m.Deprecated = deprecation
 }
 
+// addVulns fills in m.Vulns with the vulnerabilities known to affect m,
+// according to the database named by GOVULNDB.
+// m.Error is set if there's an error reading the database.
+func addVulns(m *modinfo.ModulePublic) {
+	db, err := vulndb.Default()
+	var vulns []*vulndb.Vuln
+	if err == nil {
+		vulns, err = db.ByModule(module.Version{Path: m.Path, Version: m.Version})
+	}
+	if err != nil {
+		if m.Error == nil {
+			m.Error = &modinfo.ModuleError{Err: err.Error()}
+		}
+		return
+	}
+	for _, v := range vulns {
+		mv := &modinfo.ModuleVuln{
+			ID:      v.ID,
+			Aliases: v.Aliases,
+			Summary: v.Summary,
+			Fixed:   v.Fixed,
+		}
+		for _, p := range v.Packages {
+			mv.Packages = append(mv.Packages, &modinfo.ModuleVulnPackage{Path: p.Path, Symbols: p.Symbols})
+		}
+		m.Vulns = append(m.Vulns, mv)
+	}
+}
+
 // moduleInfo returns information about module m, loaded from the requirements
 // in rs (which may be nil to indicate that m was not loaded from a requirement
 // graph).
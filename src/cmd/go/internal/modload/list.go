@@ -34,6 +34,7 @@ const (
 	ListDeprecated
 	ListVersions
 	ListRetractedVersions
+	ListVulns
 )
 
 // ListModules returns a description of the modules matching args, if known,
@@ -92,6 +93,9 @@ func ListModules(ctx context.Context, args []string, mode ListMode, reuseFile st
 					if mode&ListDeprecated != 0 {
 						addDeprecation(ctx, m)
 					}
+					if mode&ListVulns != 0 {
+						addVulns(m)
+					}
 					<-sem
 				}()
 			}
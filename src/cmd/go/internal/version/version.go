@@ -18,10 +18,13 @@ import (
 
 	"cmd/go/internal/base"
 	"cmd/go/internal/gover"
+	"cmd/go/internal/vulndb"
+
+	"golang.org/x/mod/module"
 )
 
 var CmdVersion = &base.Command{
-	UsageLine: "go version [-m] [-v] [file ...]",
+	UsageLine: "go version [-m] [-v] [-vuln] [file ...]",
 	Short:     "print Go version",
 	Long: `Version prints the build information for Go binary files.
 
@@ -40,6 +43,16 @@ module version information, when available. In the output, the module
 information consists of multiple lines following the version line, each
 indented by a leading tab character.
 
+The -vuln flag, which requires -m, causes go version to consult the
+vulnerability database named by the GOVULNDB environment variable,
+which must be set (see 'go help environment'), and print a line for each known vulnerability
+affecting a module in the binary, of the form
+
+	vuln	GO-2021-0113	golang.org/x/text@v0.3.5	fixed in v0.3.7
+
+As with 'go list -m -vuln', vulnerabilities are matched by module version
+only, whether or not the binary contains the affected packages and symbols.
+
 See also: go doc runtime/debug.BuildInfo.
 `,
 }
@@ -50,8 +63,9 @@ func init() {
 }
 
 var (
-	versionM = CmdVersion.Flag.Bool("m", false, "")
-	versionV = CmdVersion.Flag.Bool("v", false, "")
+	versionM    = CmdVersion.Flag.Bool("m", false, "")
+	versionV    = CmdVersion.Flag.Bool("v", false, "")
+	versionVuln = CmdVersion.Flag.Bool("vuln", false, "")
 )
 
 func runVersion(ctx context.Context, cmd *base.Command, args []string) {
@@ -68,6 +82,8 @@ func runVersion(ctx context.Context, cmd *base.Command, args []string) {
 			argOnlyFlag = "-m"
 		} else if !base.InGOFLAGS("-v") && *versionV {
 			argOnlyFlag = "-v"
+		} else if !base.InGOFLAGS("-vuln") && *versionVuln {
+			argOnlyFlag = "-vuln"
 		}
 		if argOnlyFlag != "" {
 			fmt.Fprintf(os.Stderr, "go: 'go version' only accepts %s flag with arguments\n", argOnlyFlag)
@@ -82,6 +98,12 @@ func runVersion(ctx context.Context, cmd *base.Command, args []string) {
 		return
 	}
 
+	if *versionVuln && !*versionM {
+		fmt.Fprintf(os.Stderr, "go: 'go version' only accepts -vuln flag with -m\n")
+		base.SetExitStatus(2)
+		return
+	}
+
 	for _, arg := range args {
 		info, err := os.Stat(arg)
 		if err != nil {
@@ -170,4 +192,38 @@ func scanFile(file string, info fs.FileInfo, mustPrint bool) {
 	if *versionM && len(mod) > 0 {
 		fmt.Printf("\t%s\n", strings.ReplaceAll(mod[:len(mod)-1], "\n", "\n\t"))
 	}
+	if *versionVuln {
+		printVulns(file, bi)
+	}
+}
+
+// printVulns prints the known vulnerabilities affecting the modules
+// recorded in bi, using the code actually linked into the binary:
+// a replaced module is checked at its replacement's version.
+func printVulns(file string, bi *buildinfo.BuildInfo) {
+	db, err := vulndb.Default()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+		base.SetExitStatus(1)
+		return
+	}
+	for _, m := range bi.Deps {
+		if m.Replace != nil {
+			m = m.Replace
+		}
+		mv := module.Version{Path: m.Path, Version: m.Version}
+		vulns, err := db.ByModule(mv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			base.SetExitStatus(1)
+			return
+		}
+		for _, v := range vulns {
+			fixed := "no fix available"
+			if v.Fixed != "" {
+				fixed = "fixed in " + v.Fixed
+			}
+			fmt.Printf("\tvuln\t%s\t%s\t%s\n", v.ID, mv, fixed)
+		}
+	}
 }
@@ -46,6 +46,10 @@ The build flags supported by go vet are those that control package resolution
 and execution, such as -C, -n, -x, -v, -tags, and -toolexec.
 For more about these flags, see 'go help build'.
 
+The -json flag, which is passed to the vet tool, also makes go vet print
+the progress of the build and the output of the build and vet tools as a
+stream of JSON objects on standard output; see 'go help buildjson'.
+
 See also: go fmt, go fix.
 	`,
 }
@@ -71,6 +75,12 @@ func runVet(ctx context.Context, cmd *base.Command, args []string) {
 	ctx, span := trace.StartSpan(ctx, fmt.Sprint("Running ", cmd.Name(), " command"))
 	defer span.Done()
 
+	// The vet tool's -json flag also makes go vet report the build
+	// as JSON events, so that all of its output is JSON.
+	if f := CmdVet.Flag.Lookup("json"); f != nil && f.Value.String() == "true" {
+		cfg.BuildJSON = true
+	}
+
 	work.BuildInit()
 	work.VetFlags = vetFlags
 	if len(vetFlags) > 0 {
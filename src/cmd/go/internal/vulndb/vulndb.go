@@ -0,0 +1,303 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vulndb reads a Go vulnerability database, as served by
+// https://vuln.go.dev, and matches its entries against module versions.
+//
+// The database is a tree of JSON files in the Open Source Vulnerability
+// (OSV) format, described at https://go.dev/security/vuln/database:
+// index/modules.json lists the vulnerabilities affecting each module, and
+// ID/<id>.json holds the OSV entry for each vulnerability. The tree may be
+// served over HTTPS or read from a local directory, which makes it possible
+// to use a mirrored copy of the database without network access.
+//
+// Entries are matched against module versions only. The affected packages
+// and symbols are reported as recorded in the database; deciding whether
+// a build actually uses them is left to tools such as govulncheck.
+package vulndb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"cmd/go/internal/cfg"
+	"cmd/go/internal/par"
+	"cmd/go/internal/web"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// An Entry is a vulnerability report in OSV format.
+// Only the fields used by the go command are decoded.
+type Entry struct {
+	ID       string     `json:"id"`
+	Summary  string     `json:"summary,omitempty"`
+	Aliases  []string   `json:"aliases,omitempty"`
+	Affected []Affected `json:"affected"`
+}
+
+// Affected describes the versions of a module affected by an Entry.
+type Affected struct {
+	Module struct {
+		Path string `json:"name"`
+	} `json:"package"`
+	Ranges            []Range `json:"ranges,omitempty"`
+	EcosystemSpecific struct {
+		Packages []Package `json:"imports,omitempty"`
+	} `json:"ecosystem_specific"`
+}
+
+// A Range is a sequence of events that introduce and fix
+// a vulnerability, ordered by semantic version.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// An Event is a single version at which a vulnerability is introduced,
+// fixed, or last known to be present. Versions are semantic versions
+// without the leading "v", and the version "0" in Introduced denotes the
+// earliest possible version. LastAffected is an inclusive upper bound,
+// used instead of Fixed when no fixed version is known.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// A Package is a package affected by a vulnerability, along with the
+// vulnerable symbols in that package, if known.
+type Package struct {
+	Path    string   `json:"path"`
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// A Vuln reports that a module version is affected by an Entry.
+type Vuln struct {
+	*Entry
+	Fixed    string    // lowest version with a fix, if any
+	Packages []Package // affected packages and symbols, if known
+}
+
+// indexModule is an entry in index/modules.json.
+type indexModule struct {
+	Path  string `json:"path"`
+	Vulns []struct {
+		ID string `json:"id"`
+	} `json:"vulns"`
+}
+
+// A Client reads entries from a vulnerability database.
+// Entries are read lazily and cached, and a Client is safe for
+// concurrent use.
+type Client struct {
+	source string // database URL or directory, for error messages
+	dir    string // local directory, if any
+	base   *url.URL
+
+	indexOnce sync.Once
+	index     map[string][]string // module path → vulnerability IDs
+	indexErr  error
+
+	entries par.ErrCache[string, *Entry]
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultClient *Client
+	defaultErr    error
+)
+
+// Default returns the Client for the database named by GOVULNDB.
+// There is no default database, so that the go command never contacts
+// one unless asked to. A database served over HTTP is also refused when
+// GOPROXY=off or -mod=vendor rule out network access.
+func Default() (*Client, error) {
+	defaultOnce.Do(func() {
+		db := cfg.Getenv("GOVULNDB")
+		if db == "" {
+			defaultErr = errors.New("GOVULNDB is not set; set it to the URL or local directory of a vulnerability database, such as https://vuln.go.dev")
+			return
+		}
+		c, err := Open(db)
+		if err == nil && c.base != nil && c.base.Scheme != "file" {
+			if cfg.GOPROXY == "off" {
+				err = fmt.Errorf("vulnerability database %s disabled by GOPROXY=off", c.source)
+			} else if cfg.BuildModExplicit && cfg.BuildMod == "vendor" {
+				err = fmt.Errorf("vulnerability database %s disabled by -mod=vendor", c.source)
+			}
+		}
+		if err != nil {
+			defaultErr = err
+			return
+		}
+		defaultClient = c
+	})
+	return defaultClient, defaultErr
+}
+
+// Open returns a Client for the database at db, which is either an
+// http, https, or file URL, or the absolute path of a local directory.
+func Open(db string) (*Client, error) {
+	if filepath.IsAbs(db) {
+		return &Client{source: db, dir: db}, nil
+	}
+	u, err := url.Parse(db)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GOVULNDB: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "file":
+	default:
+		return nil, fmt.Errorf("invalid GOVULNDB %q: must be an http, https, or file URL or an absolute path", db)
+	}
+	return &Client{source: u.Redacted(), base: u}, nil
+}
+
+// read returns the contents of the named file in the database.
+func (c *Client) read(name string) ([]byte, error) {
+	if c.dir != "" {
+		return os.ReadFile(filepath.Join(c.dir, filepath.FromSlash(name)))
+	}
+	return web.GetBytes(web.Join(c.base, name))
+}
+
+func (c *Client) loadIndex() (map[string][]string, error) {
+	c.indexOnce.Do(func() {
+		data, err := c.read("index/modules.json")
+		if err != nil {
+			c.indexErr = fmt.Errorf("reading vulnerability database %s: %w", c.source, err)
+			return
+		}
+		var mods []indexModule
+		if err := json.Unmarshal(data, &mods); err != nil {
+			c.indexErr = fmt.Errorf("reading vulnerability database %s: index/modules.json: %v", c.source, err)
+			return
+		}
+		c.index = make(map[string][]string, len(mods))
+		for _, m := range mods {
+			for _, v := range m.Vulns {
+				c.index[m.Path] = append(c.index[m.Path], v.ID)
+			}
+		}
+	})
+	return c.index, c.indexErr
+}
+
+// Entry returns the entry with the given ID.
+func (c *Client) Entry(id string) (*Entry, error) {
+	return c.entries.Do(id, func() (*Entry, error) {
+		if id == "" || strings.ContainsAny(id, `/\.`) {
+			return nil, fmt.Errorf("invalid vulnerability ID %q", id)
+		}
+		data, err := c.read("ID/" + id + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("reading vulnerability database %s: %w", c.source, err)
+		}
+		e := new(Entry)
+		if err := json.Unmarshal(data, e); err != nil {
+			return nil, fmt.Errorf("reading vulnerability database %s: ID/%s.json: %v", c.source, id, err)
+		}
+		return e, nil
+	})
+}
+
+// ByModule returns the vulnerabilities affecting the given module version,
+// ordered by ID. It returns no vulnerabilities for modules without a
+// version, such as main modules and local replacements.
+func (c *Client) ByModule(m module.Version) ([]*Vuln, error) {
+	if m.Version == "" {
+		return nil, nil
+	}
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	var vulns []*Vuln
+	for _, id := range index[m.Path] {
+		e, err := c.Entry(id)
+		if errors.Is(err, fs.ErrNotExist) {
+			// The index may be ahead of the entries in a partial mirror.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range e.Affected {
+			if a.Module.Path != m.Path {
+				continue
+			}
+			if fixed, ok := affects(a.Ranges, m.Version); ok {
+				vulns = append(vulns, &Vuln{
+					Entry:    e,
+					Fixed:    fixed,
+					Packages: a.EcosystemSpecific.Packages,
+				})
+				break
+			}
+		}
+	}
+	slices.SortFunc(vulns, func(x, y *Vuln) int { return strings.Compare(x.ID, y.ID) })
+	return vulns, nil
+}
+
+// affects reports whether version v is within any of the SEMVER ranges.
+// If so, it also returns the lowest version above v that fixes the
+// vulnerability, or "" if no fix is known.
+// A list with no ranges affects every version.
+func affects(ranges []Range, v string) (fixed string, ok bool) {
+	if len(ranges) == 0 {
+		return "", true
+	}
+	for _, r := range ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+		events := slices.Clone(r.Events)
+		slices.SortStableFunc(events, func(x, y Event) int {
+			return semver.Compare(eventVersion(x), eventVersion(y))
+		})
+		within := false
+		for _, e := range events {
+			ev := eventVersion(e)
+			// Stop at the first event after v. A last_affected
+			// version is itself still affected.
+			if c := semver.Compare(v, ev); c < 0 || c == 0 && e.LastAffected != "" {
+				if within && e.Fixed != "" {
+					return ev, true
+				}
+				break
+			}
+			within = e.Introduced != ""
+		}
+		if within {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// eventVersion returns the semantic version of e, with a leading "v".
+// The introduced version "0" sorts before every other version.
+func eventVersion(e Event) string {
+	v := e.Introduced
+	if e.Fixed != "" {
+		v = e.Fixed
+	} else if e.LastAffected != "" {
+		v = e.LastAffected
+	}
+	if v == "0" {
+		return "v0.0.0-0"
+	}
+	return "v" + v
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulndb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/module"
+)
+
+var affectsTests = []struct {
+	events  []Event
+	version string
+	fixed   string
+	ok      bool
+}{
+	{[]Event{{Introduced: "0"}}, "v0.0.1", "", true},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "v1.1.9", "v1.2.0", true},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "v1.2.0", "", false},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "v0.0.0-20200101000000-0123456789ab", "v1.2.0", true},
+	{[]Event{{Introduced: "1.1.0"}, {Fixed: "1.2.0"}}, "v1.0.0", "", false},
+	{[]Event{{Introduced: "1.1.0"}, {Fixed: "1.2.0"}}, "v1.1.0", "v1.2.0", true},
+	{[]Event{{Introduced: "1.1.0"}, {Fixed: "1.2.0"}}, "v1.2.0-pre", "v1.2.0", true},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}, {Introduced: "1.3.0"}, {Fixed: "1.3.2"}}, "v1.2.5", "", false},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}, {Introduced: "1.3.0"}, {Fixed: "1.3.2"}}, "v1.3.1", "v1.3.2", true},
+	{[]Event{{Introduced: "0"}, {Fixed: "1.2.0"}, {Introduced: "1.3.0"}}, "v1.9.0", "", true},
+	// Events need not be in order.
+	{[]Event{{Fixed: "1.3.2"}, {Introduced: "1.3.0"}}, "v1.3.1", "v1.3.2", true},
+	{[]Event{{Introduced: "2.0.0"}}, "v2.1.0+incompatible", "", true},
+	// LastAffected is an inclusive upper bound with no known fix.
+	{[]Event{{Introduced: "0"}, {LastAffected: "1.2.0"}}, "v1.1.0", "", true},
+	{[]Event{{Introduced: "0"}, {LastAffected: "1.2.0"}}, "v1.2.0", "", true},
+	{[]Event{{Introduced: "0"}, {LastAffected: "1.2.0"}}, "v1.2.1", "", false},
+	{[]Event{{Introduced: "1.1.0"}, {LastAffected: "1.2.0"}}, "v1.0.0", "", false},
+	{[]Event{{Introduced: "0"}, {LastAffected: "1.0.0"}, {Introduced: "1.3.0"}, {Fixed: "1.3.2"}}, "v1.1.0", "", false},
+	{[]Event{{Introduced: "0"}, {LastAffected: "1.0.0"}, {Introduced: "1.3.0"}, {Fixed: "1.3.2"}}, "v1.3.0", "v1.3.2", true},
+}
+
+func TestAffects(t *testing.T) {
+	for _, tt := range affectsTests {
+		fixed, ok := affects([]Range{{Type: "SEMVER", Events: tt.events}}, tt.version)
+		if fixed != tt.fixed || ok != tt.ok {
+			t.Errorf("affects(%v, %s) = %q, %v, want %q, %v", tt.events, tt.version, fixed, ok, tt.fixed, tt.ok)
+		}
+	}
+}
+
+func TestByModule(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("index/modules.json", `[{"path":"example.com/m","vulns":[{"id":"GO-0000-0002"},{"id":"GO-0000-0001"},{"id":"GO-0000-0003"}]}]`)
+	write("ID/GO-0000-0001.json", `{"id":"GO-0000-0001","affected":[{"package":{"name":"example.com/m"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.0.1"}]}]}]}`)
+	write("ID/GO-0000-0002.json", `{"id":"GO-0000-0002","affected":[{"package":{"name":"example.com/m"},"ecosystem_specific":{"imports":[{"path":"example.com/m/p","symbols":["F","T.M"]}]}}]}`)
+	// GO-0000-0003 is listed in the index but missing from the database.
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vulns, err := c.ByModule(module.Version{Path: "example.com/m", Version: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vulns) != 2 {
+		t.Fatalf("ByModule returned %d vulnerabilities, want 2", len(vulns))
+	}
+	if v := vulns[0]; v.ID != "GO-0000-0001" || v.Fixed != "v1.0.1" || len(v.Packages) != 0 {
+		t.Errorf("vulns[0] = %s fixed %q with %d packages, want GO-0000-0001 fixed \"v1.0.1\" with 0 packages", v.ID, v.Fixed, len(v.Packages))
+	}
+	if v := vulns[1]; v.ID != "GO-0000-0002" || v.Fixed != "" || len(v.Packages) != 1 || len(v.Packages[0].Symbols) != 2 {
+		t.Errorf("vulns[1] = %s fixed %q with packages %v, want GO-0000-0002 fixed \"\" with symbols F, T.M", v.ID, v.Fixed, v.Packages)
+	}
+
+	vulns, err = c.ByModule(module.Version{Path: "example.com/other", Version: "v1.0.0"})
+	if err != nil || len(vulns) != 0 {
+		t.Errorf("ByModule(example.com/other) = %v, %v, want no vulnerabilities", vulns, err)
+	}
+
+	if _, err := Open("relative/dir"); err == nil {
+		t.Errorf("Open(relative/dir) succeeded, want error")
+	}
+}
@@ -111,6 +111,7 @@ type Action struct {
 	pending      int               // number of deps yet to complete
 	priority     int               // relative execution priority
 	Failed       bool              // whether the action failed
+	cachedBuild  bool              // Mode=="build": whether the package's object was found in the build cache
 	json         *actionJSON       // action graph information
 	nonGoOverlay map[string]string // map from non-.go source files to copied files in objdir. Nil if no overlay is used.
 	traceSpan    *trace.Span
@@ -297,6 +298,9 @@ func NewBuilder(workDir string) *Builder {
 	}
 
 	b.backgroundSh = NewShell(b.WorkDir, nil)
+	if cfg.BuildJSON {
+		b.backgroundSh.jsonEnc = json.NewEncoder(os.Stdout)
+	}
 
 	if err := CheckGOOSARCHPair(cfg.Goos, cfg.Goarch); err != nil {
 		fmt.Fprintf(os.Stderr, "go: %v\n", err)
@@ -0,0 +1,204 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package work
+
+import (
+	"cmd/go/internal/load"
+	"encoding/json"
+	"errors"
+	"internal/lazyregexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A BuildEvent is an event printed by 'go build -json', 'go install -json'
+// and 'go vet -json'.
+// See 'go help buildjson' for a description of the format.
+type BuildEvent struct {
+	Time       time.Time
+	Action     string
+	ImportPath string `json:",omitempty"`
+	Output     string `json:",omitempty"`
+
+	// For "build-diagnostic" events.
+	File    string `json:",omitempty"`
+	Line    int    `json:",omitempty"`
+	Column  int    `json:",omitempty"`
+	Message string `json:",omitempty"`
+}
+
+// emitEvent prints ev as a line of JSON, if the build is printing JSON.
+func (sh *Shell) emitEvent(ev *BuildEvent) {
+	if sh.jsonEnc == nil {
+		return
+	}
+	sh.printLock.Lock()
+	defer sh.printLock.Unlock()
+	sh.emitEventLocked(ev)
+}
+
+func (sh *Shell) emitEventLocked(ev *BuildEvent) {
+	ev.Time = time.Now()
+	if ev.ImportPath == "" && sh.action != nil && sh.action.Package != nil {
+		ev.ImportPath = sh.action.Package.ImportPath
+	}
+	sh.jsonEnc.Encode(ev)
+}
+
+// reportsBuildEvents reports whether a's progress is reported
+// by "build-start", "build-cached" and "build-compiled" events.
+func reportsBuildEvents(a *Action) bool {
+	return a.Mode == "build" && a.Package != nil && a.Actor != nil
+}
+
+// emitActionStart emits the event for the start of a.
+func (b *Builder) emitActionStart(a *Action) {
+	if reportsBuildEvents(a) && !a.Failed {
+		b.Shell(a).emitEvent(&BuildEvent{Action: "build-start"})
+	}
+}
+
+// emitActionDone emits the events for the completion of a,
+// whose action function returned err.
+// It reports whether err has been reported.
+func (b *Builder) emitActionDone(a *Action, err error) bool {
+	sh := b.Shell(a)
+	if sh.jsonEnc == nil {
+		return false
+	}
+	if err == nil {
+		switch {
+		case !reportsBuildEvents(a) || a.Failed:
+		case a.cachedBuild:
+			sh.emitEvent(&BuildEvent{Action: "build-cached"})
+		default:
+			sh.emitEvent(&BuildEvent{Action: "build-compiled"})
+		}
+		return false
+	}
+	if a.Package == nil {
+		return false
+	}
+
+	out := err.Error()
+	var ipe load.ImportPathError
+	if !errors.As(err, &ipe) || ipe.ImportPath() != a.Package.ImportPath {
+		out = a.Package.ImportPath + ": " + out
+	}
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+
+	sh.printLock.Lock()
+	defer sh.printLock.Unlock()
+	sh.emitEventLocked(&BuildEvent{Action: "build-output", Output: out})
+	var cerr *cmdError
+	if errors.As(err, &cerr) {
+		text := cerr.text
+		if a.Mode == "vet" {
+			// The vet tool prefixes the errors that stop it,
+			// such as type errors, with "vet: ".
+			text = vetErrorPrefix.ReplaceAllString(text, "")
+		}
+		for _, d := range parseDiagnostics(text) {
+			sh.emitEventLocked(d)
+		}
+	}
+	sh.emitEventLocked(&BuildEvent{Action: "build-fail"})
+	return true
+}
+
+// diagLine matches the first line of a diagnostic printed by a Go
+// or C compiler: file:line: message or file:line:column: message.
+var diagLine = lazyregexp.New(`^(\S(?:.*?\S)?):([0-9]+)(?::([0-9]+))?: (.*)$`)
+
+// parseDiagnostics returns the "build-diagnostic" events
+// for the diagnostics in the output of a build tool.
+// Indented lines following a diagnostic continue its message.
+func parseDiagnostics(out string) []*BuildEvent {
+	var diags []*BuildEvent
+	for _, line := range strings.Split(out, "\n") {
+		if m := diagLine.FindStringSubmatch(line); m != nil {
+			d := &BuildEvent{Action: "build-diagnostic", File: m[1], Message: m[4]}
+			d.Line, _ = strconv.Atoi(m[2])
+			d.Column, _ = strconv.Atoi(m[3])
+			diags = append(diags, d)
+			continue
+		}
+		if len(diags) > 0 && (strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " ")) {
+			d := diags[len(diags)-1]
+			d.Message += "\n" + strings.TrimSpace(line)
+		}
+	}
+	return diags
+}
+
+// emitVetOutput emits the events for out, the output of a vet tool
+// run with -json: a "build-output" event carrying out, followed by a
+// "build-diagnostic" event for each diagnostic it reports.
+func (sh *Shell) emitVetOutput(out []byte) {
+	if len(out) == 0 {
+		return
+	}
+	sh.printLock.Lock()
+	defer sh.printLock.Unlock()
+	sh.emitEventLocked(&BuildEvent{Action: "build-output", Output: string(out)})
+	for _, d := range parseVetDiagnostics(out) {
+		sh.emitEventLocked(d)
+	}
+}
+
+// vetErrorPrefix matches the prefix of an error line printed by vet.
+var vetErrorPrefix = lazyregexp.New(`(?m)^vet: `)
+
+// vetPosn matches the position of a diagnostic in vet's JSON output:
+// file:line:column or file:line.
+var vetPosn = lazyregexp.New(`^(.+?):([0-9]+)(?::([0-9]+))?$`)
+
+// parseVetDiagnostics returns the "build-diagnostic" events for the
+// diagnostics in out, the JSON output of a vet tool, which maps each
+// package path to a map from analyzer name to either a list of
+// diagnostics or an error. Diagnostics are ordered by analyzer name.
+// If out is not in that form, parseVetDiagnostics returns nil.
+func parseVetDiagnostics(out []byte) []*BuildEvent {
+	var tree map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(out, &tree); err != nil {
+		return nil
+	}
+	var diags []*BuildEvent
+	for _, pkg := range sortedKeys(tree) {
+		analyzers := tree[pkg]
+		for _, name := range sortedKeys(analyzers) {
+			var list []struct {
+				Posn    string `json:"posn"`
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(analyzers[name], &list) != nil {
+				continue // an {"error": ...} object
+			}
+			for _, v := range list {
+				d := &BuildEvent{Action: "build-diagnostic", File: v.Posn, Message: v.Message}
+				if m := vetPosn.FindStringSubmatch(v.Posn); m != nil {
+					d.File = m[1]
+					d.Line, _ = strconv.Atoi(m[2])
+					d.Column, _ = strconv.Atoi(m[3])
+				}
+				diags = append(diags, d)
+			}
+		}
+	}
+	return diags
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
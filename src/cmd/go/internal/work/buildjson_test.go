@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package work
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	out := `# example.com/p
+./p.go:4:9: undefined: missing
+./p.go:7:2: cannot use x (variable of type int) as string value in return statement
+	have (int)
+	want (string)
+p_cgo.c:12: warning: unused variable 'y'
+C:\src\p\q.go:1:1: expected 'package', found 'EOF'
+too many errors
+`
+	want := []*BuildEvent{
+		{Action: "build-diagnostic", File: "./p.go", Line: 4, Column: 9, Message: "undefined: missing"},
+		{Action: "build-diagnostic", File: "./p.go", Line: 7, Column: 2, Message: "cannot use x (variable of type int) as string value in return statement\nhave (int)\nwant (string)"},
+		{Action: "build-diagnostic", File: "p_cgo.c", Line: 12, Message: "warning: unused variable 'y'"},
+		{Action: "build-diagnostic", File: `C:\src\p\q.go`, Line: 1, Column: 1, Message: "expected 'package', found 'EOF'"},
+	}
+	got := parseDiagnostics(out)
+	if !reflect.DeepEqual(got, want) {
+		for _, d := range got {
+			t.Logf("got %+v", *d)
+		}
+		t.Errorf("parseDiagnostics returned %d diagnostics, want %d", len(got), len(want))
+	}
+}
@@ -149,12 +149,14 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 			for _, d := range a.Deps {
 				trace.Flow(ctx, d.traceSpan, a.traceSpan)
 			}
+			b.emitActionStart(a)
 			err = a.Actor.Act(b, ctx, a)
 			span.Done()
 		}
 		if a.json != nil {
 			a.json.TimeDone = time.Now()
 		}
+		reported := b.emitActionDone(a, err)
 
 		// The actions run in parallel but all the updates to the
 		// shared work state are serialized through b.exec.
@@ -167,6 +169,8 @@ func (b *Builder) Do(ctx context.Context, root *Action) {
 					a.Package.Error = &load.PackageError{Err: err}
 					a.Package.Incomplete = true
 				}
+			} else if reported {
+				base.SetExitStatus(1)
 			} else {
 				var ipe load.ImportPathError
 				if a.Package != nil && (!errors.As(err, &ipe) || ipe.ImportPath() != a.Package.ImportPath) {
@@ -478,6 +482,7 @@ func (b *Builder) build(ctx context.Context, a *Action) (err error) {
 			// Remember that we might have them in cache
 			// and check again after we create a.Objdir.
 			cachedBuild = true
+			a.cachedBuild = true
 			a.output = []byte{} // start saving output in case we miss any cache results
 			need &^= needBuild
 			if b.NeedExport {
@@ -1332,7 +1337,20 @@ func (b *Builder) vet(ctx context.Context, a *Action) error {
 	if tool == "" {
 		tool = base.Tool("vet")
 	}
-	runErr := sh.run(p.Dir, p.ImportPath, env, cfg.BuildToolexec, tool, vetFlags, a.Objdir+"vet.cfg")
+	var runErr error
+	if sh.jsonEnc != nil {
+		// 'go vet -json' passes -json to the vet tool, which then
+		// reports its findings as JSON and exits successfully.
+		var out []byte
+		out, runErr = sh.runOut(p.Dir, env, cfg.BuildToolexec, tool, vetFlags, a.Objdir+"vet.cfg")
+		if runErr == nil {
+			sh.emitVetOutput(out)
+		} else {
+			runErr = sh.reportCmd(p.ImportPath, p.Dir, out, runErr)
+		}
+	} else {
+		runErr = sh.run(p.Dir, p.ImportPath, env, cfg.BuildToolexec, tool, vetFlags, a.Objdir+"vet.cfg")
+	}
 
 	// If vet wrote export data, save it for input to future vets.
 	if f, err := os.Open(vcfg.VetxOutput); err == nil {
@@ -90,7 +90,7 @@ func (gcToolchain) gc(b *Builder, a *Action, archive string, importcfg, embedcfg
 			fallthrough
 		case "runtime/metrics", "runtime/pprof", "runtime/trace":
 			fallthrough
-		case "sync", "syscall", "time":
+		case "sync", "sync/ctxsync", "syscall", "time":
 			extFiles++
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"cmd/go/internal/load"
 	"cmd/go/internal/par"
 	"cmd/go/internal/str"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"internal/lazyregexp"
@@ -43,7 +44,8 @@ type shellShared struct {
 
 	printLock sync.Mutex
 	printFunc func(args ...any) (int, error)
-	scriptDir string // current directory in printed script
+	scriptDir string        // current directory in printed script
+	jsonEnc   *json.Encoder // if non-nil, Print emits "build-output" events
 
 	mkdirCache par.Cache[string, error] // a cache of created directories
 }
@@ -70,6 +72,12 @@ func NewShell(workDir string, print func(a ...any) (int, error)) *Shell {
 func (sh *Shell) Print(a ...any) {
 	sh.printLock.Lock()
 	defer sh.printLock.Unlock()
+	if sh.jsonEnc != nil {
+		if out := fmt.Sprint(a...); out != "" {
+			sh.emitEventLocked(&BuildEvent{Action: "build-output", Output: out})
+		}
+		return
+	}
 	sh.printFunc(a...)
 }
 
@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Gocacheprog is a reference implementation of a GOCACHEPROG program,
+which the go command uses to store build and test results.
+
+Usage:
+
+	gocacheprog [-dir dir] [-url url]
+
+Gocacheprog stores results in the directory given by -dir, by default
+a subdirectory named gocacheprog of the standard user cache directory.
+If -url is set, gocacheprog also shares results through the HTTP server
+at that URL: results missing locally are downloaded from the server,
+and new results are uploaded to it. The requests the server must
+support are described in package cmd/internal/cacheprog.
+
+To use it, build it and point GOCACHEPROG at the binary:
+
+	go build -o /usr/local/bin/gocacheprog cmd/gocacheprog
+	export GOCACHEPROG="/usr/local/bin/gocacheprog -url=https://cache.example.com"
+
+Gocacheprog speaks the protocol on its standard input and output,
+and is not meant to be run directly.
+*/
+package main
@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: gocacheprog [-dir dir] [-url url]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+var (
+	dirFlag = flag.String("dir", "", "local cache `directory`")
+	urlFlag = flag.String("url", "", "base `URL` of an HTTP server sharing the cache")
+)
+
+func main() {
+	log.SetPrefix("gocacheprog: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 {
+		usage()
+	}
+
+	dir := *dirFlag
+	if dir == "" {
+		d, err := os.UserCacheDir()
+		if err != nil {
+			log.Fatal(err)
+		}
+		dir = filepath.Join(d, "gocacheprog")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &Server{
+		Dir:       dir,
+		RemoteURL: *urlFlag,
+		Logf:      log.Printf,
+	}
+	if err := s.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cmd/internal/cacheprog"
+)
+
+// A Server is a reference implementation of a GOCACHEPROG program.
+//
+// It stores cache entries in a local directory. If RemoteURL is set,
+// it also shares them through an HTTP server: entries missing from the
+// local directory are fetched from the server, and new entries are
+// uploaded to it. The HTTP server must support these requests, where
+// ID is a lower-case hexadecimal action or output ID:
+//
+//	GET /action/ID  - JSON action entry, or 404 Not Found
+//	PUT /action/ID  - store JSON action entry
+//	GET /output/ID  - output contents, or 404 Not Found
+//	PUT /output/ID  - store output contents
+//
+// An action entry is a JSON object with fields OutputID (hexadecimal),
+// Size and Time. Errors talking to the HTTP server are logged and
+// otherwise treated as cache misses, so that an unavailable server
+// slows builds down but does not break them.
+type Server struct {
+	// Dir is the local directory holding cache entries.
+	// It must be an absolute path.
+	Dir string
+
+	// RemoteURL is the base URL of the HTTP server sharing cache
+	// entries, or empty to use only the local directory.
+	RemoteURL string
+
+	// Client is the HTTP client used to talk to RemoteURL.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Logf, if non-nil, is used to log errors talking to RemoteURL.
+	Logf func(format string, args ...any)
+}
+
+// An actionEntry is the record of the output of an action,
+// stored locally and on the remote server.
+type actionEntry struct {
+	OutputID string // hexadecimal
+	Size     int64
+	Time     time.Time
+}
+
+// Serve runs the protocol with the go command, reading requests from r
+// and writing responses to w, until it has replied to a "close" request
+// or r reaches end of file. Requests are handled concurrently.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	if !filepath.IsAbs(s.Dir) {
+		return fmt.Errorf("cache directory %q is not an absolute path", s.Dir)
+	}
+	if err := os.MkdirAll(s.Dir, 0777); err != nil {
+		return err
+	}
+
+	var (
+		wmu  sync.Mutex // guards bw, enc and werr
+		bw   = bufio.NewWriter(w)
+		enc  = json.NewEncoder(bw)
+		werr error // first error writing a response
+		wg   sync.WaitGroup
+	)
+	write := func(res *cacheprog.Response) error {
+		wmu.Lock()
+		defer wmu.Unlock()
+		if werr == nil {
+			werr = enc.Encode(res)
+		}
+		if werr == nil {
+			werr = bw.Flush()
+		}
+		return werr
+	}
+
+	write(&cacheprog.Response{KnownCommands: []cacheprog.Cmd{cacheprog.CmdGet, cacheprog.CmdPut, cacheprog.CmdClose}})
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		req := new(cacheprog.Request)
+		if err := dec.Decode(req); err != nil {
+			wg.Wait()
+			if err == io.EOF {
+				return werr
+			}
+			return fmt.Errorf("reading request: %v", err)
+		}
+		var body []byte
+		if req.Command == cacheprog.CmdPut && req.BodySize > 0 {
+			if err := dec.Decode(&body); err != nil {
+				wg.Wait()
+				return fmt.Errorf("reading body of request %d: %v", req.ID, err)
+			}
+			if int64(len(body)) != req.BodySize {
+				wg.Wait()
+				return fmt.Errorf("request %d: body has %d bytes, want %d", req.ID, len(body), req.BodySize)
+			}
+		}
+		if req.Command == cacheprog.CmdClose {
+			wg.Wait()
+			return write(&cacheprog.Response{ID: req.ID})
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.handle(req, body)
+			if err != nil {
+				res = &cacheprog.Response{Err: err.Error()}
+			}
+			res.ID = req.ID
+			write(res)
+		}()
+	}
+}
+
+func (s *Server) handle(req *cacheprog.Request, body []byte) (*cacheprog.Response, error) {
+	switch req.Command {
+	case cacheprog.CmdGet:
+		return s.get(req.ActionID)
+	case cacheprog.CmdPut:
+		return s.put(req.ActionID, req.ObjectID, body)
+	}
+	return nil, fmt.Errorf("unknown command %q", req.Command)
+}
+
+func (s *Server) get(actionID []byte) (*cacheprog.Response, error) {
+	if len(actionID) == 0 {
+		return nil, errors.New("get: missing ActionID")
+	}
+	aid := hex.EncodeToString(actionID)
+	ent, err := s.readLocal(aid)
+	if err != nil && s.RemoteURL != "" {
+		ent, err = s.fetch(aid)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logf("fetching action %s: %v", aid, err)
+		}
+	}
+	if err != nil {
+		return &cacheprog.Response{Miss: true}, nil
+	}
+	outputID, _ := hex.DecodeString(ent.OutputID)
+	return &cacheprog.Response{
+		OutputID: outputID,
+		Size:     ent.Size,
+		Time:     &ent.Time,
+		DiskPath: s.path(ent.OutputID, "-d"),
+	}, nil
+}
+
+func (s *Server) put(actionID, objectID, body []byte) (*cacheprog.Response, error) {
+	if len(actionID) == 0 || len(objectID) == 0 {
+		return nil, errors.New("put: missing ActionID or ObjectID")
+	}
+	if sum := sha256.Sum256(body); !bytes.Equal(sum[:], objectID) {
+		return nil, fmt.Errorf("put: ObjectID %x does not match body hash %x", objectID, sum)
+	}
+	aid, oid := hex.EncodeToString(actionID), hex.EncodeToString(objectID)
+	ent := &actionEntry{OutputID: oid, Size: int64(len(body)), Time: time.Now()}
+	if err := s.writeLocal(aid, ent, body); err != nil {
+		return nil, err
+	}
+	if s.RemoteURL != "" {
+		if err := s.upload(aid, ent, body); err != nil {
+			s.logf("uploading action %s: %v", aid, err)
+		}
+	}
+	return &cacheprog.Response{DiskPath: s.path(oid, "-d")}, nil
+}
+
+// path returns the local file holding the entry for id with the given
+// suffix: "-a" for action entries and "-d" for outputs.
+func (s *Server) path(id, suffix string) string {
+	return filepath.Join(s.Dir, id[:2], id+suffix)
+}
+
+// readLocal returns the local entry for the action aid,
+// if both it and its output are present.
+func (s *Server) readLocal(aid string) (*actionEntry, error) {
+	data, err := os.ReadFile(s.path(aid, "-a"))
+	if err != nil {
+		return nil, err
+	}
+	ent, err := parseActionEntry(data)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(s.path(ent.OutputID, "-d"))
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() != ent.Size {
+		return nil, fmt.Errorf("output %s has size %d, want %d", ent.OutputID, fi.Size(), ent.Size)
+	}
+	return ent, nil
+}
+
+// writeLocal stores ent and its output in the local directory.
+func (s *Server) writeLocal(aid string, ent *actionEntry, output []byte) error {
+	if err := writeFileAtomic(s.path(ent.OutputID, "-d"), output); err != nil {
+		return err
+	}
+	data, err := json.Marshal(ent)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path(aid, "-a"), data)
+}
+
+func parseActionEntry(data []byte) (*actionEntry, error) {
+	ent := new(actionEntry)
+	if err := json.Unmarshal(data, ent); err != nil {
+		return nil, err
+	}
+	if b, err := hex.DecodeString(ent.OutputID); err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("malformed output ID %q", ent.OutputID)
+	}
+	return ent, nil
+}
+
+// writeFileAtomic writes data to name, such that concurrent readers
+// see either no file or the whole file.
+func writeFileAtomic(name string, data []byte) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// fetch copies the entry for the action aid, and its output,
+// from the remote server into the local directory.
+func (s *Server) fetch(aid string) (*actionEntry, error) {
+	data, err := s.remoteGet("action/" + aid)
+	if err != nil {
+		return nil, err
+	}
+	ent, err := parseActionEntry(data)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.remoteGet("output/" + ent.OutputID)
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(output); hex.EncodeToString(sum[:]) != ent.OutputID {
+		return nil, fmt.Errorf("output %s has hash %x", ent.OutputID, sum)
+	}
+	if int64(len(output)) != ent.Size {
+		return nil, fmt.Errorf("output %s has size %d, want %d", ent.OutputID, len(output), ent.Size)
+	}
+	if err := s.writeLocal(aid, ent, output); err != nil {
+		return nil, err
+	}
+	return ent, nil
+}
+
+// upload stores ent and its output on the remote server.
+// The output is stored first, so that other clients never find
+// an action entry whose output is missing.
+func (s *Server) upload(aid string, ent *actionEntry, output []byte) error {
+	if err := s.remotePut("output/"+ent.OutputID, output); err != nil {
+		return err
+	}
+	data, err := json.Marshal(ent)
+	if err != nil {
+		return err
+	}
+	return s.remotePut("action/"+aid, data)
+}
+
+func (s *Server) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+func (s *Server) url(path string) string {
+	return strings.TrimSuffix(s.RemoteURL, "/") + "/" + path
+}
+
+// remoteGet returns the contents of path on the remote server.
+// It returns an error wrapping os.ErrNotExist if the server has no such entry.
+func (s *Server) remoteGet(path string) ([]byte, error) {
+	resp, err := s.client().Get(s.url(path))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("GET %s: %w", path, os.ErrNotExist)
+	}
+	return nil, fmt.Errorf("GET %s: %s", path, resp.Status)
+}
+
+func (s *Server) remotePut(path string, data []byte) error {
+	req, err := http.NewRequest("PUT", s.url(path), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("PUT %s: %s", path, resp.Status)
+	}
+	return nil
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
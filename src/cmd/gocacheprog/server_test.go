@@ -0,0 +1,295 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"cmd/internal/cacheprog"
+)
+
+// A testClient drives a Server the way the go command does.
+type testClient struct {
+	t      *testing.T
+	w      *io.PipeWriter
+	dec    *json.Decoder
+	nextID int64
+	done   chan error
+}
+
+func startServer(t *testing.T, s *Server) *testClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &testClient{t: t, w: inW, dec: json.NewDecoder(outR), done: make(chan error, 1)}
+	go func() {
+		err := s.Serve(inR, outW)
+		outW.Close()
+		c.done <- err
+	}()
+
+	var res cacheprog.Response
+	if err := c.dec.Decode(&res); err != nil {
+		t.Fatalf("reading capabilities: %v", err)
+	}
+	if res.ID != 0 || len(res.KnownCommands) != 3 {
+		t.Fatalf("capabilities = %+v, want ID 0 and 3 commands", res)
+	}
+	return c
+}
+
+func (c *testClient) send(req *cacheprog.Request, body []byte) *cacheprog.Response {
+	c.t.Helper()
+	c.nextID++
+	req.ID = c.nextID
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(req)
+	if len(body) > 0 {
+		buf.WriteString(`"` + base64.StdEncoding.EncodeToString(body) + `"` + "\n")
+	}
+	if _, err := c.w.Write(buf.Bytes()); err != nil {
+		c.t.Fatal(err)
+	}
+	res := new(cacheprog.Response)
+	if err := c.dec.Decode(res); err != nil {
+		c.t.Fatalf("reading response: %v", err)
+	}
+	if res.ID != req.ID {
+		c.t.Fatalf("response ID = %d, want %d", res.ID, req.ID)
+	}
+	return res
+}
+
+func (c *testClient) put(actionID string, body []byte) *cacheprog.Response {
+	c.t.Helper()
+	aid := sha256.Sum256([]byte(actionID))
+	oid := sha256.Sum256(body)
+	return c.send(&cacheprog.Request{Command: cacheprog.CmdPut, ActionID: aid[:], ObjectID: oid[:], BodySize: int64(len(body))}, body)
+}
+
+func (c *testClient) get(actionID string) *cacheprog.Response {
+	c.t.Helper()
+	aid := sha256.Sum256([]byte(actionID))
+	return c.send(&cacheprog.Request{Command: cacheprog.CmdGet, ActionID: aid[:]}, nil)
+}
+
+func (c *testClient) close() {
+	c.t.Helper()
+	c.send(&cacheprog.Request{Command: cacheprog.CmdClose}, nil)
+	if err := <-c.done; err != nil {
+		c.t.Fatalf("Serve: %v", err)
+	}
+}
+
+func checkHit(t *testing.T, res *cacheprog.Response, want []byte) {
+	t.Helper()
+	if res.Err != "" || res.Miss {
+		t.Fatalf("get = %+v, want hit", res)
+	}
+	sum := sha256.Sum256(want)
+	if !bytes.Equal(res.OutputID, sum[:]) || res.Size != int64(len(want)) || res.Time == nil {
+		t.Errorf("get = %+v, want OutputID %x, Size %d and Time", res, sum, len(want))
+	}
+	data, err := os.ReadFile(res.DiskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("DiskPath contents = %q, want %q", data, want)
+	}
+}
+
+func TestServeLocal(t *testing.T) {
+	c := startServer(t, &Server{Dir: t.TempDir()})
+
+	if res := c.get("a"); !res.Miss {
+		t.Errorf("get before put = %+v, want miss", res)
+	}
+	res := c.put("a", []byte("hello"))
+	if res.Err != "" || res.DiskPath == "" {
+		t.Fatalf("put = %+v", res)
+	}
+	checkHit(t, c.get("a"), []byte("hello"))
+
+	// An empty output has no body.
+	c.put("empty", nil)
+	checkHit(t, c.get("empty"), nil)
+
+	// A put whose ObjectID does not match its body fails.
+	aid := sha256.Sum256([]byte("b"))
+	res = c.send(&cacheprog.Request{Command: cacheprog.CmdPut, ActionID: aid[:], ObjectID: aid[:], BodySize: 3}, []byte("bad"))
+	if !strings.Contains(res.Err, "does not match") {
+		t.Errorf("put with bad ObjectID = %+v, want error", res)
+	}
+	c.close()
+}
+
+func TestServeRelativeDir(t *testing.T) {
+	s := &Server{Dir: "cache"}
+	if err := s.Serve(strings.NewReader(""), io.Discard); err == nil {
+		t.Errorf("Serve with relative Dir succeeded")
+	}
+}
+
+// A memServer is an in-memory HTTP cache server.
+type memServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	fail    bool // respond to every request with an error
+}
+
+func (m *memServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		data, ok := m.objects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	case "PUT":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.objects[r.URL.Path] = data
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.Error(w, "bad method", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServeRemote(t *testing.T) {
+	mem := &memServer{objects: make(map[string][]byte)}
+	ts := httptest.NewServer(mem)
+	defer ts.Close()
+
+	// One machine stores a result...
+	c1 := startServer(t, &Server{Dir: t.TempDir(), RemoteURL: ts.URL, Client: ts.Client()})
+	c1.put("a", []byte("shared"))
+	c1.close()
+	if len(mem.objects) != 2 {
+		t.Fatalf("remote has %d objects after put, want 2", len(mem.objects))
+	}
+	for path := range mem.objects {
+		if !strings.HasPrefix(path, "/action/") && !strings.HasPrefix(path, "/output/") {
+			t.Errorf("unexpected remote object %s", path)
+		}
+	}
+
+	// ...and another one finds it.
+	dir2 := t.TempDir()
+	c2 := startServer(t, &Server{Dir: dir2, RemoteURL: ts.URL + "/", Client: ts.Client()})
+	res := c2.get("a")
+	checkHit(t, res, []byte("shared"))
+	if !strings.HasPrefix(res.DiskPath, dir2) {
+		t.Errorf("DiskPath = %s, want file in %s", res.DiskPath, dir2)
+	}
+	if res := c2.get("b"); !res.Miss {
+		t.Errorf("get of unknown action = %+v, want miss", res)
+	}
+
+	// The fetched result is now local.
+	mem.mu.Lock()
+	mem.fail = true
+	mem.mu.Unlock()
+	checkHit(t, c2.get("a"), []byte("shared"))
+	c2.close()
+}
+
+func TestServeRemoteCorrupt(t *testing.T) {
+	mem := &memServer{objects: make(map[string][]byte)}
+	ts := httptest.NewServer(mem)
+	defer ts.Close()
+
+	c1 := startServer(t, &Server{Dir: t.TempDir(), RemoteURL: ts.URL, Client: ts.Client()})
+	c1.put("a", []byte("original"))
+	c1.close()
+	for path := range mem.objects {
+		if strings.HasPrefix(path, "/output/") {
+			mem.objects[path] = []byte("tampered")
+		}
+	}
+
+	var logged []string
+	c2 := startServer(t, &Server{
+		Dir:       t.TempDir(),
+		RemoteURL: ts.URL,
+		Client:    ts.Client(),
+		Logf: func(format string, args ...any) {
+			logged = append(logged, format)
+		},
+	})
+	if res := c2.get("a"); !res.Miss {
+		t.Errorf("get of corrupt remote result = %+v, want miss", res)
+	}
+	c2.close()
+	if len(logged) != 1 {
+		t.Errorf("logged %d messages, want 1", len(logged))
+	}
+}
+
+func TestServeRemoteUnavailable(t *testing.T) {
+	ts := httptest.NewServer(&memServer{fail: true})
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var logged []string
+	c := startServer(t, &Server{
+		Dir:       t.TempDir(),
+		RemoteURL: ts.URL,
+		Client:    ts.Client(),
+		Logf: func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			logged = append(logged, format)
+		},
+	})
+	if res := c.get("a"); !res.Miss || res.Err != "" {
+		t.Errorf("get = %+v, want miss", res)
+	}
+	res := c.put("a", []byte("local"))
+	if res.Err != "" {
+		t.Fatalf("put = %+v, want success", res)
+	}
+	checkHit(t, c.get("a"), []byte("local"))
+	c.close()
+	if len(logged) != 2 {
+		t.Errorf("logged %d messages, want 2: %q", len(logged), logged)
+	}
+}
+
+func TestServeEOF(t *testing.T) {
+	var out bytes.Buffer
+	s := &Server{Dir: t.TempDir()}
+	if err := s.Serve(strings.NewReader(""), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var res cacheprog.Response
+	if err := json.NewDecoder(bufio.NewReader(&out)).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.KnownCommands) == 0 {
+		t.Errorf("capabilities = %+v, want KnownCommands", res)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cacheprog defines the protocol spoken between the go command
+// and a GOCACHEPROG program. The cmd/gocacheprog command is a reference
+// implementation of such a program.
+//
+// The go command starts the program named by GOCACHEPROG once per
+// invocation and exchanges JSON messages with it over the program's
+// standard input and output, one JSON object per line.
+//
+// On startup, the program writes a [Response] with ID 0 and
+// KnownCommands listing the [Cmd] values it supports. The go command
+// then sends [Request] messages, with increasing, non-zero IDs, using
+// only commands the program declared, and the program replies to each
+// with a Response carrying the same ID. Responses may be sent in any
+// order. The program should exit after replying to a "close" request.
+//
+// The protocol is versioned by its set of commands: new behavior is
+// added as new commands (for example "get2"), which the go command
+// only uses with programs that declare them. The commands defined by
+// this package are stable and will keep their meaning.
+package cacheprog
+
+import (
+	"io"
+	"time"
+)
+
+// Cmd is a command that can be issued to a GOCACHEPROG program.
+type Cmd string
+
+const (
+	// CmdGet looks up the output of an action.
+	// The request sets ActionID. The response sets Miss, or else
+	// OutputID, Size, Time and DiskPath.
+	CmdGet = Cmd("get")
+
+	// CmdPut stores the output of an action.
+	// The request sets ActionID, ObjectID and BodySize, and is followed
+	// by the body. The response sets DiskPath.
+	CmdPut = Cmd("put")
+
+	// CmdClose is sent before the go command exits.
+	// The program should flush any pending writes, reply, and exit.
+	CmdClose = Cmd("close")
+)
+
+// Request is the JSON-encoded message that's sent from cmd/go to
+// the GOCACHEPROG child process over stdin. Each JSON object is on its
+// own line. A Request of Command "put" with BodySize > 0 will be followed
+// by a line containing a base64-encoded JSON string literal of the body.
+type Request struct {
+	// ID is a unique number per process across all requests.
+	// It must be echoed in the Response from the child.
+	ID int64
+
+	// Command is the type of request.
+	// The cmd/go tool will only send commands that were declared
+	// as supported by the child.
+	Command Cmd
+
+	// ActionID is non-nil for get and puts.
+	ActionID []byte `json:",omitempty"` // or nil if not used
+
+	// ObjectID is set for Command "put".
+	// It is the SHA-256 hash of the body.
+	ObjectID []byte `json:",omitempty"` // or nil if not used
+
+	// Body is the body for "put" requests. It's sent after the JSON object
+	// as a base64-encoded JSON string when BodySize is non-zero.
+	// It's sent as a separate JSON value instead of being a struct field
+	// send in this JSON object so large values can be streamed in both directions.
+	// The base64 string body of a Request will always be written
+	// immediately after the JSON object and a newline.
+	Body io.Reader `json:"-"`
+
+	// BodySize is the number of bytes of Body. If zero, the body isn't written.
+	BodySize int64 `json:",omitempty"`
+}
+
+// Response is the JSON response from the child process to cmd/go.
+//
+// With the exception of the first protocol message that the child writes to its
+// stdout with ID==0 and KnownCommands populated, these are only sent in
+// response to a Request from cmd/go.
+//
+// Responses can be sent in any order. The ID must match the request they're
+// replying to.
+type Response struct {
+	ID  int64  // that corresponds to Request; they can be answered out of order
+	Err string `json:",omitempty"` // if non-empty, the error
+
+	// KnownCommands is included in the first message that cache helper program
+	// writes to stdout on startup (with ID==0). It includes the
+	// Request.Command types that are supported by the program.
+	//
+	// This lets us extend the protocol gracefully over time (adding "get2",
+	// etc), or fail gracefully when needed. It also lets us verify the program
+	// wants to be a cache helper.
+	KnownCommands []Cmd `json:",omitempty"`
+
+	// For Get requests.
+
+	Miss     bool       `json:",omitempty"` // cache miss
+	OutputID []byte     `json:",omitempty"`
+	Size     int64      `json:",omitempty"` // in bytes
+	Time     *time.Time `json:",omitempty"` // an Entry.Time; when the object was added to the docs
+
+	// DiskPath is the absolute path on disk of the ObjectID corresponding
+	// a "get" request's ActionID (on cache hit) or a "put" request's
+	// provided ObjectID.
+	DiskPath string `json:",omitempty"`
+}
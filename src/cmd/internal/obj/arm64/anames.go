@@ -471,18 +471,27 @@ var Anames = []string{
 	"VADDV",
 	"VAND",
 	"VBCAX",
+	"VBIC",
 	"VBIF",
 	"VBIT",
 	"VBSL",
 	"VCMEQ",
+	"VCMGT",
 	"VCMTST",
 	"VCNT",
 	"VDUP",
 	"VEOR",
 	"VEOR3",
 	"VEXT",
+	"VFADD",
+	"VFDIV",
+	"VFMAX",
+	"VFMIN",
 	"VFMLA",
 	"VFMLS",
+	"VFMUL",
+	"VFSQRT",
+	"VFSUB",
 	"VLD1",
 	"VLD1R",
 	"VLD2",
@@ -496,6 +505,7 @@ var Anames = []string{
 	"VMOVI",
 	"VMOVQ",
 	"VMOVS",
+	"VMUL",
 	"VORR",
 	"VPMULL",
 	"VPMULL2",
@@ -506,6 +516,8 @@ var Anames = []string{
 	"VREV64",
 	"VSHL",
 	"VSLI",
+	"VSMAX",
+	"VSMIN",
 	"VSRI",
 	"VST1",
 	"VST2",
@@ -879,6 +879,7 @@ var optab = []Optab{
 	{ASHA1C, C_ARNG, C_VREG, C_NONE, C_VREG, C_NONE, 49, 4, 0, 0, 0},
 	{ASHA1SU0, C_ARNG, C_ARNG, C_NONE, C_ARNG, C_NONE, 63, 4, 0, 0, 0},
 	{AVREV32, C_ARNG, C_NONE, C_NONE, C_ARNG, C_NONE, 83, 4, 0, 0, 0},
+	{AVFSQRT, C_ARNG, C_NONE, C_NONE, C_ARNG, C_NONE, 83, 4, 0, 0, 0},
 	{AVPMULL, C_ARNG, C_ARNG, C_NONE, C_ARNG, C_NONE, 93, 4, 0, 0, 0},
 	{AVEOR3, C_ARNG, C_ARNG, C_ARNG, C_ARNG, C_NONE, 103, 4, 0, 0, 0},
 	{AVXAR, C_VCON, C_ARNG, C_ARNG, C_ARNG, C_NONE, 104, 4, 0, 0, 0},
@@ -3223,6 +3224,11 @@ func buildop(ctxt *obj.Link) {
 			oprangeset(AVUZP1, t)
 			oprangeset(AVUZP2, t)
 			oprangeset(AVBIF, t)
+			oprangeset(AVBIC, t)
+			oprangeset(AVCMGT, t)
+			oprangeset(AVMUL, t)
+			oprangeset(AVSMAX, t)
+			oprangeset(AVSMIN, t)
 
 		case AVADD:
 			oprangeset(AVSUB, t)
@@ -3254,6 +3260,12 @@ func buildop(ctxt *obj.Link) {
 
 		case AVFMLA:
 			oprangeset(AVFMLS, t)
+			oprangeset(AVFADD, t)
+			oprangeset(AVFSUB, t)
+			oprangeset(AVFMUL, t)
+			oprangeset(AVFDIV, t)
+			oprangeset(AVFMAX, t)
+			oprangeset(AVFMIN, t)
 
 		case AVPMULL:
 			oprangeset(AVPMULL2, t)
@@ -3299,6 +3311,7 @@ func buildop(ctxt *obj.Link) {
 			oprangeset(AVTBX, t)
 
 		case AVCNT,
+			AVFSQRT,
 			AVMOV,
 			AVLD1,
 			AVST1,
@@ -4764,7 +4777,7 @@ func (c *ctxt7) asmout(p *obj.Prog, o *Optab, out []uint32) {
 		rel.Add = 0
 		rel.Type = objabi.R_ARM64_GOTPCREL
 
-	case 72: /* vaddp/vand/vcmeq/vorr/vadd/veor/vfmla/vfmls/vbit/vbsl/vcmtst/vsub/vbif/vuzip1/vuzip2/vrax1 Vm.<T>, Vn.<T>, Vd.<T> */
+	case 72: /* vaddp/vand/vbic/vcmeq/vcmgt/vorr/vadd/veor/vfadd/vfsub/vfmul/vfdiv/vfmax/vfmin/vfmla/vfmls/vbit/vbsl/vcmtst/vmul/vsmax/vsmin/vsub/vbif/vuzip1/vuzip2/vrax1 Vm.<T>, Vn.<T>, Vd.<T> */
 		af := int((p.From.Reg >> 5) & 15)
 		af3 := int((p.Reg >> 5) & 15)
 		at := int((p.To.Reg >> 5) & 15)
@@ -4806,15 +4819,15 @@ func (c *ctxt7) asmout(p *obj.Prog, o *Optab, out []uint32) {
 		}
 
 		switch p.As {
-		case AVORR, AVAND, AVEOR, AVBIT, AVBSL, AVBIF:
+		case AVORR, AVAND, AVBIC, AVEOR, AVBIT, AVBSL, AVBIF:
 			if af != ARNG_16B && af != ARNG_8B {
 				c.ctxt.Diag("invalid arrangement: %v", p)
 			}
-		case AVFMLA, AVFMLS:
+		case AVFMLA, AVFMLS, AVFADD, AVFSUB, AVFMUL, AVFDIV, AVFMAX, AVFMIN:
 			if af != ARNG_2D && af != ARNG_2S && af != ARNG_4S {
 				c.ctxt.Diag("invalid arrangement: %v", p)
 			}
-		case AVUMAX, AVUMIN:
+		case AVUMAX, AVUMIN, AVSMAX, AVSMIN, AVMUL:
 			if af == ARNG_2D {
 				c.ctxt.Diag("invalid arrangement: %v", p)
 			}
@@ -4822,11 +4835,11 @@ func (c *ctxt7) asmout(p *obj.Prog, o *Optab, out []uint32) {
 		switch p.As {
 		case AVAND, AVEOR:
 			size = 0
-		case AVBSL:
+		case AVBSL, AVBIC:
 			size = 1
 		case AVORR, AVBIT, AVBIF:
 			size = 2
-		case AVFMLA, AVFMLS:
+		case AVFMLA, AVFMLS, AVFADD, AVFSUB, AVFMUL, AVFDIV, AVFMAX, AVFMIN:
 			if af == ARNG_2D {
 				size = 1
 			} else {
@@ -5163,7 +5176,7 @@ func (c *ctxt7) asmout(p *obj.Prog, o *Optab, out []uint32) {
 		o1 |= (Q & 1 << 30) | (imm5 & 0x1f << 16)
 		o1 |= (uint32(rf&31) << 5) | uint32(rt&31)
 
-	case 83: /* vmov Vn.<T>, Vd.<T> */
+	case 83: /* vmov/vcnt/vrbit/vrev16/vrev32/vrev64/vfsqrt Vn.<T>, Vd.<T> */
 		af := int((p.From.Reg >> 5) & 15)
 		at := int((p.To.Reg >> 5) & 15)
 		if af != at {
@@ -5193,10 +5206,26 @@ func (c *ctxt7) asmout(p *obj.Prog, o *Optab, out []uint32) {
 		case ARNG_4S:
 			Q = 1
 			size = 2
+		case ARNG_2D:
+			Q = 1
+			size = 3
 		default:
 			c.ctxt.Diag("invalid arrangement: %v\n", p)
 		}
 
+		if p.As == AVFSQRT {
+			switch af {
+			case ARNG_2S, ARNG_4S:
+				size = 0
+			case ARNG_2D:
+				size = 1
+			default:
+				c.ctxt.Diag("invalid arrangement: %v", p)
+			}
+		} else if af == ARNG_2D {
+			c.ctxt.Diag("invalid arrangement: %v", p)
+		}
+
 		if (p.As == AVMOV || p.As == AVRBIT || p.As == AVCNT) && (af != ARNG_16B && af != ARNG_8B) {
 			c.ctxt.Diag("invalid arrangement: %v", p)
 		}
@@ -6495,12 +6524,18 @@ func (c *ctxt7) oprrr(p *obj.Prog, a obj.As) uint32 {
 	case AVAND:
 		return 7<<25 | 1<<21 | 7<<10
 
+	case AVBIC:
+		return 7<<25 | 3<<21 | 7<<10
+
 	case AVBCAX:
 		return 0xCE<<24 | 1<<21
 
 	case AVCMEQ:
 		return 1<<29 | 0x71<<21 | 0x23<<10
 
+	case AVCMGT:
+		return 7<<25 | 1<<21 | 0xd<<10
+
 	case AVCNT:
 		return 0xE<<24 | 0x10<<17 | 5<<12 | 2<<10
 
@@ -6546,6 +6581,36 @@ func (c *ctxt7) oprrr(p *obj.Prog, a obj.As) uint32 {
 	case AVFMLS:
 		return 7<<25 | 1<<23 | 1<<21 | 3<<14 | 3<<10
 
+	case AVFADD:
+		return 7<<25 | 1<<21 | 0x35<<10
+
+	case AVFSUB:
+		return 7<<25 | 1<<23 | 1<<21 | 0x35<<10
+
+	case AVFMUL:
+		return 1<<29 | 7<<25 | 1<<21 | 0x37<<10
+
+	case AVFDIV:
+		return 1<<29 | 7<<25 | 1<<21 | 0x3f<<10
+
+	case AVFMAX:
+		return 7<<25 | 1<<21 | 0x3d<<10
+
+	case AVFMIN:
+		return 7<<25 | 1<<23 | 1<<21 | 0x3d<<10
+
+	case AVFSQRT:
+		return 0x2E<<24 | 1<<23 | 0x10<<17 | 0x1f<<12 | 2<<10
+
+	case AVMUL:
+		return 7<<25 | 1<<21 | 0x27<<10
+
+	case AVSMAX:
+		return 7<<25 | 1<<21 | 0x19<<10
+
+	case AVSMIN:
+		return 7<<25 | 1<<21 | 0x1b<<10
+
 	case AVPMULL, AVPMULL2:
 		return 0xE<<24 | 1<<21 | 0x38<<10
 
@@ -2145,6 +2145,9 @@ func span6(ctxt *obj.Link, s *obj.LSym, newprog obj.ProgAlloc) {
 			c = pjc.padJump(ctxt, s, p, c)
 
 			if p.As == obj.APCALIGN {
+				// The padding belongs to the PCALIGN, so that
+				// the pc-value tables stay monotonic.
+				p.Pc = int64(c)
 				aln := p.From.Offset
 				v := addpad(int64(c), aln, ctxt, s)
 				if v > 0 {
@@ -317,6 +317,13 @@ func TestPCALIGN(t *testing.T) {
 			code: "TEXT ·foo(SB),$0-0\nMOVQ $0, AX\nPCALIGN $16\nMOVQ $2, CX\nRET\n",
 			out:  `0x0010\s00016\s\(.*\)\tMOVQ\t\$2,\sCX`,
 		},
+		{
+			// The PCALIGN itself is at the pc before its padding,
+			// not at a stale pc from an earlier assembly pass.
+			name: "PCALIGN pc",
+			code: "TEXT ·foo(SB),$0-0\nMOVQ $0, AX\nPCALIGN $8\nMOVQ $1, BX\nRET\n",
+			out:  `0x0007\s00007\s\(.*\)\tPCALIGN\t\$8`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -25,7 +25,7 @@ func IsSerialized(r *bufio.Reader) (bool, error) {
 		return false, fmt.Errorf("error reading profile header: %w", err)
 	}
 
-	return string(hdr) == serializationHeader, nil
+	return string(hdr) == serializationHeader || string(hdr) == serializationHeaderV1, nil
 }
 
 // FromSerialized parses a profile from serialization output of Profile.WriteTo.
@@ -41,13 +41,20 @@ func FromSerialized(r io.Reader) (*Profile, error) {
 		}
 		return nil, fmt.Errorf("preprocessed profile missing header")
 	}
-	if gotHdr := scanner.Text() + "\n"; gotHdr != serializationHeader {
+	gotHdr := scanner.Text() + "\n"
+	if gotHdr != serializationHeader && gotHdr != serializationHeaderV1 {
 		return nil, fmt.Errorf("preprocessed profile malformed header; got %q want %q", gotHdr, serializationHeader)
 	}
 
+	lines := false // in the line weight section
 	for scanner.Scan() {
 		readStr := scanner.Text()
 
+		if readStr == "" && gotHdr == serializationHeader {
+			lines = true
+			break
+		}
+
 		callerName := readStr
 
 		if !scanner.Scan() {
@@ -96,6 +103,52 @@ func FromSerialized(r io.Reader) (*Profile, error) {
 		d.NamedEdgeMap.Weight[edge] += weight
 		d.TotalWeight += weight
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+	}
+
+	for lines && scanner.Scan() {
+		funcName := scanner.Text()
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+			}
+			return nil, fmt.Errorf("preprocessed profile line entry missing weight")
+		}
+		readStr := scanner.Text()
+
+		split := strings.Split(readStr, " ")
+
+		if len(split) != 2 {
+			return nil, fmt.Errorf("preprocessed profile line entry got %v want 2 fields", split)
+		}
+
+		lo, err := strconv.Atoi(split[0])
+		if err != nil {
+			return nil, fmt.Errorf("preprocessed profile error processing line offset: %w", err)
+		}
+
+		line := NamedLine{
+			FuncName:   funcName,
+			LineOffset: lo,
+		}
+
+		weight, err := strconv.ParseInt(split[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("preprocessed profile error processing line weight: %w", err)
+		}
+
+		if _, ok := d.NamedLineMap.Weight[line]; ok {
+			return nil, fmt.Errorf("preprocessed profile contains duplicate line %+v", line)
+		}
+
+		d.NamedLineMap.ByWeight = append(d.NamedLineMap.ByWeight, line) // N.B. serialization is ordered.
+		d.NamedLineMap.Weight[line] = weight
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading preprocessed profile: %w", err)
+	}
 
 	return d, nil
 
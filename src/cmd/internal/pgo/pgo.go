@@ -17,6 +17,12 @@ type Profile struct {
 	// NamedEdgeMap contains all unique call edges in the profile and their
 	// edge weight.
 	NamedEdgeMap NamedEdgeMap
+
+	// NamedLineMap contains the cumulative weight of each source line in
+	// the profile. Unlike NamedEdgeMap, which only describes calls, it
+	// covers all code, and guides optimizations within a function such as
+	// basic block layout.
+	NamedLineMap NamedLineMap
 }
 
 // NamedCallEdge identifies a call edge by linker symbol names and call site
@@ -37,6 +43,23 @@ type NamedEdgeMap struct {
 	ByWeight []NamedCallEdge
 }
 
+// NamedLine identifies a source line by linker symbol name of the enclosing
+// function and line offset.
+type NamedLine struct {
+	FuncName   string
+	LineOffset int // Line offset from function start line.
+}
+
+// NamedLineMap contains the cumulative weight of each source line in the
+// profile: the weight of samples with that line anywhere on the stack.
+type NamedLineMap struct {
+	Weight map[NamedLine]int64
+
+	// ByWeight lists all keys in Weight, sorted by weight from highest to
+	// lowest.
+	ByWeight []NamedLine
+}
+
 func emptyProfile() *Profile {
 	// Initialize empty maps/slices for easier use without a requiring a
 	// nil check.
@@ -45,6 +68,10 @@ func emptyProfile() *Profile {
 			ByWeight: make([]NamedCallEdge, 0),
 			Weight:   make(map[NamedCallEdge]int64),
 		},
+		NamedLineMap: NamedLineMap{
+			ByWeight: make([]NamedLine, 0),
+			Weight:   make(map[NamedLine]int64),
+		},
 	}
 }
 
@@ -62,9 +62,38 @@ func FromPProf(r io.Reader) (*Profile, error) {
 	return &Profile{
 		TotalWeight:  totalWeight,
 		NamedEdgeMap: namedEdgeMap,
+		NamedLineMap: createNamedLineMap(g),
 	}, nil
 }
 
+// createNamedLineMap builds a map of source line weights from the
+// profile-graph.
+func createNamedLineMap(g *profile.Graph) NamedLineMap {
+	weight := make(map[NamedLine]int64)
+	for _, n := range g.Nodes {
+		if n.Info.Name == "" || n.CumValue() == 0 {
+			continue
+		}
+		// A line may have multiple nodes, one per PC.
+		line := NamedLine{
+			FuncName:   n.Info.Name,
+			LineOffset: n.Info.Lineno - n.Info.StartLine,
+		}
+		weight[line] += n.CumValue()
+	}
+
+	byWeight := make([]NamedLine, 0, len(weight))
+	for line := range weight {
+		byWeight = append(byWeight, line)
+	}
+	sortLinesByWeight(byWeight, weight)
+
+	return NamedLineMap{
+		Weight:   weight,
+		ByWeight: byWeight,
+	}
+}
+
 // createNamedEdgeMap builds a map of callsite-callee edge weights from the
 // profile-graph.
 //
@@ -119,6 +148,20 @@ func sortByWeight(edges []NamedCallEdge, weight map[NamedCallEdge]int64) {
 	})
 }
 
+func sortLinesByWeight(lines []NamedLine, weight map[NamedLine]int64) {
+	sort.Slice(lines, func(i, j int) bool {
+		li, lj := lines[i], lines[j]
+		if wi, wj := weight[li], weight[lj]; wi != wj {
+			return wi > wj // want larger weight first
+		}
+		// same weight, order by name/line number
+		if li.FuncName != lj.FuncName {
+			return li.FuncName < lj.FuncName
+		}
+		return li.LineOffset < lj.LineOffset
+	})
+}
+
 func postProcessNamedEdgeMap(weight map[NamedCallEdge]int64, weightVal int64) (edgeMap NamedEdgeMap, totalWeight int64, err error) {
 	if weightVal == 0 {
 		return NamedEdgeMap{}, 0, nil // accept but ignore profile with no samples.
@@ -16,7 +16,7 @@ import (
 //
 // The format of the serialized output is as follows.
 //
-//      GO PREPROFILE V1
+//      GO PREPROFILE V2
 //      caller_name
 //      callee_name
 //      "call site offset" "call edge weight"
@@ -25,9 +25,23 @@ import (
 //      callee_name
 //      "call site offset" "call edge weight"
 //
-// Entries are sorted by "call edge weight", from highest to lowest.
+//      func_name
+//      "line offset" "line weight"
+//      ...
+//      func_name
+//      "line offset" "line weight"
+//
+// The call edges are followed by an empty line and the line weights, which
+// are omitted entirely if there are none. Both sections are sorted by
+// weight, from highest to lowest.
+//
+// Version 1 of the format consists of only the header and the call edges.
+// FromSerialized still accepts it.
 
-const serializationHeader = "GO PREPROFILE V1\n"
+const (
+	serializationHeader   = "GO PREPROFILE V2\n"
+	serializationHeaderV1 = "GO PREPROFILE V1\n"
+)
 
 // WriteTo writes a serialized representation of Profile to w.
 //
@@ -68,6 +82,30 @@ func (d *Profile) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
+	if len(d.NamedLineMap.ByWeight) > 0 {
+		n, err = fmt.Fprintln(bw)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	for _, line := range d.NamedLineMap.ByWeight {
+		weight := d.NamedLineMap.Weight[line]
+
+		n, err = fmt.Fprintln(bw, line.FuncName)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		n, err = fmt.Fprintf(bw, "%d %d\n", line.LineOffset, weight)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
 	if err := bw.Flush(); err != nil {
 		return written, err
 	}
@@ -24,6 +24,17 @@ func equal(got, want *Profile) error {
 	if !reflect.DeepEqual(got.NamedEdgeMap.Weight, want.NamedEdgeMap.Weight) {
 		return fmt.Errorf("got.NamedEdgeMap.Weight != want.NamedEdgeMap.Weight\ngot = %+v\nwant = %+v", got.NamedEdgeMap.Weight, want.NamedEdgeMap.Weight)
 	}
+	if len(got.NamedLineMap.ByWeight) == 0 && len(want.NamedLineMap.ByWeight) == 0 {
+		// Profiles without line weights are equal regardless of
+		// whether their maps are allocated.
+		return nil
+	}
+	if !reflect.DeepEqual(got.NamedLineMap.ByWeight, want.NamedLineMap.ByWeight) {
+		return fmt.Errorf("got.NamedLineMap.ByWeight != want.NamedLineMap.ByWeight\ngot = %+v\nwant = %+v", got.NamedLineMap.ByWeight, want.NamedLineMap.ByWeight)
+	}
+	if !reflect.DeepEqual(got.NamedLineMap.Weight, want.NamedLineMap.Weight) {
+		return fmt.Errorf("got.NamedLineMap.Weight != want.NamedLineMap.Weight\ngot = %+v\nwant = %+v", got.NamedLineMap.Weight, want.NamedLineMap.Weight)
+	}
 
 	return nil
 }
@@ -95,6 +106,42 @@ func TestRoundTrip(t *testing.T) {
 	testRoundTrip(t, d)
 }
 
+func TestRoundTripLines(t *testing.T) {
+	d := emptyProfile()
+	d.TotalWeight = 2
+	edge := NamedCallEdge{CallerName: "a", CalleeName: "b", CallSiteOffset: 14}
+	d.NamedEdgeMap.ByWeight = []NamedCallEdge{edge}
+	d.NamedEdgeMap.Weight[edge] = 2
+	d.NamedLineMap.ByWeight = []NamedLine{
+		{FuncName: "a", LineOffset: 14},
+		{FuncName: "b", LineOffset: -1},
+	}
+	d.NamedLineMap.Weight[NamedLine{FuncName: "a", LineOffset: 14}] = 5
+	d.NamedLineMap.Weight[NamedLine{FuncName: "b", LineOffset: -1}] = 3
+
+	b := testRoundTrip(t, d)
+
+	want := serializationHeader + "a\nb\n14 2\n\na\n14 5\nb\n-1 3\n"
+	if string(b) != want {
+		t.Errorf("WriteTo got %q want %q", string(b), want)
+	}
+}
+
+func TestFromSerializedV1(t *testing.T) {
+	got, err := FromSerialized(strings.NewReader(serializationHeaderV1 + "a\nb\n14 2\n"))
+	if err != nil {
+		t.Fatalf("FromSerialized got err %v want nil", err)
+	}
+	want := emptyProfile()
+	want.TotalWeight = 2
+	edge := NamedCallEdge{CallerName: "a", CalleeName: "b", CallSiteOffset: 14}
+	want.NamedEdgeMap.ByWeight = []NamedCallEdge{edge}
+	want.NamedEdgeMap.Weight[edge] = 2
+	if err := equal(got, want); err != nil {
+		t.Errorf("FromSerialized output does not match: %v", err)
+	}
+}
+
 func constructFuzzProfile(t *testing.T, b []byte) *Profile {
 	// The fuzzer can't construct an arbitrary structure, so instead we
 	// consume bytes from b to act as our edge data.
@@ -66,6 +66,13 @@ Flags:
 		The dynamic header is on by default, even without any
 		references to dynamic libraries, because many common
 		system tools now assume the presence of the header.
+	-deadcodejson file
+		Write a JSON report to file listing the functions and methods removed
+		by dead code elimination and the exported package-level functions,
+		methods, and variables that are not reachable from the program's
+		entry points. Functions whose every call was inlined are reported
+		as removed, but not as unreachable, unless the inlined calls were
+		optimized away entirely.
 	-dumpdep
 		Dump symbol dependency graph.
 	-extar ar
@@ -114,6 +121,16 @@ Flags:
 		Link with race detection libraries.
 	-s
 		Omit the symbol table and debug information.
+	-sizejson file
+		Write a JSON report to file attributing the size of the output to
+		packages and to kinds of symbols: text, rodata, data, bss, type
+		metadata, pclntab, and DWARF. The pclntab entries, pc-value tables,
+		and inlining trees of functions are attributed to their packages;
+		the pclntab header and file tables, and other data synthesized by
+		the linker, are reported under the package "". For each package,
+		the report also gives the shortest chain of imports from the main
+		package that caused it to be linked in. DWARF sizes are before
+		compression.
 	-tmpdir dir
 		Write temporary files to dir.
 		Temporary files are only used in external linking mode.
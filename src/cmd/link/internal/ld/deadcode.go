@@ -10,9 +10,12 @@ import (
 	"cmd/internal/sys"
 	"cmd/link/internal/loader"
 	"cmd/link/internal/sym"
+	"encoding/json"
 	"fmt"
 	"internal/abi"
 	"internal/buildcfg"
+	"os"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -560,3 +563,176 @@ func (d *deadcodePass) decodetypeMethods(ldr *loader.Loader, arch *sys.Arch, sym
 	const sizeofMethod = 4 * 4 // sizeof reflect.method in program
 	return d.decodeMethodSig(ldr, arch, symIdx, relocs, off, sizeofMethod, mcount)
 }
+
+// A deadcodeReport is the JSON report written by the -deadcodejson flag.
+type deadcodeReport struct {
+	Eliminated    []deadcodeSym // functions and methods removed by dead code elimination
+	UnusedExports []deadcodeSym // exported package-level symbols not reachable from the entry points, excluding inlined functions
+}
+
+// A deadcodeSym describes a symbol in a deadcodeReport.
+type deadcodeSym struct {
+	Package string // import path
+	Name    string // name within the package, such as "F", "T.M", or "(*T).M"
+	Kind    string // "func", "method", or "var"
+	Size    int64  // size in bytes
+}
+
+// writeDeadcodeReport writes a report of the Go functions, methods, and
+// variables left unreachable by the deadcode pass to the named file.
+func writeDeadcodeReport(ctxt *Link, file string) {
+	ldr := ctxt.loader
+
+	// A function whose every call was inlined is eliminated, but it is
+	// still used: its reachable callers record it in their inline trees.
+	// Inlined calls that compiled to no instructions of their own leave
+	// no trace there, so such functions are still reported as unused.
+	inlined := make(map[string]bool)
+	for s := loader.Sym(1); s < loader.Sym(ldr.NDef()); s++ {
+		if !ldr.AttrReachable(s) || ldr.SymType(s) != sym.STEXT {
+			continue
+		}
+		fi := ldr.FuncInfo(s)
+		if !fi.Valid() {
+			continue
+		}
+		fi.Preload()
+		for i, n := 0, int(fi.NumInlTree()); i < n; i++ {
+			inlined[ldr.SymName(fi.InlTree(i).Func)] = true
+		}
+	}
+
+	var report deadcodeReport
+	seen := make(map[deadcodeSym]bool)
+	for s := loader.Sym(1); s < loader.Sym(ldr.NDef()); s++ {
+		if ldr.AttrReachable(s) || ldr.SymVersion(s) >= sym.SymVerStatic {
+			continue
+		}
+		var kind string
+		switch ldr.SymType(s) {
+		case sym.STEXT:
+			kind = "func"
+		case sym.SDATA, sym.SNOPTRDATA, sym.SBSS, sym.SNOPTRBSS:
+			kind = "var"
+		default:
+			continue
+		}
+		pkg := ldr.SymPkg(s)
+		name, ok := strings.CutPrefix(ldr.SymName(s), objabi.PathToPrefix(pkg)+".")
+		if pkg == "" || !ok || name == "" || strings.Contains(name, "..") {
+			// Not a Go symbol defined by its package,
+			// or a compiler-generated one like pkg..stmp_0.
+			continue
+		}
+		exported, isMethod, ok := classifySymName(name)
+		if !ok {
+			continue
+		}
+		if isMethod {
+			if kind != "func" {
+				continue
+			}
+			kind = "method"
+		}
+		ds := deadcodeSym{Package: pkg, Name: name, Kind: kind, Size: ldr.SymSize(s)}
+		if seen[ds] {
+			// The same function may have both ABI0 and ABIInternal definitions.
+			continue
+		}
+		seen[ds] = true
+		if kind != "var" {
+			report.Eliminated = append(report.Eliminated, ds)
+		}
+		if exported && pkg != "main" && !inlined[ldr.SymName(s)] {
+			report.UnusedExports = append(report.UnusedExports, ds)
+		}
+	}
+	for _, list := range [][]deadcodeSym{report.Eliminated, report.UnusedExports} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Package != list[j].Package {
+				return list[i].Package < list[j].Package
+			}
+			return list[i].Name < list[j].Name
+		})
+	}
+
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		Exitf("writing deadcode report: %v", err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0666); err != nil {
+		Exitf("writing deadcode report: %v", err)
+	}
+}
+
+// classifySymName classifies the name of a function or variable within
+// its package, such as "F", "T.M", "(*T).M", or "F.func1".
+// It reports whether the name denotes a package-level symbol or a method
+// whose identifiers are all exported, and whether it denotes a method.
+// It returns ok == false for closures and other compiler-generated
+// functions, which have no corresponding declaration in the source.
+func classifySymName(name string) (exported, isMethod, ok bool) {
+	// Strip type arguments of generic instantiations, like F[go.shape.int].
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	name = b.String()
+
+	var parts []string
+	if rest, found := strings.CutPrefix(name, "(*"); found {
+		recv, method, found := strings.Cut(rest, ").")
+		if !found {
+			return false, false, false
+		}
+		parts = []string{recv, method}
+	} else {
+		parts = strings.Split(name, ".")
+	}
+	switch len(parts) {
+	case 1:
+	case 2:
+		isMethod = true
+	default:
+		return false, false, false
+	}
+	exported = true
+	for _, p := range parts {
+		if p == "" || strings.ContainsAny(p, "(*)") {
+			return false, false, false
+		}
+		if isMethod && isCompilerGenerated(p) {
+			// A closure, like F.func1, or a wrapper, like F.gowrap1.
+			return false, false, false
+		}
+		exported = exported && isExportedName(p)
+	}
+	return exported, isMethod, true
+}
+
+// isCompilerGenerated reports whether the final element of a function
+// name denotes a closure or wrapper generated by the compiler.
+func isCompilerGenerated(name string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" && strings.Trim(rest, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isExportedName reports whether name begins with an upper-case letter.
+func isExportedName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
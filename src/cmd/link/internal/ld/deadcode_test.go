@@ -6,7 +6,9 @@ package ld
 
 import (
 	"bytes"
+	"encoding/json"
 	"internal/testenv"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -57,3 +59,140 @@ func TestDeadcode(t *testing.T) {
 		})
 	}
 }
+
+func TestDeadcodeReport(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.23\n",
+		"main.go": `package main
+
+import "example.com/m/lib"
+
+func main() {
+	lib.Used()
+	lib.T{}.Used()
+	lib.Inlined("inlined")
+}
+
+func unused() {}
+`,
+		"lib/lib.go": `package lib
+
+var Unreachable []int
+
+//go:noinline
+func Used() { println("used") }
+
+//go:noinline
+func Unused() { println("unused") }
+
+//go:noinline
+func unexported() { println("unexported") }
+
+// Inlined is inlined into its only caller, so its body is eliminated.
+func Inlined(s string) { println(s) }
+
+func InlinableUnused() int { return 7 }
+
+type T struct{}
+
+//go:noinline
+func (T) Used() { println("T.Used") }
+
+//go:noinline
+func (*T) Unused() { Unreachable = append(Unreachable, 1) }
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(tmpdir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report := filepath.Join(tmpdir, "deadcode.json")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-deadcodejson="+report, "-o", filepath.Join(tmpdir, "m.exe"), ".")
+	cmd.Dir = tmpdir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+	}
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r deadcodeReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	has := func(list []deadcodeSym, pkg, name, kind string) bool {
+		for _, s := range list {
+			if s.Package == pkg && s.Name == name && s.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+	for _, want := range []struct {
+		list       []deadcodeSym
+		listName   string
+		pkg, name  string
+		kind       string
+		wantInList bool
+	}{
+		{r.Eliminated, "Eliminated", "main", "unused", "func", true},
+		{r.Eliminated, "Eliminated", "main", "main", "func", false},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "Unused", "func", true},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "unexported", "func", true},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "Used", "func", false},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "(*T).Unused", "method", true},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "T.Used", "method", false},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "Unused", "func", true},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "(*T).Unused", "method", true},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "Unreachable", "var", true},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "unexported", "func", false},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "Used", "func", false},
+		{r.UnusedExports, "UnusedExports", "main", "unused", "func", false},
+		{r.Eliminated, "Eliminated", "example.com/m/lib", "Inlined", "func", true},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "Inlined", "func", false},
+		{r.UnusedExports, "UnusedExports", "example.com/m/lib", "InlinableUnused", "func", true},
+	} {
+		if got := has(want.list, want.pkg, want.name, want.kind); got != want.wantInList {
+			t.Errorf("%s contains %s %s.%s = %v, want %v", want.listName, want.kind, want.pkg, want.name, got, want.wantInList)
+		}
+	}
+	if t.Failed() {
+		t.Logf("report:\n%s", data)
+	}
+}
+
+func TestClassifySymName(t *testing.T) {
+	tests := []struct {
+		name                   string
+		exported, isMethod, ok bool
+	}{
+		{"F", true, false, true},
+		{"f", false, false, true},
+		{"T.M", true, true, true},
+		{"T.m", false, true, true},
+		{"(*T).M", true, true, true},
+		{"(*t).M", false, true, true},
+		{"F.func1", false, false, false},
+		{"F.gowrap2", false, false, false},
+		{"F.func1.1", false, false, false},
+		{"Map[go.shape.int,go.shape.string]", true, false, true},
+		{"(*List[go.shape.int]).Push", true, true, true},
+	}
+	for _, tt := range tests {
+		exported, isMethod, ok := classifySymName(tt.name)
+		if exported != tt.exported || isMethod != tt.isMethod || ok != tt.ok {
+			t.Errorf("classifySymName(%q) = %v, %v, %v, want %v, %v, %v", tt.name, exported, isMethod, ok, tt.exported, tt.isMethod, tt.ok)
+		}
+	}
+}
@@ -948,8 +948,12 @@ func (d *dwctxt) synthesizemaptypes(ctxt *Link, die *dwarf.DWDie) {
 		// Construct hash<K,V>
 		dwhs := d.mkinternaltype(ctxt, dwarf.DW_ABRV_STRUCTTYPE, "hash", keyname, valname, func(dwh *dwarf.DWDie) {
 			d.copychildren(ctxt, dwh, hash)
-			d.substitutetype(dwh, "buckets", d.defptrto(dwhbs))
-			d.substitutetype(dwh, "oldbuckets", d.defptrto(dwhbs))
+			// The Swiss table map has no array of buckets, so its
+			// fields keep their generic types.
+			if !buildcfg.Experiment.SwissMap {
+				d.substitutetype(dwh, "buckets", d.defptrto(dwhbs))
+				d.substitutetype(dwh, "oldbuckets", d.defptrto(dwhbs))
+			}
 			newattr(dwh, dwarf.DW_AT_byte_size, dwarf.DW_CLS_CONSTANT, getattr(hash, dwarf.DW_AT_byte_size).Value, nil)
 		})
 
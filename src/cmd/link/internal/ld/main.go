@@ -70,6 +70,8 @@ var (
 
 	flagInstallSuffix = flag.String("installsuffix", "", "set package directory `suffix`")
 	flagDumpDep       = flag.Bool("dumpdep", false, "dump symbol dependency graph")
+	flagDeadcodeJSON  = flag.String("deadcodejson", "", "write a JSON report of eliminated functions and unreachable exported symbols to `file`")
+	flagSizeJSON      = flag.String("sizejson", "", "write a JSON report of binary size by package and symbol kind to `file`")
 	flagRace          = flag.Bool("race", false, "enable race detector")
 	flagMsan          = flag.Bool("msan", false, "enable MSan interface")
 	flagAsan          = flag.Bool("asan", false, "enable ASan interface")
@@ -353,6 +355,9 @@ func Main(arch *sys.Arch, theArch Arch) {
 
 	bench.Start("deadcode")
 	deadcode(ctxt)
+	if *flagDeadcodeJSON != "" {
+		writeDeadcodeReport(ctxt, *flagDeadcodeJSON)
+	}
 
 	bench.Start("linksetup")
 	ctxt.linksetup()
@@ -422,6 +427,9 @@ func Main(arch *sys.Arch, theArch Arch) {
 	ctxt.dodata(symGroupType)
 	bench.Start("address")
 	order := ctxt.address()
+	if *flagSizeJSON != "" {
+		writeSizeReport(ctxt, *flagSizeJSON)
+	}
 	bench.Start("dwarfcompress")
 	dwarfcompress(ctxt)
 	bench.Start("layout")
@@ -526,6 +526,21 @@ func numPCData(ldr *loader.Loader, s loader.Sym, fi loader.FuncInfo) uint32 {
 	return numPCData
 }
 
+// numFuncData returns the number of FuncData syms for the FuncInfo.
+// NB: Preload must be called on valid FuncInfos before calling this function.
+func numFuncData(ldr *loader.Loader, s loader.Sym, fi loader.FuncInfo) uint32 {
+	if !fi.Valid() {
+		return 0
+	}
+	numFuncData := uint32(ldr.NumFuncdata(s))
+	if fi.NumInlTree() > 0 {
+		if numFuncData < abi.FUNCDATA_InlTree+1 {
+			numFuncData = abi.FUNCDATA_InlTree + 1
+		}
+	}
+	return numFuncData
+}
+
 // generateFunctab creates the runtime.functab
 //
 // runtime.functab contains two things:
@@ -586,14 +601,8 @@ func (state pclntab) calculateFunctabSize(ctxt *Link, funcs []loader.Sym) (int64
 		size += funcSize
 		if fi.Valid() {
 			fi.Preload()
-			numFuncData := ldr.NumFuncdata(s)
-			if fi.NumInlTree() > 0 {
-				if numFuncData < abi.FUNCDATA_InlTree+1 {
-					numFuncData = abi.FUNCDATA_InlTree + 1
-				}
-			}
 			size += int64(numPCData(ldr, s, fi) * 4)
-			size += int64(numFuncData * 4)
+			size += int64(numFuncData(ldr, s, fi) * 4)
 		}
 	}
 
@@ -0,0 +1,240 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"cmd/link/internal/loader"
+	"cmd/link/internal/sym"
+	"encoding/json"
+	"os"
+	"sort"
+)
+
+// A sizeReport is the JSON report written by the -sizejson flag.
+type sizeReport struct {
+	Sections []sizeSection // output sections, in address order
+	Packages []sizePackage // packages, largest first
+}
+
+// A sizeSection describes the size of an output section.
+type sizeSection struct {
+	Name string
+	Size int64
+}
+
+// A sizePackage attributes the symbols in the binary to a package.
+//
+// The parts of the pclntab generated for each function (its function
+// table entry, pc-value tables, inlining tree, and name) are attributed
+// to the function's package. The rest of the pclntab, such as its header
+// and file name tables, stays with the linker-synthesized package "".
+// Funcdata symbols like GC maps are shared between identical functions
+// and are attributed to the package whose object file defined them.
+type sizePackage struct {
+	Path  string           // import path, or "" for symbols synthesized by the linker
+	Size  int64            // total size of the package's symbols
+	Kinds map[string]int64 // size by symbol kind (see symSizeKind)
+	// Why is the shortest import chain from the main package to this
+	// package, starting with "main" and ending with Path. It is empty
+	// for packages the linker loads on its own, like runtime when it
+	// is not imported.
+	Why []string `json:",omitempty"`
+}
+
+// symSizeKind returns the kind of s for the purposes of the size report:
+// one of "text", "rodata", "type", "pclntab", "data", "bss", "dwarf", or
+// "other".
+func symSizeKind(ldr *loader.Loader, s loader.Sym) string {
+	if ldr.IsGoType(s) || ldr.IsItab(s) {
+		return "type"
+	}
+	switch t := ldr.SymType(s); {
+	case t == sym.STEXT:
+		return "text"
+	case t == sym.STYPE, t == sym.STYPERELRO, t == sym.STYPELINK, t == sym.SITABLINK:
+		return "type"
+	case t == sym.SPCLNTAB, t == sym.SFUNCTAB, t == sym.SFUNCTABRELRO, t == sym.SGOFUNC, t == sym.SGOFUNCRELRO:
+		return "pclntab"
+	case t >= sym.SDWARFSECT && t <= sym.SDWARFLINES:
+		return "dwarf"
+	case t == sym.SBSS, t == sym.SNOPTRBSS, t == sym.STLSBSS, t == sym.SLIBFUZZER_8BIT_COUNTER, t == sym.SCOVERAGE_COUNTER:
+		return "bss"
+	case t > sym.STEXT && t < sym.SFirstWritable:
+		return "rodata"
+	case t > sym.SFirstWritable && t < sym.SDWARFSECT:
+		return "data"
+	}
+	return "other"
+}
+
+// writeSizeReport writes a report attributing the size of the output to
+// packages and symbol kinds to the named file. It must run after address
+// assignment, when symbol and section sizes are final.
+// DWARF sizes are reported before compression.
+func writeSizeReport(ctxt *Link, file string) {
+	ldr := ctxt.loader
+	var report sizeReport
+
+	for _, seg := range []*sym.Segment{&Segtext, &Segrodata, &Segrelrodata, &Segdata, &Segdwarf, &Segpdata, &Segxdata} {
+		for _, sect := range seg.Sections {
+			report.Sections = append(report.Sections, sizeSection{Name: sect.Name, Size: int64(sect.Length)})
+		}
+	}
+
+	// Symbols that carry or contain other symbols are accounted for
+	// by their contents.
+	containers := make(map[loader.Sym]bool)
+	for s := loader.Sym(1); s < loader.Sym(ldr.NSym()); s++ {
+		if ldr.AttrReachable(s) {
+			if outer := ldr.OuterSym(s); outer != 0 {
+				containers[outer] = true
+			}
+		}
+	}
+
+	pkgs := make(map[string]*sizePackage)
+	add := func(path, kind string, size int64) {
+		p := pkgs[path]
+		if p == nil {
+			p = &sizePackage{Path: path, Kinds: make(map[string]int64)}
+			pkgs[path] = p
+		}
+		p.Size += size
+		p.Kinds[kind] += size
+	}
+	for s := loader.Sym(1); s < loader.Sym(ldr.NSym()); s++ {
+		if !ldr.AttrReachable(s) || containers[s] {
+			continue
+		}
+		// Count only symbols laid out in the output,
+		// directly or as part of a carrier symbol.
+		placed := s
+		if outer := ldr.OuterSym(s); outer != 0 {
+			placed = outer
+		}
+		size := ldr.SymSize(s)
+		if size == 0 || ldr.SymSect(placed) == nil {
+			continue
+		}
+		kind := symSizeKind(ldr, s)
+		if ldr.SymSect(placed).Name == ".gopclntab" {
+			kind = "pclntab"
+		}
+		add(ldr.SymPkg(s), kind, size)
+	}
+
+	// The pclntab and the inlining trees are generated by the linker.
+	// Move the parts describing each function to the function's package.
+	for path, size := range pclntabSizes(ctxt) {
+		if path != "" && size != 0 {
+			add("", "pclntab", -size)
+			add(path, "pclntab", size)
+		}
+	}
+
+	why := importChains(ctxt)
+	for _, p := range pkgs {
+		p.Why = why[p.Path]
+		report.Packages = append(report.Packages, *p)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool {
+		pi, pj := report.Packages[i], report.Packages[j]
+		if pi.Size != pj.Size {
+			return pi.Size > pj.Size
+		}
+		return pi.Path < pj.Path
+	})
+
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		Exitf("writing size report: %v", err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0666); err != nil {
+		Exitf("writing size report: %v", err)
+	}
+}
+
+// pclntabSizes returns, for each package, the number of bytes of the
+// pclntab and of the inlining trees generated by the linker that describe
+// the package's functions: their entries in the function table, their
+// pc-value tables and inlining trees, and their names. A pc-value table
+// shared by several functions is attributed to the first of them, as it
+// is laid out only once.
+func pclntabSizes(ctxt *Link) map[string]int64 {
+	ldr := ctxt.loader
+	container := ctxt.findContainerSyms()
+	var funcs []loader.Sym
+	for _, s := range ctxt.Textp {
+		if emitPcln(ctxt, s, container) {
+			funcs = append(funcs, s)
+		}
+	}
+
+	sizes := make(map[string]int64)
+
+	// runtime.funcnametab holds the names of the functions
+	// and of the functions inlined into them.
+	walkFuncs(ctxt, funcs, func(s loader.Sym) {
+		sizes[ldr.SymPkg(s)] += int64(len(ldr.SymName(s)) + 1)
+	})
+
+	seen := make(map[loader.Sym]bool)
+	var pcsp, pcfile, pcline, pcinline loader.Sym
+	var pcdata []loader.Sym
+	for _, s := range funcs {
+		pkg := ldr.SymPkg(s)
+
+		// runtime.functab holds a pc->func table entry and a func object.
+		sizes[pkg] += 2*4 + funcSize
+
+		fi := ldr.FuncInfo(s)
+		if !fi.Valid() {
+			continue
+		}
+		fi.Preload()
+		sizes[pkg] += int64(numPCData(ldr, s, fi)*4 + numFuncData(ldr, s, fi)*4)
+
+		// runtime.pctab holds the deduplicated pc-value tables.
+		pcsp, pcfile, pcline, pcinline, pcdata = ldr.PcdataAuxs(s, pcdata)
+		pcSyms := append([]loader.Sym{pcsp, pcfile, pcline}, pcdata...)
+		if n := fi.NumInlTree(); n > 0 {
+			pcSyms = append(pcSyms, pcinline)
+			sizes[pkg] += int64(n) * 16 // sizeof(runtime.inlinedCall)
+		}
+		for _, pcSym := range pcSyms {
+			if !seen[pcSym] {
+				seen[pcSym] = true
+				sizes[pkg] += ldr.SymSize(pcSym)
+			}
+		}
+	}
+	return sizes
+}
+
+// importChains returns, for each package reachable from the main package
+// through imports, the shortest import chain from the main package to it.
+func importChains(ctxt *Link) map[string][]string {
+	chains := make(map[string][]string)
+	var queue []*sym.Library
+	for _, lib := range ctxt.Library {
+		if lib.Main {
+			chains[lib.Pkg] = []string{lib.Pkg}
+			queue = append(queue, lib)
+		}
+	}
+	for len(queue) > 0 {
+		lib := queue[0]
+		queue = queue[1:]
+		for _, imp := range lib.Imports {
+			if _, ok := chains[imp.Pkg]; ok {
+				continue
+			}
+			chain := chains[lib.Pkg]
+			chains[imp.Pkg] = append(chain[:len(chain):len(chain)], imp.Pkg)
+			queue = append(queue, imp)
+		}
+	}
+	return chains
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"encoding/json"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSizeReport(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "main.go")
+	err := os.WriteFile(src, []byte(`package main
+
+import "fmt"
+
+var table [1 << 16]byte
+
+func main() { table[1]++; fmt.Println(table[1]) }
+`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := filepath.Join(tmpdir, "size.json")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-sizejson="+report, "-o", filepath.Join(tmpdir, "main.exe"), src)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+	}
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r sizeReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	var sectTotal, pkgTotal int64
+	for _, s := range r.Sections {
+		sectTotal += s.Size
+	}
+	pkgs := make(map[string]sizePackage)
+	for i, p := range r.Packages {
+		if i > 0 && p.Size > r.Packages[i-1].Size {
+			t.Errorf("package %s (%d bytes) sorted after %s (%d bytes)", p.Path, p.Size, r.Packages[i-1].Path, r.Packages[i-1].Size)
+		}
+		var kindTotal int64
+		for kind, n := range p.Kinds {
+			if n < 0 {
+				t.Errorf("package %s: %s has negative size %d", p.Path, kind, n)
+			}
+			kindTotal += n
+		}
+		if kindTotal != p.Size {
+			t.Errorf("package %s: kinds add up to %d bytes, want %d", p.Path, kindTotal, p.Size)
+		}
+		pkgTotal += p.Size
+		pkgs[p.Path] = p
+	}
+	if pkgTotal > sectTotal {
+		t.Errorf("packages add up to %d bytes, more than the %d bytes in sections", pkgTotal, sectTotal)
+	}
+
+	main, ok := pkgs["main"]
+	if !ok {
+		t.Fatalf("no entry for package main in report:\n%s", data)
+	}
+	if main.Kinds["text"] == 0 || main.Kinds["bss"] < 1<<16 || main.Kinds["pclntab"] == 0 {
+		t.Errorf("package main kinds = %v, want text, pclntab and at least %d bytes of bss", main.Kinds, 1<<16)
+	}
+	if want := []string{"main", "fmt"}; !slices.Equal(pkgs["fmt"].Why, want) {
+		t.Errorf("fmt Why = %q, want %q", pkgs["fmt"].Why, want)
+	}
+	if why := pkgs["strconv"].Why; len(why) != 3 || why[0] != "main" || why[2] != "strconv" {
+		t.Errorf("strconv Why = %q, want [main <pkg> strconv]", why)
+	}
+	// The pclntab header and file tables are not attributed to packages,
+	// but they are much smaller than the per-function parts.
+	if n := pkgs[""].Kinds["pclntab"]; n == 0 || n > pkgs["runtime"].Kinds["pclntab"] {
+		t.Errorf("linker-synthesized kinds = %v, want some pclntab, but less than runtime's %d bytes", pkgs[""].Kinds, pkgs["runtime"].Kinds["pclntab"])
+	}
+}
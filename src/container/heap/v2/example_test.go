@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap_test
+
+import (
+	"container/heap/v2"
+	"fmt"
+)
+
+// This example pushes some integers onto a heap and
+// removes them in increasing order.
+func Example_intHeap() {
+	h := heap.New[int]()
+	h.Init([]int{2, 1, 5})
+	h.Push(3)
+	fmt.Printf("minimum: %d\n", h.Min())
+	for v := range h.Drain() {
+		fmt.Printf("%d ", v)
+	}
+	// Output:
+	// minimum: 1
+	// 1 2 3 5
+}
+
+// An Item is something we manage in a priority queue.
+type Item struct {
+	value    string // The value of the item; arbitrary.
+	priority int    // The priority of the item in the queue.
+	index    int    // The index of the item in the heap, maintained by the heap.
+}
+
+// This example creates a priority queue with some items, adds and
+// manipulates an item, and then removes the items in priority order.
+func Example_priorityQueue() {
+	// Some items and their priorities.
+	items := map[string]int{
+		"banana": 3, "apple": 2, "pear": 4,
+	}
+
+	// Create a priority queue that pops the highest priority first
+	// and keeps each item's index up to date.
+	pq := heap.NewIndexed(
+		func(a, b *Item) int { return b.priority - a.priority },
+		func(item *Item, i int) { item.index = i },
+	)
+	for value, priority := range items {
+		pq.Push(&Item{value: value, priority: priority})
+	}
+
+	// Insert a new item and then modify its priority.
+	item := &Item{value: "orange", priority: 1}
+	pq.Push(item)
+	item.priority = 5
+	pq.Fix(item.index)
+
+	// Take the items out; they arrive in decreasing priority order.
+	for item := range pq.Drain() {
+		fmt.Printf("%.2d:%s ", item.priority, item.value)
+	}
+	// Output:
+	// 05:orange 04:pear 03:banana 02:apple
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package heap provides a generic min-heap data structure.
+// A heap is a tree with the property that each node is the
+// minimum-valued node in its subtree.
+//
+// It is a type-safe replacement for the container/heap package:
+// instead of implementing an interface, a client creates a [Heap]
+// holding values of any type, ordered by a comparison function.
+// Values need neither boxing in an interface value nor type assertions.
+//
+// A heap is a common way to implement a priority queue. To build a
+// priority queue, order the values by (negative) priority, so Push adds
+// items while Pop removes the highest-priority item from the queue.
+// To change the priority of an item already in the queue, create the
+// heap with [NewIndexed] so that the item can track its index.
+package heap
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A Heap is a min-heap of values of type T.
+// The zero value is not usable; create heaps with [New], [NewFunc]
+// or [NewIndexed].
+type Heap[T any] struct {
+	s        []T
+	cmp      func(T, T) int
+	setIndex func(T, int) // may be nil
+}
+
+// New returns an empty heap of values of an ordered type,
+// ordered according to [cmp.Compare].
+func New[T cmp.Ordered]() *Heap[T] {
+	return &Heap[T]{cmp: cmp.Compare[T]}
+}
+
+// NewFunc returns an empty heap of values ordered according
+// to the comparison function cmp, which must return a negative
+// number when a < b, a positive number when a > b and zero when
+// a == b or a and b are incomparable. The heap's minimum is a
+// value v for which cmp(v, w) <= 0 for every value w in the heap.
+func NewFunc[T any](cmp func(a, b T) int) *Heap[T] {
+	return &Heap[T]{cmp: cmp}
+}
+
+// NewIndexed is like [NewFunc], but the heap also calls setIndex(v, i)
+// whenever value v moves to index i in the heap, including when it is
+// first added. When v is removed from the heap, setIndex(v, -1) is
+// called. The indexes may be passed to [Heap.Fix] and [Heap.Remove].
+func NewIndexed[T any](cmp func(a, b T) int, setIndex func(v T, i int)) *Heap[T] {
+	return &Heap[T]{cmp: cmp, setIndex: setIndex}
+}
+
+// Init replaces the contents of h with the values in s and
+// establishes the heap ordering. The heap takes ownership of s,
+// which must not be used by the caller afterward.
+// The complexity is O(n) where n = len(s).
+func (h *Heap[T]) Init(s []T) {
+	h.clear()
+	h.s = s
+	if h.setIndex != nil {
+		for i, v := range h.s {
+			h.setIndex(v, i)
+		}
+	}
+	n := len(h.s)
+	for i := n/2 - 1; i >= 0; i-- {
+		h.down(i, n)
+	}
+}
+
+// Len returns the number of values in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.s)
+}
+
+// Push adds the value v to the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Push(v T) {
+	h.s = append(h.s, v)
+	n := len(h.s) - 1
+	if h.setIndex != nil {
+		h.setIndex(v, n)
+	}
+	h.up(n)
+}
+
+// Min returns the minimum value in the heap without removing it.
+// It panics if the heap is empty.
+// The complexity is O(1).
+func (h *Heap[T]) Min() T {
+	if len(h.s) == 0 {
+		panic("heap: Min of empty heap")
+	}
+	return h.s[0]
+}
+
+// Pop removes and returns the minimum value in the heap.
+// It panics if the heap is empty.
+// The complexity is O(log n) where n = h.Len().
+// Pop is equivalent to h.Remove(0).
+func (h *Heap[T]) Pop() T {
+	if len(h.s) == 0 {
+		panic("heap: Pop of empty heap")
+	}
+	return h.Remove(0)
+}
+
+// Remove removes and returns the value at index i from the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Remove(i int) T {
+	n := len(h.s) - 1
+	if n != i {
+		h.swap(i, n)
+		if !h.down(i, n) {
+			h.up(i)
+		}
+	}
+	v := h.s[n]
+	var zero T
+	h.s[n] = zero // avoid memory leaks
+	h.s = h.s[:n]
+	if h.setIndex != nil {
+		h.setIndex(v, -1)
+	}
+	return v
+}
+
+// Fix re-establishes the heap ordering after the value at index i has
+// changed. Changing the value at index i and then calling Fix is
+// equivalent to, but less expensive than, calling h.Remove(i) followed
+// by a Push of the new value.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[T]) Fix(i int) {
+	if !h.down(i, len(h.s)) {
+		h.up(i)
+	}
+}
+
+// Clear removes all values from the heap.
+func (h *Heap[T]) Clear() {
+	h.clear()
+	h.s = h.s[:0]
+}
+
+// clear zeroes the values in the heap, reporting their
+// removal to setIndex.
+func (h *Heap[T]) clear() {
+	if h.setIndex != nil {
+		for _, v := range h.s {
+			h.setIndex(v, -1)
+		}
+	}
+	clear(h.s)
+}
+
+// All returns an iterator over the values in the heap, in an
+// unspecified order. The heap must not be modified during iteration.
+func (h *Heap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range h.s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Drain returns an iterator that removes values from the heap and
+// yields them in increasing order, until the heap is empty or the
+// iteration stops. Values may be pushed onto the heap during
+// iteration, and are yielded in order with the other values.
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for len(h.s) > 0 {
+			if !yield(h.Pop()) {
+				return
+			}
+		}
+	}
+}
+
+func (h *Heap[T]) less(i, j int) bool {
+	return h.cmp(h.s[i], h.s[j]) < 0
+}
+
+func (h *Heap[T]) swap(i, j int) {
+	h.s[i], h.s[j] = h.s[j], h.s[i]
+	if h.setIndex != nil {
+		h.setIndex(h.s[i], i)
+		h.setIndex(h.s[j], j)
+	}
+}
+
+func (h *Heap[T]) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !h.less(j, i) {
+			break
+		}
+		h.swap(i, j)
+		j = i
+	}
+}
+
+func (h *Heap[T]) down(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && h.less(j2, j1) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !h.less(j, i) {
+			break
+		}
+		h.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
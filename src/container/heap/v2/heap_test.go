@@ -0,0 +1,305 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	heapv1 "container/heap"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func (h *Heap[T]) verify(t *testing.T, i int) {
+	t.Helper()
+	n := h.Len()
+	j1 := 2*i + 1
+	j2 := 2*i + 2
+	if j1 < n {
+		if h.less(j1, i) {
+			t.Errorf("heap invariant invalidated [%d] = %v > [%d] = %v", i, h.s[i], j1, h.s[j1])
+			return
+		}
+		h.verify(t, j1)
+	}
+	if j2 < n {
+		if h.less(j2, i) {
+			t.Errorf("heap invariant invalidated [%d] = %v > [%d] = %v", i, h.s[i], j2, h.s[j2])
+			return
+		}
+		h.verify(t, j2)
+	}
+}
+
+func TestInit0(t *testing.T) {
+	h := New[int]()
+	h.Init(make([]int, 20)) // all elements are the same
+	h.verify(t, 0)
+
+	for i := 1; h.Len() > 0; i++ {
+		x := h.Pop()
+		h.verify(t, 0)
+		if x != 0 {
+			t.Errorf("%d.th pop got %d; want %d", i, x, 0)
+		}
+	}
+}
+
+func TestInit1(t *testing.T) {
+	h := New[int]()
+	var s []int
+	for i := 20; i > 0; i-- {
+		s = append(s, i) // all elements are different
+	}
+	h.Init(s)
+	h.verify(t, 0)
+
+	for i := 1; h.Len() > 0; i++ {
+		x := h.Pop()
+		h.verify(t, 0)
+		if x != i {
+			t.Errorf("%d.th pop got %d; want %d", i, x, i)
+		}
+	}
+}
+
+func TestPush(t *testing.T) {
+	h := New[int]()
+	h.verify(t, 0)
+
+	for i := 20; i > 10; i-- {
+		h.Push(i)
+	}
+	h.verify(t, 0)
+
+	for i := 10; i > 0; i-- {
+		h.Push(i)
+		h.verify(t, 0)
+	}
+
+	for i := 1; h.Len() > 0; i++ {
+		if m := h.Min(); m != i {
+			t.Errorf("%d.th min got %d; want %d", i, m, i)
+		}
+		x := h.Pop()
+		if i < 20 {
+			h.Push(20 + i)
+		}
+		h.verify(t, 0)
+		if x != i {
+			t.Errorf("%d.th pop got %d; want %d", i, x, i)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	h := New[int]()
+	for i := 0; i < 10; i++ {
+		h.Push(i)
+	}
+	h.verify(t, 0)
+
+	for h.Len() > 0 {
+		i := h.Len() - 1
+		x := h.Remove(i)
+		if x != i {
+			t.Errorf("Remove(%d) got %d; want %d", i, x, i)
+		}
+		h.verify(t, 0)
+	}
+
+	for i := 0; i < 10; i++ {
+		h.Push(i)
+	}
+	h.Remove(0)
+	h.Remove(5)
+	h.verify(t, 0)
+	h.Remove(h.Len() / 2)
+	h.verify(t, 0)
+}
+
+func TestFix(t *testing.T) {
+	h := New[int]()
+	h.verify(t, 0)
+
+	for i := 200; i > 0; i -= 10 {
+		h.Push(i)
+	}
+	h.verify(t, 0)
+
+	if h.s[0] != 10 {
+		t.Fatalf("Expected head to be 10, was %d", h.s[0])
+	}
+	h.s[0] = 210
+	h.Fix(0)
+	h.verify(t, 0)
+
+	for i := 100; i > 0; i-- {
+		elem := rand.IntN(h.Len())
+		if i&1 == 0 {
+			h.s[elem] *= 2
+		} else {
+			h.s[elem] /= 2
+		}
+		h.Fix(elem)
+		h.verify(t, 0)
+	}
+}
+
+type item struct {
+	value    string
+	priority int
+	index    int
+}
+
+func TestIndexed(t *testing.T) {
+	h := NewIndexed(func(a, b *item) int { return b.priority - a.priority },
+		func(it *item, i int) { it.index = i })
+	checkIndexes := func() {
+		t.Helper()
+		for i, it := range h.s {
+			if it.index != i {
+				t.Errorf("item %q has index %d, is at %d", it.value, it.index, i)
+			}
+		}
+	}
+
+	items := []*item{{"a", 3, 0}, {"b", 2, 0}, {"c", 4, 0}, {"d", 1, 0}}
+	h.Init(slices.Clone(items))
+	h.verify(t, 0)
+	checkIndexes()
+
+	e := &item{"e", 0, 0}
+	h.Push(e)
+	checkIndexes()
+
+	// Raise the priority of e above all others.
+	e.priority = 5
+	h.Fix(e.index)
+	h.verify(t, 0)
+	checkIndexes()
+
+	// Remove b.
+	if it := h.Remove(items[1].index); it != items[1] {
+		t.Errorf("Remove(b.index) = %q; want b", it.value)
+	}
+	if items[1].index != -1 {
+		t.Errorf("removed item has index %d; want -1", items[1].index)
+	}
+	checkIndexes()
+
+	var got []string
+	for it := range h.Drain() {
+		got = append(got, it.value)
+		if it.index != -1 {
+			t.Errorf("popped item %q has index %d; want -1", it.value, it.index)
+		}
+		checkIndexes()
+	}
+	if want := []string{"e", "c", "a", "d"}; !slices.Equal(got, want) {
+		t.Errorf("Drain() yielded %v; want %v", got, want)
+	}
+
+	h.Init([]*item{items[0], items[1]})
+	h.Clear()
+	if h.Len() != 0 || items[0].index != -1 || items[1].index != -1 {
+		t.Errorf("after Clear, Len() = %d, indexes %d, %d; want 0, -1, -1", h.Len(), items[0].index, items[1].index)
+	}
+}
+
+func TestIter(t *testing.T) {
+	h := NewFunc(func(a, b int) int { return b - a }) // max-heap
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		h.Push(v)
+	}
+
+	got := slices.Sorted(h.All())
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("All() yielded %v; want %v", got, want)
+	}
+	if h.Len() != 5 {
+		t.Errorf("All() modified heap: Len() = %d; want 5", h.Len())
+	}
+
+	got = got[:0]
+	for v := range h.Drain() {
+		got = append(got, v)
+		if v == 4 {
+			h.Push(10) // larger than the rest, yielded next
+		}
+		if v == 3 {
+			break
+		}
+	}
+	if want := []int{5, 4, 10, 3}; !slices.Equal(got, want) {
+		t.Errorf("Drain() yielded %v; want %v", got, want)
+	}
+	if got := slices.Collect(h.Drain()); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("Drain() after break yielded %v; want [2 1]", got)
+	}
+}
+
+func TestEmptyPanics(t *testing.T) {
+	h := New[string]()
+	for _, f := range []func(){
+		func() { h.Pop() },
+		func() { h.Min() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic on empty heap")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+// intHeap is the container/heap implementation of a heap of ints,
+// for comparison in the benchmarks.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// The benchmarks compare Heap with the container/heap package it replaces.
+
+func BenchmarkDup(b *testing.B) {
+	const n = 10000
+	b.Run("v1", func(b *testing.B) {
+		h := make(intHeap, 0, n)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				heapv1.Push(&h, 1000) // all elements are the same
+			}
+			for h.Len() > 0 {
+				heapv1.Pop(&h)
+			}
+		}
+	})
+	b.Run("v2", func(b *testing.B) {
+		h := New[int]()
+		h.Init(make([]int, 0, n))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				h.Push(1000) // all elements are the same
+			}
+			for h.Len() > 0 {
+				h.Pop()
+			}
+		}
+	})
+}
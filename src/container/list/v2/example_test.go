@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package list_test
+
+import (
+	"container/list/v2"
+	"fmt"
+)
+
+func Example() {
+	// Create a new list and put some numbers in it.
+	l := list.New[int]()
+	e4 := l.PushBack(4)
+	e1 := l.PushFront(1)
+	l.InsertBefore(3, e4)
+	l.InsertAfter(2, e1)
+
+	// Remove the odd numbers.
+	for e := range l.All() {
+		if e.Value%2 == 1 {
+			l.Remove(e)
+		}
+	}
+
+	// Iterate through list and print its contents.
+	for v := range l.Values() {
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 2
+	// 4
+}
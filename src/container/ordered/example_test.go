@@ -0,0 +1,57 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ordered_test
+
+import (
+	"container/ordered"
+	"fmt"
+)
+
+func ExampleMap() {
+	m := ordered.NewMap[string, int]()
+	m.Set("banana", 3)
+	m.Set("apple", 7)
+	m.Set("cherry", 5)
+	m.Set("apple", 1)
+
+	for k, v := range m.All() {
+		fmt.Println(k, v)
+	}
+	if k, v, ok := m.Max(); ok {
+		fmt.Println("max:", k, v)
+	}
+	// Output:
+	// apple 1
+	// banana 3
+	// cherry 5
+	// max: cherry 5
+}
+
+func ExampleMap_Range() {
+	m := ordered.NewMap[int, string]()
+	for i, s := range []string{"zero", "one", "two", "three", "four", "five"} {
+		m.Set(i, s)
+	}
+	for k, v := range m.Range(2, 5) {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// 2 two
+	// 3 three
+	// 4 four
+}
+
+func ExampleSet() {
+	s := ordered.NewSet[int]()
+	for _, v := range []int{5, 2, 8, 2, 1} {
+		s.Add(v)
+	}
+	for v := range s.Backward() {
+		fmt.Print(v, " ")
+	}
+	fmt.Println()
+	// Output:
+	// 8 5 2 1
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ordered implements maps and sets whose keys are kept in
+// sorted order, using balanced binary trees.
+//
+// Unlike Go's built-in maps, a [Map] or [Set] iterates over its keys
+// in increasing order, can iterate over just the keys in a range, and
+// can find its minimum and maximum keys, all in time proportional to
+// the logarithm of its size plus the number of keys visited.
+//
+// Keys are ordered by a comparison function. [NewMap] and [NewSet]
+// use [cmp.Compare] for keys of ordered types; [NewMapFunc] and
+// [NewSetFunc] accept an arbitrary comparison function.
+//
+// A Map or Set must not be modified during iteration over it.
+// Maps and sets are not safe for concurrent use by multiple goroutines
+// without additional synchronization.
+package ordered
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A Map is a map from keys of type K to values of type V,
+// ordered by key.
+// The zero value is not usable; create maps with [NewMap] or [NewMapFunc].
+type Map[K, V any] struct {
+	root *node[K, V]
+	len  int
+	cmp  func(K, K) int
+}
+
+// node is a node of an AVL tree.
+type node[K, V any] struct {
+	key         K
+	val         V
+	left, right *node[K, V]
+	height      int // height of the subtree rooted at this node
+}
+
+// NewMap returns an empty map with keys of an ordered type,
+// ordered according to [cmp.Compare].
+func NewMap[K cmp.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{cmp: cmp.Compare[K]}
+}
+
+// NewMapFunc returns an empty map with keys ordered according to
+// the comparison function cmp, which must return a negative number
+// when a < b, a positive number when a > b and zero when a == b.
+// Keys for which cmp returns zero are the same key.
+func NewMapFunc[K, V any](cmp func(a, b K) int) *Map[K, V] {
+	return &Map[K, V]{cmp: cmp}
+}
+
+// Len returns the number of keys in m.
+func (m *Map[K, V]) Len() int {
+	return m.len
+}
+
+// Get returns the value for key, and reports whether key is in m.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	n := m.root
+	for n != nil {
+		c := m.cmp(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Set sets the value for key to val.
+func (m *Map[K, V]) Set(key K, val V) {
+	m.root = m.insert(m.root, key, val)
+}
+
+// Delete removes key from m, and reports whether key was in m.
+func (m *Map[K, V]) Delete(key K) bool {
+	n := m.len
+	m.root = m.delete(m.root, key)
+	return m.len < n
+}
+
+// Clear removes all keys from m.
+func (m *Map[K, V]) Clear() {
+	m.root = nil
+	m.len = 0
+}
+
+// Min returns the smallest key in m and its value.
+// If m is empty, ok is false.
+func (m *Map[K, V]) Min() (key K, val V, ok bool) {
+	n := m.root
+	if n == nil {
+		return
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.val, true
+}
+
+// Max returns the largest key in m and its value.
+// If m is empty, ok is false.
+func (m *Map[K, V]) Max() (key K, val V, ok bool) {
+	n := m.root
+	if n == nil {
+		return
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.val, true
+}
+
+// All returns an iterator over the key-value pairs in m,
+// in increasing order of keys.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.ascend(yield)
+	}
+}
+
+// Backward returns an iterator over the key-value pairs in m,
+// in decreasing order of keys.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.descend(yield)
+	}
+}
+
+// Keys returns an iterator over the keys in m, in increasing order.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.root.ascend(func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Values returns an iterator over the values in m,
+// in increasing order of their keys.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.root.ascend(func(_ K, v V) bool { return yield(v) })
+	}
+}
+
+// Range returns an iterator over the key-value pairs in m whose keys
+// k satisfy lo <= k < hi, in increasing order of keys.
+func (m *Map[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascendRange(m.root, lo, hi, yield)
+	}
+}
+
+// ascend calls yield for each key-value pair in the subtree
+// rooted at n, in increasing order, until yield returns false.
+// It reports whether the iteration should continue.
+func (n *node[K, V]) ascend(yield func(K, V) bool) bool {
+	for n != nil {
+		if !n.left.ascend(yield) || !yield(n.key, n.val) {
+			return false
+		}
+		n = n.right
+	}
+	return true
+}
+
+// descend is like ascend, in decreasing order.
+func (n *node[K, V]) descend(yield func(K, V) bool) bool {
+	for n != nil {
+		if !n.right.descend(yield) || !yield(n.key, n.val) {
+			return false
+		}
+		n = n.left
+	}
+	return true
+}
+
+// ascendRange is like ascend, but only visits keys in [lo, hi).
+func (m *Map[K, V]) ascendRange(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	for n != nil {
+		if m.cmp(n.key, lo) < 0 {
+			// Everything to the left is out of range.
+			n = n.right
+			continue
+		}
+		if !m.ascendRange(n.left, lo, hi, yield) {
+			return false
+		}
+		if m.cmp(n.key, hi) >= 0 {
+			// Everything to the right is out of range.
+			return true
+		}
+		if !yield(n.key, n.val) {
+			return false
+		}
+		n = n.right
+	}
+	return true
+}
+
+func (n *node[K, V]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// fixHeight recomputes n.height from the heights of its children.
+func (n *node[K, V]) fixHeight() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+}
+
+func (n *node[K, V]) rotateLeft() *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.fixHeight()
+	r.fixHeight()
+	return r
+}
+
+func (n *node[K, V]) rotateRight() *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.fixHeight()
+	l.fixHeight()
+	return l
+}
+
+// balance restores the AVL property at n, whose subtrees are balanced
+// and differ in height by at most 2, and returns the new subtree root.
+func (n *node[K, V]) balance() *node[K, V] {
+	n.fixHeight()
+	switch bf := n.left.getHeight() - n.right.getHeight(); {
+	case bf > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// insert sets key to val in the subtree rooted at n
+// and returns the new subtree root.
+func (m *Map[K, V]) insert(n *node[K, V], key K, val V) *node[K, V] {
+	if n == nil {
+		m.len++
+		return &node[K, V]{key: key, val: val, height: 1}
+	}
+	c := m.cmp(key, n.key)
+	switch {
+	case c < 0:
+		n.left = m.insert(n.left, key, val)
+	case c > 0:
+		n.right = m.insert(n.right, key, val)
+	default:
+		n.val = val
+		return n
+	}
+	return n.balance()
+}
+
+// delete removes key from the subtree rooted at n
+// and returns the new subtree root.
+func (m *Map[K, V]) delete(n *node[K, V], key K) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	c := m.cmp(key, n.key)
+	switch {
+	case c < 0:
+		n.left = m.delete(n.left, key)
+	case c > 0:
+		n.right = m.delete(n.right, key)
+	default:
+		m.len--
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		// Replace n by the smallest node in its right subtree.
+		right, succ := n.right.deleteMin()
+		succ.left, succ.right = n.left, right
+		n = succ
+	}
+	return n.balance()
+}
+
+// deleteMin removes the smallest node from the subtree rooted at n,
+// and returns the new subtree root and the removed node.
+func (n *node[K, V]) deleteMin() (root, minNode *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	n.left, minNode = n.left.deleteMin()
+	return n.balance(), minNode
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ordered
+
+import (
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// check verifies the structure of m: its keys are in order, the tree
+// is balanced with correct heights, and Len matches the node count.
+func (m *Map[K, V]) check(t *testing.T) {
+	t.Helper()
+	var count int
+	var walk func(n *node[K, V]) int
+	walk = func(n *node[K, V]) int {
+		if n == nil {
+			return 0
+		}
+		count++
+		if n.left != nil && m.cmp(n.left.key, n.key) >= 0 {
+			t.Fatalf("left child %v >= parent %v", n.left.key, n.key)
+		}
+		if n.right != nil && m.cmp(n.right.key, n.key) <= 0 {
+			t.Fatalf("right child %v <= parent %v", n.right.key, n.key)
+		}
+		hl, hr := walk(n.left), walk(n.right)
+		if hl-hr > 1 || hr-hl > 1 {
+			t.Fatalf("unbalanced node %v: heights %d, %d", n.key, hl, hr)
+		}
+		if h := 1 + max(hl, hr); n.height != h {
+			t.Fatalf("node %v has height %d, want %d", n.key, n.height, h)
+		}
+		return n.height
+	}
+	walk(m.root)
+	if count != m.Len() {
+		t.Fatalf("Len() = %d, but tree has %d nodes", m.Len(), count)
+	}
+}
+
+type kv struct {
+	k, v int
+}
+
+func collect2(seq iter.Seq2[int, int]) []kv {
+	var s []kv
+	for k, v := range seq {
+		s = append(s, kv{k, v})
+	}
+	return s
+}
+
+func TestMapRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	m := NewMap[int, int]()
+	want := make(map[int]int)
+	for i := range 5000 {
+		k := r.IntN(500)
+		switch r.IntN(3) {
+		case 0, 1:
+			m.Set(k, i)
+			want[k] = i
+		case 2:
+			_, present := want[k]
+			if got := m.Delete(k); got != present {
+				t.Fatalf("Delete(%d) = %v, want %v", k, got, present)
+			}
+			delete(want, k)
+		}
+		if i%100 == 0 {
+			m.check(t)
+		}
+		v, ok := m.Get(k)
+		wv, wok := want[k]
+		if v != wv || ok != wok {
+			t.Fatalf("Get(%d) = %d, %v, want %d, %v", k, v, ok, wv, wok)
+		}
+	}
+	m.check(t)
+
+	keys := slices.Sorted(maps.Keys(want))
+	var wantAll []kv
+	for _, k := range keys {
+		wantAll = append(wantAll, kv{k, want[k]})
+	}
+	if got := collect2(m.All()); !slices.Equal(got, wantAll) {
+		t.Errorf("All() = %v, want %v", got, wantAll)
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, keys) {
+		t.Errorf("Keys() = %v, want %v", got, keys)
+	}
+	var wantValues []int
+	for _, k := range keys {
+		wantValues = append(wantValues, want[k])
+	}
+	if got := slices.Collect(m.Values()); !slices.Equal(got, wantValues) {
+		t.Errorf("Values() = %v, want %v", got, wantValues)
+	}
+	slices.Reverse(wantAll)
+	if got := collect2(m.Backward()); !slices.Equal(got, wantAll) {
+		t.Errorf("Backward() = %v, want %v", got, wantAll)
+	}
+
+	if k, v, ok := m.Min(); !ok || k != keys[0] || v != want[k] {
+		t.Errorf("Min() = %d, %d, %v, want %d, %d, true", k, v, ok, keys[0], want[keys[0]])
+	}
+	if k, v, ok := m.Max(); !ok || k != keys[len(keys)-1] || v != want[k] {
+		t.Errorf("Max() = %d, %d, %v, want %d, %d, true", k, v, ok, keys[len(keys)-1], want[keys[len(keys)-1]])
+	}
+
+	for range 100 {
+		lo, hi := r.IntN(600)-50, r.IntN(600)-50
+		var wantRange []kv
+		for _, k := range keys {
+			if lo <= k && k < hi {
+				wantRange = append(wantRange, kv{k, want[k]})
+			}
+		}
+		if got := collect2(m.Range(lo, hi)); !slices.Equal(got, wantRange) {
+			t.Fatalf("Range(%d, %d) = %v, want %v", lo, hi, got, wantRange)
+		}
+	}
+
+	m.Clear()
+	m.check(t)
+	if _, _, ok := m.Min(); ok || m.Len() != 0 {
+		t.Errorf("after Clear, Len() = %d, Min() ok = %v", m.Len(), ok)
+	}
+}
+
+func TestMapSequential(t *testing.T) {
+	// Sequential insertion and deletion are the classic worst
+	// cases for unbalanced trees.
+	m := NewMap[int, int]()
+	const n = 1 << 12
+	for i := range n {
+		m.Set(i, -i)
+	}
+	m.check(t)
+	if h := m.root.height; h > 13 {
+		t.Errorf("height of tree with %d keys = %d, want <= 13", n, h)
+	}
+	for i := range n / 2 {
+		m.Delete(i)
+	}
+	m.check(t)
+	if k, v, ok := m.Min(); !ok || k != n/2 || v != -n/2 {
+		t.Errorf("Min() = %d, %d, %v, want %d, %d, true", k, v, ok, n/2, -n/2)
+	}
+}
+
+func TestMapBreak(t *testing.T) {
+	m := NewMap[int, int]()
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	seqs := map[string]iter.Seq2[int, int]{
+		"All":      m.All(),
+		"Backward": m.Backward(),
+		"Range":    m.Range(10, 90),
+	}
+	for name, seq := range seqs {
+		n := 0
+		for range seq {
+			n++
+			if n == 5 {
+				break
+			}
+		}
+		if n != 5 {
+			t.Errorf("%s: got %d pairs before break, want 5", name, n)
+		}
+	}
+}
+
+func TestMapFunc(t *testing.T) {
+	m := NewMapFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	m.Set("b", 1)
+	m.Set("A", 2)
+	m.Set("B", 3) // same key as "b"
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+	var got []string
+	for k, v := range m.All() {
+		got = append(got, k+"="+string(rune('0'+v)))
+	}
+	if want := []string{"A=2", "b=3"}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	m := NewMap[string, int]()
+	if v, ok := m.Get("x"); ok || v != 0 {
+		t.Errorf("Get on empty map = %d, %v", v, ok)
+	}
+	if m.Delete("x") {
+		t.Errorf("Delete on empty map = true")
+	}
+	if _, _, ok := m.Max(); ok {
+		t.Errorf("Max on empty map: ok = true")
+	}
+	for k := range m.All() {
+		t.Errorf("All on empty map yielded %q", k)
+	}
+}
+
+// The benchmarks compare Map with a built-in map whose keys are
+// sorted for ordered iteration.
+
+func BenchmarkOrderedIteration(b *testing.B) {
+	const n = 1000
+	keys := rand.Perm(n)
+	b.Run("map", func(b *testing.B) {
+		m := make(map[int]int)
+		for _, k := range keys {
+			m[k] = k
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for _, k := range slices.Sorted(maps.Keys(m)) {
+				sum += m[k]
+			}
+		}
+	})
+	b.Run("ordered", func(b *testing.B) {
+		m := NewMap[int, int]()
+		for _, k := range keys {
+			m.Set(k, k)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for _, v := range m.All() {
+				sum += v
+			}
+		}
+	})
+}
+
+func BenchmarkSet(b *testing.B) {
+	const n = 1000
+	keys := rand.Perm(n)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := NewMap[int, int]()
+		for _, k := range keys {
+			m.Set(k, k)
+		}
+	}
+}
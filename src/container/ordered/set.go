@@ -0,0 +1,90 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ordered
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A Set is a set of keys of type K, ordered by key.
+// The zero value is not usable; create sets with [NewSet] or [NewSetFunc].
+type Set[K any] struct {
+	m Map[K, struct{}]
+}
+
+// NewSet returns an empty set of keys of an ordered type,
+// ordered according to [cmp.Compare].
+func NewSet[K cmp.Ordered]() *Set[K] {
+	return &Set[K]{Map[K, struct{}]{cmp: cmp.Compare[K]}}
+}
+
+// NewSetFunc returns an empty set of keys ordered according to
+// the comparison function cmp, as for [NewMapFunc].
+func NewSetFunc[K any](cmp func(a, b K) int) *Set[K] {
+	return &Set[K]{Map[K, struct{}]{cmp: cmp}}
+}
+
+// Len returns the number of keys in s.
+func (s *Set[K]) Len() int {
+	return s.m.Len()
+}
+
+// Contains reports whether key is in s.
+func (s *Set[K]) Contains(key K) bool {
+	_, ok := s.m.Get(key)
+	return ok
+}
+
+// Add adds key to s, and reports whether it was not already in s.
+func (s *Set[K]) Add(key K) bool {
+	n := s.m.len
+	s.m.Set(key, struct{}{})
+	return s.m.len > n
+}
+
+// Delete removes key from s, and reports whether key was in s.
+func (s *Set[K]) Delete(key K) bool {
+	return s.m.Delete(key)
+}
+
+// Clear removes all keys from s.
+func (s *Set[K]) Clear() {
+	s.m.Clear()
+}
+
+// Min returns the smallest key in s.
+// If s is empty, ok is false.
+func (s *Set[K]) Min() (key K, ok bool) {
+	key, _, ok = s.m.Min()
+	return
+}
+
+// Max returns the largest key in s.
+// If s is empty, ok is false.
+func (s *Set[K]) Max() (key K, ok bool) {
+	key, _, ok = s.m.Max()
+	return
+}
+
+// All returns an iterator over the keys in s, in increasing order.
+func (s *Set[K]) All() iter.Seq[K] {
+	return s.m.Keys()
+}
+
+// Backward returns an iterator over the keys in s, in decreasing order.
+func (s *Set[K]) Backward() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.m.root.descend(func(k K, _ struct{}) bool { return yield(k) })
+	}
+}
+
+// Range returns an iterator over the keys k in s that satisfy
+// lo <= k < hi, in increasing order.
+func (s *Set[K]) Range(lo, hi K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.m.ascendRange(s.m.root, lo, hi, func(k K, _ struct{}) bool { return yield(k) })
+	}
+}
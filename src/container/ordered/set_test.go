@@ -0,0 +1,71 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ordered
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet[string]()
+	for _, k := range []string{"pear", "apple", "fig", "apple", "kiwi"} {
+		s.Add(k)
+	}
+	if s.Add("fig") {
+		t.Errorf("Add of existing key = true")
+	}
+	if !s.Add("date") {
+		t.Errorf("Add of new key = false")
+	}
+	s.m.check(t)
+
+	want := []string{"apple", "date", "fig", "kiwi", "pear"}
+	if got := slices.Collect(s.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if s.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", s.Len(), len(want))
+	}
+	if got := slices.Collect(s.Backward()); !slices.Equal(got, []string{"pear", "kiwi", "fig", "date", "apple"}) {
+		t.Errorf("Backward() = %v", got)
+	}
+	if got := slices.Collect(s.Range("b", "g")); !slices.Equal(got, []string{"date", "fig"}) {
+		t.Errorf(`Range("b", "g") = %v, want [date fig]`, got)
+	}
+	if k, ok := s.Min(); !ok || k != "apple" {
+		t.Errorf("Min() = %q, %v, want apple, true", k, ok)
+	}
+	if k, ok := s.Max(); !ok || k != "pear" {
+		t.Errorf("Max() = %q, %v, want pear, true", k, ok)
+	}
+
+	if !s.Contains("kiwi") || s.Contains("lime") {
+		t.Errorf("Contains(kiwi), Contains(lime) = %v, %v, want true, false", s.Contains("kiwi"), s.Contains("lime"))
+	}
+	if !s.Delete("kiwi") || s.Delete("kiwi") || s.Contains("kiwi") {
+		t.Errorf("Delete did not remove kiwi exactly once")
+	}
+	s.m.check(t)
+
+	s.Clear()
+	if _, ok := s.Min(); ok || s.Len() != 0 {
+		t.Errorf("after Clear, Len() = %d, Min() ok = %v", s.Len(), ok)
+	}
+}
+
+func TestSetFunc(t *testing.T) {
+	// Order by decreasing value.
+	s := NewSetFunc(func(a, b int) int { return b - a })
+	for _, k := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		s.Add(k)
+	}
+	if got, want := slices.Collect(s.All()), []int{9, 6, 5, 4, 3, 2, 1}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(s.Range(5, 2)), []int{5, 4, 3}; !slices.Equal(got, want) {
+		t.Errorf("Range(5, 2) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ring_test
+
+import (
+	"container/ring/v2"
+	"fmt"
+)
+
+func Example() {
+	// Create a new ring of size 5
+	r := ring.New[int](5)
+
+	// Initialize the ring with some integer values
+	i := 0
+	for e := range r.All() {
+		e.Value = i
+		i++
+	}
+
+	// Move the pointer forward by three steps and
+	// print the values, starting there
+	for v := range r.Move(3).Values() {
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 3
+	// 4
+	// 0
+	// 1
+	// 2
+}
@@ -0,0 +1,642 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package acme implements the ACME protocol for automatic certificate
+// management, as described in RFC 8555.
+//
+// Most programs need only a [Manager], which obtains and renews
+// certificates on demand and is installed as [crypto/tls.Config.GetCertificate].
+// The [Client] type provides lower-level access to an ACME server.
+//
+// Both the HTTP-01 (RFC 8555, Section 8.3) and TLS-ALPN-01 (RFC 8737)
+// challenge types are supported.
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LetsEncryptURL is the directory URL of Let's Encrypt's production CA.
+const LetsEncryptURL = "https://acme-v02.api.letsencrypt.org/directory"
+
+// Status values of ACME objects.
+const (
+	StatusPending     = "pending"
+	StatusReady       = "ready"
+	StatusProcessing  = "processing"
+	StatusValid       = "valid"
+	StatusInvalid     = "invalid"
+	StatusDeactivated = "deactivated"
+	StatusExpired     = "expired"
+	StatusRevoked     = "revoked"
+)
+
+// maxResponseSize bounds the size of responses read from the server.
+const maxResponseSize = 5 << 20
+
+// errNoKey is returned by methods that need the account key
+// when Client.Key is nil.
+var errNoKey = errors.New("acme: Client.Key is nil")
+
+// A Client is an ACME client for a single account.
+//
+// A Client's methods may be called concurrently.
+type Client struct {
+	// Key is the account key, used to sign all requests.
+	// It must be an RSA, ECDSA P-256 or ECDSA P-384 key.
+	Key crypto.Signer
+
+	// DirectoryURL is the URL of the CA's directory.
+	// If empty, LetsEncryptURL is used.
+	DirectoryURL string
+
+	// HTTPClient is used to make requests to the CA.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// UserAgent, if not empty, is added to the User-Agent header of requests.
+	UserAgent string
+
+	mu     sync.Mutex
+	dir    *Directory
+	kid    string   // account URL
+	nonces []string // unused nonces
+}
+
+// A Directory lists the resources of an ACME server.
+type Directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+	RevokeCert string `json:"revokeCert"`
+	KeyChange  string `json:"keyChange"`
+	Meta       struct {
+		TermsOfService string `json:"termsOfService"`
+		Website        string `json:"website"`
+	} `json:"meta"`
+}
+
+// An Account is an ACME account.
+type Account struct {
+	URI     string   `json:"-"`
+	Status  string   `json:"status"`
+	Contact []string `json:"contact"`
+	Orders  string   `json:"orders"`
+}
+
+// An Identifier is a name a certificate is requested for.
+type Identifier struct {
+	Type  string `json:"type"` // "dns"
+	Value string `json:"value"`
+}
+
+// An Order is a request for a certificate.
+type Order struct {
+	URI         string       `json:"-"`
+	Status      string       `json:"status"`
+	Expires     time.Time    `json:"expires"`
+	Identifiers []Identifier `json:"identifiers"`
+	AuthzURLs   []string     `json:"authorizations"`
+	FinalizeURL string       `json:"finalize"`
+	CertURL     string       `json:"certificate"`
+	Error       *Error       `json:"error"`
+}
+
+// An Authorization is the server's record of the client's authority
+// over an identifier.
+type Authorization struct {
+	URI        string       `json:"-"`
+	Status     string       `json:"status"`
+	Expires    time.Time    `json:"expires"`
+	Identifier Identifier   `json:"identifier"`
+	Challenges []*Challenge `json:"challenges"`
+	Wildcard   bool         `json:"wildcard"`
+}
+
+// A Challenge is a way of proving control of an identifier.
+type Challenge struct {
+	Type   string `json:"type"`
+	URI    string `json:"url"`
+	Token  string `json:"token"`
+	Status string `json:"status"`
+	Error  *Error `json:"error"`
+}
+
+// An Error is an ACME problem document (RFC 8555, Section 6.7),
+// returned by the server or recorded in an object.
+type Error struct {
+	// StatusCode is the HTTP status code of the response,
+	// or zero for errors recorded in objects.
+	StatusCode  int    `json:"status"`
+	ProblemType string `json:"type"`
+	Detail      string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("acme: %d %s: %s", e.StatusCode, e.ProblemType, e.Detail)
+	}
+	return fmt.Sprintf("acme: %s: %s", e.ProblemType, e.Detail)
+}
+
+const badNonceProblem = "urn:ietf:params:acme:error:badNonce"
+
+// Discover fetches the server's directory.
+// Other methods call Discover as needed.
+func (c *Client) Discover(ctx context.Context) (*Directory, error) {
+	c.mu.Lock()
+	dir := c.dir
+	c.mu.Unlock()
+	if dir != nil {
+		return dir, nil
+	}
+	url := c.DirectoryURL
+	if url == "" {
+		url = LetsEncryptURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	dir = new(Directory)
+	if err := decodeResponse(res, http.StatusOK, dir); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.dir = dir
+	c.mu.Unlock()
+	return dir, nil
+}
+
+// Register creates an account with the CA, or finds the existing account
+// for the client's key. The contact URLs are typically "mailto:" addresses.
+// Registering requires agreeing to the CA's terms of service.
+func (c *Client) Register(ctx context.Context, contact []string, agreeToTerms bool) (*Account, error) {
+	if c.Key == nil {
+		return nil, errNoKey
+	}
+	dir, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := struct {
+		Contact              []string `json:"contact,omitempty"`
+		TermsOfServiceAgreed bool     `json:"termsOfServiceAgreed,omitempty"`
+	}{contact, agreeToTerms}
+	res, err := c.post(ctx, dir.NewAccount, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	acct := new(Account)
+	if err := decodeResponse(res, http.StatusCreated, acct); err != nil {
+		return nil, err
+	}
+	acct.URI = res.Header.Get("Location")
+	if acct.URI == "" {
+		return nil, errors.New("acme: account response has no Location")
+	}
+	c.mu.Lock()
+	c.kid = acct.URI
+	c.mu.Unlock()
+	return acct, nil
+}
+
+// AuthorizeOrder creates an order for a certificate for the given
+// DNS names.
+func (c *Client) AuthorizeOrder(ctx context.Context, names ...string) (*Order, error) {
+	dir, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := struct {
+		Identifiers []Identifier `json:"identifiers"`
+	}{}
+	for _, name := range names {
+		req.Identifiers = append(req.Identifiers, Identifier{Type: "dns", Value: name})
+	}
+	res, err := c.post(ctx, dir.NewOrder, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	o := new(Order)
+	if err := decodeResponse(res, http.StatusCreated, o); err != nil {
+		return nil, err
+	}
+	o.URI = res.Header.Get("Location")
+	return o, nil
+}
+
+// GetOrder fetches an order.
+func (c *Client) GetOrder(ctx context.Context, url string) (*Order, error) {
+	o := new(Order)
+	if err := c.postAsGet(ctx, url, o); err != nil {
+		return nil, err
+	}
+	o.URI = url
+	return o, nil
+}
+
+// GetAuthorization fetches an authorization.
+func (c *Client) GetAuthorization(ctx context.Context, url string) (*Authorization, error) {
+	a := new(Authorization)
+	if err := c.postAsGet(ctx, url, a); err != nil {
+		return nil, err
+	}
+	a.URI = url
+	return a, nil
+}
+
+// Accept informs the server that the client is ready for the challenge
+// to be validated. The response to the challenge must be in place first.
+func (c *Client) Accept(ctx context.Context, chal *Challenge) (*Challenge, error) {
+	res, err := c.post(ctx, chal.URI, struct{}{}, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	ch := new(Challenge)
+	if err := decodeResponse(res, http.StatusOK, ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// WaitAuthorization polls an authorization until it is no longer pending.
+// It returns an error if the authorization does not become valid.
+func (c *Client) WaitAuthorization(ctx context.Context, url string) (*Authorization, error) {
+	for {
+		a, err := c.GetAuthorization(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		switch a.Status {
+		case StatusValid:
+			return a, nil
+		case StatusPending:
+		default:
+			for _, ch := range a.Challenges {
+				if ch.Error != nil {
+					return nil, fmt.Errorf("acme: authorization for %s is %s: %w", a.Identifier.Value, a.Status, ch.Error)
+				}
+			}
+			return nil, fmt.Errorf("acme: authorization for %s is %s", a.Identifier.Value, a.Status)
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WaitOrder polls an order until it is ready to be finalized or valid.
+// It returns an error if the order becomes invalid.
+func (c *Client) WaitOrder(ctx context.Context, url string) (*Order, error) {
+	for {
+		o, err := c.GetOrder(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		switch o.Status {
+		case StatusReady, StatusValid:
+			return o, nil
+		case StatusPending, StatusProcessing:
+		default:
+			if o.Error != nil {
+				return nil, fmt.Errorf("acme: order is %s: %w", o.Status, o.Error)
+			}
+			return nil, fmt.Errorf("acme: order is %s", o.Status)
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// CreateOrderCert finalizes the order at orderURL, which must be ready,
+// by sending the DER-encoded certificate signing request csr to its
+// finalizeURL. It polls the order until the certificate is issued and
+// returns it as a chain of DER-encoded certificates, leaf first.
+func (c *Client) CreateOrderCert(ctx context.Context, orderURL, finalizeURL string, csr []byte) ([][]byte, error) {
+	req := struct {
+		CSR string `json:"csr"`
+	}{base64.RawURLEncoding.EncodeToString(csr)}
+	res, err := c.post(ctx, finalizeURL, req, false)
+	if err != nil {
+		return nil, err
+	}
+	o := new(Order)
+	err = decodeResponse(res, http.StatusOK, o)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	for o.Status != StatusValid {
+		switch o.Status {
+		case StatusReady, StatusProcessing:
+		default:
+			if o.Error != nil {
+				return nil, fmt.Errorf("acme: order is %s: %w", o.Status, o.Error)
+			}
+			return nil, fmt.Errorf("acme: order is %s", o.Status)
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return nil, err
+		}
+		if o, err = c.GetOrder(ctx, orderURL); err != nil {
+			return nil, err
+		}
+	}
+	return c.FetchCert(ctx, o.CertURL)
+}
+
+// FetchCert downloads a certificate chain, returning its
+// DER-encoded certificates, leaf first.
+func (c *Client) FetchCert(ctx context.Context, url string) ([][]byte, error) {
+	res, err := c.post(ctx, url, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, responseError(res)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	var chain [][]byte
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+		if b.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("acme: unexpected %s block in certificate chain", b.Type)
+		}
+		chain = append(chain, b.Bytes)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("acme: no certificates in response")
+	}
+	return chain, nil
+}
+
+// KeyAuthorization returns the key authorization for token,
+// as defined in RFC 8555, Section 8.1.
+func (c *Client) KeyAuthorization(token string) (string, error) {
+	if c.Key == nil {
+		return "", errNoKey
+	}
+	th, err := JWKThumbprint(c.Key.Public())
+	if err != nil {
+		return "", err
+	}
+	return token + "." + th, nil
+}
+
+// HTTP01ChallengePath returns the URL path at which the response
+// to an HTTP-01 challenge with the given token must be served.
+func HTTP01ChallengePath(token string) string {
+	return "/.well-known/acme-challenge/" + token
+}
+
+// HTTP01ChallengeResponse returns the body to serve for
+// an HTTP-01 challenge with the given token.
+func (c *Client) HTTP01ChallengeResponse(token string) (string, error) {
+	return c.KeyAuthorization(token)
+}
+
+// ALPNProto is the ALPN protocol name used by the TLS-ALPN-01 challenge.
+// A server answering TLS-ALPN-01 challenges must list it in
+// crypto/tls.Config.NextProtos.
+const ALPNProto = "acme-tls/1"
+
+// idPeACMEIdentifier is the OID of the acmeIdentifier certificate
+// extension (RFC 8737, Section 6.1).
+var idPeACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+// TLSALPN01ChallengeCert returns the certificate to present for a
+// TLS-ALPN-01 challenge with the given token, for connections that
+// negotiate [ALPNProto] with the given server name.
+func (c *Client) TLSALPN01ChallengeCert(token, domain string) (tls.Certificate, error) {
+	keyAuth, err := c.KeyAuthorization(token)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	digest := sha256.Sum256([]byte(keyAuth))
+	extValue, err := asn1.Marshal(digest[:])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: domain},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{domain},
+		ExtraExtensions: []pkix.Extension{
+			{Id: idPeACMEIdentifier, Critical: true, Value: extValue},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// postAsGet fetches the object at url into v using a POST-as-GET request.
+func (c *Client) postAsGet(ctx context.Context, url string, v any) error {
+	res, err := c.post(ctx, url, nil, false)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return decodeResponse(res, http.StatusOK, v)
+}
+
+// post sends a JWS-signed POST request with the given payload.
+// If useJWK is set, the request is signed with the account's public key
+// rather than its account URL, as required for newAccount requests.
+// A request rejected because of a bad nonce is retried once.
+func (c *Client) post(ctx context.Context, url string, payload any, useJWK bool) (*http.Response, error) {
+	if c.Key == nil {
+		return nil, errNoKey
+	}
+	kid := ""
+	if !useJWK {
+		c.mu.Lock()
+		kid = c.kid
+		c.mu.Unlock()
+		if kid == "" {
+			return nil, errors.New("acme: client is not registered")
+		}
+	}
+	for retry := 0; ; retry++ {
+		nonce, err := c.nonce(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err := jwsEncodeJSON(payload, c.Key, kid, nonce, url)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/jose+json")
+		res, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode >= 400 {
+			err := responseError(res)
+			res.Body.Close()
+			if e, ok := err.(*Error); ok && e.ProblemType == badNonceProblem && retry == 0 {
+				continue
+			}
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+// nonce returns an unused nonce, fetching one if necessary.
+func (c *Client) nonce(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if n := len(c.nonces); n > 0 {
+		nonce := c.nonces[n-1]
+		c.nonces = c.nonces[:n-1]
+		c.mu.Unlock()
+		return nonce, nil
+	}
+	c.mu.Unlock()
+
+	dir, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "HEAD", dir.NewNonce, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+	nonce := res.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return "", errors.New("acme: server did not provide a nonce")
+	}
+	return nonce, nil
+}
+
+// maxNonces bounds the number of unused nonces a Client keeps.
+const maxNonces = 100
+
+// do sends req, recording the nonce in the response.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ua := "Go-acme"
+	if c.UserAgent != "" {
+		ua = c.UserAgent + " " + ua
+	}
+	req.Header.Set("User-Agent", ua)
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if nonce := res.Header.Get("Replay-Nonce"); nonce != "" {
+		c.mu.Lock()
+		if len(c.nonces) < maxNonces {
+			c.nonces = append(c.nonces, nonce)
+		}
+		c.mu.Unlock()
+	}
+	return res, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// decodeResponse decodes the JSON body of res into v.
+// It returns an error if the response is an error or has
+// an unexpected status. A 200 response is accepted in place
+// of a 201, since servers report existing objects with 200.
+func decodeResponse(res *http.Response, wantStatus int, v any) error {
+	if res.StatusCode >= 400 {
+		return responseError(res)
+	}
+	if res.StatusCode != wantStatus && res.StatusCode != http.StatusOK {
+		return fmt.Errorf("acme: unexpected response status %s", res.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("acme: decoding response: %w", err)
+	}
+	return nil
+}
+
+// responseError returns the error described by an error response.
+func responseError(res *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	e := &Error{}
+	if json.Unmarshal(data, e) != nil || e.ProblemType == "" {
+		e.Detail = string(bytes.TrimSpace(data))
+		e.ProblemType = "about:blank"
+	}
+	e.StatusCode = res.StatusCode
+	return e
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
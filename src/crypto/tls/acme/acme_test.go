@@ -0,0 +1,845 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCA is an in-process ACME server. It validates challenges by
+// calling the HTTP handler and TLS configuration of the server under test
+// directly, rather than by connecting to the challenged domain.
+type testCA struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	key  *ecdsa.PrivateKey
+	root *x509.Certificate
+
+	// challengeTypes are the challenge types offered in authorizations.
+	challengeTypes []string
+	// http and tls answer HTTP-01 and TLS-ALPN-01 challenges.
+	http http.Handler
+	tls  *tls.Config
+
+	mu       sync.Mutex
+	n        int
+	nonces   map[string]bool
+	badNonce bool // reject the next request with badNonce
+	deferred bool // answer finalize requests with a processing order and no Location
+	accounts map[string]*ecdsa.PublicKey
+	orders   map[string]*Order
+	authzs   map[string]*Authorization
+	certs    map[string][]byte
+	issued   int
+}
+
+func newTestCA(t *testing.T, challengeTypes ...string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test ACME CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &testCA{
+		t:              t,
+		key:            key,
+		root:           root,
+		challengeTypes: challengeTypes,
+		nonces:         make(map[string]bool),
+		accounts:       make(map[string]*ecdsa.PublicKey),
+		orders:         make(map[string]*Order),
+		authzs:         make(map[string]*Authorization),
+		certs:          make(map[string][]byte),
+	}
+	ca.srv = httptest.NewServer(http.HandlerFunc(ca.serveHTTP))
+	t.Cleanup(ca.srv.Close)
+	return ca
+}
+
+func (ca *testCA) url(path string) string { return ca.srv.URL + path }
+
+func (ca *testCA) client() *Client {
+	return &Client{DirectoryURL: ca.url("/directory"), HTTPClient: ca.srv.Client()}
+}
+
+// newID returns a new object path with the given prefix. ca.mu must be held.
+func (ca *testCA) newID(prefix string) string {
+	ca.n++
+	return fmt.Sprintf("/%s/%d", prefix, ca.n)
+}
+
+func (ca *testCA) newNonce() string {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.n++
+	nonce := fmt.Sprintf("nonce%d", ca.n)
+	ca.nonces[nonce] = true
+	return nonce
+}
+
+func (ca *testCA) problem(w http.ResponseWriter, status int, typ, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&Error{StatusCode: status, ProblemType: typ, Detail: detail})
+}
+
+func (ca *testCA) reply(w http.ResponseWriter, status int, location string, v any) {
+	if location != "" {
+		w.Header().Set("Location", ca.url(location))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (ca *testCA) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", ca.newNonce())
+	switch {
+	case r.URL.Path == "/directory":
+		ca.reply(w, http.StatusOK, "", map[string]string{
+			"newNonce":   ca.url("/new-nonce"),
+			"newAccount": ca.url("/new-account"),
+			"newOrder":   ca.url("/new-order"),
+		})
+		return
+	case r.URL.Path == "/new-nonce":
+		w.WriteHeader(http.StatusOK)
+		return
+	case r.Method != "POST":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, kid, pub, err := ca.verifyJWS(r)
+	if err != nil {
+		typ := "urn:ietf:params:acme:error:malformed"
+		if errors.Is(err, errTestBadNonce) {
+			typ = badNonceProblem
+		}
+		ca.problem(w, http.StatusBadRequest, typ, err.Error())
+		return
+	}
+
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	path := r.URL.Path
+	switch prefix, _, _ := strings.Cut(path[1:], "/"); prefix {
+	case "new-account":
+		if pub == nil {
+			ca.problem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed", "newAccount requires jwk")
+			return
+		}
+		var req struct {
+			TermsOfServiceAgreed bool `json:"termsOfServiceAgreed"`
+		}
+		json.Unmarshal(payload, &req)
+		if !req.TermsOfServiceAgreed {
+			ca.problem(w, http.StatusForbidden, "urn:ietf:params:acme:error:userActionRequired", "must agree to terms")
+			return
+		}
+		for id, p := range ca.accounts {
+			if p.Equal(pub) {
+				ca.reply(w, http.StatusOK, id, &Account{Status: StatusValid})
+				return
+			}
+		}
+		id := ca.newID("account")
+		ca.accounts[id] = pub
+		ca.reply(w, http.StatusCreated, id, &Account{Status: StatusValid})
+
+	case "new-order":
+		var req struct {
+			Identifiers []Identifier `json:"identifiers"`
+		}
+		if err := json.Unmarshal(payload, &req); err != nil || len(req.Identifiers) == 0 {
+			ca.problem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed", "bad order")
+			return
+		}
+		o := &Order{Status: StatusPending, Identifiers: req.Identifiers}
+		for _, ident := range req.Identifiers {
+			id := ca.newID("authz")
+			a := &Authorization{Status: StatusPending, Identifier: ident}
+			for _, typ := range ca.challengeTypes {
+				a.Challenges = append(a.Challenges, &Challenge{
+					Type:   typ,
+					URI:    ca.url(ca.newID("chal") + id),
+					Token:  base64.RawURLEncoding.EncodeToString([]byte(ca.newID("token"))),
+					Status: StatusPending,
+				})
+			}
+			ca.authzs[id] = a
+			o.AuthzURLs = append(o.AuthzURLs, ca.url(id))
+		}
+		id := ca.newID("order")
+		o.FinalizeURL = ca.url("/finalize" + id)
+		ca.orders[id] = o
+		ca.reply(w, http.StatusCreated, id, o)
+
+	case "authz":
+		a, ok := ca.authzs[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		ca.reply(w, http.StatusOK, "", a)
+
+	case "chal":
+		// Challenge paths are /chal/N/authz/M.
+		i := strings.Index(path, "/authz/")
+		a := ca.authzs[path[i:]]
+		for _, ch := range a.Challenges {
+			if ch.URI != ca.url(path) {
+				continue
+			}
+			// Validate synchronously, without holding the lock,
+			// since validation calls back into the server under test.
+			ca.mu.Unlock()
+			err := ca.validate(ch, a.Identifier.Value, kid)
+			ca.mu.Lock()
+			if err != nil {
+				ch.Status, a.Status = StatusInvalid, StatusInvalid
+				ch.Error = &Error{ProblemType: "urn:ietf:params:acme:error:unauthorized", Detail: err.Error()}
+			} else {
+				ch.Status, a.Status = StatusValid, StatusValid
+				ca.updateOrders()
+			}
+			ca.reply(w, http.StatusOK, "", ch)
+			return
+		}
+		http.NotFound(w, r)
+
+	case "order":
+		o, ok := ca.orders[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if o.Status == StatusProcessing {
+			o.Status = StatusValid
+		}
+		ca.reply(w, http.StatusOK, "", o)
+
+	case "finalize":
+		id := strings.TrimPrefix(path, "/finalize")
+		o, ok := ca.orders[id]
+		if !ok || o.Status != StatusReady {
+			ca.problem(w, http.StatusForbidden, "urn:ietf:params:acme:error:orderNotReady", "order not ready")
+			return
+		}
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		json.Unmarshal(payload, &req)
+		der, err := ca.issue(req.CSR, o.Identifiers)
+		if err != nil {
+			ca.problem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:badCSR", err.Error())
+			return
+		}
+		certID := ca.newID("cert")
+		ca.certs[certID] = der
+		o.Status, o.CertURL = StatusValid, ca.url(certID)
+		if ca.deferred {
+			// RFC 8555, Section 7.4: the CA may issue the certificate
+			// later, and need not send the order URL in a Location header.
+			o.Status, id = StatusProcessing, ""
+		}
+		ca.reply(w, http.StatusOK, id, o)
+
+	case "cert":
+		der, ok := ca.certs[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+		pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: ca.root.Raw})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// updateOrders marks orders whose authorizations are all valid as ready.
+func (ca *testCA) updateOrders() {
+	for _, o := range ca.orders {
+		if o.Status != StatusPending {
+			continue
+		}
+		ready := true
+		for _, u := range o.AuthzURLs {
+			if ca.authzs[strings.TrimPrefix(u, ca.srv.URL)].Status != StatusValid {
+				ready = false
+			}
+		}
+		if ready {
+			o.Status = StatusReady
+		}
+	}
+}
+
+var errTestBadNonce = errors.New("bad nonce")
+
+// verifyJWS checks the JWS body of r and returns its payload,
+// along with the account URL or embedded public key that signed it.
+func (ca *testCA) verifyJWS(r *http.Request) (payload []byte, kid string, jwk *ecdsa.PublicKey, err error) {
+	if ct := r.Header.Get("Content-Type"); ct != "application/jose+json" {
+		return nil, "", nil, fmt.Errorf("Content-Type %q", ct)
+	}
+	var jws struct{ Protected, Payload, Signature string }
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return nil, "", nil, err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	var header struct {
+		Alg, Nonce, URL, Kid string
+		JWK                  *struct{ Crv, Kty, X, Y string }
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return nil, "", nil, err
+	}
+
+	ca.mu.Lock()
+	validNonce := ca.nonces[header.Nonce] && !ca.badNonce
+	delete(ca.nonces, header.Nonce)
+	ca.badNonce = false
+	pub := ca.accounts[strings.TrimPrefix(header.Kid, ca.srv.URL)]
+	ca.mu.Unlock()
+
+	if !validNonce {
+		return nil, "", nil, errTestBadNonce
+	}
+	if header.URL != ca.url(r.URL.Path) {
+		return nil, "", nil, fmt.Errorf("url %q in header, request for %q", header.URL, r.URL.Path)
+	}
+	switch {
+	case header.JWK != nil && header.Kid != "":
+		return nil, "", nil, errors.New("both jwk and kid in header")
+	case header.JWK != nil:
+		if header.JWK.Kty != "EC" || header.JWK.Crv != "P-256" {
+			return nil, "", nil, fmt.Errorf("unsupported jwk %+v", header.JWK)
+		}
+		x, _ := base64.RawURLEncoding.DecodeString(header.JWK.X)
+		y, _ := base64.RawURLEncoding.DecodeString(header.JWK.Y)
+		jwk = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		pub = jwk
+	case pub == nil:
+		return nil, "", nil, fmt.Errorf("unknown account %q", header.Kid)
+	}
+	if header.Alg != "ES256" {
+		return nil, "", nil, fmt.Errorf("alg %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil || len(sig) != 64 {
+		return nil, "", nil, errors.New("malformed signature")
+	}
+	digest := sha256.Sum256([]byte(jws.Protected + "." + jws.Payload))
+	if !ecdsa.Verify(pub, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return nil, "", nil, errors.New("bad signature")
+	}
+	payload, err = base64.RawURLEncoding.DecodeString(jws.Payload)
+	return payload, header.Kid, jwk, err
+}
+
+// validate checks the response to the challenge ch for domain,
+// made by the account kid.
+func (ca *testCA) validate(ch *Challenge, domain, kid string) error {
+	ca.mu.Lock()
+	pub := ca.accounts[strings.TrimPrefix(kid, ca.srv.URL)]
+	ca.mu.Unlock()
+	th, err := JWKThumbprint(pub)
+	if err != nil {
+		return err
+	}
+	keyAuth := ch.Token + "." + th
+
+	switch ch.Type {
+	case "http-01":
+		req := httptest.NewRequest("GET", "http://"+domain+HTTP01ChallengePath(ch.Token), nil)
+		rec := httptest.NewRecorder()
+		ca.http.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Body.String() != keyAuth {
+			return fmt.Errorf("http-01 response %d %q, want %q", rec.Code, rec.Body, keyAuth)
+		}
+		return nil
+
+	case "tls-alpn-01":
+		c, s := net.Pipe()
+		defer c.Close()
+		go func() {
+			defer s.Close()
+			tls.Server(s, ca.tls).Handshake()
+		}()
+		conn := tls.Client(c, &tls.Config{
+			ServerName:         domain,
+			NextProtos:         []string{ALPNProto},
+			InsecureSkipVerify: true,
+		})
+		if err := conn.Handshake(); err != nil {
+			return err
+		}
+		cs := conn.ConnectionState()
+		if cs.NegotiatedProtocol != ALPNProto {
+			return fmt.Errorf("negotiated %q", cs.NegotiatedProtocol)
+		}
+		leaf := cs.PeerCertificates[0]
+		if err := leaf.VerifyHostname(domain); err != nil {
+			return err
+		}
+		want := sha256.Sum256([]byte(keyAuth))
+		for _, ext := range leaf.Extensions {
+			if !ext.Id.Equal(idPeACMEIdentifier) {
+				continue
+			}
+			var got []byte
+			if _, err := asn1.Unmarshal(ext.Value, &got); err != nil || !ext.Critical || !bytes.Equal(got, want[:]) {
+				return errors.New("bad acmeIdentifier extension")
+			}
+			return nil
+		}
+		return errors.New("no acmeIdentifier extension")
+	}
+	return fmt.Errorf("unknown challenge type %q", ch.Type)
+}
+
+// issue signs the base64url-encoded CSR, checking it is for exactly the
+// identifiers of the order. ca.mu must be held.
+func (ca *testCA) issue(b64 string, idents []Identifier) ([]byte, error) {
+	der, err := base64.RawURLEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	if len(csr.DNSNames) != len(idents) {
+		return nil, fmt.Errorf("CSR names %q do not match order", csr.DNSNames)
+	}
+	for i, name := range csr.DNSNames {
+		if name != idents[i].Value {
+			return nil, fmt.Errorf("CSR names %q do not match order", csr.DNSNames)
+		}
+	}
+	ca.issued++
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(ca.n)),
+		Subject:      pkix.Name{CommonName: csr.DNSNames[0]},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     csr.DNSNames,
+	}
+	return x509.CreateCertificate(rand.Reader, tmpl, ca.root, csr.PublicKey, ca.key)
+}
+
+func (ca *testCA) issuedCount() int {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	return ca.issued
+}
+
+// verify checks that cert was issued by ca for name.
+func (ca *testCA) verify(t *testing.T, cert *tls.Certificate, name string) {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.root)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: name, Roots: roots}); err != nil {
+		t.Errorf("certificate for %s: %v", name, err)
+	}
+}
+
+func hello(name string) *tls.ClientHelloInfo {
+	return &tls.ClientHelloInfo{ServerName: name}
+}
+
+func TestManagerTLSALPN01(t *testing.T) {
+	ca := newTestCA(t, "http-01", "tls-alpn-01")
+	dir := t.TempDir()
+	m := &Manager{Client: ca.client(), Cache: DirCache(dir), AgreeToTerms: true, Email: "admin@example.com"}
+	ca.tls = m.TLSConfig()
+
+	cert, err := m.GetCertificate(hello("Example.COM."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.verify(t, cert, "example.com")
+	again, err := m.GetCertificate(hello("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != cert {
+		t.Error("second GetCertificate did not return the same certificate")
+	}
+	if n := ca.issuedCount(); n != 1 {
+		t.Errorf("CA issued %d certificates, want 1", n)
+	}
+
+	// A new Manager with the same cache uses the cached certificate
+	// and account key.
+	if _, err := os.Stat(filepath.Join(dir, accountKeyName)); err != nil {
+		t.Errorf("account key not cached: %v", err)
+	}
+	m2 := &Manager{Client: ca.client(), Cache: DirCache(dir), AgreeToTerms: true}
+	cached, err := m2.GetCertificate(hello("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cached.Certificate[0], cert.Certificate[0]) {
+		t.Error("new Manager did not use the cached certificate")
+	}
+	if n := ca.issuedCount(); n != 1 {
+		t.Errorf("CA issued %d certificates, want 1", n)
+	}
+
+	// The ALPN challenge certificate is only served while a
+	// challenge is pending.
+	h := hello("example.com")
+	h.SupportedProtos = []string{ALPNProto}
+	if _, err := m.GetCertificate(h); err == nil {
+		t.Error("GetCertificate returned a challenge certificate with no challenge pending")
+	}
+}
+
+func TestManagerDeferredIssuance(t *testing.T) {
+	ca := newTestCA(t, "tls-alpn-01")
+	ca.deferred = true
+	m := &Manager{Client: ca.client(), AgreeToTerms: true}
+	ca.tls = m.TLSConfig()
+	cert, err := m.GetCertificate(hello("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.verify(t, cert, "example.com")
+}
+
+func TestManagerHTTP01(t *testing.T) {
+	ca := newTestCA(t, "http-01")
+	m := &Manager{Client: ca.client(), AgreeToTerms: true}
+
+	// Without an HTTP handler, HTTP-01 is not attempted.
+	if _, err := m.GetCertificate(hello("example.org")); err == nil {
+		t.Fatal("GetCertificate succeeded without a supported challenge")
+	}
+
+	ca.http = m.HTTPHandler(nil)
+	cert, err := m.GetCertificate(hello("example.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.verify(t, cert, "example.org")
+}
+
+func TestManagerHostPolicy(t *testing.T) {
+	ca := newTestCA(t, "tls-alpn-01")
+	m := &Manager{Client: ca.client(), AgreeToTerms: true, HostPolicy: HostWhitelist("allowed.example")}
+	ca.tls = m.TLSConfig()
+
+	if _, err := m.GetCertificate(hello("other.example")); err == nil {
+		t.Error("GetCertificate succeeded for a host not in the whitelist")
+	}
+	if _, err := m.GetCertificate(hello("ALLOWED.example")); err != nil {
+		t.Error(err)
+	}
+	for _, name := range []string{"", "../x", `a\b`} {
+		if _, err := m.GetCertificate(hello(name)); err == nil {
+			t.Errorf("GetCertificate(%q) succeeded", name)
+		}
+	}
+	if n := ca.issuedCount(); n != 1 {
+		t.Errorf("CA issued %d certificates, want 1", n)
+	}
+}
+
+// A countingCache is a Cache that counts its calls.
+type countingCache struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingCache) count() {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.count()
+	return nil, ErrCacheMiss
+}
+
+func (c *countingCache) Put(ctx context.Context, key string, data []byte) error {
+	c.count()
+	return nil
+}
+
+func (c *countingCache) Delete(ctx context.Context, key string) error {
+	c.count()
+	return nil
+}
+
+func TestManagerHostPolicyRejectsEarly(t *testing.T) {
+	cache := new(countingCache)
+	m := &Manager{
+		Client:     &Client{DirectoryURL: "http://invalid.test/directory"},
+		Cache:      cache,
+		HostPolicy: HostWhitelist("allowed.example"),
+	}
+	for i := range 100 {
+		name := fmt.Sprintf("random-%d.example", i)
+		if _, err := m.GetCertificate(hello(name)); err == nil {
+			t.Fatalf("GetCertificate(%q) succeeded", name)
+		}
+	}
+	if n := len(m.state); n != 0 {
+		t.Errorf("Manager keeps state for %d rejected names, want 0", n)
+	}
+	if cache.calls != 0 {
+		t.Errorf("Manager made %d cache calls for rejected names, want 0", cache.calls)
+	}
+}
+
+func TestManagerRenew(t *testing.T) {
+	ca := newTestCA(t, "tls-alpn-01")
+	// Certificates from the test CA are valid for a day,
+	// so they are always due for renewal.
+	m := &Manager{Client: ca.client(), AgreeToTerms: true, RenewBefore: 48 * time.Hour}
+	ca.tls = m.TLSConfig()
+
+	first, err := m.GetCertificate(hello("example.net"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The next handshake gets the current certificate
+	// and starts a renewal in the background.
+	if cert, err := m.GetCertificate(hello("example.net")); err != nil || cert != first {
+		t.Fatalf("GetCertificate = %p, %v; want current certificate %p", cert, err, first)
+	}
+	st := m.state["example.net"]
+	for {
+		st.mu.Lock()
+		cert, renewing := st.cert, st.renewing
+		st.mu.Unlock()
+		if !renewing {
+			if cert == first {
+				t.Fatal("certificate not replaced after renewal")
+			}
+			ca.verify(t, cert, "example.net")
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestManagerRegisterError(t *testing.T) {
+	ca := newTestCA(t, "tls-alpn-01")
+	m := &Manager{Client: ca.client()}
+	_, err := m.GetCertificate(hello("example.com"))
+	var e *Error
+	if !errors.As(err, &e) || e.StatusCode != http.StatusForbidden || !strings.HasSuffix(e.ProblemType, ":userActionRequired") {
+		t.Errorf("GetCertificate without agreeing to terms: %v", err)
+	}
+}
+
+func TestClientNoKey(t *testing.T) {
+	ca := newTestCA(t)
+	c := ca.client()
+	if _, err := c.Register(context.Background(), nil, true); err != errNoKey {
+		t.Errorf("Register with nil Key: got error %v, want %v", err, errNoKey)
+	}
+	if _, err := c.KeyAuthorization("token"); err != errNoKey {
+		t.Errorf("KeyAuthorization with nil Key: got error %v, want %v", err, errNoKey)
+	}
+	if _, err := c.GetOrder(context.Background(), ca.url("/order/1")); err != errNoKey {
+		t.Errorf("GetOrder with nil Key: got error %v, want %v", err, errNoKey)
+	}
+}
+
+func TestClientBadNonceRetry(t *testing.T) {
+	ca := newTestCA(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := ca.client()
+	c.Key = key
+	ca.badNonce = true
+	acct, err := c.Register(context.Background(), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(acct.URI, ca.url("/account/")) {
+		t.Errorf("account URI = %q", acct.URI)
+	}
+	// Registering again finds the existing account.
+	again, err := c.Register(context.Background(), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.URI != acct.URI {
+		t.Errorf("second Register: URI = %q, want %q", again.URI, acct.URI)
+	}
+}
+
+func TestHTTPHandlerFallback(t *testing.T) {
+	m := new(Manager)
+	h := m.HTTPHandler(nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "http://example.com:80/path?q=1", nil))
+	if loc := rec.Header().Get("Location"); rec.Code != http.StatusFound || loc != "https://example.com/path?q=1" {
+		t.Errorf("redirect = %d %q", rec.Code, loc)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "http://example.com"+HTTP01ChallengePath("unknown"), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown token: status %d, want 404", rec.Code)
+	}
+
+	h = m.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "fallback")
+	}))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "http://example.com/", nil))
+	if got := rec.Body.String(); got != "fallback" {
+		t.Errorf("body = %q, want fallback", got)
+	}
+}
+
+func TestJWSEncodeJSON(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := jwsEncodeJSON(map[string]string{"a": "b"}, key, "https://ca/acct/1", "n1", "https://ca/x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var jws struct{ Protected, Payload, Signature string }
+		if err := json.Unmarshal(b, &jws); err != nil {
+			t.Fatal(err)
+		}
+		head, _ := base64.RawURLEncoding.DecodeString(jws.Protected)
+		var header map[string]string
+		if err := json.Unmarshal(head, &header); err != nil {
+			t.Fatal(err)
+		}
+		alg, hash, _ := jwsAlgorithm(key)
+		want := map[string]string{"alg": alg, "kid": "https://ca/acct/1", "nonce": "n1", "url": "https://ca/x"}
+		if fmt.Sprint(header) != fmt.Sprint(want) {
+			t.Errorf("%s: header = %v, want %v", alg, header, want)
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(jws.Signature)
+		size := len(sig) / 2
+		h := hash.New()
+		h.Write([]byte(jws.Protected + "." + jws.Payload))
+		if !ecdsa.Verify(&key.PublicKey, h.Sum(nil), new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])) {
+			t.Errorf("%s: signature does not verify", alg)
+		}
+	}
+
+	if _, _, err := jwsAlgorithm(unsupportedSigner{}); err == nil {
+		t.Error("jwsAlgorithm accepted an unsupported key")
+	}
+}
+
+type unsupportedSigner struct{}
+
+func (unsupportedSigner) Public() crypto.PublicKey { return "key" }
+func (unsupportedSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, errors.New("unsupported")
+}
+
+func TestDirCache(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "certs")
+	c := DirCache(dir)
+	if _, err := c.Get(ctx, "example.com"); err != ErrCacheMiss {
+		t.Fatalf("Get on empty cache = %v, want ErrCacheMiss", err)
+	}
+	if err := c.Put(ctx, "example.com", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Get(ctx, "example.com"); err != nil || string(got) != "data" {
+		t.Errorf("Get = %q, %v; want data", got, err)
+	}
+	if fi, err := os.Stat(dir); err != nil || fi.Mode().Perm() != 0700 {
+		t.Errorf("cache directory: %v, %v", fi, err)
+	}
+
+	// Keys cannot escape the directory.
+	if err := c.Put(ctx, "../escape", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); err != nil {
+		t.Errorf("entry for ../escape not stored in cache directory: %v", err)
+	}
+
+	if err := c.Delete(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, "example.com"); err != ErrCacheMiss {
+		t.Errorf("Get after Delete = %v, want ErrCacheMiss", err)
+	}
+	if err := c.Delete(ctx, "example.com"); err != nil {
+		t.Errorf("Delete of missing key = %v", err)
+	}
+}
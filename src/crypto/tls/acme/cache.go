@@ -0,0 +1,87 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acme
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrCacheMiss is returned by a [Cache] when no data is stored under a key.
+var ErrCacheMiss = errors.New("acme: certificate cache miss")
+
+// A Cache stores certificates and account keys for a [Manager].
+//
+// Keys are domain names, or names ending in "+key" for account keys.
+// Stored data contains private keys and should be protected accordingly.
+// A Cache's methods may be called concurrently.
+type Cache interface {
+	// Get returns the data stored under key,
+	// or ErrCacheMiss if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores data under key, replacing any previous data.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the data stored under key.
+	// Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// DirCache is a [Cache] storing each entry in a file in a directory.
+// The directory is created with permissions 0700 if it does not exist.
+type DirCache string
+
+var _ Cache = DirCache("")
+
+func (d DirCache) path(key string) string {
+	// Clean the key as a rooted path, so that it cannot
+	// name a file outside the directory.
+	return filepath.Join(string(d), filepath.Clean("/"+key))
+}
+
+// Get implements [Cache.Get].
+func (d DirCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrCacheMiss
+	}
+	return data, err
+}
+
+// Put implements [Cache.Put]. The file is replaced atomically.
+func (d DirCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(string(d), "tmp-")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Rename(tmp, d.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// Delete implements [Cache.Delete].
+func (d DirCache) Delete(ctx context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
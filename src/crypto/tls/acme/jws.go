@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acme
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// jwsEncodeJSON signs payload with key and returns a JWS in the flattened
+// JSON serialization (RFC 7515, Section 7.2.2), as required by RFC 8555.
+// If kid is empty, the public key is embedded in the protected header as a
+// JWK; otherwise the header refers to the account URL kid.
+// A nil payload produces an empty payload, as used by POST-as-GET requests.
+func jwsEncodeJSON(payload any, key crypto.Signer, kid, nonce, url string) ([]byte, error) {
+	alg, hash, err := jwsAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+	header := map[string]any{
+		"alg": alg,
+		"url": url,
+	}
+	if nonce != "" {
+		header["nonce"] = nonce
+	}
+	if kid != "" {
+		header["kid"] = kid
+	} else {
+		jwk, err := jwkEncode(key.Public())
+		if err != nil {
+			return nil, err
+		}
+		header["jwk"] = json.RawMessage(jwk)
+	}
+	phead, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(phead)
+
+	var body string
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	h := hash.New()
+	h.Write([]byte(protected + "." + body))
+	sig, err := jwsSign(key, hash, h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}{
+		Protected: protected,
+		Payload:   body,
+		Signature: base64.RawURLEncoding.EncodeToString(sig),
+	})
+}
+
+// jwsAlgorithm returns the JWS algorithm name and hash for key.
+func jwsAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS256", crypto.SHA256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		}
+	}
+	return "", 0, fmt.Errorf("acme: unsupported key type %T", key.Public())
+}
+
+// jwsSign signs digest with key. ECDSA signatures are converted from
+// ASN.1 to the fixed-size r||s encoding JWS requires (RFC 7518, Section 3.4).
+func jwsSign(key crypto.Signer, hash crypto.Hash, digest []byte) ([]byte, error) {
+	sig, err := key.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return sig, nil
+	}
+	var v struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(sig, &v); err != nil || len(rest) != 0 {
+		return nil, errors.New("acme: malformed ECDSA signature")
+	}
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	out := make([]byte, 2*size)
+	v.R.FillBytes(out[:size])
+	v.S.FillBytes(out[size:])
+	return out, nil
+}
+
+// jwkEncode encodes pub as a JSON Web Key (RFC 7517), with its members
+// in lexicographic order and no whitespace, as required for computing
+// thumbprints (RFC 7638).
+func jwkEncode(pub crypto.PublicKey) (string, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		e := big.NewInt(int64(pub.E)).Bytes()
+		return fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, b64(e), b64(pub.N.Bytes())), nil
+	case *ecdsa.PublicKey:
+		p := pub.Curve.Params()
+		size := (p.BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		pub.X.FillBytes(x)
+		pub.Y.FillBytes(y)
+		return fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, p.Name, b64(x), b64(y)), nil
+	}
+	return "", fmt.Errorf("acme: unsupported key type %T", pub)
+}
+
+// JWKThumbprint returns the base64url-encoded SHA-256 thumbprint
+// of the JSON Web Key for pub, as defined in RFC 7638.
+func JWKThumbprint(pub crypto.PublicKey) (string, error) {
+	jwk, err := jwkEncode(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(jwk))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
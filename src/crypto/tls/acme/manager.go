@@ -0,0 +1,484 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRenewBefore = 30 * 24 * time.Hour
+	obtainTimeout      = 5 * time.Minute
+	accountKeyName     = "acme_account+key"
+)
+
+// A Manager obtains certificates from an ACME CA on demand and
+// renews them before they expire.
+//
+// A Manager is typically installed in a crypto/tls.Config using
+// [Manager.TLSConfig] or by setting GetCertificate to [Manager.GetCertificate].
+// To answer HTTP-01 challenges, the handler returned by
+// [Manager.HTTPHandler] must also be serving on port 80.
+//
+// The exported fields must not be changed after the Manager is first used.
+type Manager struct {
+	// Client is the ACME client used to talk to the CA.
+	// If its Key is nil, the Manager uses the account key stored in
+	// Cache, generating and storing one if necessary.
+	// If Client is nil, a Client for Let's Encrypt is used.
+	Client *Client
+
+	// Cache stores certificates and the account key across restarts.
+	// If nil, certificates are kept in memory only.
+	Cache Cache
+
+	// HostPolicy, if not nil, is called with the server name of each
+	// handshake before a certificate for it is looked up in memory or in
+	// Cache, or obtained. If it returns an error, the handshake fails.
+	// If HostPolicy is nil, certificates are obtained for any host name
+	// clients ask for, which allows clients to exhaust the CA's rate
+	// limits and the Manager's memory; use [HostWhitelist] to restrict
+	// the names.
+	HostPolicy func(ctx context.Context, host string) error
+
+	// RenewBefore is how long before expiry a certificate is renewed.
+	// If zero, certificates are renewed 30 days before they expire.
+	RenewBefore time.Duration
+
+	// Email, if not empty, is registered as the account's contact address.
+	Email string
+
+	// AgreeToTerms indicates agreement to the CA's terms of service,
+	// which is required to create an account.
+	AgreeToTerms bool
+
+	initOnce sync.Once
+	client   *Client
+
+	regMu      sync.Mutex
+	registered bool
+
+	mu        sync.Mutex
+	state     map[string]*certState
+	tokens    map[string]string           // HTTP-01 token to response
+	alpnCerts map[string]*tls.Certificate // TLS-ALPN-01 challenge certificates by domain
+	tryHTTP01 bool                        // HTTPHandler has been called
+}
+
+// A certState holds the certificate for a single domain.
+type certState struct {
+	mu       sync.Mutex // held while obtaining a certificate
+	cert     *tls.Certificate
+	renewing bool
+}
+
+// HostWhitelist returns a policy for [Manager.HostPolicy] that permits
+// only the given host names, compared case-insensitively.
+func HostWhitelist(hosts ...string) func(context.Context, string) error {
+	allowed := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		allowed[strings.ToLower(h)] = true
+	}
+	return func(_ context.Context, host string) error {
+		if !allowed[strings.ToLower(host)] {
+			return fmt.Errorf("acme: host %q not configured in HostWhitelist", host)
+		}
+		return nil
+	}
+}
+
+func (m *Manager) init() {
+	m.initOnce.Do(func() {
+		m.client = m.Client
+		if m.client == nil {
+			m.client = &Client{DirectoryURL: LetsEncryptURL}
+		}
+		m.state = make(map[string]*certState)
+		m.tokens = make(map[string]string)
+		m.alpnCerts = make(map[string]*tls.Certificate)
+	})
+}
+
+// TLSConfig returns a crypto/tls.Config that obtains certificates from m
+// and answers TLS-ALPN-01 challenges.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: m.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1", ALPNProto},
+	}
+}
+
+// GetCertificate returns a certificate for the server name in hello,
+// obtaining one from the CA if necessary. It also answers TLS-ALPN-01
+// challenges. It is suitable for use as crypto/tls.Config.GetCertificate.
+//
+// A certificate in the cache or in memory is returned immediately,
+// starting a renewal in the background if it is due. Otherwise the
+// handshake waits while a certificate is obtained.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.init()
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if name == "" {
+		return nil, errors.New("acme: missing server name")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("acme: invalid server name %q", name)
+	}
+
+	for _, proto := range hello.SupportedProtos {
+		if proto == ALPNProto {
+			m.mu.Lock()
+			cert := m.alpnCerts[name]
+			m.mu.Unlock()
+			if cert == nil {
+				return nil, fmt.Errorf("acme: no TLS-ALPN-01 challenge pending for %q", name)
+			}
+			return cert, nil
+		}
+	}
+
+	ctx := context.Background()
+	if hello.Context() != nil {
+		ctx = hello.Context()
+	}
+	return m.cert(ctx, name)
+}
+
+// cert returns the certificate for name, obtaining it if necessary.
+func (m *Manager) cert(ctx context.Context, name string) (*tls.Certificate, error) {
+	// Check the policy first, so that clients cannot make m keep
+	// state or read the cache for arbitrary names.
+	if m.HostPolicy != nil {
+		if err := m.HostPolicy(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	st := m.state[name]
+	if st == nil {
+		st = new(certState)
+		m.state[name] = st
+	}
+	m.mu.Unlock()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := time.Now()
+	if st.cert == nil {
+		if cert, err := m.cacheGet(ctx, name); err == nil && validAt(cert, now) {
+			st.cert = cert
+		}
+	}
+	if st.cert != nil && validAt(st.cert, now) {
+		if !st.renewing && st.cert.Leaf.NotAfter.Sub(now) < m.renewBefore() {
+			st.renewing = true
+			go m.renew(name, st)
+		}
+		return st.cert, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, obtainTimeout)
+	defer cancel()
+	cert, err := m.obtain(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	st.cert = cert
+	m.cachePut(ctx, name, cert)
+	return cert, nil
+}
+
+// renew obtains a new certificate for name in the background.
+// If renewal fails, the current certificate remains in use and
+// renewal is retried by the next handshake.
+func (m *Manager) renew(name string, st *certState) {
+	ctx, cancel := context.WithTimeout(context.Background(), obtainTimeout)
+	defer cancel()
+	cert, err := m.obtain(ctx, name)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.renewing = false
+	if err == nil {
+		st.cert = cert
+		m.cachePut(ctx, name, cert)
+	}
+}
+
+func (m *Manager) renewBefore() time.Duration {
+	if m.RenewBefore > 0 {
+		return m.RenewBefore
+	}
+	return defaultRenewBefore
+}
+
+// validAt reports whether cert is within its validity period at now.
+func validAt(cert *tls.Certificate, now time.Time) bool {
+	return cert.Leaf != nil && !now.Before(cert.Leaf.NotBefore) && now.Before(cert.Leaf.NotAfter)
+}
+
+// obtain requests a new certificate for name from the CA.
+func (m *Manager) obtain(ctx context.Context, name string) (*tls.Certificate, error) {
+	if err := m.register(ctx); err != nil {
+		return nil, err
+	}
+	o, err := m.client.AuthorizeOrder(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, url := range o.AuthzURLs {
+		if err := m.authorize(ctx, url); err != nil {
+			return nil, err
+		}
+	}
+	if o, err = m.client.WaitOrder(ctx, o.URI); err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{name}}, key)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := m.client.CreateOrderCert(ctx, o.URI, o.FinalizeURL, csr)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := leaf.VerifyHostname(name); err != nil {
+		return nil, fmt.Errorf("acme: issued certificate is not valid for %s: %w", name, err)
+	}
+	if pub, ok := leaf.PublicKey.(*ecdsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+		return nil, errors.New("acme: issued certificate does not match the requested key")
+	}
+	return &tls.Certificate{Certificate: chain, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// authorize satisfies the authorization at url, if it is not already valid.
+func (m *Manager) authorize(ctx context.Context, url string) error {
+	a, err := m.client.GetAuthorization(ctx, url)
+	if err != nil {
+		return err
+	}
+	if a.Status == StatusValid {
+		return nil
+	}
+
+	m.mu.Lock()
+	types := []string{"tls-alpn-01"}
+	if m.tryHTTP01 {
+		types = append(types, "http-01")
+	}
+	m.mu.Unlock()
+	var chal *Challenge
+	for _, typ := range types {
+		for _, ch := range a.Challenges {
+			if ch.Type == typ && chal == nil {
+				chal = ch
+			}
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("acme: no supported challenge for %s among %d offered", a.Identifier.Value, len(a.Challenges))
+	}
+
+	domain := a.Identifier.Value
+	switch chal.Type {
+	case "tls-alpn-01":
+		cert, err := m.client.TLSALPN01ChallengeCert(chal.Token, domain)
+		if err != nil {
+			return err
+		}
+		m.mu.Lock()
+		m.alpnCerts[domain] = &cert
+		m.mu.Unlock()
+		defer func() {
+			m.mu.Lock()
+			delete(m.alpnCerts, domain)
+			m.mu.Unlock()
+		}()
+	case "http-01":
+		resp, err := m.client.HTTP01ChallengeResponse(chal.Token)
+		if err != nil {
+			return err
+		}
+		m.mu.Lock()
+		m.tokens[chal.Token] = resp
+		m.mu.Unlock()
+		defer func() {
+			m.mu.Lock()
+			delete(m.tokens, chal.Token)
+			m.mu.Unlock()
+		}()
+	}
+
+	if _, err := m.client.Accept(ctx, chal); err != nil {
+		return err
+	}
+	_, err = m.client.WaitAuthorization(ctx, url)
+	return err
+}
+
+// register creates the account with the CA, once.
+func (m *Manager) register(ctx context.Context) error {
+	m.regMu.Lock()
+	defer m.regMu.Unlock()
+	if m.registered {
+		return nil
+	}
+	if m.client.Key == nil {
+		key, err := m.accountKey(ctx)
+		if err != nil {
+			return err
+		}
+		m.client.Key = key
+	}
+	var contact []string
+	if m.Email != "" {
+		contact = []string{"mailto:" + m.Email}
+	}
+	if _, err := m.client.Register(ctx, contact, m.AgreeToTerms); err != nil {
+		return err
+	}
+	m.registered = true
+	return nil
+}
+
+// accountKey returns the account key from the cache,
+// generating and storing one if there is none.
+func (m *Manager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	if m.Cache != nil {
+		data, err := m.Cache.Get(ctx, accountKeyName)
+		if err == nil {
+			b, _ := pem.Decode(data)
+			if b == nil || b.Type != "EC PRIVATE KEY" {
+				return nil, errors.New("acme: invalid account key in cache")
+			}
+			return x509.ParseECPrivateKey(b.Bytes)
+		}
+		if err != ErrCacheMiss {
+			return nil, err
+		}
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if m.Cache != nil {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+		if err := m.Cache.Put(ctx, accountKeyName, data); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// cacheGet loads the certificate for name from the cache.
+func (m *Manager) cacheGet(ctx context.Context, name string) (*tls.Certificate, error) {
+	if m.Cache == nil {
+		return nil, ErrCacheMiss
+	}
+	data, err := m.Cache.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// The entry holds the private key followed by the certificate chain;
+	// X509KeyPair picks out the blocks it needs from each argument.
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// cachePut stores the certificate for name in the cache.
+// Errors are ignored: the certificate remains usable from memory.
+func (m *Manager) cachePut(ctx context.Context, name string, cert *tls.Certificate) {
+	if m.Cache == nil {
+		return
+	}
+	der, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	for _, c := range cert.Certificate {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c})
+	}
+	m.Cache.Put(ctx, name, buf.Bytes())
+}
+
+// HTTPHandler returns a handler that answers HTTP-01 challenges and
+// passes all other requests to fallback. If fallback is nil, other
+// requests are redirected to HTTPS.
+//
+// Calling HTTPHandler enables the HTTP-01 challenge type, which is
+// used when the CA does not offer TLS-ALPN-01.
+func (m *Manager) HTTPHandler(fallback http.Handler) http.Handler {
+	m.init()
+	m.mu.Lock()
+	m.tryHTTP01 = true
+	m.mu.Unlock()
+	if fallback == nil {
+		fallback = http.HandlerFunc(redirectHTTPS)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.URL.Path, HTTP01ChallengePath(""))
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		m.mu.Lock()
+		resp, ok := m.tokens[token]
+		m.mu.Unlock()
+		if !ok {
+			http.Error(w, "no such challenge", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(resp))
+	})
+}
+
+func redirectHTTPS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "use HTTPS", http.StatusBadRequest)
+		return
+	}
+	host := r.Host
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusFound)
+}
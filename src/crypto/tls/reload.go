@@ -0,0 +1,305 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+const (
+	defaultCheckInterval = time.Minute
+	defaultOCSPRefresh   = time.Hour
+)
+
+// A CertificateReloader serves a certificate loaded from a pair of files,
+// reloading it when the files change, so that certificates can be rotated
+// without restarting the program.
+//
+// A CertificateReloader is meant to be installed as [Config.GetCertificate]
+// or [Config.GetClientCertificate] using the methods of the same names.
+// Each handshake uses the most recently loaded certificate; a certificate
+// that fails to load does not replace the current one.
+//
+// The exported fields must be set before calling [CertificateReloader.Start]
+// and must not be changed afterwards.
+type CertificateReloader struct {
+	// CertFile and KeyFile name the files holding the certificate chain
+	// and private key, in the format accepted by LoadX509KeyPair.
+	CertFile string
+	KeyFile  string
+
+	// CheckInterval is how often the files are checked for changes.
+	// A file is considered changed if its size or modification time
+	// changes. If zero, the files are checked once a minute.
+	CheckInterval time.Duration
+
+	// FetchOCSP, if not nil, is called to obtain an OCSP response for
+	// the leaf certificate, which is then stapled to the certificate.
+	// The issuer is nil if the certificate file does not include the
+	// issuing certificate.
+	//
+	// FetchOCSP is called whenever a certificate is loaded, and again
+	// halfway through the validity period of the current response, as
+	// given by its thisUpdate and nextUpdate fields, or hourly if that
+	// cannot be determined. If FetchOCSP fails, the certificate is
+	// served with its previous staple, if any, and the fetch is retried
+	// at the next check.
+	FetchOCSP func(leaf, issuer *x509.Certificate) ([]byte, error)
+
+	cert atomic.Pointer[Certificate]
+
+	mu          sync.Mutex // serializes loads and guards the fields below
+	certStamp   fileStamp
+	keyStamp    fileStamp
+	ocspRefresh time.Time // when to next call FetchOCSP
+	err         error     // result of the most recent check or reload
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// A fileStamp identifies a version of a file's contents.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func statFile(name string) (fileStamp, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{fi.Size(), fi.ModTime()}, nil
+}
+
+// Start loads the certificate and starts checking the files for changes
+// in a background goroutine. It returns an error if the certificate
+// cannot be loaded, in which case no goroutine is started.
+// A failure to fetch an OCSP response is reported by
+// [CertificateReloader.Err] instead.
+// Call [CertificateReloader.Close] to stop the goroutine.
+func (r *CertificateReloader) Start() error {
+	// Hold r.mu throughout, so that concurrent calls to Start
+	// cannot both start a goroutine.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		return errors.New("tls: CertificateReloader already started")
+	}
+	r.err = r.loadLocked(true)
+	if r.err != nil && r.cert.Load() == nil {
+		return r.err
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	interval := r.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	go r.run(interval, r.stop, r.done)
+	return nil
+}
+
+func (r *CertificateReloader) run(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		r.mu.Lock()
+		r.err = r.loadLocked(false)
+		r.mu.Unlock()
+	}
+}
+
+// Close stops the background goroutine started by
+// [CertificateReloader.Start]. The most recently loaded certificate
+// continues to be served.
+func (r *CertificateReloader) Close() error {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return nil
+}
+
+// Reload loads the certificate from the files immediately, whether or
+// not they have changed, and fetches a new OCSP response if FetchOCSP
+// is set. If the certificate cannot be loaded, the current certificate
+// is kept and the error is returned. An error from FetchOCSP is also
+// returned, but the newly loaded certificate is used regardless.
+func (r *CertificateReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.loadLocked(true)
+	r.err = err
+	return err
+}
+
+// loadLocked loads the certificate if the files have changed or force
+// is set, and refreshes the OCSP staple if it is due.
+// r.mu must be held.
+func (r *CertificateReloader) loadLocked(force bool) error {
+	certStamp, err := statFile(r.CertFile)
+	if err != nil {
+		return err
+	}
+	keyStamp, err := statFile(r.KeyFile)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !force && certStamp == r.certStamp && keyStamp == r.keyStamp && r.cert.Load() != nil {
+		if r.FetchOCSP == nil || now.Before(r.ocspRefresh) {
+			return nil
+		}
+		// Refresh the staple of the current certificate.
+		cert := *r.cert.Load()
+		if err := r.fetchOCSPLocked(&cert, now); err != nil {
+			return err
+		}
+		r.cert.Store(&cert)
+		return nil
+	}
+
+	cert, err := LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+	// Record the stamps taken before reading the files, so that a change
+	// made while they were being read is picked up at the next check.
+	r.certStamp, r.keyStamp = certStamp, keyStamp
+	var ocspErr error
+	if r.FetchOCSP != nil {
+		ocspErr = r.fetchOCSPLocked(&cert, now)
+	}
+	r.cert.Store(&cert)
+	return ocspErr
+}
+
+// fetchOCSPLocked calls FetchOCSP for cert and staples the result to it.
+// If FetchOCSP fails, cert keeps the staple of the current certificate
+// if it has the same leaf.
+// r.mu must be held.
+func (r *CertificateReloader) fetchOCSPLocked(cert *Certificate, now time.Time) error {
+	var issuer *x509.Certificate
+	if len(cert.Certificate) > 1 {
+		issuer, _ = x509.ParseCertificate(cert.Certificate[1])
+	}
+	staple, err := r.FetchOCSP(cert.Leaf, issuer)
+	if err != nil {
+		if old := r.cert.Load(); old != nil && old.Leaf != nil && old.Leaf.Equal(cert.Leaf) {
+			cert.OCSPStaple = old.OCSPStaple
+		}
+		r.ocspRefresh = time.Time{} // retry at the next check
+		return err
+	}
+	cert.OCSPStaple = staple
+	r.ocspRefresh = now.Add(defaultOCSPRefresh)
+	if thisUpdate, nextUpdate, ok := ocspValidity(staple); ok {
+		if refresh := thisUpdate.Add(nextUpdate.Sub(thisUpdate) / 2); refresh.After(now) {
+			r.ocspRefresh = refresh
+		}
+	}
+	return nil
+}
+
+// GetCertificate returns the current certificate.
+// It is suitable for use as [Config.GetCertificate].
+func (r *CertificateReloader) GetCertificate(*ClientHelloInfo) (*Certificate, error) {
+	return r.current()
+}
+
+// GetClientCertificate returns the current certificate.
+// It is suitable for use as [Config.GetClientCertificate].
+func (r *CertificateReloader) GetClientCertificate(*CertificateRequestInfo) (*Certificate, error) {
+	return r.current()
+}
+
+func (r *CertificateReloader) current() (*Certificate, error) {
+	if c := r.cert.Load(); c != nil {
+		return c, nil
+	}
+	return nil, errors.New("tls: CertificateReloader has no certificate loaded")
+}
+
+// NotAfter returns the expiration time of the current leaf certificate,
+// or the zero time if no certificate has been loaded.
+func (r *CertificateReloader) NotAfter() time.Time {
+	if c := r.cert.Load(); c != nil {
+		return c.Leaf.NotAfter
+	}
+	return time.Time{}
+}
+
+// Err returns the error from the most recent check or reload,
+// or nil if it succeeded.
+func (r *CertificateReloader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// ocspValidity returns the thisUpdate and nextUpdate times of the first
+// response in a DER-encoded OCSP response, as defined in RFC 6960,
+// Section 4.2.1. The response is not verified.
+func ocspValidity(der []byte) (thisUpdate, nextUpdate time.Time, ok bool) {
+	var (
+		input       = cryptobyte.String(der)
+		resp, bytes cryptobyte.String
+		basic, tbs  cryptobyte.String
+		responses   cryptobyte.String
+		single      cryptobyte.String
+		skip        cryptobyte.String
+		skipTag     cryptobyte_asn1.Tag
+		status      int
+		oid         cryptobyte.String
+	)
+	if !input.ReadASN1(&resp, cryptobyte_asn1.SEQUENCE) ||
+		!resp.ReadASN1Enum(&status) || status != 0 ||
+		!resp.ReadASN1(&bytes, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+		!bytes.ReadASN1(&bytes, cryptobyte_asn1.SEQUENCE) ||
+		!bytes.ReadASN1(&oid, cryptobyte_asn1.OBJECT_IDENTIFIER) ||
+		!bytes.ReadASN1(&basic, cryptobyte_asn1.OCTET_STRING) ||
+		!basic.ReadASN1(&basic, cryptobyte_asn1.SEQUENCE) ||
+		!basic.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) ||
+		// version, responderID, producedAt
+		!tbs.SkipOptionalASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+		!tbs.ReadAnyASN1(&skip, &skipTag) ||
+		!tbs.SkipASN1(cryptobyte_asn1.GeneralizedTime) ||
+		!tbs.ReadASN1(&responses, cryptobyte_asn1.SEQUENCE) ||
+		!responses.ReadASN1(&single, cryptobyte_asn1.SEQUENCE) ||
+		// certID, certStatus
+		!single.SkipASN1(cryptobyte_asn1.SEQUENCE) ||
+		!single.ReadAnyASN1(&skip, &skipTag) ||
+		!single.ReadASN1GeneralizedTime(&thisUpdate) {
+		return time.Time{}, time.Time{}, false
+	}
+	var next cryptobyte.String
+	var hasNext bool
+	if !single.ReadOptionalASN1(&next, &hasNext, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) ||
+		!hasNext || !next.ReadASN1GeneralizedTime(&nextUpdate) || !nextUpdate.After(thisUpdate) {
+		return time.Time{}, time.Time{}, false
+	}
+	return thisUpdate, nextUpdate, true
+}
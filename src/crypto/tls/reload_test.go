@@ -0,0 +1,283 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// writeTestKeyPair generates a self-signed certificate for name, in the
+// manner of generate_cert.go, and writes it and its key to certFile and
+// keyFile. The files' modification time is set to mtime.
+func writeTestKeyPair(t *testing.T, certFile, keyFile, name string, mtime time.Time) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Now().Add(-time.Hour)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{name},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []struct {
+		name, typ string
+		der       []byte
+	}{
+		{certFile, "CERTIFICATE", der},
+		{keyFile, "PRIVATE KEY", keyDER},
+	} {
+		data := pem.EncodeToMemory(&pem.Block{Type: f.typ, Bytes: f.der})
+		if err := os.WriteFile(f.name, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(f.name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func commonName(t *testing.T, r *CertificateReloader) string {
+	t.Helper()
+	c, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Leaf.Subject.CommonName
+}
+
+func TestCertificateReloaderReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	mtime := time.Now().Add(-time.Hour)
+	writeTestKeyPair(t, certFile, keyFile, "one.example", mtime)
+
+	r := &CertificateReloader{CertFile: certFile, KeyFile: keyFile, CheckInterval: time.Hour}
+	if _, err := r.GetCertificate(nil); err == nil {
+		t.Error("GetCertificate succeeded before Start")
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got := commonName(t, r); got != "one.example" {
+		t.Errorf("certificate for %q, want one.example", got)
+	}
+	if r.NotAfter().IsZero() {
+		t.Error("NotAfter is zero")
+	}
+
+	writeTestKeyPair(t, certFile, keyFile, "two.example", mtime)
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, r); got != "two.example" {
+		t.Errorf("after Reload, certificate for %q, want two.example", got)
+	}
+
+	// A broken key file does not replace the current certificate.
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Error("Reload with bad key succeeded")
+	}
+	if r.Err() == nil {
+		t.Error("Err is nil after failed Reload")
+	}
+	if got := commonName(t, r); got != "two.example" {
+		t.Errorf("after failed Reload, certificate for %q, want two.example", got)
+	}
+}
+
+func TestCertificateReloaderStartError(t *testing.T) {
+	dir := t.TempDir()
+	r := &CertificateReloader{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: filepath.Join(dir, "missing.key")}
+	if err := r.Start(); err == nil {
+		r.Close()
+		t.Fatal("Start succeeded with missing files")
+	}
+}
+
+func TestCertificateReloaderConcurrentStart(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestKeyPair(t, certFile, keyFile, "one.example", time.Now().Add(-time.Hour))
+
+	for range 20 {
+		r := &CertificateReloader{CertFile: certFile, KeyFile: keyFile, CheckInterval: time.Hour}
+		const n = 8
+		errc := make(chan error, n)
+		for range n {
+			go func() { errc <- r.Start() }()
+		}
+		started := 0
+		for range n {
+			if err := <-errc; err == nil {
+				started++
+			}
+		}
+		r.Close()
+		if started != 1 {
+			t.Fatalf("%d concurrent calls to Start succeeded, want 1", started)
+		}
+	}
+}
+
+func TestCertificateReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	mtime := time.Now().Add(-time.Hour)
+	writeTestKeyPair(t, certFile, keyFile, "one.example", mtime)
+
+	r := &CertificateReloader{CertFile: certFile, KeyFile: keyFile, CheckInterval: time.Millisecond}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	old, _ := r.GetCertificate(nil)
+
+	writeTestKeyPair(t, certFile, keyFile, "two.example", mtime.Add(time.Minute))
+	for commonName(t, r) != "two.example" {
+		time.Sleep(time.Millisecond)
+	}
+	// The previously served certificate must not have been modified.
+	if old.Leaf.Subject.CommonName != "one.example" {
+		t.Errorf("old certificate was modified")
+	}
+}
+
+func TestCertificateReloaderOCSP(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestKeyPair(t, certFile, keyFile, "example.golang", time.Now().Add(-time.Hour))
+
+	staple := []byte("staple 1")
+	var fetchErr error
+	r := &CertificateReloader{
+		CertFile:      certFile,
+		KeyFile:       keyFile,
+		CheckInterval: time.Hour,
+		FetchOCSP: func(leaf, issuer *x509.Certificate) ([]byte, error) {
+			if leaf.Subject.CommonName != "example.golang" {
+				t.Errorf("FetchOCSP called for %q", leaf.Subject.CommonName)
+			}
+			return staple, fetchErr
+		},
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	serverConfig := testConfig.Clone()
+	serverConfig.Certificates = nil
+	serverConfig.GetCertificate = r.GetCertificate
+	clientConfig := testConfig.Clone()
+	clientConfig.InsecureSkipVerify = true
+	_, cs, err := testHandshake(t, clientConfig, serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cs.OCSPResponse, staple) {
+		t.Errorf("OCSP response = %q, want %q", cs.OCSPResponse, staple)
+	}
+
+	// A failed fetch keeps the previous staple.
+	staple, fetchErr = nil, errors.New("responder down")
+	if err := r.Reload(); err != fetchErr {
+		t.Errorf("Reload = %v, want %v", err, fetchErr)
+	}
+	c, _ := r.GetCertificate(nil)
+	if got := string(c.OCSPStaple); got != "staple 1" {
+		t.Errorf("staple after failed fetch = %q, want %q", got, "staple 1")
+	}
+}
+
+func TestOCSPValidity(t *testing.T) {
+	thisUpdate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextUpdate := thisUpdate.Add(7 * 24 * time.Hour)
+	der := testOCSPResponse(thisUpdate, nextUpdate)
+	gotThis, gotNext, ok := ocspValidity(der)
+	if !ok || !gotThis.Equal(thisUpdate) || !gotNext.Equal(nextUpdate) {
+		t.Errorf("ocspValidity = %v, %v, %v; want %v, %v, true", gotThis, gotNext, ok, thisUpdate, nextUpdate)
+	}
+	for _, bad := range [][]byte{nil, []byte("staple"), der[:len(der)-1]} {
+		if _, _, ok := ocspValidity(bad); ok {
+			t.Errorf("ocspValidity(%x) succeeded", bad)
+		}
+	}
+}
+
+// testOCSPResponse returns a minimal, unsigned OCSP response with
+// the given validity period.
+func testOCSPResponse(thisUpdate, nextUpdate time.Time) []byte {
+	explicit := func(n uint8) cryptobyte_asn1.Tag {
+		return cryptobyte_asn1.Tag(n).Constructed().ContextSpecific()
+	}
+	var basic cryptobyte.Builder
+	basic.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // tbsResponseData
+			b.AddASN1(explicit(2), func(b *cryptobyte.Builder) { // responderID byKey
+				b.AddASN1OctetString(make([]byte, 20))
+			})
+			b.AddASN1GeneralizedTime(thisUpdate)
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // responses
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) { // certID
+						b.AddASN1Int64(1)
+					})
+					b.AddASN1(cryptobyte_asn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {}) // good
+					b.AddASN1GeneralizedTime(thisUpdate)
+					b.AddASN1(explicit(0), func(b *cryptobyte.Builder) {
+						b.AddASN1GeneralizedTime(nextUpdate)
+					})
+				})
+			})
+		})
+	})
+	var resp cryptobyte.Builder
+	resp.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Enum(0) // successful
+		b.AddASN1(explicit(0), func(b *cryptobyte.Builder) {
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier([]int{1, 3, 6, 1, 5, 5, 7, 48, 1, 1})
+				b.AddASN1OctetString(basic.BytesOrPanic())
+			})
+		})
+	})
+	return resp.BytesOrPanic()
+}
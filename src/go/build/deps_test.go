@@ -54,8 +54,6 @@ var depsRules = `
 	internal/goarch, unsafe
 	< internal/abi, internal/chacha8rand;
 
-	unsafe < maps;
-
 	# RUNTIME is the core runtime group of packages, all of them very light-weight.
 	internal/abi,
 	internal/chacha8rand,
@@ -77,6 +75,7 @@ var depsRules = `
 	< internal/race
 	< internal/msan
 	< internal/asan
+	< iter
 	< sync
 	< internal/bisect
 	< internal/godebug
@@ -85,20 +84,24 @@ var depsRules = `
 	< internal/oserror, math/bits
 	< RUNTIME;
 
-	internal/race
-	< iter;
+	iter < iter/xiter;
 
 	# slices depends on unsafe for overlapping check, cmp for comparison
 	# semantics, and math/bits for # calculating bitlength of numbers.
-	unsafe, cmp, math/bits
+	iter, unsafe, cmp, math/bits
 	< slices;
 
+	iter, unsafe < maps;
+
 	RUNTIME, slices
 	< sort;
 
 	sort
 	< container/heap;
 
+	cmp, iter
+	< container/heap/v2, container/list/v2, container/ordered, container/ring/v2;
+
 	RUNTIME
 	< io;
 
@@ -128,6 +131,9 @@ var depsRules = `
 	< context
 	< TIME;
 
+	TIME
+	< sync/ctxsync;
+
 	TIME, io, path, sort
 	< io/fs;
 
@@ -141,6 +147,9 @@ var depsRules = `
 	MATH
 	< math/cmplx;
 
+	MATH
+	< simd;
+
 	MATH
 	< math/rand, math/rand/v2;
 
@@ -153,7 +162,7 @@ var depsRules = `
 	unicode !< strconv;
 
 	# STR is basic string and buffer manipulation.
-	RUNTIME, io, unicode/utf8, unicode/utf16, unicode
+	RUNTIME, io, iter, unicode/utf8, unicode/utf16, unicode
 	< bytes, strings
 	< bufio;
 
@@ -213,6 +222,7 @@ var depsRules = `
 	  net/internal/socktest,
 	  net/url,
 	  runtime/trace,
+	  sync/errgroup,
 	  text/scanner,
 	  text/tabwriter;
 
@@ -561,6 +571,12 @@ var depsRules = `
 	net/http, flag
 	< net/http/httptest;
 
+	net/http
+	< net/http/sse;
+
+	encoding/json, net/http
+	< crypto/tls/acme;
+
 	net/http, regexp
 	< net/http/cgi
 	< net/http/fcgi;
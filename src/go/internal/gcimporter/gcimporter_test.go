@@ -178,6 +178,12 @@ func TestImportTypeparamTests(t *testing.T) {
 				// changes in test/typeparams.
 				t.Skipf("not detected as a run test")
 			}
+			if entry.Name() == "genalias.go" {
+				// Instances of generic aliases are exported as the types
+				// they denote, so the imported signatures don't mention
+				// the aliases. TestGenericAlias covers importing them.
+				t.Skip("generic alias instances are not preserved in export data")
+			}
 
 			// Compile and import, and compare the resulting package with the package
 			// that was type-checked directly.
@@ -750,3 +756,47 @@ func lookupObj(t *testing.T, scope *types.Scope, name string) types.Object {
 	t.Fatalf("%s not found", name)
 	return nil
 }
+
+func TestGenericAlias(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	// This package only handles gc export data.
+	if runtime.Compiler != "gc" {
+		t.Skipf("gc-built packages not available (compiler = %s)", runtime.Compiler)
+	}
+
+	pkg := compileAndImportPkg(t, "genalias")
+
+	for _, test := range []struct {
+		name, tparams, rhs string
+	}{
+		{"A", "[P any]", "T[P, string]"},
+		{"List", "[E any]", "[]E"},
+	} {
+		obj := lookupObj(t, pkg.Scope(), test.name)
+		alias, ok := obj.Type().(*types.Alias)
+		if !ok {
+			t.Errorf("%s: got %T, want *types.Alias", test.name, obj.Type())
+			continue
+		}
+		if got := types.TypeString(alias, types.RelativeTo(pkg)); got != test.name {
+			t.Errorf("%s: got type %s", test.name, got)
+		}
+		var tparams []string
+		for i := 0; i < alias.TypeParams().Len(); i++ {
+			tp := alias.TypeParams().At(i)
+			tparams = append(tparams, tp.Obj().Name()+" "+types.TypeString(tp.Constraint(), nil))
+		}
+		if got := "[" + strings.Join(tparams, ", ") + "]"; got != test.tparams {
+			t.Errorf("%s: got type parameters %s, want %s", test.name, got, test.tparams)
+		}
+		if got := types.TypeString(types.Unalias(alias), types.RelativeTo(pkg)); got != test.rhs {
+			t.Errorf("%s: got aliased type %s, want %s", test.name, got, test.rhs)
+		}
+	}
+
+	// Uses of generic aliases are recorded as the types they denote.
+	if got, want := types.TypeString(lookupObj(t, pkg.Scope(), "X").Type(), types.RelativeTo(pkg)), "T[int, string]"; got != want {
+		t.Errorf("X: got type %s, want %s", got, want)
+	}
+}
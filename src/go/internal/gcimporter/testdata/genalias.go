@@ -0,0 +1,18 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package genalias
+
+type T[P any, Q comparable] struct {
+	p P
+	q Q
+}
+
+type A[P any] = T[P, string]
+
+type List[E any] = []E
+
+var X A[int]
+
+func F[E any](l List[E]) List[E] { return l }
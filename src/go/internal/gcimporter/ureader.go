@@ -479,8 +479,12 @@ func (pr *pkgReader) objIdx(idx pkgbits.Index) (*types.Package, string) {
 
 		case pkgbits.ObjAlias:
 			pos := r.pos()
+			var tparams []*types.TypeParam
+			if r.p.AliasTypeParams() {
+				tparams = r.typeParamNames()
+			}
 			typ := r.typ()
-			declare(newAliasTypeName(pos, objPkg, objName, typ))
+			declare(newAliasTypeName(pos, objPkg, objName, typ, tparams))
 
 		case pkgbits.ObjConst:
 			pos := r.pos()
@@ -658,12 +662,14 @@ func pkgScope(pkg *types.Package) *types.Scope {
 }
 
 // newAliasTypeName returns a new TypeName, with a materialized *types.Alias if supported.
-func newAliasTypeName(pos token.Pos, pkg *types.Package, name string, rhs types.Type) *types.TypeName {
+// Aliases with type parameters are always materialized.
+func newAliasTypeName(pos token.Pos, pkg *types.Package, name string, rhs types.Type, tparams []*types.TypeParam) *types.TypeName {
 	// When GODEBUG=gotypesalias=1, the Type() of the return value is a
 	// *types.Alias. Copied from x/tools/internal/aliases.NewAlias.
-	if godebug.New("gotypesalias").Value() == "1" {
+	if len(tparams) > 0 || godebug.New("gotypesalias").Value() == "1" {
 		tname := types.NewTypeName(pos, pkg, name, nil)
-		_ = types.NewAlias(tname, rhs) // form TypeName -> Alias cycle
+		alias := types.NewAlias(tname, rhs) // form TypeName -> Alias cycle
+		alias.SetTypeParams(tparams)
 		return tname
 	}
 	return types.NewTypeName(pos, pkg, name, rhs)
@@ -3022,3 +3022,75 @@ type B = T[A]
 		t.Errorf("Unalias(type B = T[A]) = %q, want %q", got, want)
 	}
 }
+
+func TestGenericAlias(t *testing.T) {
+	t.Setenv("GODEBUG", "gotypesalias=1")
+	const src = `package p
+
+type T[P any, Q comparable] struct{ p P; q Q }
+
+type A[P any] = T[P, string]
+
+var x A[int]
+`
+	info := &Info{Instances: make(map[*ast.Ident]Instance)}
+	pkg := mustTypecheck(src, &Config{GoVersion: "go1.23"}, info)
+
+	A := pkg.Scope().Lookup("A").Type().(*Alias)
+	if got := A.TypeParams().Len(); got != 1 {
+		t.Fatalf("A.TypeParams().Len() = %d, want 1", got)
+	}
+	if A.TypeArgs() != nil || A.Origin() != A {
+		t.Errorf("generic alias A has TypeArgs %v and Origin %v", A.TypeArgs(), A.Origin())
+	}
+
+	x := pkg.Scope().Lookup("x").Type().(*Alias)
+	if x.Origin() != A || x.Obj() != A.Obj() {
+		t.Errorf("x has Origin %v and Obj %v, want %v and %v", x.Origin(), x.Obj(), A, A.Obj())
+	}
+	if got, want := x.TypeArgs().Len(), 1; got != want || x.TypeArgs().At(0) != Typ[Int] {
+		t.Errorf("x.TypeArgs() = %v, want [int]", x.TypeArgs())
+	}
+	if got, want := x.String(), "p.A[int]"; got != want {
+		t.Errorf("x.String() = %q, want %q", got, want)
+	}
+	if got, want := Unalias(x).String(), "p.T[int, string]"; got != want {
+		t.Errorf("Unalias(x).String() = %q, want %q", got, want)
+	}
+
+	for id, inst := range info.Instances {
+		if id.Name == "A" && !Identical(inst.Type, x) {
+			t.Errorf("Instances[A] = %v, want %v", inst.Type, x)
+		}
+	}
+}
+
+func TestGenericAliasGoVersion(t *testing.T) {
+	const src = `package p; type A[P any] = []P`
+	for _, test := range []struct {
+		godebug, goVersion string
+		alias              bool // whether A is a (generic) Alias
+	}{
+		{"", "go1.23", true},
+		{"", "go1.22", false},
+		{"", "", false},
+		{"gotypesalias=0", "go1.23", false},
+		{"gotypesalias=1", "", true},
+	} {
+		t.Setenv("GODEBUG", test.godebug)
+		pkg, err := typecheck(src, &Config{GoVersion: test.goVersion}, nil)
+		if !test.alias {
+			if err == nil {
+				t.Errorf("GODEBUG=%q, GoVersion=%q: got no error, want one", test.godebug, test.goVersion)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("GODEBUG=%q, GoVersion=%q: %v", test.godebug, test.goVersion, err)
+			continue
+		}
+		if A, _ := pkg.Scope().Lookup("A").Type().(*Alias); A == nil || A.TypeParams().Len() != 1 {
+			t.Errorf("GODEBUG=%q, GoVersion=%q: A is not a generic alias", test.godebug, test.goVersion)
+		}
+	}
+}
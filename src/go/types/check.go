@@ -259,7 +259,7 @@ func NewChecker(conf *Config, fset *token.FileSet, pkg *Package, info *Info) *Ch
 	// (previously, pkg.goVersion was mutated here: go.dev/issue/61212)
 
 	return &Checker{
-		enableAlias: gotypesalias.Value() == "1",
+		enableAlias: aliasesEnabled(asGoVersion(conf.GoVersion)),
 		conf:        conf,
 		ctxt:        conf.Context,
 		fset:        fset,
@@ -271,6 +271,20 @@ func NewChecker(conf *Config, fset *token.FileSet, pkg *Package, info *Info) *Ch
 	}
 }
 
+// aliasesEnabled reports whether alias declarations produce Alias types
+// in a package with the given Go version: always for gotypesalias=1,
+// never for gotypesalias=0, and otherwise if version is go1.23 or later,
+// since generic type aliases require Alias types.
+func aliasesEnabled(version goVersion) bool {
+	switch gotypesalias.Value() {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+	return version.isValid() && version.cmp(go1_23) >= 0
+}
+
 // initFiles initializes the files-specific portion of checker.
 // The provided files must all belong to the same package.
 func (check *Checker) initFiles(files []*ast.File) {
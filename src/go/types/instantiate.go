@@ -50,6 +50,8 @@ func Instantiate(ctxt *Context, orig Type, targs []Type, validate bool) (Type, e
 	if validate {
 		var tparams []*TypeParam
 		switch t := orig.(type) {
+		case *Alias:
+			tparams = t.TypeParams().list()
 		case *Named:
 			tparams = t.TypeParams().list()
 		case *Signature:
@@ -121,6 +123,16 @@ func (check *Checker) instance(pos token.Pos, orig Type, targs []Type, expanding
 	case *Named:
 		res = check.newNamedInstance(pos, orig, targs, expanding) // substituted lazily
 
+	case *Alias:
+		tparams := orig.TypeParams()
+		if !check.validateTArgLen(pos, orig.obj.Name(), tparams.Len(), len(targs)) {
+			return Typ[Invalid]
+		}
+		if tparams.Len() == 0 {
+			return orig // nothing to do (minor optimization)
+		}
+		res = check.newAliasInstance(pos, orig, targs, expanding, ctxt)
+
 	case *Signature:
 		assert(expanding == nil) // function instances cannot be reached from Named types
 
@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package abi
+
+// RF_State is the state of a range-over-func loop body, as tracked by
+// the checks the compiler inserts around each loop. The compiler and
+// the runtime share these values: the compiler stores them in the
+// per-loop state variables, and the runtime turns the state in which
+// a loop body was wrongly called into a meaningful panic message.
+type RF_State int
+
+// For best code generation, RF_DONE and RF_READY should be 0 and 1.
+const (
+	RF_DONE          = RF_State(iota) // body of loop has exited in a non-panic way
+	RF_READY                          // body of loop has not exited yet, is not running -- this is not a panic index
+	RF_PANIC                          // body of loop is either currently running, or has panicked
+	RF_EXHAUSTED                      // iterator function returned, i.e., sequence is "exhausted"
+	RF_MISSING_PANIC                  // body of loop panicked but iterator function defer-recovered it away
+)
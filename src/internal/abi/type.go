@@ -166,6 +166,11 @@ var kindNames = []string{
 	UnsafePointer: "unsafe.Pointer",
 }
 
+// TypeOf returns the abi.Type of some value.
+func TypeOf(a any) *Type {
+	return (*EmptyInterface)(unsafe.Pointer(&a)).Type
+}
+
 func (t *Type) Kind() Kind { return t.Kind_ & KindMask }
 
 func (t *Type) HasName() bool {
@@ -65,6 +65,7 @@ const KnownEnv = `
 	GOTOOLCHAIN
 	GOTOOLDIR
 	GOVCS
+	GOVULNDB
 	GOWASM
 	GOWORK
 	GO_EXTLINK_ENABLED
@@ -1,8 +0,0 @@
-// Code generated by mkconsts.go. DO NOT EDIT.
-
-//go:build goexperiment.cacheprog
-
-package goexperiment
-
-const CacheProg = true
-const CacheProgInt = 1
@@ -106,15 +106,13 @@ type Flags struct {
 	// copy of the iteration variable.
 	LoopVar bool
 
-	// CacheProg adds support to cmd/go to use a child process to implement
-	// the build cache; see https://github.com/golang/go/issues/59719.
-	CacheProg bool
-
 	// NewInliner enables a new+improved version of the function
 	// inlining phase within the Go compiler.
 	NewInliner bool
 
-	// RangeFunc enables range over func.
+	// RangeFunc enabled range over func before it became part of
+	// the language in Go 1.23. It no longer has any effect, but is
+	// still accepted so that existing GOEXPERIMENT settings work.
 	RangeFunc bool
 
 	// Range enables range over int and func.
@@ -123,4 +121,12 @@ type Flags struct {
 	// ExecTracer2 controls whether to use the new execution trace
 	// implementation.
 	ExecTracer2 bool
+
+	// SwissMap enables the Swiss table map implementation in the
+	// runtime in place of the bucket-and-overflow hash map.
+	SwissMap bool
+
+	// SIMD causes the "simd" standard library package to be visible
+	// and enables its compiler intrinsics.
+	SIMD bool
 }
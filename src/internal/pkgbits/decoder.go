@@ -65,6 +65,10 @@ func (pr *PkgDecoder) PkgPath() string { return pr.pkgPath }
 // SyncMarkers reports whether pr uses sync markers.
 func (pr *PkgDecoder) SyncMarkers() bool { return pr.sync }
 
+// AliasTypeParams reports whether alias objects in pr
+// are encoded with their type parameter names.
+func (pr *PkgDecoder) AliasTypeParams() bool { return pr.version >= 2 }
+
 // NewPkgDecoder returns a PkgDecoder initialized to read the Unified
 // IR export data from input. pkgPath is the package path for the
 // compilation unit that produced the export data.
@@ -87,7 +91,7 @@ func NewPkgDecoder(pkgPath, input string) PkgDecoder {
 		panic(fmt.Errorf("unsupported version: %v", pr.version))
 	case 0:
 		// no flags
-	case 1:
+	case 1, 2:
 		var flags uint32
 		assert(binary.Read(r, binary.LittleEndian, &flags) == nil)
 		pr.sync = flags&flagSyncMarkers != 0
@@ -21,10 +21,12 @@ import (
 //
 //   - v1: adds the flags uint32 word
 //
+//   - v2: adds type parameter names to alias objects
+//
 // TODO(mdempsky): For the next version bump:
 //   - remove the legacy "has init" bool from the public root
 //   - remove obj's "derived func instance" bool
-const currentVersion uint32 = 1
+const currentVersion uint32 = 2
 
 // A PkgEncoder provides methods for encoding a package's Unified IR
 // export data.
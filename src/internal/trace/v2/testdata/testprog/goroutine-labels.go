@@ -0,0 +1,42 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Tests that goroutines with profiler labels are labeled in the trace.
+
+//go:build ignore
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+)
+
+func main() {
+	// Start tracing.
+	if err := trace.Start(os.Stdout); err != nil {
+		log.Fatalf("failed to start tracing: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	pprof.Do(context.Background(), pprof.Labels("worker", "fetch"), func(context.Context) {
+		// This goroutine inherits its labels, which are recorded
+		// when it starts running.
+		wg.Go(func() {})
+	})
+	wg.Wait()
+
+	// This goroutine sets its labels while running.
+	wg.Go(func() {
+		pprof.Do(context.Background(), pprof.Labels("worker", "store"), func(context.Context) {})
+	})
+	wg.Wait()
+
+	// End of traced execution.
+	trace.Stop()
+}
@@ -535,6 +535,40 @@ func TestTraceIterPull(t *testing.T) {
 	testTraceProg(t, "iter-pull.go", nil)
 }
 
+func TestTraceGoroutineLabels(t *testing.T) {
+	testTraceProg(t, "goroutine-labels.go", func(t *testing.T, tb, _ []byte, _ bool) {
+		want := map[string]bool{
+			`{"worker":"fetch"}`: false,
+			`{"worker":"store"}`: false,
+		}
+		r, err := trace.NewReader(bytes.NewReader(tb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for {
+			ev, err := r.ReadEvent()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ev.Kind() != trace.EventLabel {
+				continue
+			}
+			l := ev.Label()
+			if _, ok := want[l.Label]; ok && l.Resource.Kind == trace.ResourceGoroutine {
+				want[l.Label] = true
+			}
+		}
+		for label, found := range want {
+			if !found {
+				t.Errorf("no goroutine label event for %s", label)
+			}
+		}
+	})
+}
+
 func testTraceProg(t *testing.T, progName string, extra func(t *testing.T, trace, stderr []byte, stress bool)) {
 	testenv.MustHaveGoRun(t)
 
@@ -551,7 +585,7 @@ func testTraceProg(t *testing.T, progName string, extra func(t *testing.T, trace
 			cmd.Args = append(cmd.Args, "-race")
 		}
 		cmd.Args = append(cmd.Args, testPath)
-		cmd.Env = append(os.Environ(), "GOEXPERIMENT=exectracer2", "GOEXPERIMENT=rangefunc")
+		cmd.Env = append(os.Environ(), "GOEXPERIMENT=exectracer2")
 		// Add a stack ownership check. This is cheap enough for testing.
 		godebug := "tracecheckstackownership=1"
 		if stress {
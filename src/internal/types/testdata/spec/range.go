@@ -16,6 +16,7 @@ type T struct{}
 func (*T) PM() {}
 func (T) M()   {}
 
+func f0(func() bool)                  {}
 func f1()                             {}
 func f2(func())                       {}
 func f4(func(int) bool)               {}
@@ -36,10 +37,20 @@ func test() {
 	}
 	for range f2 /* ERROR "cannot range over f2 (value of type func(func())): func must be func(yield func(...) bool): yield func does not return bool" */ {
 	}
-	for range f4 /* ERROR "range over f4 (value of type func(func(int) bool)) must have one iteration variable" */ {
+	for range f4 {
 	}
 	for _ = range f4 {
 	}
+	for _, _ /* ERROR "range over f4 (value of type func(func(int) bool)) permits only one iteration variable" */ = range f4 {
+	}
+	for range f0 {
+	}
+	for _ /* ERROR "range over f0 (value of type func(func() bool)) permits no iteration variables" */ = range f0 {
+	}
+	for range f5 {
+	}
+	for _ = range f5 {
+	}
 	for _, _ = range f5 {
 	}
 	for _ = range f7 {
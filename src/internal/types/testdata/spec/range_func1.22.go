@@ -0,0 +1,17 @@
+// -lang=go1.22
+
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Check that range over func requires go1.23 or later.
+
+package p
+
+func _(f func(func(int) bool)) {
+	for range f /* ERROR "cannot range over f (variable of type func(func(int) bool)): requires go1.23 or later" */ {
+	}
+	for x := range f /* ERROR "requires go1.23 or later" */ {
+		_ = x
+	}
+}
@@ -28,14 +28,41 @@ type _[P any, Q int] = RHS[P, Q]
 type _[P int | float64] = RHS[P, int]
 type _[P, Q any] = RHS[P, Q /* ERROR "Q does not satisfy ~int" */]
 
-// ----------------------------------------------------------------------------
-// NOTE: The code below does now work yet.
-// TODO: Implement this.
-
 // A generic type alias may be used like any other generic type.
 type A[P any] = RHS[P, int]
 
-func _(a A /* ERROR "not a generic type" */ [string]) {
+func _(a A[string]) {
 	a.p = "foo"
 	a.q = 42
 }
+
+// An instance of a generic type alias denotes the same type
+// as the corresponding instance of its RHS.
+var _ RHS[string, int] = A[string]{}
+var _ A[string] = RHS[string, int]{}
+
+// A generic type alias must be instantiated to be used.
+var _ A /* ERROR "cannot use generic type A without instantiation" */
+
+// The number of type arguments must match.
+var _ A /* ERROR "too many type arguments for type A: have 2, want 1" */ [int, string]
+
+// Type arguments must satisfy the constraints of the alias type parameters.
+type B[P ~int] = RHS[string, P]
+
+var _ B[int]
+var _ B[string /* ERROR "string does not satisfy ~int" */]
+
+// The RHS of a generic type alias may be any type.
+type Pair[K comparable, V any] = map[K]V
+type List[E any] = []E
+
+var _ Pair[string, int] = map[string]int{}
+var _ List[int] = []int{1, 2, 3}
+
+func _[T any](x List[T]) []T { return x }
+
+// A generic type alias may refer to another generic type alias.
+type L2[E any] = List[E]
+
+var _ []float64 = L2[float64]{}
@@ -2,13 +2,138 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build goexperiment.rangefunc
+/*
+Package iter provides basic definitions and operations related to
+iterators over sequences.
 
-// Package iter provides basic definitions and operations
-// related to iteration in Go.
-//
-// This package is experimental and can only be imported
-// when building with GOEXPERIMENT=rangefunc.
+# Iterators
+
+An iterator is a function that passes successive elements of a
+sequence to a callback function, conventionally named yield.
+The function stops either when the sequence is finished or
+when yield returns false, indicating to stop the iteration early.
+This package defines [Seq] and [Seq2]
+(pronounced like seek—the first syllable of sequence)
+as shorthands for iterators that pass 1 or 2 values per sequence element
+to yield:
+
+	type (
+		Seq[V any]     func(yield func(V) bool)
+		Seq2[K, V any] func(yield func(K, V) bool)
+	)
+
+Seq2 represents a sequence of paired values, conventionally key-value
+or index-value pairs.
+
+Yield returns true if the iterator should continue with the next
+element in the sequence, false if it should stop.
+
+Iterator functions are most often called by a range loop, as in:
+
+	func PrintAll[V any](seq iter.Seq[V]) {
+		for v := range seq {
+			fmt.Println(v)
+		}
+	}
+
+# Naming Conventions
+
+Iterator functions and methods are named for the sequence being walked:
+
+	// All returns an iterator over all elements in s.
+	func (s *Set[V]) All() iter.Seq[V]
+
+The iterator method on a collection type is conventionally named All,
+because it iterates a sequence of all the values in the collection.
+
+For a type containing multiple possible sequences, the iterator's name
+can indicate which sequence is being provided:
+
+	// Cities returns an iterator over the major cities in the country.
+	func (c *Country) Cities() iter.Seq[*City]
+
+	// Languages returns an iterator over the official spoken languages of the country.
+	func (c *Country) Languages() iter.Seq[string]
+
+If an iterator requires additional configuration, the constructor function
+can take additional configuration arguments:
+
+	// Scan returns an iterator over key-value pairs with min ≤ key ≤ max.
+	func (m *Map[K, V]) Scan(min, max K) iter.Seq2[K, V]
+
+	// Split returns an iterator over the (possibly-empty) substrings of s
+	// separated by sep.
+	func Split(s, sep string) iter.Seq[string]
+
+When there are multiple possible iteration orders, the method name may
+indicate that order:
+
+	// All returns an iterator over the list from head to tail.
+	func (l *List[V]) All() iter.Seq[V]
+
+	// Backward returns an iterator over the list from tail to head.
+	func (l *List[V]) Backward() iter.Seq[V]
+
+# Single-Use Iterators
+
+Most iterators provide the ability to walk an entire sequence:
+when called, the iterator does any setup necessary to start the
+sequence, then calls yield on successive elements of the sequence,
+and then cleans up before returning. Calling the iterator again
+walks the sequence again.
+
+Some iterators break that convention, providing the ability to walk a
+sequence only once. These “single-use iterators” typically report values
+from a data stream that cannot be rewound to start over.
+Calling the iterator again after stopping early may continue the
+stream, but calling it again after the sequence is finished will yield
+no values at all. Doc comments for functions or methods that return
+single-use iterators should document this fact:
+
+	// Lines returns an iterator over lines read from r.
+	// It returns a single-use iterator.
+	func (r *Reader) Lines() iter.Seq[string]
+
+# Pulling Values
+
+Functions and methods that accept or return iterators
+should use the standard [Seq] or [Seq2] types, to ensure
+compatibility with range loops and other iterator adapters.
+The standard iterators can be thought of as “push iterators”, which
+push values to the yield function.
+
+Sometimes a range loop is not the most natural way to consume values
+of the sequence. In this case, [Pull] converts a standard push iterator
+to a “pull iterator”, which can be called to pull one value at a time
+from the sequence. [Pull] starts an iterator and returns a pair
+of functions—next and stop—which return the next value from the iterator
+and stop it, respectively.
+
+# Misbehaving Iterators
+
+An iterator that calls yield after yield has returned false, after
+the range loop using it has finished, or after the loop body has
+panicked, is incorrect. When an iterator is used in a range loop, the
+compiled loop checks for these errors and reports them with a run-time
+panic. A loop also panics if its iterator recovers a panic raised by
+the loop body and then returns normally, because that would silently
+swallow the panic.
+
+# Standard Library Usage
+
+A few packages in the standard library provide iterator-based APIs,
+most notably the [maps] and [slices] packages.
+For example, [maps.Keys] returns an iterator over the keys of a map,
+while [slices.Sorted] collects the values of an iterator into a slice,
+sorts them, and returns the slice, so to iterate over the sorted keys of a map:
+
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		...
+	}
+
+The [iter/xiter] package provides adapters, such as Map, Filter and Zip,
+for composing iterators into pipelines.
+*/
 package iter
 
 import (
@@ -2,12 +2,11 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build goexperiment.rangefunc
-
-package iter
+package iter_test
 
 import (
 	"fmt"
+	. "iter"
 	"runtime"
 	"testing"
 )
@@ -0,0 +1,60 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xiter_test
+
+import (
+	"fmt"
+	"iter/xiter"
+	"slices"
+	"strings"
+)
+
+func Example() {
+	words := strings.Fields("the the quick brown fox fox jumps over the lazy dog")
+	long := func(s string) bool { return len(s) > 3 }
+	for i, w := range xiter.Enumerate(xiter.Filter(long, xiter.Compact(slices.Values(words)))) {
+		fmt.Println(i, strings.ToUpper(w))
+	}
+	// Output:
+	// 0 QUICK
+	// 1 BROWN
+	// 2 JUMPS
+	// 3 OVER
+	// 4 LAZY
+}
+
+func ExampleZip() {
+	names := []string{"Alice", "Bob", "Vera"}
+	ages := []int{30, 25}
+	for z := range xiter.Zip(slices.Values(names), slices.Values(ages)) {
+		if z.Ok2 {
+			fmt.Println(z.V1, z.V2)
+		} else {
+			fmt.Println(z.V1, "unknown")
+		}
+	}
+	// Output:
+	// Alice 30
+	// Bob 25
+	// Vera unknown
+}
+
+func ExampleReduce() {
+	sum := xiter.Reduce(func(sum, v int) int { return sum + v }, 0, slices.Values([]int{1, 2, 3, 4}))
+	fmt.Println(sum)
+	// Output:
+	// 10
+}
+
+func ExampleTakeWhile() {
+	lines := []string{"header: a", "header: b", "", "body"}
+	isHeader := func(s string) bool { return s != "" }
+	for h := range xiter.TakeWhile(isHeader, slices.Values(lines)) {
+		fmt.Println(h)
+	}
+	// Output:
+	// header: a
+	// header: b
+}
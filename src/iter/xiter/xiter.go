@@ -0,0 +1,341 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package xiter implements adapters for composing iterators
+// of type [iter.Seq] and [iter.Seq2] into pipelines.
+//
+// The adapters are lazy: each returns an iterator that does no work
+// until it is used, and then draws values from the underlying
+// iterators only as they are needed. When the consumer of an adapter
+// stops early, the adapter stops the underlying iterators too, and it
+// never calls its yield function again after yield returns false.
+//
+// Functions that take a callback take it as the first argument, so
+// that a pipeline reads from the innermost iterator outward:
+//
+//	evens := xiter.Filter(func(n int) bool { return n%2 == 0 }, slices.Values(nums))
+//	for s := range xiter.Map(strconv.Itoa, evens) {
+//		...
+//	}
+//
+// Most functions come in two forms, one for [iter.Seq] and one,
+// with the suffix 2, for [iter.Seq2].
+package xiter
+
+import "iter"
+
+// Concat returns an iterator over the concatenation of the sequences.
+func Concat[V any](seqs ...iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, seq := range seqs {
+			for v := range seq {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Concat2 returns an iterator over the concatenation of the sequences.
+func Concat2[K, V any](seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, seq := range seqs {
+			for k, v := range seq {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Map returns an iterator over f applied to seq.
+func Map[In, Out any](f func(In) Out, seq iter.Seq[In]) iter.Seq[Out] {
+	return func(yield func(Out) bool) {
+		for in := range seq {
+			if !yield(f(in)) {
+				return
+			}
+		}
+	}
+}
+
+// Map2 returns an iterator over f applied to seq.
+func Map2[KIn, VIn, KOut, VOut any](f func(KIn, VIn) (KOut, VOut), seq iter.Seq2[KIn, VIn]) iter.Seq2[KOut, VOut] {
+	return func(yield func(KOut, VOut) bool) {
+		for k, v := range seq {
+			if !yield(f(k, v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over seq that only includes
+// the values v for which f(v) is true.
+func Filter[V any](f func(V) bool, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range seq {
+			if f(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Filter2 returns an iterator over seq that only includes
+// the pairs k, v for which f(k, v) is true.
+func Filter2[K, V any](f func(K, V) bool, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if f(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Limit returns an iterator over seq that stops after n values.
+func Limit[V any](seq iter.Seq[V], n int) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if n <= 0 {
+			return
+		}
+		n := n
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if n--; n <= 0 {
+				return
+			}
+		}
+	}
+}
+
+// Limit2 returns an iterator over seq that stops after n key-value pairs.
+func Limit2[K, V any](seq iter.Seq2[K, V], n int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n <= 0 {
+			return
+		}
+		n := n
+		for k, v := range seq {
+			if !yield(k, v) {
+				return
+			}
+			if n--; n <= 0 {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns an iterator over the values of seq up to,
+// but not including, the first value v for which f(v) is false.
+func TakeWhile[V any](f func(V) bool, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range seq {
+			if !f(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile2 returns an iterator over the pairs of seq up to,
+// but not including, the first pair k, v for which f(k, v) is false.
+func TakeWhile2[K, V any](f func(K, V) bool, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if !f(k, v) || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// SkipWhile returns an iterator over the values of seq starting at
+// the first value v for which f(v) is false.
+func SkipWhile[V any](f func(V) bool, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		skipping := true
+		for v := range seq {
+			if skipping {
+				if f(v) {
+					continue
+				}
+				skipping = false
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SkipWhile2 returns an iterator over the pairs of seq starting at
+// the first pair k, v for which f(k, v) is false.
+func SkipWhile2[K, V any](f func(K, V) bool, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		skipping := true
+		for k, v := range seq {
+			if skipping {
+				if f(k, v) {
+					continue
+				}
+				skipping = false
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce combines the values in seq using f.
+// For each value v in seq, it updates sum = f(sum, v)
+// and then returns the final sum.
+// For example, if iterating over seq yields v1, v2, v3,
+// Reduce returns f(f(f(sum, v1), v2), v3).
+func Reduce[Sum, V any](f func(Sum, V) Sum, sum Sum, seq iter.Seq[V]) Sum {
+	for v := range seq {
+		sum = f(sum, v)
+	}
+	return sum
+}
+
+// Reduce2 combines the values in seq using f.
+// For each pair k, v in seq, it updates sum = f(sum, k, v)
+// and then returns the final sum.
+// For example, if iterating over seq yields (k1, v1), (k2, v2), (k3, v3)
+// Reduce2 returns f(f(f(sum, k1, v1), k2, v2), k3, v3).
+func Reduce2[Sum, K, V any](f func(Sum, K, V) Sum, sum Sum, seq iter.Seq2[K, V]) Sum {
+	for k, v := range seq {
+		sum = f(sum, k, v)
+	}
+	return sum
+}
+
+// Enumerate returns an iterator over the values of seq
+// paired with their indices, starting at 0.
+func Enumerate[V any](seq iter.Seq[V]) iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Compact returns an iterator over seq that replaces each run of
+// consecutive equal values with a single copy of the value.
+// It is the iterator analogue of [slices.Compact].
+func Compact[V comparable](seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		var last V
+		first := true
+		for v := range seq {
+			if !first && v == last {
+				continue
+			}
+			first = false
+			last = v
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// CompactFunc is like [Compact] but uses an equality function to
+// compare values. For runs of values that compare equal, CompactFunc
+// keeps the first one.
+func CompactFunc[V any](eq func(V, V) bool, seq iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		var last V
+		first := true
+		for v := range seq {
+			if !first && eq(last, v) {
+				continue
+			}
+			first = false
+			last = v
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// A Zipped is a pair of zipped values, one of which may be missing,
+// drawn from two different sequences.
+type Zipped[V1, V2 any] struct {
+	V1  V1
+	Ok1 bool // whether V1 is present (if not, it will be zero)
+	V2  V2
+	Ok2 bool // whether V2 is present (if not, it will be zero)
+}
+
+// Zip returns an iterator that iterates x and y in parallel,
+// yielding Zipped values of successive elements of x and y.
+// If one sequence ends before the other, the iteration continues
+// with Zipped values in which either Ok1 or Ok2 is false,
+// depending on which sequence ended first.
+//
+// Each time its result is used, Zip pulls values from x and y
+// using [iter.Pull], and it stops both before returning.
+func Zip[V1, V2 any](x iter.Seq[V1], y iter.Seq[V2]) iter.Seq[Zipped[V1, V2]] {
+	return func(yield func(z Zipped[V1, V2]) bool) {
+		next1, stop1 := iter.Pull(x)
+		defer stop1()
+		next2, stop2 := iter.Pull(y)
+		defer stop2()
+		for {
+			var val Zipped[V1, V2]
+			val.V1, val.Ok1 = next1()
+			val.V2, val.Ok2 = next2()
+			if (!val.Ok1 && !val.Ok2) || !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// A Zipped2 is a pair of zipped key-value pairs,
+// one of which may be missing, drawn from two different sequences.
+type Zipped2[K1, V1, K2, V2 any] struct {
+	K1  K1
+	V1  V1
+	Ok1 bool // whether K1, V1 are present (if not, they will be zero)
+	K2  K2
+	V2  V2
+	Ok2 bool // whether K2, V2 are present (if not, they will be zero)
+}
+
+// Zip2 returns an iterator that iterates x and y in parallel,
+// yielding Zipped2 values of successive elements of x and y.
+// If one sequence ends before the other, the iteration continues
+// with Zipped2 values in which either Ok1 or Ok2 is false,
+// depending on which sequence ended first.
+func Zip2[K1, V1, K2, V2 any](x iter.Seq2[K1, V1], y iter.Seq2[K2, V2]) iter.Seq[Zipped2[K1, V1, K2, V2]] {
+	return func(yield func(z Zipped2[K1, V1, K2, V2]) bool) {
+		next1, stop1 := iter.Pull2(x)
+		defer stop1()
+		next2, stop2 := iter.Pull2(y)
+		defer stop2()
+		for {
+			var val Zipped2[K1, V1, K2, V2]
+			val.K1, val.V1, val.Ok1 = next1()
+			val.K2, val.V2, val.Ok2 = next2()
+			if (!val.Ok1 && !val.Ok2) || !yield(val) {
+				return
+			}
+		}
+	}
+}
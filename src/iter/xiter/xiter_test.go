@@ -0,0 +1,381 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xiter_test
+
+import (
+	"iter"
+	. "iter/xiter"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// source is an iterator over a list of values that records how it
+// was used, so that tests can check that adapters stop it promptly.
+type source[V any] struct {
+	t       *testing.T
+	vals    []V
+	pulled  int  // number of values yielded
+	stopped bool // whether yield returned false
+}
+
+func (s *source[V]) seq(yield func(V) bool) {
+	s.pulled, s.stopped = 0, false
+	for _, v := range s.vals {
+		s.pulled++
+		if !yield(v) {
+			s.stopped = true
+			return
+		}
+	}
+}
+
+func (s *source[V]) seq2(yield func(int, V) bool) {
+	s.pulled, s.stopped = 0, false
+	for i, v := range s.vals {
+		s.pulled++
+		if !yield(i, v) {
+			s.stopped = true
+			return
+		}
+	}
+}
+
+func newSource[V any](t *testing.T, vals ...V) *source[V] {
+	return &source[V]{t: t, vals: vals}
+}
+
+// take calls seq directly, without a range loop, and collects at most
+// n values. It fails the test if seq calls yield after yield returned
+// false.
+func take[V any](t *testing.T, seq iter.Seq[V], n int) []V {
+	t.Helper()
+	var got []V
+	done := false
+	seq(func(v V) bool {
+		if done {
+			t.Errorf("yield called after it returned false (with %v)", v)
+			return false
+		}
+		if len(got) == n {
+			done = true
+			return false
+		}
+		got = append(got, v)
+		if len(got) == n {
+			done = true
+			return false
+		}
+		return true
+	})
+	return got
+}
+
+type pair[K, V any] struct {
+	k K
+	v V
+}
+
+// take2 is like take for an iter.Seq2.
+func take2[K, V any](t *testing.T, seq iter.Seq2[K, V], n int) []pair[K, V] {
+	t.Helper()
+	var got []pair[K, V]
+	done := false
+	seq(func(k K, v V) bool {
+		if done {
+			t.Errorf("yield called after it returned false (with %v, %v)", k, v)
+			return false
+		}
+		got = append(got, pair[K, V]{k, v})
+		if len(got) == n {
+			done = true
+			return false
+		}
+		return true
+	})
+	return got
+}
+
+func TestConcat(t *testing.T) {
+	a := newSource(t, 1, 2)
+	b := newSource(t, 3, 4)
+	c := newSource(t, 5, 6)
+	seq := Concat(a.seq, b.seq, Concat[int](), c.seq)
+	if got, want := take(t, seq, -1), []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("Concat = %v, want %v", got, want)
+	}
+	if got, want := take(t, seq, 3), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Concat stopped after 3 = %v, want %v", got, want)
+	}
+	if !b.stopped || b.pulled != 1 {
+		t.Errorf("Concat did not stop the second sequence promptly: pulled %d, stopped %v", b.pulled, b.stopped)
+	}
+	if c.pulled != 6-4 {
+		t.Errorf("Concat pulled from third sequence after stopping")
+	}
+}
+
+func TestConcat2(t *testing.T) {
+	a := newSource(t, "a", "b")
+	b := newSource(t, "c")
+	seq := Concat2(a.seq2, b.seq2)
+	want := []pair[int, string]{{0, "a"}, {1, "b"}, {0, "c"}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("Concat2 = %v, want %v", got, want)
+	}
+	if got := take2(t, seq, 1); !slices.Equal(got, want[:1]) || !a.stopped {
+		t.Errorf("Concat2 stopped after 1 = %v (stopped %v), want %v", got, a.stopped, want[:1])
+	}
+}
+
+func TestMap(t *testing.T) {
+	src := newSource(t, 1, 2, 3, 4)
+	seq := Map(strconv.Itoa, src.seq)
+	if got, want := take(t, seq, -1), []string{"1", "2", "3", "4"}; !slices.Equal(got, want) {
+		t.Errorf("Map = %v, want %v", got, want)
+	}
+	if got, want := take(t, seq, 2), []string{"1", "2"}; !slices.Equal(got, want) {
+		t.Errorf("Map stopped after 2 = %v, want %v", got, want)
+	}
+	if !src.stopped || src.pulled != 2 {
+		t.Errorf("Map did not stop its input promptly: pulled %d, stopped %v", src.pulled, src.stopped)
+	}
+}
+
+func TestMap2(t *testing.T) {
+	src := newSource(t, "a", "b", "c")
+	seq := Map2(func(i int, s string) (string, int) { return s, i * 10 }, src.seq2)
+	want := []pair[string, int]{{"a", 0}, {"b", 10}, {"c", 20}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("Map2 = %v, want %v", got, want)
+	}
+	if got := take2(t, seq, 2); !slices.Equal(got, want[:2]) || !src.stopped || src.pulled != 2 {
+		t.Errorf("Map2 stopped after 2 = %v (pulled %d), want %v", got, src.pulled, want[:2])
+	}
+}
+
+func even(n int) bool { return n%2 == 0 }
+
+func TestFilter(t *testing.T) {
+	src := newSource(t, 1, 2, 3, 4, 5, 6)
+	seq := Filter(even, src.seq)
+	if got, want := take(t, seq, -1), []int{2, 4, 6}; !slices.Equal(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+	if got, want := take(t, seq, 2), []int{2, 4}; !slices.Equal(got, want) {
+		t.Errorf("Filter stopped after 2 = %v, want %v", got, want)
+	}
+	if !src.stopped || src.pulled != 4 {
+		t.Errorf("Filter did not stop its input promptly: pulled %d, stopped %v", src.pulled, src.stopped)
+	}
+}
+
+func TestFilter2(t *testing.T) {
+	src := newSource(t, "a", "b", "c", "d")
+	seq := Filter2(func(i int, _ string) bool { return even(i) }, src.seq2)
+	want := []pair[int, string]{{0, "a"}, {2, "c"}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("Filter2 = %v, want %v", got, want)
+	}
+	if got := take2(t, seq, 1); !slices.Equal(got, want[:1]) || !src.stopped || src.pulled != 1 {
+		t.Errorf("Filter2 stopped after 1 = %v (pulled %d), want %v", got, src.pulled, want[:1])
+	}
+}
+
+func TestLimit(t *testing.T) {
+	for n := -1; n <= 5; n++ {
+		src := newSource(t, 1, 2, 3)
+		got := take(t, Limit(src.seq, n), -1)
+		want := []int{1, 2, 3}[:max(0, min(n, 3))]
+		if !slices.Equal(got, want) {
+			t.Errorf("Limit(%d) = %v, want %v", n, got, want)
+		}
+		// Limit must not pull a value beyond the limit.
+		if src.pulled != len(want) {
+			t.Errorf("Limit(%d) pulled %d values, want %d", n, src.pulled, len(want))
+		}
+	}
+
+	// The result is reusable.
+	seq := Limit(newSource(t, 1, 2, 3).seq, 2)
+	for range 2 {
+		if got, want := take(t, seq, -1), []int{1, 2}; !slices.Equal(got, want) {
+			t.Errorf("Limit(2) = %v, want %v", got, want)
+		}
+	}
+	if got, want := take(t, seq, 1), []int{1}; !slices.Equal(got, want) {
+		t.Errorf("Limit(2) stopped after 1 = %v, want %v", got, want)
+	}
+}
+
+func TestLimit2(t *testing.T) {
+	src := newSource(t, "a", "b", "c")
+	got := take2(t, Limit2(src.seq2, 2), -1)
+	want := []pair[int, string]{{0, "a"}, {1, "b"}}
+	if !slices.Equal(got, want) || src.pulled != 2 {
+		t.Errorf("Limit2(2) = %v (pulled %d), want %v", got, src.pulled, want)
+	}
+	src = newSource(t, "a", "b", "c")
+	if got := take2(t, Limit2(src.seq2, 0), -1); len(got) != 0 || src.pulled != 0 {
+		t.Errorf("Limit2(0) = %v (pulled %d), want none", got, src.pulled)
+	}
+}
+
+func TestTakeWhile(t *testing.T) {
+	src := newSource(t, 2, 4, 5, 6)
+	seq := TakeWhile(even, src.seq)
+	if got, want := take(t, seq, -1), []int{2, 4}; !slices.Equal(got, want) {
+		t.Errorf("TakeWhile = %v, want %v", got, want)
+	}
+	if !src.stopped || src.pulled != 3 {
+		t.Errorf("TakeWhile did not stop its input promptly: pulled %d, stopped %v", src.pulled, src.stopped)
+	}
+	if got, want := take(t, seq, 1), []int{2}; !slices.Equal(got, want) {
+		t.Errorf("TakeWhile stopped after 1 = %v, want %v", got, want)
+	}
+}
+
+func TestTakeWhile2(t *testing.T) {
+	src := newSource(t, "a", "b", "c")
+	seq := TakeWhile2(func(i int, _ string) bool { return i < 2 }, src.seq2)
+	want := []pair[int, string]{{0, "a"}, {1, "b"}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) || !src.stopped {
+		t.Errorf("TakeWhile2 = %v (stopped %v), want %v", got, src.stopped, want)
+	}
+}
+
+func TestSkipWhile(t *testing.T) {
+	src := newSource(t, 2, 4, 5, 6, 7)
+	seq := SkipWhile(even, src.seq)
+	if got, want := take(t, seq, -1), []int{5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("SkipWhile = %v, want %v", got, want)
+	}
+	if got, want := take(t, seq, 2), []int{5, 6}; !slices.Equal(got, want) {
+		t.Errorf("SkipWhile stopped after 2 = %v, want %v", got, want)
+	}
+	if !src.stopped || src.pulled != 4 {
+		t.Errorf("SkipWhile did not stop its input promptly: pulled %d, stopped %v", src.pulled, src.stopped)
+	}
+}
+
+func TestSkipWhile2(t *testing.T) {
+	src := newSource(t, "a", "b", "c")
+	seq := SkipWhile2(func(_ int, s string) bool { return s == "a" }, src.seq2)
+	want := []pair[int, string]{{1, "b"}, {2, "c"}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("SkipWhile2 = %v, want %v", got, want)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce(func(sum, v int) int { return sum + v }, 10, newSource(t, 1, 2, 3).seq)
+	if sum != 16 {
+		t.Errorf("Reduce = %d, want 16", sum)
+	}
+	s := Reduce2(func(s string, i int, v string) string { return s + strconv.Itoa(i) + v }, "", newSource(t, "a", "b").seq2)
+	if s != "0a1b" {
+		t.Errorf("Reduce2 = %q, want %q", s, "0a1b")
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	src := newSource(t, "a", "b", "c")
+	seq := Enumerate(src.seq)
+	want := []pair[int, string]{{0, "a"}, {1, "b"}, {2, "c"}}
+	if got := take2(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("Enumerate = %v, want %v", got, want)
+	}
+	// The indices restart each time the result is used.
+	if got := take2(t, seq, 2); !slices.Equal(got, want[:2]) || !src.stopped || src.pulled != 2 {
+		t.Errorf("Enumerate stopped after 2 = %v (pulled %d), want %v", got, src.pulled, want[:2])
+	}
+}
+
+func TestCompact(t *testing.T) {
+	src := newSource(t, 0, 0, 1, 1, 1, 2, 0, 3, 3)
+	seq := Compact(src.seq)
+	if got, want := take(t, seq, -1), []int{0, 1, 2, 0, 3}; !slices.Equal(got, want) {
+		t.Errorf("Compact = %v, want %v", got, want)
+	}
+	if got, want := take(t, seq, 2), []int{0, 1}; !slices.Equal(got, want) {
+		t.Errorf("Compact stopped after 2 = %v, want %v", got, want)
+	}
+	if !src.stopped || src.pulled != 3 {
+		t.Errorf("Compact did not stop its input promptly: pulled %d, stopped %v", src.pulled, src.stopped)
+	}
+
+	abs := func(a, b int) bool { return a == b || a == -b }
+	seq = CompactFunc(abs, newSource(t, 1, -1, 2, -2, 2, 3, -1).seq)
+	if got, want := take(t, seq, -1), []int{1, 2, 3, -1}; !slices.Equal(got, want) {
+		t.Errorf("CompactFunc = %v, want %v", got, want)
+	}
+}
+
+func TestZip(t *testing.T) {
+	x := newSource(t, 1, 2, 3)
+	y := newSource(t, "a", "b")
+	seq := Zip(x.seq, y.seq)
+	want := []Zipped[int, string]{
+		{1, true, "a", true},
+		{2, true, "b", true},
+		{3, true, "", false},
+	}
+	if got := take(t, seq, -1); !slices.Equal(got, want) {
+		t.Errorf("Zip = %v, want %v", got, want)
+	}
+	if got := take(t, seq, 1); !slices.Equal(got, want[:1]) {
+		t.Errorf("Zip stopped after 1 = %v, want %v", got, want[:1])
+	}
+	// Zip pulls one value ahead from each sequence at most,
+	// and stops both when it is done.
+	if !x.stopped || x.pulled != 1 || !y.stopped || y.pulled != 1 {
+		t.Errorf("Zip did not stop its inputs: x pulled %d, stopped %v; y pulled %d, stopped %v",
+			x.pulled, x.stopped, y.pulled, y.stopped)
+	}
+}
+
+func TestZip2(t *testing.T) {
+	x := newSource(t, "a")
+	y := newSource(t, 1.5, 2.5)
+	want := []Zipped2[int, string, int, float64]{
+		{0, "a", true, 0, 1.5, true},
+		{0, "", false, 1, 2.5, true},
+	}
+	if got := take(t, Zip2(x.seq2, y.seq2), -1); !slices.Equal(got, want) {
+		t.Errorf("Zip2 = %v, want %v", got, want)
+	}
+}
+
+// TestPanic checks that a panic in the consumer of a pipeline
+// propagates through the adapters.
+func TestPanic(t *testing.T) {
+	seq := Limit(Map(strconv.Itoa, Filter(even, Compact(Concat(newSource(t, 1, 2, 2, 4).seq)))), 10)
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want boom", r)
+		}
+	}()
+	for range seq {
+		panic("boom")
+	}
+	t.Errorf("loop did not panic")
+}
+
+// TestPipelineAllocs checks that the adapters allocate only when
+// building a pipeline, not per value.
+func TestPipelineAllocs(t *testing.T) {
+	sumSquares := func(s []int) int {
+		return Reduce(func(sum, v int) int { return sum + v }, 0,
+			Limit(Map(func(v int) int { return v * v }, Filter(even, slices.Values(s))), 3))
+	}
+	if sum := sumSquares([]int{1, 2, 3, 4, 5, 6, 7, 8}); sum != 4+16+36 {
+		t.Errorf("sum = %d, want %d", sum, 4+16+36)
+	}
+	short := testing.AllocsPerRun(100, func() { sumSquares(make([]int, 1)) })
+	long := testing.AllocsPerRun(100, func() { sumSquares(make([]int, 100)) })
+	if long != short {
+		t.Errorf("pipeline over 100 values allocated %v times, over 1 value %v times; want the same", long, short)
+	}
+}
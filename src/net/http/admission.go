@@ -0,0 +1,216 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Admission control: request rate and handler concurrency limits.
+
+package http
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A RateLimit is a token-bucket limit on the rate of requests.
+//
+// Tokens are added to the bucket at a rate of Limit per second,
+// up to a maximum of Burst tokens. Each request consumes one token;
+// a request arriving when the bucket is empty is rejected.
+// The bucket starts full.
+type RateLimit struct {
+	// Limit is the sustained number of requests allowed per second.
+	// A zero or negative Limit means no limit.
+	Limit float64
+
+	// Burst is the maximum number of requests allowed at once.
+	// If Burst is less than 1, a burst of 1 is used.
+	Burst int
+}
+
+func (l RateLimit) enabled() bool { return l.Limit > 0 }
+
+func (l RateLimit) burst() float64 { return float64(max(l.Burst, 1)) }
+
+// A tokenBucket implements a RateLimit.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take consumes a token from the bucket if one is available.
+// If not, it returns false and the time until a token is available.
+func (b *tokenBucket) take(l RateLimit, now time.Time) (ok bool, wait time.Duration) {
+	b.refill(l, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.Limit * float64(time.Second))
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *tokenBucket) refill(l RateLimit, now time.Time) {
+	if b.last.IsZero() {
+		b.tokens = l.burst()
+	} else if d := now.Sub(b.last); d > 0 {
+		b.tokens = math.Min(l.burst(), b.tokens+d.Seconds()*l.Limit)
+	}
+	b.last = now
+}
+
+// full reports whether the bucket would be full at time now,
+// in which case it is indistinguishable from a new bucket.
+func (b *tokenBucket) full(l RateLimit, now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*l.Limit >= l.burst()
+}
+
+// An admission implements a Server's rate and concurrency limits.
+type admission struct {
+	mu        sync.Mutex
+	server    tokenBucket
+	clients   map[string]*tokenBucket
+	nextSweep int // sweep idle clients when len(clients) reaches nextSweep
+
+	sem    chan struct{} // holds a value for each running handler
+	queued int           // number of requests waiting for sem; guarded by mu
+}
+
+// minClientSweep is the smallest number of tracked clients at which
+// idle clients are removed.
+const minClientSweep = 1024
+
+func (srv *Server) hasAdmissionLimits() bool {
+	return srv.MaxConcurrentHandlers > 0 || srv.RequestRate.enabled() || srv.ClientRequestRate.enabled()
+}
+
+func (srv *Server) admission() *admission {
+	srv.admissionOnce.Do(func() {
+		a := &admission{
+			clients:   make(map[string]*tokenBucket),
+			nextSweep: minClientSweep,
+		}
+		if srv.MaxConcurrentHandlers > 0 {
+			a.sem = make(chan struct{}, srv.MaxConcurrentHandlers)
+		}
+		srv.admissionState = a
+	})
+	return srv.admissionState
+}
+
+// admit applies the server's admission limits to req.
+// If the request is admitted, admit returns a function to be called
+// when the handler returns. Otherwise, it writes a rejection response
+// to rw and returns nil.
+func (srv *Server) admit(rw ResponseWriter, req *Request) (release func()) {
+	a := srv.admission()
+	now := time.Now()
+	if srv.ClientRequestRate.enabled() {
+		if ok, wait := a.takeClient(srv.ClientRequestRate, clientKey(req), now); !ok {
+			srv.reject(rw, StatusTooManyRequests, wait)
+			return nil
+		}
+	}
+	if srv.RequestRate.enabled() {
+		a.mu.Lock()
+		ok, wait := a.server.take(srv.RequestRate, now)
+		a.mu.Unlock()
+		if !ok {
+			srv.reject(rw, StatusServiceUnavailable, wait)
+			return nil
+		}
+	}
+	if a.sem == nil {
+		return func() {}
+	}
+	select {
+	case a.sem <- struct{}{}:
+		return a.release
+	default:
+	}
+
+	// Wait in the queue for a handler slot.
+	a.mu.Lock()
+	if a.queued >= srv.MaxQueuedRequests {
+		a.mu.Unlock()
+		srv.reject(rw, StatusServiceUnavailable, 0)
+		return nil
+	}
+	a.queued++
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.queued--
+		a.mu.Unlock()
+	}()
+	var timeout <-chan time.Time
+	if srv.QueueTimeout > 0 {
+		t := time.NewTimer(srv.QueueTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case a.sem <- struct{}{}:
+		return a.release
+	case <-timeout:
+		srv.reject(rw, StatusServiceUnavailable, 0)
+	case <-req.Context().Done():
+		// The client is gone; there is nobody to respond to,
+		// but record the rejection all the same.
+		srv.reject(rw, StatusServiceUnavailable, 0)
+	}
+	return nil
+}
+
+func (a *admission) release() {
+	<-a.sem
+}
+
+// takeClient takes a token from the bucket for the client identified by key.
+func (a *admission) takeClient(l RateLimit, key string, now time.Time) (ok bool, wait time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := a.clients[key]
+	if b == nil {
+		if len(a.clients) >= a.nextSweep {
+			// Forget clients whose buckets have refilled;
+			// a new bucket would behave identically.
+			for k, cb := range a.clients {
+				if cb.full(l, now) {
+					delete(a.clients, k)
+				}
+			}
+			a.nextSweep = max(minClientSweep, 2*len(a.clients))
+		}
+		b = new(tokenBucket)
+		a.clients[key] = b
+	}
+	return b.take(l, now)
+}
+
+// clientKey returns the key identifying the client that sent req
+// for the purposes of ClientRequestRate: its IP address.
+func clientKey(req *Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// reject replies to a request refused by admission control.
+// For HTTP/1 connections, it reports StateThrottled to the
+// ConnState hook.
+func (srv *Server) reject(rw ResponseWriter, code int, retryAfter time.Duration) {
+	if w, ok := rw.(*response); ok {
+		c := w.conn
+		c.setState(c.rwc, StateThrottled, runHooks)
+	}
+	if retryAfter > 0 {
+		secs := int64(math.Ceil(retryAfter.Seconds()))
+		rw.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	}
+	Error(rw, StatusText(code), code)
+}
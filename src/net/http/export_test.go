@@ -280,6 +280,13 @@ func (s *Server) ExportAllConnsByState() map[ConnState]int {
 	return states
 }
 
+func (s *Server) QueuedRequestsForTesting() int {
+	a := s.admission()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.queued
+}
+
 func (r *Request) WithT(t *testing.T) *Request {
 	return r.WithContext(context.WithValue(r.Context(), tLogKey{}, t.Logf))
 }
@@ -401,11 +401,11 @@ func TestConflictsWith(t *testing.T) {
 func TestRegisterConflict(t *testing.T) {
 	mux := NewServeMux()
 	pat1 := "/a/{x}/"
-	if err := mux.registerErr(pat1, NotFoundHandler()); err != nil {
+	if err := mux.registerErr(pat1, nil, NotFoundHandler()); err != nil {
 		t.Fatal(err)
 	}
 	pat2 := "/a/{y}/{z...}"
-	err := mux.registerErr(pat2, NotFoundHandler())
+	err := mux.registerErr(pat2, nil, NotFoundHandler())
 	var got string
 	if err == nil {
 		got = "<nil>"
@@ -81,6 +81,29 @@ func (n *routingNode) set(p *pattern, h Handler) {
 	n.handler = h
 }
 
+// findLeaf returns the node at which p would be added to the tree at root,
+// or nil if there is none. The node need not hold p or any pattern.
+func (root *routingNode) findLeaf(p *pattern) *routingNode {
+	n := root.findChild(p.host)
+	if n != nil {
+		n = n.findChild(p.method)
+	}
+	for _, seg := range p.segments {
+		if n == nil {
+			return nil
+		}
+		switch {
+		case seg.multi:
+			n = n.findChild("*")
+		case seg.wild:
+			n = n.findChild("")
+		default:
+			n = n.findChild(seg.s)
+		}
+	}
+	return n
+}
+
 // addChild adds a child node with the given key to n
 // if one does not exist, and returns the child.
 func (n *routingNode) addChild(key string) *routingNode {
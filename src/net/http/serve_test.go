@@ -34,6 +34,7 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -388,6 +389,89 @@ func TestServeMuxHandler(t *testing.T) {
 	}
 }
 
+func TestServeMuxRoutes(t *testing.T) {
+	mux := NewServeMux()
+	h := HandlerFunc(func(ResponseWriter, *Request) {})
+	sub := NewServeMux()
+	mux.Handle("GET /items/{id}", h)
+	mux.Handle("example.com/", h)
+	mux.Mount("POST  /api/{v}/", sub)
+
+	got := mux.Routes()
+	want := []struct {
+		pattern, method, host, path string
+		mounted                     bool
+	}{
+		{"GET /items/{id}", "GET", "", "/items/{id}", false},
+		{"example.com/", "", "example.com", "/", false},
+		{"POST  /api/{v}/", "POST", "", "/api/{v}/", true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d routes, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Pattern != w.pattern || g.Method != w.method || g.Host != w.host || g.Path != w.path || g.Mounted != w.mounted {
+			t.Errorf("route %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if got[2].Handler != sub {
+		t.Errorf("mounted route handler = %v, want sub mux", got[2].Handler)
+	}
+}
+
+func TestServeMuxMount(t *testing.T) {
+	sub := NewServeMux()
+	sub.HandleFunc("/users/{id}", func(w ResponseWriter, r *Request) {
+		fmt.Fprintf(w, "path=%s version=%s id=%s", r.URL.Path, r.PathValue("version"), r.PathValue("id"))
+	})
+	sub.HandleFunc("/{$}", func(w ResponseWriter, r *Request) {
+		fmt.Fprintf(w, "root version=%s", r.PathValue("version"))
+	})
+	mux := NewServeMux()
+	mux.Mount("/v/{version}/", sub)
+	mux.Mount("/static/", HandlerFunc(func(w ResponseWriter, r *Request) {
+		fmt.Fprintf(w, "path=%s raw=%s", r.URL.Path, r.URL.RawPath)
+	}))
+
+	for _, test := range []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/v/2/users/7", 200, "path=/users/7 version=2 id=7"},
+		{"/v/2/", 200, "root version=2"},
+		{"/v/2", 301, ""},
+		{"/v/2/nope", 404, ""},
+		{"/static/a/b.css", 200, "path=/a/b.css raw="},
+		{"/static/a%2Fb", 200, "path=/a/b raw=/a%2Fb"},
+	} {
+		r := httptest.NewRequest("GET", test.path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != test.code {
+			t.Errorf("%s: code = %d, want %d", test.path, w.Code, test.code)
+			continue
+		}
+		if test.body != "" && w.Body.String() != test.body {
+			t.Errorf("%s: body = %q, want %q", test.path, w.Body.String(), test.body)
+		}
+	}
+}
+
+func TestServeMuxMountPanics(t *testing.T) {
+	for _, prefix := range []string{"/a", "/a/{$}", "/a/{rest...}", "/{x"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Mount(%q) did not panic", prefix)
+				}
+			}()
+			NewServeMux().Mount(prefix, NotFoundHandler())
+		}()
+	}
+}
+
 // Issue 24297
 func TestServeMuxHandleFuncWithNilHandler(t *testing.T) {
 	setParallel(t)
@@ -7144,3 +7228,158 @@ func testErrorContentLength(t *testing.T, mode testMode) {
 		t.Fatalf("read body: %q, want %q", string(body), errorBody)
 	}
 }
+
+func TestServerClientRequestRate(t *testing.T) { run(t, testServerClientRequestRate) }
+func testServerClientRequestRate(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}), func(ts *httptest.Server) {
+		ts.Config.ClientRequestRate = RateLimit{Limit: 0.5, Burst: 2}
+	})
+	for i, want := range []int{200, 200, 429} {
+		res, err := cst.c.Get(cst.ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Fatalf("request %d: status = %d, want %d", i, res.StatusCode, want)
+		}
+		if want == 429 {
+			if got := res.Header.Get("Retry-After"); got != "2" {
+				t.Errorf("Retry-After = %q, want %q", got, "2")
+			}
+		}
+	}
+}
+
+func TestServerRequestRate(t *testing.T) { run(t, testServerRequestRate) }
+func testServerRequestRate(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}), func(ts *httptest.Server) {
+		ts.Config.RequestRate = RateLimit{Limit: 1e-3}
+	})
+	for i, want := range []int{200, 503} {
+		res, err := cst.c.Get(cst.ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Fatalf("request %d: status = %d, want %d", i, res.StatusCode, want)
+		}
+	}
+}
+
+func TestServerMaxConcurrentHandlers(t *testing.T) { run(t, testServerMaxConcurrentHandlers) }
+func testServerMaxConcurrentHandlers(t *testing.T, mode testMode) {
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		entered <- struct{}{}
+		<-unblock
+	}), func(ts *httptest.Server) {
+		ts.Config.MaxConcurrentHandlers = 1
+		ts.Config.MaxQueuedRequests = 1
+	})
+
+	get := func() <-chan int {
+		c := make(chan int, 1)
+		go func() {
+			res, err := cst.c.Get(cst.ts.URL)
+			if err != nil {
+				t.Error(err)
+				c <- 0
+				return
+			}
+			res.Body.Close()
+			c <- res.StatusCode
+		}()
+		return c
+	}
+
+	first := get()
+	<-entered
+	second := get()
+	for cst.ts.Config.QueuedRequestsForTesting() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := <-get(); got != 503 {
+		t.Errorf("request with full queue: status = %d, want 503", got)
+	}
+
+	close(unblock)
+	if got := <-first; got != 200 {
+		t.Errorf("first request: status = %d, want 200", got)
+	}
+	<-entered
+	if got := <-second; got != 200 {
+		t.Errorf("queued request: status = %d, want 200", got)
+	}
+}
+
+func TestServerQueueTimeout(t *testing.T) { run(t, testServerQueueTimeout) }
+func testServerQueueTimeout(t *testing.T, mode testMode) {
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		close(entered)
+		<-unblock
+	}), func(ts *httptest.Server) {
+		ts.Config.MaxConcurrentHandlers = 1
+		ts.Config.MaxQueuedRequests = 1
+		ts.Config.QueueTimeout = time.Millisecond
+	})
+	defer close(unblock)
+
+	go func() {
+		res, err := cst.c.Get(cst.ts.URL)
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+	<-entered
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != 503 {
+		t.Errorf("status = %d, want 503", res.StatusCode)
+	}
+}
+
+func TestServerConnStateThrottled(t *testing.T) {
+	run(t, testServerConnStateThrottled, []testMode{http1Mode})
+}
+func testServerConnStateThrottled(t *testing.T, mode testMode) {
+	var (
+		mu     sync.Mutex
+		states []ConnState
+	)
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}), func(ts *httptest.Server) {
+		ts.Config.RequestRate = RateLimit{Limit: 1e-3}
+		ts.Config.ConnState = func(c net.Conn, state ConnState) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, state)
+		}
+	})
+	for range 2 {
+		res, err := cst.c.Get(cst.ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	want := []ConnState{StateNew, StateActive, StateIdle, StateActive, StateThrottled, StateIdle}
+	for {
+		mu.Lock()
+		got := slices.Clone(states)
+		mu.Unlock()
+		if len(got) >= len(want) {
+			if !slices.Equal(got[:len(want)], want) {
+				t.Fatalf("states = %v, want %v", got, want)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
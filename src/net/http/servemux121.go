@@ -11,8 +11,10 @@ package http
 // they mostly involve renaming functions, usually by unexporting them.
 
 import (
+	"errors"
 	"internal/godebug"
 	"net/url"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -37,6 +39,7 @@ type serveMux121 struct {
 	m     map[string]muxEntry
 	es    []muxEntry // slice of entries sorted from longest to shortest.
 	hosts bool       // whether any patterns contain hostnames
+	rts   []Route    // registrations, in order, for ServeMux.Routes
 }
 
 type muxEntry struct {
@@ -55,17 +58,39 @@ func (mux *serveMux121) handle(pattern string, handler Handler) {
 	if handler == nil {
 		panic("http: nil handler")
 	}
-	if _, exist := mux.m[pattern]; exist {
-		panic("http: multiple registrations for " + pattern)
+	if e, exist := mux.m[pattern]; exist {
+		// A pattern registered with HandleIf takes one unconditional handler.
+		c, ok := e.h.(*conditionalHandler)
+		if !ok || c.fallback != nil {
+			panic("http: multiple registrations for " + pattern)
+		}
+		mux.setLocked(pattern, c.with(nil, handler))
+	} else {
+		mux.setLocked(pattern, handler)
 	}
+	mux.rts = append(mux.rts, route121(pattern, nil, handler))
+}
 
+// setLocked registers handler for pattern, replacing the handler
+// already registered for pattern, if any.
+// mux.mu must be held.
+func (mux *serveMux121) setLocked(pattern string, handler Handler) {
 	if mux.m == nil {
 		mux.m = make(map[string]muxEntry)
 	}
+	_, exist := mux.m[pattern]
 	e := muxEntry{h: handler, pattern: pattern}
 	mux.m[pattern] = e
 	if pattern[len(pattern)-1] == '/' {
-		mux.es = appendSorted(mux.es, e)
+		if exist {
+			for i := range mux.es {
+				if mux.es[i].pattern == pattern {
+					mux.es[i] = e
+				}
+			}
+		} else {
+			mux.es = appendSorted(mux.es, e)
+		}
 	}
 
 	if pattern[0] != '/' {
@@ -209,3 +234,77 @@ func (mux *serveMux121) shouldRedirectRLocked(host, path string) bool {
 
 	return false
 }
+
+// The methods below are not derived from Go 1.21; they implement
+// newer ServeMux methods for Go 1.21 patterns.
+
+// checkPattern implements ServeMux.CheckPattern.
+func (mux *serveMux121) checkPattern(pattern string) error {
+	if pattern == "" {
+		return errors.New("http: invalid pattern")
+	}
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	if e, exist := mux.m[pattern]; exist {
+		if c, ok := e.h.(*conditionalHandler); ok && c.fallback == nil {
+			return nil
+		}
+		return errors.New("http: multiple registrations for " + pattern)
+	}
+	return nil
+}
+
+// handleIf implements ServeMux.HandleIf.
+func (mux *serveMux121) handleIf(pattern string, match func(*Request) bool, handler Handler) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	if pattern == "" {
+		panic("http: invalid pattern")
+	}
+	if handler == nil {
+		panic("http: nil handler")
+	}
+	c := new(conditionalHandler)
+	if e, exist := mux.m[pattern]; exist {
+		var ok bool
+		if c, ok = e.h.(*conditionalHandler); !ok {
+			c = &conditionalHandler{fallback: e.h}
+		}
+	}
+	mux.setLocked(pattern, c.with(match, handler))
+	mux.rts = append(mux.rts, route121(pattern, match, handler))
+}
+
+// routes implements ServeMux.Routes.
+func (mux *serveMux121) routes() []Route {
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	return slices.Clone(mux.rts)
+}
+
+// route121 returns the Route for a registration of handler
+// for pattern with the given match function.
+func route121(pattern string, match func(*Request) bool, handler Handler) Route {
+	rt := Route{Pattern: pattern, Handler: handler, Match: match}
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		rt.Host, rt.Path = pattern[:i], pattern[i:]
+	}
+	if m, ok := handler.(*mountedHandler); ok {
+		rt.Handler = m.h
+		rt.Mounted = true
+	}
+	return rt
+}
+
+// mount implements ServeMux.Mount.
+func (mux *serveMux121) mount(prefix string, handler Handler) {
+	i := strings.IndexByte(prefix, '/')
+	if i < 0 || !strings.HasSuffix(prefix, "/") {
+		panic("http: mount prefix " + prefix + " does not end in a slash")
+	}
+	if handler == nil {
+		panic("http: nil handler")
+	}
+	mux.handle(prefix, &mountedHandler{prefix: strings.TrimSuffix(prefix[i:], "/"), h: handler})
+}
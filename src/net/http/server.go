@@ -2434,11 +2434,18 @@ func RedirectHandler(url string, code int) Handler {
 //     This change mostly affects how paths with %2F escapes adjacent to slashes are treated.
 //     See https://go.dev/issue/21955 for details.
 type ServeMux struct {
-	mu       sync.RWMutex
-	tree     routingNode
-	index    routingIndex
-	patterns []*pattern  // TODO(jba): remove if possible
-	mux121   serveMux121 // used only when GODEBUG=httpmuxgo121=1
+	mu     sync.RWMutex
+	tree   routingNode
+	index  routingIndex
+	routes []muxRoute  // registered patterns, in registration order
+	mux121 serveMux121 // used only when GODEBUG=httpmuxgo121=1
+}
+
+// A muxRoute is a pattern registered with a ServeMux and its handler.
+type muxRoute struct {
+	pat     *pattern
+	match   func(*Request) bool // from ServeMux.HandleIf; nil otherwise
+	handler Handler
 }
 
 // NewServeMux allocates and returns a new [ServeMux].
@@ -2523,13 +2530,13 @@ func (mux *ServeMux) findHandler(r *Request) (h Handler, patStr string, _ *patte
 		// If r.URL.Path is /tree and its handler is not registered,
 		// the /tree -> /tree/ redirect applies to CONNECT requests
 		// but the path canonicalization does not.
-		_, _, u := mux.matchOrRedirect(host, r.Method, path, r.URL)
+		_, _, _, u := mux.matchOrRedirect(host, r.Method, path, r.URL)
 		if u != nil {
 			return RedirectHandler(u.String(), StatusMovedPermanently), u.Path, nil, nil
 		}
 		// Redo the match, this time with r.Host instead of r.URL.Host.
 		// Pass a nil URL to skip the trailing-slash redirect logic.
-		n, matches, _ = mux.matchOrRedirect(r.Host, r.Method, path, nil)
+		n, h, matches, _ = mux.matchOrRedirect(r.Host, r.Method, path, nil)
 	} else {
 		// All other requests have any port stripped and path cleaned
 		// before passing to mux.handler.
@@ -2539,7 +2546,7 @@ func (mux *ServeMux) findHandler(r *Request) (h Handler, patStr string, _ *patte
 		// If the given path is /tree and its handler is not registered,
 		// redirect for /tree/.
 		var u *url.URL
-		n, matches, u = mux.matchOrRedirect(host, r.Method, path, r.URL)
+		n, h, matches, u = mux.matchOrRedirect(host, r.Method, path, r.URL)
 		if u != nil {
 			return RedirectHandler(u.String(), StatusMovedPermanently), u.Path, nil, nil
 		}
@@ -2566,20 +2573,26 @@ func (mux *ServeMux) findHandler(r *Request) (h Handler, patStr string, _ *patte
 		}
 		return NotFoundHandler(), "", nil, nil
 	}
-	return n.handler, n.pattern.String(), n.pattern, matches
+	return h, n.pattern.String(), n.pattern, matches
 }
 
 // matchOrRedirect looks up a node in the tree that matches the host, method and path.
 //
+// It returns the node's handler as well, since [ServeMux.HandleIf] may
+// replace it while mux.mu is held.
+//
 // If the url argument is non-nil, handler also deals with trailing-slash
 // redirection: when a path doesn't match exactly, the match is tried again
 // after appending "/" to the path. If that second match succeeds, the last
 // return value is the URL to redirect to.
-func (mux *ServeMux) matchOrRedirect(host, method, path string, u *url.URL) (_ *routingNode, matches []string, redirectTo *url.URL) {
+func (mux *ServeMux) matchOrRedirect(host, method, path string, u *url.URL) (_ *routingNode, h Handler, matches []string, redirectTo *url.URL) {
 	mux.mu.RLock()
 	defer mux.mu.RUnlock()
 
 	n, matches := mux.tree.match(host, method, path)
+	if n != nil {
+		h = n.handler
+	}
 	// If we have an exact match, or we were asked not to try trailing-slash redirection,
 	// or the URL already has a trailing slash, then we're done.
 	if !exactMatch(n, path) && u != nil && !strings.HasSuffix(path, "/") {
@@ -2587,10 +2600,10 @@ func (mux *ServeMux) matchOrRedirect(host, method, path string, u *url.URL) (_ *
 		path += "/"
 		n2, _ := mux.tree.match(host, method, path)
 		if exactMatch(n2, path) {
-			return nil, nil, &url.URL{Path: cleanPath(u.Path) + "/", RawQuery: u.RawQuery}
+			return nil, nil, nil, &url.URL{Path: cleanPath(u.Path) + "/", RawQuery: u.RawQuery}
 		}
 	}
-	return n, matches, nil
+	return n, h, matches, nil
 }
 
 // exactMatch reports whether the node's pattern exactly matches the path.
@@ -2697,7 +2710,7 @@ func (mux *ServeMux) Handle(pattern string, handler Handler) {
 	if use121 {
 		mux.mux121.handle(pattern, handler)
 	} else {
-		mux.register(pattern, handler)
+		mux.register(pattern, nil, handler)
 	}
 }
 
@@ -2708,7 +2721,7 @@ func (mux *ServeMux) HandleFunc(pattern string, handler func(ResponseWriter, *Re
 	if use121 {
 		mux.mux121.handleFunc(pattern, handler)
 	} else {
-		mux.register(pattern, HandlerFunc(handler))
+		mux.register(pattern, nil, HandlerFunc(handler))
 	}
 }
 
@@ -2718,7 +2731,7 @@ func Handle(pattern string, handler Handler) {
 	if use121 {
 		DefaultServeMux.mux121.handle(pattern, handler)
 	} else {
-		DefaultServeMux.register(pattern, handler)
+		DefaultServeMux.register(pattern, nil, handler)
 	}
 }
 
@@ -2728,17 +2741,103 @@ func HandleFunc(pattern string, handler func(ResponseWriter, *Request)) {
 	if use121 {
 		DefaultServeMux.mux121.handleFunc(pattern, handler)
 	} else {
-		DefaultServeMux.register(pattern, HandlerFunc(handler))
+		DefaultServeMux.register(pattern, nil, HandlerFunc(handler))
+	}
+}
+
+// HandleIf registers handler for pattern, to serve only the requests for
+// which match returns true. It can be used to route on request headers,
+// with [MatchHeader], or on any other property of the request.
+//
+// Several handlers may be registered for the same pattern with HandleIf,
+// provided they use the same pattern string. A request matching the
+// pattern is served by the first of them, in registration order, whose
+// match function returns true. A handler registered for that pattern string
+// with [ServeMux.Handle], before or after them, serves the requests that
+// none of them accept; if there is none, those requests get a 404 Not Found
+// response. Matching does not go on to other, less specific patterns.
+//
+// Match functions may be called concurrently, and must not modify the
+// request.
+//
+// HandleIf panics if match is nil, and under the same conditions as
+// [ServeMux.Handle], except that a pattern string may be registered more
+// than once as described above.
+func (mux *ServeMux) HandleIf(pattern string, match func(*Request) bool, handler Handler) {
+	if match == nil {
+		panic("http: nil match function")
+	}
+	if use121 {
+		mux.mux121.handleIf(pattern, match, handler)
+	} else {
+		mux.register(pattern, match, handler)
+	}
+}
+
+// MatchHeader returns a match function for [ServeMux.HandleIf] that reports
+// whether a request has a header field named key with the given value.
+// The key is case insensitive, as for [Header.Get]. A request with several
+// fields named key matches if any of them has the value; field values are
+// compared whole, without splitting comma-separated lists.
+// If value is empty, any request with a field named key matches.
+func MatchHeader(key, value string) func(*Request) bool {
+	key = CanonicalHeaderKey(key)
+	return func(r *Request) bool {
+		vs, ok := r.Header[key]
+		if value == "" {
+			return ok
+		}
+		return slices.Contains(vs, value)
+	}
+}
+
+// A conditionalHandler serves a pattern registered with ServeMux.HandleIf.
+// It is immutable: registering another handler for the pattern replaces it.
+type conditionalHandler struct {
+	conds    []muxCondition
+	fallback Handler // registered with ServeMux.Handle; may be nil
+}
+
+// A muxCondition is a handler registered with ServeMux.HandleIf.
+type muxCondition struct {
+	match func(*Request) bool
+	h     Handler
+}
+
+// with returns a copy of c with h added to it, as a conditional handler
+// if match is non-nil and as the fallback otherwise.
+func (c *conditionalHandler) with(match func(*Request) bool, h Handler) *conditionalHandler {
+	c2 := &conditionalHandler{conds: c.conds, fallback: c.fallback}
+	if match == nil {
+		c2.fallback = h
+	} else {
+		c2.conds = append(slices.Clip(c.conds), muxCondition{match, h})
 	}
+	return c2
 }
 
-func (mux *ServeMux) register(pattern string, handler Handler) {
-	if err := mux.registerErr(pattern, handler); err != nil {
+func (c *conditionalHandler) ServeHTTP(w ResponseWriter, r *Request) {
+	for _, cond := range c.conds {
+		if cond.match(r) {
+			cond.h.ServeHTTP(w, r)
+			return
+		}
+	}
+	if c.fallback != nil {
+		c.fallback.ServeHTTP(w, r)
+		return
+	}
+	NotFound(w, r)
+}
+
+func (mux *ServeMux) register(pattern string, match func(*Request) bool, handler Handler) {
+	if err := mux.registerErr(pattern, match, handler); err != nil {
 		panic(err)
 	}
 }
 
-func (mux *ServeMux) registerErr(patstr string, handler Handler) error {
+// registerErr registers handler for patstr, conditionally if match is non-nil.
+func (mux *ServeMux) registerErr(patstr string, match func(*Request) bool, handler Handler) error {
 	if patstr == "" {
 		return errors.New("http: invalid pattern")
 	}
@@ -2765,21 +2864,228 @@ func (mux *ServeMux) registerErr(patstr string, handler Handler) error {
 
 	mux.mu.Lock()
 	defer mux.mu.Unlock()
-	// Check for conflict.
-	if err := mux.index.possiblyConflictingPatterns(pat, func(pat2 *pattern) error {
+	if n := mux.sharedLeafLocked(pat, match); n != nil {
+		c, ok := n.handler.(*conditionalHandler)
+		if !ok {
+			c = &conditionalHandler{fallback: n.handler}
+		}
+		n.handler = c.with(match, handler)
+		mux.routes = append(mux.routes, muxRoute{pat, match, handler})
+		return nil
+	}
+	if err := mux.checkConflictsLocked(pat); err != nil {
+		return err
+	}
+	h := handler
+	if match != nil {
+		h = new(conditionalHandler).with(match, handler)
+	}
+	mux.tree.addPattern(pat, h)
+	mux.index.addPattern(pat)
+	mux.routes = append(mux.routes, muxRoute{pat, match, handler})
+	return nil
+}
+
+// sharedLeafLocked returns the tree node holding the pattern registered
+// with the same string as pat, if a handler registered for pat with match
+// can share it as described at [ServeMux.HandleIf]. Otherwise it returns nil.
+// mux.mu must be held.
+func (mux *ServeMux) sharedLeafLocked(pat *pattern, match func(*Request) bool) *routingNode {
+	n := mux.tree.findLeaf(pat)
+	if n == nil || n.pattern == nil || n.pattern.str != pat.str {
+		return nil
+	}
+	if c, ok := n.handler.(*conditionalHandler); match != nil || ok && c.fallback == nil {
+		return n
+	}
+	return nil
+}
+
+// checkConflictsLocked returns an error if pat conflicts with
+// a registered pattern.
+// mux.mu must be held.
+func (mux *ServeMux) checkConflictsLocked(pat *pattern) error {
+	return mux.index.possiblyConflictingPatterns(pat, func(pat2 *pattern) error {
 		if pat.conflictsWith(pat2) {
 			d := describeConflict(pat, pat2)
+			if pat.loc == "" {
+				return fmt.Errorf("pattern %q conflicts with pattern %q (registered at %s):\n%s",
+					pat, pat2, pat2.loc, d)
+			}
 			return fmt.Errorf("pattern %q (registered at %s) conflicts with pattern %q (registered at %s):\n%s",
 				pat, pat.loc, pat2, pat2.loc, d)
 		}
 		return nil
-	}); err != nil {
-		return err
+	})
+}
+
+// CheckPattern reports whether pattern could be registered with mux.
+// It returns a non-nil error if pattern is invalid or conflicts with
+// a pattern that is already registered; those are the conditions
+// under which [ServeMux.Handle] would panic. A pattern registered only
+// with [ServeMux.HandleIf] does not conflict with the same pattern string.
+func (mux *ServeMux) CheckPattern(pattern string) error {
+	if use121 {
+		return mux.mux121.checkPattern(pattern)
 	}
-	mux.tree.addPattern(pat, handler)
-	mux.index.addPattern(pat)
-	mux.patterns = append(mux.patterns, pat)
-	return nil
+	if pattern == "" {
+		return errors.New("http: invalid pattern")
+	}
+	pat, err := parsePattern(pattern)
+	if err != nil {
+		return fmt.Errorf("parsing %q: %w", pattern, err)
+	}
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	if mux.sharedLeafLocked(pat, nil) != nil {
+		return nil
+	}
+	return mux.checkConflictsLocked(pat)
+}
+
+// A Route describes a pattern registered with a [ServeMux].
+type Route struct {
+	// Pattern is the pattern as it was registered.
+	Pattern string
+
+	// Method, Host and Path are the parts of Pattern.
+	// Method and Host are empty if the pattern does not specify them.
+	Method string
+	Host   string
+	Path   string
+
+	// Handler is the handler registered for Pattern.
+	// For a route added with [ServeMux.Mount], it is the mounted handler.
+	Handler Handler
+
+	// Match is the match function the route was registered with using
+	// [ServeMux.HandleIf], or nil.
+	Match func(*Request) bool
+
+	// Mounted reports whether the route was added with [ServeMux.Mount],
+	// so that Handler sees request paths with the prefix removed.
+	Mounted bool
+}
+
+// Routes returns the patterns registered with mux, in the order
+// in which they were registered.
+// Routes added to mounted muxes are not included; they can be
+// found by calling Routes on a [Route.Handler] that is a *ServeMux.
+func (mux *ServeMux) Routes() []Route {
+	if use121 {
+		return mux.mux121.routes()
+	}
+	mux.mu.RLock()
+	defer mux.mu.RUnlock()
+	routes := make([]Route, 0, len(mux.routes))
+	for _, r := range mux.routes {
+		p := r.pat
+		rest := p.str
+		if p.method != "" {
+			rest = strings.TrimLeft(rest[len(p.method):], " \t")
+		}
+		rt := Route{
+			Pattern: p.str,
+			Method:  p.method,
+			Host:    p.host,
+			Path:    rest[len(p.host):],
+			Handler: r.handler,
+			Match:   r.match,
+		}
+		if m, ok := r.handler.(*mountedHandler); ok {
+			rt.Handler = m.h
+			rt.Mounted = true
+		}
+		routes = append(routes, rt)
+	}
+	return routes
+}
+
+// Mount registers handler to serve the subtree of requests matching prefix.
+// The prefix is a pattern whose path ends in a slash, such as "/api/"
+// or "GET example.com/v/{version}/". It may contain wildcards.
+//
+// Before calling handler, Mount removes the path segments matched by
+// prefix from the request URL, leaving a path that begins with a slash.
+// For example, with the prefix "/v/{version}/", a request for
+// "/v/2/users/7" is passed to handler with the path "/users/7".
+// The values of the prefix's wildcards remain available to handler
+// through [Request.PathValue], even if handler is itself a [ServeMux].
+//
+// Mount panics under the same conditions as [ServeMux.Handle], or if
+// the path of prefix does not end in a slash.
+// Middleware can be applied to the whole subtree by wrapping handler.
+func (mux *ServeMux) Mount(prefix string, handler Handler) {
+	if use121 {
+		mux.mux121.mount(prefix, handler)
+		return
+	}
+	pat, err := parsePattern(prefix)
+	if err != nil {
+		panic(fmt.Errorf("parsing %q: %w", prefix, err))
+	}
+	if last := pat.lastSegment(); !last.multi || last.s != "" {
+		panic(fmt.Errorf("http: mount prefix %q does not end in a slash", prefix))
+	}
+	if handler == nil {
+		panic("http: nil handler")
+	}
+	mux.register(prefix, nil, &mountedHandler{pat: pat, h: handler})
+}
+
+// A mountedHandler serves a subtree registered with ServeMux.Mount.
+type mountedHandler struct {
+	pat    *pattern // parsed prefix; nil for Go 1.21 behavior
+	prefix string   // path prefix to strip when pat is nil
+	h      Handler
+}
+
+func (m *mountedHandler) ServeHTTP(w ResponseWriter, r *Request) {
+	if m.pat == nil {
+		StripPrefix(m.prefix, m.h).ServeHTTP(w, r)
+		return
+	}
+	// Remove one path segment for each pattern segment
+	// before the final trailing slash.
+	rest := r.URL.EscapedPath()
+	for range len(m.pat.segments) - 1 {
+		// rest begins with the slash before the segment to remove.
+		if rest == "" {
+			break
+		}
+		slash := strings.IndexByte(rest[1:], '/')
+		if slash < 0 {
+			rest = ""
+			break
+		}
+		rest = rest[1+slash:] // keep the slash after the segment
+	}
+	if rest == "" {
+		rest = "/"
+	}
+
+	r2 := new(Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = pathUnescape(rest)
+	r2.URL.RawPath = ""
+	if r2.URL.EscapedPath() != rest {
+		r2.URL.RawPath = rest
+	}
+	// Move the prefix's wildcard values to otherValues, so they
+	// survive matching by a mounted ServeMux.
+	r2.pat, r2.matches = nil, nil
+	r2.otherValues = make(map[string]string, len(r.otherValues)+len(r.matches))
+	for k, v := range r.otherValues {
+		r2.otherValues[k] = v
+	}
+	for _, seg := range m.pat.segments {
+		if seg.wild && seg.s != "" {
+			r2.otherValues[seg.s] = r.PathValue(seg.s)
+		}
+	}
+	m.h.ServeHTTP(w, r2)
 }
 
 // Serve accepts incoming HTTP connections on the listener l,
@@ -2913,12 +3219,52 @@ type Server struct {
 	// value.
 	ConnContext func(ctx context.Context, c net.Conn) context.Context
 
+	// MaxConcurrentHandlers, if positive, limits the number of
+	// handlers that may run at once. A request arriving when the
+	// limit is reached waits for a running handler to return,
+	// as long as fewer than MaxQueuedRequests requests are already
+	// waiting; otherwise it is rejected with a 503 (Service
+	// Unavailable) response.
+	MaxConcurrentHandlers int
+
+	// MaxQueuedRequests is the maximum number of requests that may
+	// wait for a handler when MaxConcurrentHandlers is reached.
+	// If zero, requests are rejected instead of waiting.
+	MaxQueuedRequests int
+
+	// QueueTimeout is the maximum amount of time a request may
+	// wait for a handler before it is rejected with a 503 (Service
+	// Unavailable) response. If zero or negative, a request waits
+	// until a handler is available or the request is canceled.
+	QueueTimeout time.Duration
+
+	// RequestRate limits the rate of requests accepted by the server.
+	// Requests exceeding the limit are rejected with a 503 (Service
+	// Unavailable) response with a Retry-After header.
+	// The zero value means no limit.
+	RequestRate RateLimit
+
+	// ClientRequestRate limits the rate of requests accepted from
+	// each client IP address, as given by Request.RemoteAddr.
+	// Requests exceeding the limit are rejected with a 429 (Too Many
+	// Requests) response with a Retry-After header.
+	// The zero value means no limit.
+	//
+	// Requests are rejected by ClientRequestRate before they are
+	// counted against RequestRate or MaxConcurrentHandlers.
+	// When a request on an HTTP/1 connection is rejected by any of
+	// these limits, the ConnState hook is called with StateThrottled.
+	ClientRequestRate RateLimit
+
 	inShutdown atomic.Bool // true when server is in shutdown
 
 	disableKeepAlives atomic.Bool
 	nextProtoOnce     sync.Once // guards setupHTTP2_* init
 	nextProtoErr      error     // result of http2.ConfigureServer if used
 
+	admissionOnce  sync.Once // guards admissionState init
+	admissionState *admission
+
 	mu         sync.Mutex
 	listeners  map[*net.Listener]struct{}
 	activeConn map[*conn]struct{}
@@ -3088,6 +3434,8 @@ const (
 	// and doesn't fire again until the request has been
 	// handled. After the request is handled, the state
 	// transitions to StateClosed, StateHijacked, or StateIdle.
+	// If the request is rejected by the Server's admission limits,
+	// the state first transitions to StateThrottled.
 	// For HTTP/2, StateActive fires on the transition from zero
 	// to one active request, and only transitions away once all
 	// active requests are complete. That means that ConnState
@@ -3109,14 +3457,23 @@ const (
 	// This is a terminal state. Hijacked connections do not
 	// transition to StateClosed.
 	StateClosed
+
+	// StateThrottled represents a connection whose current request
+	// was rejected by the Server's MaxConcurrentHandlers, RequestRate
+	// or ClientRequestRate limits. The hook fires before the
+	// rejection response is written. Connections transition from
+	// StateThrottled as they would from StateActive.
+	// StateThrottled is reported for HTTP/1 connections only.
+	StateThrottled
 )
 
 var stateName = map[ConnState]string{
-	StateNew:      "new",
-	StateActive:   "active",
-	StateIdle:     "idle",
-	StateHijacked: "hijacked",
-	StateClosed:   "closed",
+	StateNew:       "new",
+	StateActive:    "active",
+	StateIdle:      "idle",
+	StateHijacked:  "hijacked",
+	StateClosed:    "closed",
+	StateThrottled: "throttled",
 }
 
 func (c ConnState) String() string {
@@ -3130,6 +3487,13 @@ type serverHandler struct {
 }
 
 func (sh serverHandler) ServeHTTP(rw ResponseWriter, req *Request) {
+	if sh.srv.hasAdmissionLimits() {
+		release := sh.srv.admit(rw, req)
+		if release == nil {
+			return
+		}
+		defer release()
+	}
 	handler := sh.srv.Handler
 	if handler == nil {
 		handler = DefaultServeMux
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"testing"
 	"time"
 )
@@ -149,7 +150,7 @@ func TestRegisterErr(t *testing.T) {
 		{"/a", h, `conflicts with pattern.* \(registered at .*/server_test.go:\d+`},
 	} {
 		t.Run(fmt.Sprintf("%s:%#v", test.pattern, test.handler), func(t *testing.T) {
-			err := mux.registerErr(test.pattern, test.handler)
+			err := mux.registerErr(test.pattern, nil, test.handler)
 			if err == nil {
 				t.Fatal("got nil error")
 			}
@@ -161,6 +162,201 @@ func TestRegisterErr(t *testing.T) {
 	}
 }
 
+func TestCheckPattern(t *testing.T) {
+	mux := NewServeMux()
+	mux.Handle("/a/{x}", &handler{})
+
+	for _, test := range []struct {
+		pattern    string
+		wantRegexp string
+	}{
+		{"/b", ""},
+		{"/a/b", ""},
+		{"", "invalid pattern"},
+		{"/{x", `parsing "/\{x": at offset 1: bad wildcard segment`},
+		{"/a/{y}", `pattern "/a/\{y\}" conflicts with pattern "/a/\{x\}" \(registered at .*/server_test.go:\d+`},
+	} {
+		err := mux.CheckPattern(test.pattern)
+		if test.wantRegexp == "" {
+			if err != nil {
+				t.Errorf("CheckPattern(%q) = %v, want nil", test.pattern, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("CheckPattern(%q) = nil, want error", test.pattern)
+			continue
+		}
+		re := regexp.MustCompile(test.wantRegexp)
+		if g := err.Error(); !re.MatchString(g) {
+			t.Errorf("\ngot %q\nwant string matching %q", g, test.wantRegexp)
+		}
+	}
+}
+
+// A codeRecorder is a ResponseWriter that records the status code.
+type codeRecorder struct {
+	h    Header
+	code int
+}
+
+func (r *codeRecorder) Header() Header {
+	if r.h == nil {
+		r.h = Header{}
+	}
+	return r.h
+}
+
+func (r *codeRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = StatusOK
+	}
+	return len(b), nil
+}
+
+func (r *codeRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func TestServeMuxRoutes121(t *testing.T) {
+	defer func(u bool) { use121 = u }(use121)
+	use121 = true
+
+	mux := NewServeMux()
+	h := HandlerFunc(func(ResponseWriter, *Request) {})
+	sub := NewServeMux()
+	mux.Handle("/z", h)
+	mux.HandleIf("/a", MatchHeader("X", "1"), h)
+	mux.Handle("example.com/", h)
+	mux.Mount("/api/", sub)
+	mux.Handle("/a", h)
+
+	got := mux.Routes()
+	want := []struct {
+		pattern, host, path string
+		match, mounted      bool
+	}{
+		{"/z", "", "/z", false, false},
+		{"/a", "", "/a", true, false},
+		{"example.com/", "example.com", "/", false, false},
+		{"/api/", "", "/api/", false, true},
+		{"/a", "", "/a", false, false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d routes, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Pattern != w.pattern || g.Host != w.host || g.Path != w.path || (g.Match != nil) != w.match || g.Mounted != w.mounted {
+			t.Errorf("route %d = %+v, want %+v", i, g, w)
+		}
+	}
+	if got[3].Handler != sub {
+		t.Errorf("mounted route handler = %v, want sub mux", got[3].Handler)
+	}
+}
+
+func TestServeMuxHandleIf(t *testing.T) {
+	run := func(t *testing.T, test121 bool) {
+		defer func(u bool) { use121 = u }(use121)
+		use121 = test121
+
+		var served string
+		h := func(name string) Handler {
+			return HandlerFunc(func(w ResponseWriter, r *Request) { served = name })
+		}
+		serve := func(mux *ServeMux, path string, header Header) (string, int) {
+			served = ""
+			r := &Request{Method: "GET", URL: &url.URL{Path: path}, Header: header}
+			w := &codeRecorder{}
+			mux.ServeHTTP(w, r)
+			return served, w.code
+		}
+		mustPanic := func(name string, f func()) {
+			t.Helper()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}
+
+		mux := NewServeMux()
+		mux.HandleIf("/api/", MatchHeader("x-version", "2"), h("v2"))
+		mux.HandleIf("/api/", MatchHeader("Accept", ""), h("accept"))
+		mux.Handle("/", h("root"))
+		for _, test := range []struct {
+			header Header
+			want   string
+			code   int
+		}{
+			{Header{"X-Version": {"1", "2"}, "Accept": {"*/*"}}, "v2", 0},
+			{Header{"X-Version": {"1"}, "Accept": {"*/*"}}, "accept", 0},
+			{Header{"Accept": {""}}, "accept", 0},
+			{Header{"X-Version": {"1, 2"}}, "", StatusNotFound},
+			{nil, "", StatusNotFound},
+		} {
+			if got, code := serve(mux, "/api/x", test.header); got != test.want || code != test.code {
+				t.Errorf("/api/x with header %v: served by %q with code %d, want %q with code %d",
+					test.header, got, code, test.want, test.code)
+			}
+		}
+		if err := mux.CheckPattern("/api/"); err != nil {
+			t.Errorf("CheckPattern of conditional pattern = %v, want nil", err)
+		}
+
+		// An unconditional handler serves the requests no condition accepts.
+		mux.Handle("/api/", h("fallback"))
+		if got, _ := serve(mux, "/api/x", nil); got != "fallback" {
+			t.Errorf("/api/x without header: served by %q, want %q", got, "fallback")
+		}
+		if got, _ := serve(mux, "/api/x", Header{"X-Version": {"2"}}); got != "v2" {
+			t.Errorf("/api/x with header: served by %q, want %q", got, "v2")
+		}
+		if err := mux.CheckPattern("/api/"); err == nil {
+			t.Errorf("CheckPattern of pattern with fallback = nil, want error")
+		}
+		mustPanic("second unconditional Handle", func() { mux.Handle("/api/", h("again")) })
+
+		// A conditional handler can be added to an unconditional one.
+		mux.Handle("/b", h("b"))
+		mux.HandleIf("/b", MatchHeader("X-B", ""), h("b-header"))
+		if got, _ := serve(mux, "/b", Header{"X-B": {"1"}}); got != "b-header" {
+			t.Errorf("/b with header: served by %q, want %q", got, "b-header")
+		}
+		if got, _ := serve(mux, "/b", nil); got != "b" {
+			t.Errorf("/b without header: served by %q, want %q", got, "b")
+		}
+
+		mustPanic("HandleIf with nil match", func() { mux.HandleIf("/c", nil, h("c")) })
+		mustPanic("HandleIf with nil handler", func() { mux.HandleIf("/c", MatchHeader("X", ""), nil) })
+		if !use121 {
+			// Conditional handlers must use the same pattern string.
+			mux.HandleIf("/p/{x}", MatchHeader("X", ""), h("p"))
+			mustPanic("HandleIf with equivalent pattern", func() { mux.HandleIf("/p/{y}", MatchHeader("Y", ""), h("p")) })
+		}
+
+		// Routes lists each handler registered for the pattern.
+		var got []string
+		for _, r := range mux.Routes() {
+			if r.Pattern == "/api/" {
+				served = ""
+				r.Handler.ServeHTTP(&codeRecorder{}, nil)
+				got = append(got, fmt.Sprintf("%s:%v", served, r.Match != nil))
+			}
+		}
+		if want := []string{"v2:true", "accept:true", "fallback:false"}; !slices.Equal(got, want) {
+			t.Errorf("routes for /api/ = %v, want %v", got, want)
+		}
+	}
+
+	t.Run("latest", func(t *testing.T) { run(t, false) })
+	t.Run("1.21", func(t *testing.T) { run(t, true) })
+}
+
 func TestExactMatch(t *testing.T) {
 	for _, test := range []struct {
 		pattern string
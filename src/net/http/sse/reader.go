@@ -0,0 +1,340 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxEventSize is the default maximum size of an event
+// read by a [Reader] or [Stream].
+const DefaultMaxEventSize = 1 << 20
+
+// ErrEventTooLarge is returned by [Reader.Next] and [Stream.Next] when an
+// event, counting its comments and field lines, exceeds the maximum size.
+var ErrEventTooLarge = errors.New("sse: event too large")
+
+// A Reader parses server-sent events from an event stream.
+type Reader struct {
+	// MaxEventSize is the maximum number of bytes, not counting line
+	// endings, read for a single event, including any comments and
+	// unrecognized fields. If zero, DefaultMaxEventSize is used.
+	// Once an event exceeds it, Next returns ErrEventTooLarge and the
+	// Reader should not be used further.
+	MaxEventSize int
+
+	br      *bufio.Reader
+	line    []byte
+	skipLF  bool // previous line ended in CR; skip a following LF
+	started bool // byte order mark has been checked
+
+	lastID string        // last event ID buffer
+	retry  time.Duration // most recent reconnection time, or 0
+}
+
+// NewReader returns a Reader parsing events from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{br: bufio.NewReader(r)}
+}
+
+// LastEventID returns the last event ID seen on the stream.
+// It is the value a client should send in the Last-Event-ID
+// header when reconnecting.
+func (r *Reader) LastEventID() string { return r.lastID }
+
+// Retry returns the most recent reconnection time sent by the server,
+// or 0 if none has been sent.
+func (r *Reader) Retry() time.Duration { return r.retry }
+
+// Next returns the next event in the stream.
+// At the end of the stream Next returns [io.EOF];
+// an incomplete final event is discarded.
+func (r *Reader) Next() (Event, error) {
+	var (
+		data    strings.Builder
+		hasData bool
+		typ     string
+		retry   time.Duration
+	)
+	maxSize := r.MaxEventSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxEventSize
+	}
+	size := 0 // bytes read for this event so far
+	for {
+		line, err := r.readLine(maxSize - size)
+		if err != nil {
+			return Event{}, err
+		}
+		size += len(line)
+		if len(line) == 0 {
+			if !hasData {
+				// Nothing to dispatch; reset and keep reading.
+				typ, retry, size = "", 0, 0
+				continue
+			}
+			return Event{
+				ID:    r.lastID,
+				Type:  typ,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: retry,
+			}, nil
+		}
+		if line[0] == ':' {
+			continue // comment
+		}
+		field, value := string(line), ""
+		if i := strings.IndexByte(field, ':'); i >= 0 {
+			field, value = field[:i], field[i+1:]
+			value = strings.TrimPrefix(value, " ")
+		}
+		switch field {
+		case "event":
+			typ = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if strings.IndexByte(value, 0) < 0 {
+				r.lastID = value
+			}
+		case "retry":
+			if ms, ok := parseDigits(value); ok {
+				retry = time.Duration(ms) * time.Millisecond
+				r.retry = retry
+			}
+		}
+	}
+}
+
+// parseDigits parses s, which must consist only of ASCII digits.
+func parseDigits(s string) (int64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > int64(1<<63-1)/int64(time.Millisecond) {
+		return 0, false
+	}
+	return n, true
+}
+
+// readLine returns the next line of the stream, without its line ending.
+// Lines may end in CRLF, LF or CR. If the line is longer than max bytes,
+// readLine returns ErrEventTooLarge.
+// The returned slice is valid until the next call to readLine.
+func (r *Reader) readLine(max int) ([]byte, error) {
+	if !r.started {
+		r.started = true
+		if b, err := r.br.Peek(3); err == nil && string(b) == "\xEF\xBB\xBF" {
+			r.br.Discard(3)
+		}
+	}
+	r.line = r.line[:0]
+	for {
+		c, err := r.br.ReadByte()
+		if err != nil {
+			// A final line without a line ending belongs to
+			// an incomplete event, and is discarded.
+			return nil, err
+		}
+		if r.skipLF {
+			r.skipLF = false
+			if c == '\n' {
+				continue
+			}
+		}
+		switch c {
+		case '\r':
+			r.skipLF = true
+			return r.line, nil
+		case '\n':
+			return r.line, nil
+		}
+		if len(r.line) >= max {
+			return nil, ErrEventTooLarge
+		}
+		r.line = append(r.line, c)
+	}
+}
+
+// defaultRetry is the reconnection time used by a Stream
+// until the server sends one.
+const defaultRetry = 3 * time.Second
+
+// ErrStreamClosed is returned by [Stream.Next] after [Stream.Close].
+var ErrStreamClosed = errors.New("sse: stream closed")
+
+// A Stream reads events from an HTTP event source,
+// reconnecting when the connection is lost.
+//
+// When reconnecting, a Stream waits for the reconnection time most
+// recently sent by the server, and sends the last event ID it has
+// seen in the Last-Event-ID header so the server can resume the stream.
+type Stream struct {
+	// MaxEventSize is the maximum size of an event, as for
+	// Reader.MaxEventSize. An event exceeding it stops the Stream,
+	// and Next returns ErrEventTooLarge.
+	MaxEventSize int
+
+	client *http.Client
+	req    *http.Request
+
+	resp   *http.Response
+	r      *Reader
+	lastID string
+	retry  time.Duration
+	err    error // sticky error; no more reconnection attempts
+	waited bool  // the next connection attempt is a reconnection
+}
+
+// NewStream returns a Stream reading events from the event source
+// identified by req, using client to send requests.
+// If client is nil, [http.DefaultClient] is used.
+//
+// Each connection is made with a clone of req, so req must not
+// have a body. Canceling req's context stops the Stream.
+// No request is sent until the first call to [Stream.Next].
+func NewStream(client *http.Client, req *http.Request) *Stream {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Stream{
+		client: client,
+		req:    req,
+		retry:  defaultRetry,
+	}
+}
+
+// LastEventID returns the last event ID seen on the stream.
+func (s *Stream) LastEventID() string {
+	if s.r != nil {
+		return s.r.LastEventID()
+	}
+	return s.lastID
+}
+
+// Next returns the next event from the event source.
+//
+// If the connection is lost, Next reconnects and continues reading.
+// If a connection attempt fails, Next returns the error; calling Next
+// again makes another attempt after the reconnection time.
+// If the server responds with a status other than 200 OK or a content
+// type other than [ContentType], Next returns an error and the Stream
+// stops. A 204 No Content response stops the Stream with [io.EOF].
+func (s *Stream) Next() (Event, error) {
+	for {
+		if s.err != nil {
+			return Event{}, s.err
+		}
+		if s.r == nil {
+			if err := s.connect(); err != nil {
+				return Event{}, err
+			}
+		}
+		ev, err := s.r.Next()
+		if err == nil {
+			if ev.Retry > 0 {
+				s.retry = ev.Retry
+			}
+			return ev, nil
+		}
+		s.disconnect()
+		if err == ErrEventTooLarge {
+			// Reconnecting would most likely read the same event again.
+			s.err = err
+		} else if ctxErr := s.req.Context().Err(); ctxErr != nil {
+			s.err = ctxErr
+		}
+	}
+}
+
+// Close closes the current connection, if any, and stops the Stream.
+func (s *Stream) Close() error {
+	s.disconnect()
+	if s.err == nil {
+		s.err = ErrStreamClosed
+	}
+	return nil
+}
+
+func (s *Stream) disconnect() {
+	if s.r == nil {
+		return
+	}
+	s.lastID = s.r.LastEventID()
+	if r := s.r.Retry(); r > 0 {
+		s.retry = r
+	}
+	s.resp.Body.Close()
+	s.resp, s.r = nil, nil
+	s.waited = true
+}
+
+func (s *Stream) connect() error {
+	ctx := s.req.Context()
+	if s.waited {
+		t := time.NewTimer(s.retry)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			s.err = ctx.Err()
+			return s.err
+		case <-t.C:
+		}
+	}
+	// Any failure from here on is followed by a delay before
+	// the next attempt.
+	s.waited = true
+
+	req := s.req.Clone(ctx)
+	req.Header.Set("Accept", ContentType)
+	req.Header.Set("Cache-Control", "no-cache")
+	if s.lastID != "" {
+		req.Header.Set("Last-Event-ID", s.lastID)
+	} else {
+		req.Header.Del("Last-Event-ID")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			s.err = ctxErr
+		}
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		s.err = io.EOF
+	case resp.StatusCode != http.StatusOK:
+		s.err = fmt.Errorf("sse: unexpected response status %q", resp.Status)
+	default:
+		if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != ContentType {
+			s.err = fmt.Errorf("sse: unexpected response content type %q", resp.Header.Get("Content-Type"))
+		}
+	}
+	if s.err != nil {
+		resp.Body.Close()
+		return s.err
+	}
+	s.resp = resp
+	s.r = NewReader(resp.Body)
+	s.r.MaxEventSize = s.MaxEventSize
+	s.r.lastID = s.lastID
+	return nil
+}
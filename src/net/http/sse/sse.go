@@ -0,0 +1,265 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sse implements the server-sent events protocol, as used by the
+// EventSource interface described in the HTML Living Standard
+// (https://html.spec.whatwg.org/multipage/server-sent-events.html).
+//
+// A [Writer] sends events from an HTTP handler to a client.
+// A [Reader] parses events from an event stream, and a [Stream]
+// reads events from an HTTP event source, reconnecting as needed.
+package sse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An Event is a single server-sent event.
+type Event struct {
+	// ID is the event ID. When sending, an empty ID leaves the
+	// client's last event ID unchanged. When receiving, ID is the
+	// last event ID seen on the stream, which may have been set
+	// by an earlier event.
+	ID string
+
+	// Type is the event type. An empty Type is equivalent to "message".
+	Type string
+
+	// Data is the event payload. Data may contain newlines.
+	Data string
+
+	// Retry, if positive, asks the client to wait the given
+	// duration before reconnecting after the connection is lost.
+	// Retry is sent and received with millisecond precision.
+	Retry time.Duration
+}
+
+// ContentType is the media type of an event stream.
+const ContentType = "text/event-stream"
+
+var (
+	// ErrInvalidField is returned by [Writer.Send] when an event's ID
+	// or Type contains a newline, or its ID contains a NUL byte.
+	ErrInvalidField = errors.New("sse: invalid event field")
+
+	// ErrWriterClosed is returned by a [Writer] used after [Writer.Close].
+	ErrWriterClosed = errors.New("sse: writer closed")
+)
+
+// A Writer sends server-sent events to a client.
+//
+// Writes are flushed to the client as soon as each event is written,
+// using an [http.ResponseController].
+// A Writer's methods may be called concurrently, but like the
+// ResponseController it is built on, a Writer may not be used after
+// the [http.Handler.ServeHTTP] method has returned.
+//
+// Once a write fails, all later writes return the same error.
+type Writer struct {
+	// WriteTimeout, if non-zero, is the maximum duration allowed
+	// to write and flush each event. It is implemented by setting
+	// the response write deadline before each write.
+	WriteTimeout time.Duration
+
+	w  http.ResponseWriter
+	rc *http.ResponseController
+
+	mu  sync.Mutex
+	buf []byte
+	err error
+
+	hbStop chan struct{} // closed to stop the heartbeat
+	hbDone chan struct{} // closed when the heartbeat goroutine exits
+}
+
+// NewWriter returns a Writer sending events to w.
+//
+// NewWriter sets the Content-Type header to [ContentType] and the
+// Cache-Control header to "no-cache", unless they are already set.
+// The response header is written with the first event; handlers
+// wanting a status other than 200 should call WriteHeader first.
+func NewWriter(w http.ResponseWriter) *Writer {
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", ContentType)
+	}
+	if h.Get("Cache-Control") == "" {
+		h.Set("Cache-Control", "no-cache")
+	}
+	return &Writer{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+}
+
+// Send writes ev to the client and flushes it.
+//
+// An event is always dispatched by the client, even if its Data is empty.
+// Send returns [ErrInvalidField] if ev.ID or ev.Type cannot be represented.
+func (w *Writer) Send(ev Event) error {
+	if !validField(ev.ID) || strings.IndexByte(ev.ID, 0) >= 0 || !validField(ev.Type) {
+		return ErrInvalidField
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.buf[:0]
+	if ev.ID != "" {
+		b = appendField(b, "id", ev.ID)
+	}
+	if ev.Type != "" {
+		b = appendField(b, "event", ev.Type)
+	}
+	if ev.Retry > 0 {
+		b = appendField(b, "retry", strconv.FormatInt(ev.Retry.Milliseconds(), 10))
+	}
+	for _, line := range splitLines(ev.Data) {
+		b = appendField(b, "data", line)
+	}
+	b = append(b, '\n')
+	w.buf = b
+	return w.writeLocked(b)
+}
+
+// Comment writes a comment to the client and flushes it.
+// Comments are ignored by clients, but may be used to keep
+// a connection from being closed by intermediaries.
+func (w *Writer) Comment(text string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.buf[:0]
+	for _, line := range splitLines(text) {
+		b = append(b, ':')
+		if line != "" {
+			b = append(b, ' ')
+			b = append(b, line...)
+		}
+		b = append(b, '\n')
+	}
+	w.buf = b
+	return w.writeLocked(b)
+}
+
+// writeLocked writes b and flushes it.
+// w.mu must be held.
+func (w *Writer) writeLocked(b []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.WriteTimeout > 0 {
+		if err := w.rc.SetWriteDeadline(time.Now().Add(w.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			w.err = err
+			return err
+		}
+	}
+	if _, err := w.w.Write(b); err != nil {
+		w.err = err
+		return err
+	}
+	if err := w.rc.Flush(); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
+
+// Heartbeat starts a goroutine that writes an empty comment to the
+// client every interval, keeping idle connections open.
+// Heartbeat panics if interval is not positive or a heartbeat is
+// already running.
+//
+// The returned stop function stops the heartbeat and waits for the
+// goroutine to exit. It must be called before the handler returns.
+// The heartbeat stops by itself if a write fails; the error is
+// returned by later calls to the Writer's methods.
+func (w *Writer) Heartbeat(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		panic("sse: non-positive interval for Heartbeat")
+	}
+	w.mu.Lock()
+	if w.hbStop != nil {
+		w.mu.Unlock()
+		panic("sse: Heartbeat called twice")
+	}
+	stopc, done := make(chan struct{}), make(chan struct{})
+	w.hbStop, w.hbDone = stopc, done
+	w.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-stopc:
+				return
+			case <-t.C:
+			}
+			w.mu.Lock()
+			err := w.writeLocked([]byte(":\n"))
+			w.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return func() {
+		w.stopHeartbeat(stopc)
+		<-done
+	}
+}
+
+// stopHeartbeat stops the heartbeat identified by stopc, if it is running.
+func (w *Writer) stopHeartbeat(stopc chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hbStop != nil && w.hbStop == stopc {
+		close(w.hbStop)
+		w.hbStop, w.hbDone = nil, nil
+	}
+}
+
+// Close stops any running heartbeat and prevents further writes.
+// It does not close the underlying connection.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	stopc, done := w.hbStop, w.hbDone
+	w.mu.Unlock()
+	if stopc != nil {
+		w.stopHeartbeat(stopc)
+		<-done
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = ErrWriterClosed
+	}
+	return nil
+}
+
+// validField reports whether s can be sent as a single field value.
+func validField(s string) bool {
+	return strings.IndexByte(s, '\n') < 0 && strings.IndexByte(s, '\r') < 0
+}
+
+func appendField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	b = append(b, ": "...)
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// splitLines splits s at CRLF, LF and CR line endings.
+func splitLines(s string) []string {
+	if strings.IndexByte(s, '\r') >= 0 {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+		s = strings.ReplaceAll(s, "\r", "\n")
+	}
+	return strings.Split(s, "\n")
+}
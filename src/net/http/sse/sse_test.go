@@ -0,0 +1,355 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReader(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		stream string
+		want   []Event
+		lastID string
+	}{{
+		name:   "simple",
+		stream: "data: hello\n\n",
+		want:   []Event{{Data: "hello"}},
+	}, {
+		name:   "multiline data",
+		stream: "data: YHOO\ndata: +2\ndata: 10\n\n",
+		want:   []Event{{Data: "YHOO\n+2\n10"}},
+	}, {
+		name:   "comments and fields",
+		stream: ": test stream\n\ndata: first event\nid: 1\n\ndata:second event\nid\n\ndata:  third event\n\n",
+		want: []Event{
+			{ID: "1", Data: "first event"},
+			{ID: "", Data: "second event"},
+			{ID: "", Data: " third event"},
+		},
+	}, {
+		name:   "empty data",
+		stream: "data\n\ndata\ndata\n\ndata:\n",
+		want:   []Event{{Data: ""}, {Data: "\n"}},
+	}, {
+		name:   "line endings",
+		stream: "data: a\r\ndata: b\rdata: c\n\r\ndata: d\r\r",
+		want:   []Event{{Data: "a\nb\nc"}, {Data: "d"}},
+	}, {
+		name:   "byte order mark",
+		stream: "\xEF\xBB\xBFdata: x\n\n",
+		want:   []Event{{Data: "x"}},
+	}, {
+		name:   "event type and retry",
+		stream: "event: add\nretry: 1500\ndata: 73857293\n\nretry: 1x\nevent: remove\ndata: 2153\n\n",
+		want: []Event{
+			{Type: "add", Data: "73857293", Retry: 1500 * time.Millisecond},
+			{Type: "remove", Data: "2153"},
+		},
+	}, {
+		name:   "id without data",
+		stream: "id: 7\n\nid: bad\x00id\n\n",
+		lastID: "7",
+	}, {
+		name:   "unknown fields",
+		stream: "foo: bar\ndata: x\nDATA: y\n\n",
+		want:   []Event{{Data: "x"}},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.stream))
+			var got []Event
+			for {
+				ev, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next: %v", err)
+				}
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+			if got := r.LastEventID(); got != tt.lastID {
+				t.Errorf("LastEventID = %q, want %q", got, tt.lastID)
+			}
+		})
+	}
+}
+
+// An endlessReader repeats its contents forever.
+// Reads should use buffers whose size is a multiple of its length.
+type endlessReader string
+
+func (s endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = s[i%len(s)]
+	}
+	return len(p), nil
+}
+
+func TestReaderMaxEventSize(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		stream io.Reader
+	}{
+		{"endless line", io.MultiReader(strings.NewReader("data: "), endlessReader("x"))},
+		{"endless comment", io.MultiReader(strings.NewReader(":"), endlessReader("x"))},
+		{"endless event", endlessReader(":\n")},
+		{"many data lines", strings.NewReader(strings.Repeat("data: 0123456789\n", 10) + "\n")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(tt.stream)
+			r.MaxEventSize = 100
+			if _, err := r.Next(); err != ErrEventTooLarge {
+				t.Fatalf("Next = %v, want ErrEventTooLarge", err)
+			}
+		})
+	}
+
+	// Events at the limit are read, and the limit applies to each event.
+	stream := strings.Repeat("data: 01234567\n\n", 3)
+	r := NewReader(strings.NewReader(stream))
+	r.MaxEventSize = len("data: 01234567")
+	for range 3 {
+		if ev, err := r.Next(); err != nil || ev.Data != "01234567" {
+			t.Fatalf("Next = %q, %v, want %q", ev.Data, err, "01234567")
+		}
+	}
+
+	// The default limit applies if MaxEventSize is unset.
+	r = NewReader(io.MultiReader(strings.NewReader("data: "), endlessReader("x")))
+	if _, err := r.Next(); err != ErrEventTooLarge {
+		t.Fatalf("Next with default limit = %v, want ErrEventTooLarge", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewWriter(rec)
+	events := []Event{
+		{Data: "hello"},
+		{ID: "1", Type: "update", Data: "a\nb\r\nc"},
+		{Retry: 2 * time.Second, Data: ""},
+	}
+	for _, ev := range events {
+		if err := w.Send(ev); err != nil {
+			t.Fatalf("Send(%v): %v", ev, err)
+		}
+	}
+	if err := w.Comment("ping"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.Header().Get("Content-Type"), ContentType; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	if !rec.Flushed {
+		t.Errorf("response was not flushed")
+	}
+	const want = "data: hello\n\n" +
+		"id: 1\nevent: update\ndata: a\ndata: b\ndata: c\n\n" +
+		"retry: 2000\ndata: \n\n" +
+		": ping\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	// The stream must round trip through a Reader.
+	r := NewReader(strings.NewReader(rec.Body.String()))
+	events[1].Data = "a\nb\nc"
+	events[2].ID = "1"
+	for _, want := range events {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("read %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestWriterInvalidField(t *testing.T) {
+	w := NewWriter(httptest.NewRecorder())
+	for _, ev := range []Event{
+		{ID: "a\nb"},
+		{ID: "a\x00b"},
+		{Type: "x\ry"},
+	} {
+		if err := w.Send(ev); err != ErrInvalidField {
+			t.Errorf("Send(%q) = %v, want ErrInvalidField", ev, err)
+		}
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	w := NewWriter(httptest.NewRecorder())
+	stop := w.Heartbeat(time.Hour)
+	w.Close()
+	stop()
+	if err := w.Send(Event{Data: "x"}); err != ErrWriterClosed {
+		t.Errorf("Send after Close = %v, want ErrWriterClosed", err)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w := NewWriter(rw)
+		defer w.Close()
+		stop := w.Heartbeat(time.Millisecond)
+		defer stop()
+		time.Sleep(20 * time.Millisecond)
+		w.Send(Event{Data: "done"})
+	}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(body), ":\n") || !strings.HasSuffix(string(body), "data: done\n\n") {
+		t.Errorf("body = %q, want heartbeats followed by event", body)
+	}
+}
+
+func TestStreamReconnect(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		lastIDs []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		lastIDs = append(lastIDs, req.Header.Get("Last-Event-ID"))
+		n := len(lastIDs)
+		mu.Unlock()
+		if got := req.Header.Get("Accept"); got != ContentType {
+			t.Errorf("Accept = %q, want %q", got, ContentType)
+		}
+		w := NewWriter(rw)
+		switch n {
+		case 1:
+			w.Send(Event{ID: "1", Data: "one", Retry: time.Millisecond})
+			w.Send(Event{ID: "2", Data: "two"})
+			// Close the connection with an incomplete event.
+			rw.Write([]byte("data: lost\n"))
+		case 2:
+			w.Send(Event{ID: "3", Data: "three"})
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStream(ts.Client(), req)
+	defer s.Close()
+	var got []string
+	for {
+		ev, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		got = append(got, ev.ID+":"+ev.Data)
+	}
+	if want := []string{"1:one", "2:two", "3:three"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"", "2", "3"}; !reflect.DeepEqual(lastIDs, want) {
+		t.Errorf("Last-Event-ID headers = %q, want %q", lastIDs, want)
+	}
+	if got := s.LastEventID(); got != "3" {
+		t.Errorf("LastEventID = %q, want %q", got, "3")
+	}
+}
+
+func TestStreamBadResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Write([]byte("data: x\n\n"))
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	defer s.Close()
+	_, err := s.Next()
+	if err == nil || !strings.Contains(err.Error(), "content type") {
+		t.Fatalf("Next = %v, want content type error", err)
+	}
+	if _, err2 := s.Next(); err2 != err {
+		t.Errorf("second Next = %v, want %v", err2, err)
+	}
+}
+
+func TestStreamEventTooLarge(t *testing.T) {
+	var connects int
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		connects++
+		w := NewWriter(rw)
+		w.Send(Event{Data: strings.Repeat("x", 200), Retry: time.Millisecond})
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	s.MaxEventSize = 100
+	defer s.Close()
+	if _, err := s.Next(); err != ErrEventTooLarge {
+		t.Fatalf("Next = %v, want ErrEventTooLarge", err)
+	}
+	// The Stream stops rather than reading the same event again.
+	if _, err := s.Next(); err != ErrEventTooLarge {
+		t.Errorf("second Next = %v, want ErrEventTooLarge", err)
+	}
+	if connects != 1 {
+		t.Errorf("server saw %d connections, want 1", connects)
+	}
+}
+
+func TestStreamContextCancel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w := NewWriter(rw)
+		w.Send(Event{Data: "x", Retry: time.Hour})
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	s := NewStream(ts.Client(), req)
+	defer s.Close()
+	if _, err := s.Next(); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(10*time.Millisecond, cancel)
+	// The server closes the stream, and the reconnection
+	// wait is interrupted by the cancellation.
+	if _, err := s.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next = %v, want context.Canceled", err)
+	}
+}
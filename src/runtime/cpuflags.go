@@ -24,9 +24,13 @@ const (
 var (
 	// Set in runtime.cpuinit.
 	// TODO: deprecate these; use internal/cpu directly.
-	x86HasPOPCNT bool
-	x86HasSSE41  bool
-	x86HasFMA    bool
+	x86HasPOPCNT   bool
+	x86HasSSE41    bool
+	x86HasFMA      bool
+	x86HasAVX      bool
+	x86HasAVX2     bool
+	x86HasAVX512F  bool
+	x86HasAVX512BW bool
 
 	armHasVFPv4 bool
 
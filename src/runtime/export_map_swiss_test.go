@@ -0,0 +1,72 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package runtime
+
+import "unsafe"
+
+// MapBucketsCount returns the number of groups of m.
+func MapBucketsCount(m map[int]int) int {
+	h := *(**hmap)(unsafe.Pointer(&m))
+	if h.dirLen == 0 {
+		return 1
+	}
+	n := 0
+	for i, tab := range h.directory() {
+		if tab.index == i {
+			n += int(tab.capacity) / groupSlots
+		}
+	}
+	return n
+}
+
+func MapBucketsPointerIsNil(m map[int]int) bool {
+	h := *(**hmap)(unsafe.Pointer(&m))
+	return h.groups == nil
+}
+
+func OverLoadFactor(count int, B uint8) bool {
+	return uintptr(count) > uintptr(1)<<B*maxAvgGroupLoad
+}
+
+// MapTombstoneCheck checks the bookkeeping of the tables of m.
+func MapTombstoneCheck(m map[int]int) {
+	h := *(**hmap)(unsafe.Pointer(&m))
+	i := any(m)
+	t := *(**maptype)(unsafe.Pointer(&i))
+
+	if h.dirLen == 0 {
+		return
+	}
+	count := 0
+	for i, tab := range h.directory() {
+		if tab.index != i {
+			continue
+		}
+		used, deleted := 0, 0
+		for x := uintptr(0); x <= tab.groupMask(); x++ {
+			g := groupAt(t, tab.groups, x)
+			for j := 0; j < groupSlots; j++ {
+				switch c := g.ctrl[j]; {
+				case c&ctrlFull != 0:
+					used++
+				case c == ctrlDeleted:
+					deleted++
+				}
+			}
+		}
+		if used != int(tab.used) {
+			panic("bad table used count")
+		}
+		if used+deleted+int(tab.growthLeft) != int(tab.capacity)/groupSlots*maxAvgGroupLoad {
+			panic("bad table growth left")
+		}
+		count += used
+	}
+	if count != h.count {
+		panic("bad map count")
+	}
+}
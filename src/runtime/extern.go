@@ -202,6 +202,12 @@ It is a comma-separated list of name=val pairs setting these named variables:
 	IDs will refer to the ID of the goroutine at the time of creation; it's possible for this
 	ID to be reused for another goroutine. Setting N to 0 will report no ancestry information.
 
+	tracebacklabels: setting tracebacklabels=1 adds the goroutine's profiler labels,
+	as set by runtime/pprof.Do or runtime/pprof.SetGoroutineLabels, to the header of
+	each goroutine in a traceback, for example
+	goroutine 7 [chan receive] {"worker":"fetch"}:
+	This also affects the information returned by runtime.Stack.
+
 	tracefpunwindoff: setting tracefpunwindoff=1 forces the execution tracer to
 	use the runtime's default stack unwinder instead of frame pointer unwinding.
 	This increases tracer overhead, but could be helpful as a workaround or for
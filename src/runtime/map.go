@@ -2,6 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+//go:build !goexperiment.swissmap
+
 package runtime
 
 // This file contains the implementation of Go's map type.
@@ -100,6 +102,9 @@ const (
 	noCheck = 1<<(8*goarch.PtrSize) - 1
 )
 
+// exported value for testing
+const hashLoad = float32(loadFactorNum) / float32(loadFactorDen)
+
 // isEmpty reports whether the given tophash array entry represents an empty bucket entry.
 func isEmpty(x uint8) bool {
 	return x <= emptyOne
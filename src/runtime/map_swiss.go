@@ -0,0 +1,1266 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package runtime
+
+// This file contains the Swiss table implementation of Go's map type,
+// used in place of map.go when GOEXPERIMENT=swissmap is set.
+//
+// A Swiss table is an open-addressing hash table. Slots are arranged in
+// groups of 8, and each group has an 8-byte control word holding one
+// control byte per slot: empty, deleted, or full together with the low
+// 7 bits of the hash of the slot's key (h2). A lookup probes groups in
+// a quadratic sequence starting at the group selected by the remaining
+// hash bits (h1). In each group, it compares h2 with all 8 control bytes
+// at once using word-sized bit tricks, compares keys only for the slots
+// that match, and stops at the first group that has an empty slot.
+// Tables are grown before they are more than 7/8 full, so every probe
+// sequence ends.
+//
+// A group has exactly the layout of the compiler's map bucket type:
+// 8 control bytes (the bucket's tophash array), 8 keys, 8 elems and an
+// overflow word that is not used here. The compiler, reflect and the
+// garbage collector therefore need no knowledge of which implementation
+// is in use. Control bytes are chosen so that zeroed memory reads as
+// empty slots, since the compiler may allocate the first group of a map
+// on the stack.
+//
+// A map holding at most 8 entries uses a single group and no table.
+// Larger maps use a directory of tables indexed by the top bits of the
+// hash (extendible hashing). A table holds at most maxTableCapacity
+// slots. When it fills up, it is replaced by a table twice its size or,
+// once at the maximum size, split into two tables each covering half of
+// its part of the directory. Growing a map thus never rehashes more than
+// maxTableCapacity entries at a time, bounding the cost of any single
+// insert, instead of rehashing the whole map at once.
+//
+// Entries never move within a table, and a group or table that has been
+// replaced is never modified again. An iterator walking a replaced table
+// keeps walking it, looking up each key in the current map to return the
+// current elem and to skip keys deleted since the table was replaced.
+
+import (
+	"internal/abi"
+	"internal/goarch"
+	"runtime/internal/math"
+	"runtime/internal/sys"
+	"unsafe"
+)
+
+const (
+	// Control byte values.
+	ctrlEmpty   = 0x00
+	ctrlDeleted = 0x01
+	ctrlFull    = 0x80 // ORed with the h2 hash bits of the key
+
+	// Number of slots in a group.
+	groupSlots = abi.MapBucketCount
+
+	// Maximum number of full or deleted slots per group, on average,
+	// in a table.
+	maxAvgGroupLoad = 7
+
+	// Maximum number of slots in a table, and its log_2.
+	maxTableCapacity     = 1024
+	maxTableCapacityBits = 10
+
+	// data offset should be the size of the bmap struct, but needs to be
+	// aligned correctly. For amd64p32 this means 64-bit alignment
+	// even though pointers are 32 bit.
+	dataOffset = unsafe.Offsetof(struct {
+		b bmap
+		v int64
+	}{}.v)
+
+	// flags
+	hashWriting = 4 // a goroutine is writing to the map
+
+	ctrlLSBs = 0x0101010101010101
+	ctrlMSBs = 0x8080808080808080
+)
+
+// exported value for testing
+const hashLoad = float32(maxAvgGroupLoad) / float32(groupSlots)
+
+// A header for a Go map.
+type hmap struct {
+	// Note: the format of the hmap is also encoded in cmd/compile/internal/reflectdata/reflect.go,
+	// using the field names of map.go. The size of the structure and the positions of count,
+	// hash0, buckets (here groups) and the pointer fields must stay in sync with it.
+	count       int // # live cells == size of map.  Must be first (used by len() builtin)
+	flags       uint8
+	globalDepth uint8  // log_2 of the directory length
+	clearSeq    uint16 // number of times the map has been cleared, to stop iterators
+	hash0       uint32 // hash seed
+
+	// groups is the group of a small map, or nil if it is not allocated yet.
+	// Otherwise, it points to the directory, an array of dirLen tables.
+	groups unsafe.Pointer
+	_      unsafe.Pointer
+	dirLen uintptr // length of the directory, or 0 for a small map
+	_      unsafe.Pointer
+}
+
+// A group of slots, laid out as a map bucket.
+type bmap struct {
+	ctrl [groupSlots]uint8
+	// Followed by groupSlots keys and then groupSlots elems.
+	// Followed by an overflow word, which is unused.
+}
+
+// A table is an open-addressing hash table holding the entries whose
+// hashes share the same top localDepth bits.
+type table struct {
+	used       uint16 // number of full slots
+	capacity   uint16 // number of slots, a power of two
+	growthLeft uint16 // number of empty slots that may be filled before rehashing
+	localDepth uint8
+
+	// index is the first directory index referring to this table,
+	// or -1 if the table has been replaced and is no longer part of the map.
+	index int
+
+	groups unsafe.Pointer // array of capacity/groupSlots groups
+}
+
+// A hash iteration structure.
+// It must have the size and pointer layout of the hiter in map.go;
+// see cmd/compile/internal/reflectdata/reflect.go and reflect/value.go.
+type hiter struct {
+	key    unsafe.Pointer // Must be in first position.  Write nil to indicate iteration end (see cmd/compile/internal/walk/range.go).
+	elem   unsafe.Pointer // Must be in second position (see cmd/compile/internal/walk/range.go).
+	t      *maptype
+	h      *hmap
+	groups unsafe.Pointer // group of a small map at initialization
+	tab    *table         // table being walked, or nil
+	_      unsafe.Pointer
+	_      unsafe.Pointer
+
+	// offsets holds the random offset at which to start walking each
+	// table in its low maxTableCapacityBits bits, and the offset at which
+	// to start walking the directory in the remaining bits.
+	offsets     uintptr
+	clearSeq    uint16 // h.clearSeq at initialization
+	globalDepth uint8  // h.globalDepth as of the last call to mapiternext
+	small       bool   // the map was small at initialization
+	dirIdx      uintptr
+	entryIdx    uintptr
+}
+
+func (it *hiter) entryOffset() uintptr {
+	return it.offsets & (maxTableCapacity - 1)
+}
+
+func (it *hiter) dirOffset() uintptr {
+	return it.offsets >> maxTableCapacityBits
+}
+
+func (it *hiter) setDirOffset(off uintptr) {
+	it.offsets = it.offsets&(maxTableCapacity-1) | off<<maxTableCapacityBits
+}
+
+// h1 returns the hash bits selecting the first group to probe.
+func h1(hash uintptr) uintptr {
+	return hash >> 7
+}
+
+// h2 returns the hash bits stored in the control byte of a full slot.
+func h2(hash uintptr) uint8 {
+	return uint8(hash & 0x7f)
+}
+
+// ctrlWord returns the control bytes of g, that of slot i in byte i.
+func (g *bmap) ctrlWord() uint64 {
+	if goarch.BigEndian {
+		var w uint64
+		for i := 0; i < groupSlots; i++ {
+			w |= uint64(g.ctrl[i]) << (8 * i)
+		}
+		return w
+	}
+	p := unsafe.Pointer(&g.ctrl)
+	if goarch.PtrSize == 4 {
+		// Groups are only pointer-aligned.
+		return uint64(*(*uint32)(p)) | uint64(*(*uint32)(add(p, 4)))<<32
+	}
+	return *(*uint64)(p)
+}
+
+// The match functions return a bitset of the slots of a control word
+// with a property, with the high bit of byte i set if slot i has it.
+
+// matchH2 returns the full slots with the given h2. It may also report
+// full slots with other h2 values, which fail the key comparison.
+func matchH2(w uint64, h uint8) uint64 {
+	v := w ^ (ctrlLSBs * uint64(ctrlFull|h))
+	return (v - ctrlLSBs) &^ v & ctrlMSBs
+}
+
+// matchEmpty returns the empty slots.
+func matchEmpty(w uint64) uint64 {
+	// A control byte is empty if neither its high nor its low bit is set.
+	return ^(w | w<<7) & ctrlMSBs
+}
+
+// matchEmptyOrDeleted returns the slots that are not full.
+func matchEmptyOrDeleted(w uint64) uint64 {
+	return ^w & ctrlMSBs
+}
+
+// matchFull returns the full slots.
+func matchFull(w uint64) uint64 {
+	return w & ctrlMSBs
+}
+
+// firstMatch returns the lowest slot in the non-empty bitset m.
+func firstMatch(m uint64) uintptr {
+	return uintptr(sys.TrailingZeros64(m)) >> 3
+}
+
+func groupAt(t *maptype, groups unsafe.Pointer, i uintptr) *bmap {
+	return (*bmap)(add(groups, i*uintptr(t.BucketSize)))
+}
+
+// key returns the key slot i of g, which holds a pointer to the key if t.IndirectKey().
+func (g *bmap) key(t *maptype, i uintptr) unsafe.Pointer {
+	return add(unsafe.Pointer(g), dataOffset+i*uintptr(t.KeySize))
+}
+
+// elem returns the elem slot i of g, which holds a pointer to the elem if t.IndirectElem().
+func (g *bmap) elem(t *maptype, i uintptr) unsafe.Pointer {
+	return add(unsafe.Pointer(g), dataOffset+groupSlots*uintptr(t.KeySize)+i*uintptr(t.ValueSize))
+}
+
+func (g *bmap) keyAt(t *maptype, i uintptr) unsafe.Pointer {
+	k := g.key(t, i)
+	if t.IndirectKey() {
+		k = *((*unsafe.Pointer)(k))
+	}
+	return k
+}
+
+func (g *bmap) elemAt(t *maptype, i uintptr) unsafe.Pointer {
+	e := g.elem(t, i)
+	if t.IndirectElem() {
+		e = *((*unsafe.Pointer)(e))
+	}
+	return e
+}
+
+// A probeSeq is a triangular probe sequence over the groups of a table,
+// which visits every group once when the number of groups is a power of two.
+type probeSeq struct {
+	mask   uintptr
+	offset uintptr
+	index  uintptr
+}
+
+func makeProbeSeq(hash, mask uintptr) probeSeq {
+	return probeSeq{mask: mask, offset: h1(hash) & mask}
+}
+
+func (s probeSeq) next() probeSeq {
+	s.index++
+	s.offset = (s.offset + s.index) & s.mask
+	return s
+}
+
+func newTable(t *maptype, capacity uintptr, localDepth uint8) *table {
+	return &table{
+		capacity:   uint16(capacity),
+		growthLeft: uint16(capacity / groupSlots * maxAvgGroupLoad),
+		localDepth: localDepth,
+		groups:     newarray(t.Bucket, int(capacity/groupSlots)),
+	}
+}
+
+func (tab *table) groupMask() uintptr {
+	return uintptr(tab.capacity)/groupSlots - 1
+}
+
+// find returns the group and slot of key in tab.
+func (tab *table) find(t *maptype, hash uintptr, key unsafe.Pointer) (*bmap, uintptr, bool) {
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		w := g.ctrlWord()
+		for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if t.Key.Equal(key, g.keyAt(t, i)) {
+				return g, i, true
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return nil, 0, false
+		}
+	}
+}
+
+// assign returns the elem slot of key in tab, inserting key if it is not present.
+// It returns nil if key must be inserted but tab has no growth left.
+func (tab *table) assign(t *maptype, h *hmap, hash uintptr, key unsafe.Pointer) unsafe.Pointer {
+	var insertGroup *bmap
+	var insertSlot uintptr
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		w := g.ctrlWord()
+		for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if k := g.keyAt(t, i); t.Key.Equal(key, k) {
+				// already have a mapping for key. Update it.
+				if t.NeedKeyUpdate() {
+					typedmemmove(t.Key, k, key)
+				}
+				return g.elem(t, i)
+			}
+		}
+		if insertGroup == nil {
+			if m := matchEmptyOrDeleted(w); m != 0 {
+				insertGroup, insertSlot = g, firstMatch(m)
+			}
+		}
+		if matchEmpty(w) != 0 {
+			break
+		}
+	}
+
+	// Reusing a deleted slot does not reduce the growth left.
+	if insertGroup.ctrl[insertSlot] == ctrlEmpty {
+		if tab.growthLeft == 0 {
+			return nil
+		}
+		tab.growthLeft--
+	}
+	insertNew(t, insertGroup, insertSlot, hash, key)
+	tab.used++
+	h.count++
+	return insertGroup.elem(t, insertSlot)
+}
+
+// insertNew stores key, with a zero elem, in the free slot i of g.
+func insertNew(t *maptype, g *bmap, i uintptr, hash uintptr, key unsafe.Pointer) {
+	k := g.key(t, i)
+	if t.IndirectKey() {
+		kmem := newobject(t.Key)
+		*(*unsafe.Pointer)(k) = kmem
+		k = kmem
+	}
+	if t.IndirectElem() {
+		vmem := newobject(t.Elem)
+		*(*unsafe.Pointer)(g.elem(t, i)) = vmem
+	}
+	typedmemmove(t.Key, k, key)
+	g.ctrl[i] = ctrlFull | h2(hash)
+}
+
+// uncheckedInsert copies the entry in slot i of src, whose key has the
+// given hash, into tab, which must not contain the key and must have room.
+func (tab *table) uncheckedInsert(t *maptype, hash uintptr, src *bmap, i uintptr) {
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		if m := matchEmpty(g.ctrlWord()); m != 0 {
+			j := firstMatch(m)
+			if t.IndirectKey() {
+				*(*unsafe.Pointer)(g.key(t, j)) = *(*unsafe.Pointer)(src.key(t, i))
+			} else {
+				typedmemmove(t.Key, g.key(t, j), src.key(t, i))
+			}
+			if t.IndirectElem() {
+				*(*unsafe.Pointer)(g.elem(t, j)) = *(*unsafe.Pointer)(src.elem(t, i))
+			} else {
+				typedmemmove(t.Elem, g.elem(t, j), src.elem(t, i))
+			}
+			g.ctrl[j] = ctrlFull | h2(hash)
+			tab.used++
+			tab.growthLeft--
+			return
+		}
+	}
+}
+
+// clear deletes all entries of tab in place.
+func (tab *table) clear(t *maptype) {
+	size := uintptr(tab.capacity) / groupSlots * uintptr(t.BucketSize)
+	if t.Bucket.Pointers() {
+		memclrHasPointers(tab.groups, size)
+	} else {
+		memclrNoHeapPointers(tab.groups, size)
+	}
+	tab.used = 0
+	tab.growthLeft = tab.capacity / groupSlots * maxAvgGroupLoad
+}
+
+// directory returns the directory of a map with tables.
+func (h *hmap) directory() []*table {
+	return unsafe.Slice((**table)(h.groups), h.dirLen)
+}
+
+func (h *hmap) setDirectory(dir []*table) {
+	h.groups = unsafe.Pointer(&dir[0])
+	h.dirLen = uintptr(len(dir))
+}
+
+func (h *hmap) directoryAt(i uintptr) *table {
+	return *(**table)(add(h.groups, i*goarch.PtrSize))
+}
+
+// tableFor returns the table holding the key with the given hash.
+func (h *hmap) tableFor(hash uintptr) *table {
+	if h.globalDepth == 0 {
+		return h.directoryAt(0)
+	}
+	return h.directoryAt(hash >> (goarch.PtrSize*8 - uintptr(h.globalDepth)))
+}
+
+// find returns the group and slot of key in h, and the table holding
+// them, which is nil for a small map.
+func (h *hmap) find(t *maptype, hash uintptr, key unsafe.Pointer) (*table, *bmap, uintptr, bool) {
+	if h.dirLen == 0 {
+		g := (*bmap)(h.groups)
+		if g == nil {
+			return nil, nil, 0, false
+		}
+		for m := matchH2(g.ctrlWord(), h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if t.Key.Equal(key, g.keyAt(t, i)) {
+				return nil, g, i, true
+			}
+		}
+		return nil, nil, 0, false
+	}
+	tab := h.tableFor(hash)
+	g, i, ok := tab.find(t, hash, key)
+	return tab, g, i, ok
+}
+
+// assign returns the elem slot of key in h, inserting key if it is not present.
+func (h *hmap) assign(t *maptype, hash uintptr, key unsafe.Pointer) unsafe.Pointer {
+	for {
+		if h.dirLen == 0 {
+			if h.groups == nil {
+				h.groups = newobject(t.Bucket)
+			}
+			g := (*bmap)(h.groups)
+			w := g.ctrlWord()
+			for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+				i := firstMatch(m)
+				if k := g.keyAt(t, i); t.Key.Equal(key, k) {
+					if t.NeedKeyUpdate() {
+						typedmemmove(t.Key, k, key)
+					}
+					return g.elem(t, i)
+				}
+			}
+			// The slots of a small map are never marked deleted.
+			if m := matchEmpty(w); m != 0 {
+				i := firstMatch(m)
+				insertNew(t, g, i, hash, key)
+				h.count++
+				return g.elem(t, i)
+			}
+			h.growSmall(t)
+			continue
+		}
+		tab := h.tableFor(hash)
+		if elem := tab.assign(t, h, hash, key); elem != nil {
+			return elem
+		}
+		h.rehash(t, tab)
+	}
+}
+
+// deleteSlot deletes the entry in slot i of g, in tab.
+func (h *hmap) deleteSlot(t *maptype, tab *table, g *bmap, i uintptr) {
+	// Only clear key if there are pointers in it.
+	k := g.key(t, i)
+	if t.IndirectKey() {
+		*(*unsafe.Pointer)(k) = nil
+	} else if t.Key.Pointers() {
+		memclrHasPointers(k, t.Key.Size_)
+	}
+	e := g.elem(t, i)
+	if t.IndirectElem() {
+		*(*unsafe.Pointer)(e) = nil
+	} else if t.Elem.Pointers() {
+		memclrHasPointers(e, t.Elem.Size_)
+	} else {
+		memclrNoHeapPointers(e, t.Elem.Size_)
+	}
+
+	if tab == nil {
+		g.ctrl[i] = ctrlEmpty
+	} else {
+		// If g has an empty slot, every probe sequence reaching g
+		// stops there, so the slot can be marked empty and reused
+		// freely. Otherwise it must be marked deleted so that probe
+		// sequences continue past g.
+		if matchEmpty(g.ctrlWord()) != 0 {
+			g.ctrl[i] = ctrlEmpty
+			tab.growthLeft++
+		} else {
+			g.ctrl[i] = ctrlDeleted
+		}
+		tab.used--
+	}
+	h.count--
+	if h.count == 0 {
+		// Reset the hash seed to make it more difficult for attackers to
+		// repeatedly trigger hash collisions. See issue 25237.
+		h.hash0 = uint32(rand())
+	}
+}
+
+// growSmall moves the entries of a small map, whose group is full, into a table.
+// The group itself is left unchanged for the benefit of iterators.
+func (h *hmap) growSmall(t *maptype) {
+	g := (*bmap)(h.groups)
+	tab := newTable(t, 2*groupSlots, 0)
+	for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+		i := firstMatch(m)
+		tab.uncheckedInsert(t, t.Hasher(g.keyAt(t, i), uintptr(h.hash0)), g, i)
+	}
+	h.globalDepth = 0
+	h.setDirectory([]*table{tab})
+}
+
+// rehash makes room in tab, which has no growth left. It replaces tab
+// with a table twice the size or, if most of the slots taken are deleted,
+// of the same size. A table that is already at the maximum size is split.
+func (h *hmap) rehash(t *maptype, tab *table) {
+	capacity := 2 * uintptr(tab.capacity)
+	if uintptr(tab.used) <= uintptr(tab.capacity)/groupSlots*maxAvgGroupLoad/2 {
+		capacity = uintptr(tab.capacity)
+	}
+	if capacity > maxTableCapacity {
+		h.split(t, tab)
+		return
+	}
+	nt := newTable(t, capacity, tab.localDepth)
+	for gi := uintptr(0); gi <= tab.groupMask(); gi++ {
+		g := groupAt(t, tab.groups, gi)
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			nt.uncheckedInsert(t, t.Hasher(g.keyAt(t, i), uintptr(h.hash0)), g, i)
+		}
+	}
+	h.replaceTable(tab, nt, nt)
+}
+
+// split replaces tab with two tables, dividing its entries by the next hash bit.
+func (h *hmap) split(t *maptype, tab *table) {
+	localDepth := tab.localDepth + 1
+	left := newTable(t, uintptr(tab.capacity), localDepth)
+	right := newTable(t, uintptr(tab.capacity), localDepth)
+	shift := goarch.PtrSize*8 - uintptr(localDepth)
+	for gi := uintptr(0); gi <= tab.groupMask(); gi++ {
+		g := groupAt(t, tab.groups, gi)
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			hash := t.Hasher(g.keyAt(t, i), uintptr(h.hash0))
+			if hash>>shift&1 == 0 {
+				left.uncheckedInsert(t, hash, g, i)
+			} else {
+				right.uncheckedInsert(t, hash, g, i)
+			}
+		}
+	}
+	if tab.localDepth == h.globalDepth {
+		h.growDirectory()
+	}
+	h.replaceTable(tab, left, right)
+}
+
+// growDirectory doubles the length of the directory.
+func (h *hmap) growDirectory() {
+	old := h.directory()
+	dir := make([]*table, 2*len(old))
+	for i, tab := range old {
+		dir[2*i] = tab
+		dir[2*i+1] = tab
+	}
+	h.globalDepth++
+	for i := 0; i < len(dir); i += 1 << (h.globalDepth - dir[i].localDepth) {
+		dir[i].index = i
+	}
+	h.setDirectory(dir)
+}
+
+// replaceTable replaces old in the directory with left and right, which
+// take the first and second half of its directory entries. They may be
+// the same table.
+func (h *hmap) replaceTable(old, left, right *table) {
+	dir := h.directory()
+	n := 1 << (h.globalDepth - old.localDepth)
+	start := old.index
+	for i := start; i < start+n; i++ {
+		if i < start+n/2 || n == 1 {
+			dir[i] = left
+		} else {
+			dir[i] = right
+		}
+	}
+	left.index = start
+	if right != left {
+		right.index = start + n/2
+	}
+	old.index = -1
+}
+
+func makemap64(t *maptype, hint int64, h *hmap) *hmap {
+	if int64(int(hint)) != hint {
+		hint = 0
+	}
+	return makemap(t, int(hint), h)
+}
+
+// makemap_small implements Go map creation for make(map[k]v) and
+// make(map[k]v, hint) when hint is known to be at most bucketCnt
+// at compile time and the map needs to be allocated on the heap.
+func makemap_small() *hmap {
+	h := new(hmap)
+	h.hash0 = uint32(rand())
+	return h
+}
+
+// makemap implements Go map creation for make(map[k]v, hint).
+// If the compiler has determined that the map or the first group
+// can be created on the stack, h and/or group may be non-nil.
+// If h != nil, the map can be created directly in h.
+// If h.groups != nil, the group pointed to is used as the group of a small map.
+func makemap(t *maptype, hint int, h *hmap) *hmap {
+	mem, overflow := math.MulUintptr(uintptr(hint), t.Bucket.Size_)
+	if overflow || mem > maxAlloc {
+		hint = 0
+	}
+
+	if h == nil {
+		h = new(hmap)
+	}
+	h.hash0 = uint32(rand())
+
+	if hint <= groupSlots {
+		// The group is allocated lazily, in mapassign.
+		return h
+	}
+
+	// Allocate tables holding hint entries at most 7/8 full.
+	capacity := uintptr(2 * groupSlots)
+	for capacity/groupSlots*maxAvgGroupLoad < uintptr(hint) {
+		capacity <<= 1
+	}
+	var depth uint8
+	for capacity > maxTableCapacity {
+		capacity >>= 1
+		depth++
+	}
+	dir := make([]*table, 1<<depth)
+	for i := range dir {
+		dir[i] = newTable(t, capacity, depth)
+		dir[i].index = i
+	}
+	h.globalDepth = depth
+	h.setDirectory(dir)
+	return h
+}
+
+// mapaccess1 returns a pointer to h[key].  Never returns nil, instead
+// it will return a reference to the zero object for the elem type if
+// the key is not in the map.
+// NOTE: The returned pointer may keep the whole map live, so don't
+// hold onto it for very long.
+func mapaccess1(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapaccess1)
+		racereadpc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled && h != nil {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled && h != nil {
+		asanread(key, t.Key.Size_)
+	}
+	if h == nil || h.count == 0 {
+		if err := mapKeyError(t, key); err != nil {
+			panic(err) // see issue 23734
+		}
+		return unsafe.Pointer(&zeroVal[0])
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	hash := t.Hasher(key, uintptr(h.hash0))
+	if _, g, i, ok := h.find(t, hash, key); ok {
+		return g.elemAt(t, i)
+	}
+	return unsafe.Pointer(&zeroVal[0])
+}
+
+func mapaccess2(t *maptype, h *hmap, key unsafe.Pointer) (unsafe.Pointer, bool) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapaccess2)
+		racereadpc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled && h != nil {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled && h != nil {
+		asanread(key, t.Key.Size_)
+	}
+	if h == nil || h.count == 0 {
+		if err := mapKeyError(t, key); err != nil {
+			panic(err) // see issue 23734
+		}
+		return unsafe.Pointer(&zeroVal[0]), false
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	hash := t.Hasher(key, uintptr(h.hash0))
+	if _, g, i, ok := h.find(t, hash, key); ok {
+		return g.elemAt(t, i), true
+	}
+	return unsafe.Pointer(&zeroVal[0]), false
+}
+
+// returns both key and elem. Used by map iterator.
+func mapaccessK(t *maptype, h *hmap, key unsafe.Pointer) (unsafe.Pointer, unsafe.Pointer) {
+	if h == nil || h.count == 0 {
+		return nil, nil
+	}
+	hash := t.Hasher(key, uintptr(h.hash0))
+	if _, g, i, ok := h.find(t, hash, key); ok {
+		return g.keyAt(t, i), g.elemAt(t, i)
+	}
+	return nil, nil
+}
+
+func mapaccess1_fat(t *maptype, h *hmap, key, zero unsafe.Pointer) unsafe.Pointer {
+	e := mapaccess1(t, h, key)
+	if e == unsafe.Pointer(&zeroVal[0]) {
+		return zero
+	}
+	return e
+}
+
+func mapaccess2_fat(t *maptype, h *hmap, key, zero unsafe.Pointer) (unsafe.Pointer, bool) {
+	e := mapaccess1(t, h, key)
+	if e == unsafe.Pointer(&zeroVal[0]) {
+		return zero, false
+	}
+	return e, true
+}
+
+// Like mapaccess, but allocates a slot for the key if it is not present in the map.
+func mapassign(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapassign)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled {
+		asanread(key, t.Key.Size_)
+	}
+	return h.assignKey(t, key)
+}
+
+// assignKey implements mapassign, after instrumentation.
+func (h *hmap) assignKey(t *maptype, key unsafe.Pointer) unsafe.Pointer {
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+	hash := t.Hasher(key, uintptr(h.hash0))
+
+	// Set hashWriting after calling t.hasher, since t.hasher may panic,
+	// in which case we have not actually done a write.
+	h.flags ^= hashWriting
+
+	elem := h.assign(t, hash, key)
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
+	if t.IndirectElem() {
+		elem = *((*unsafe.Pointer)(elem))
+	}
+	return elem
+}
+
+func mapdelete(t *maptype, h *hmap, key unsafe.Pointer) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapdelete)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled && h != nil {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled && h != nil {
+		asanread(key, t.Key.Size_)
+	}
+	if h == nil || h.count == 0 {
+		if err := mapKeyError(t, key); err != nil {
+			panic(err) // see issue 23734
+		}
+		return
+	}
+	h.deleteKey(t, key)
+}
+
+// deleteKey implements mapdelete for a non-empty map, after instrumentation.
+func (h *hmap) deleteKey(t *maptype, key unsafe.Pointer) {
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+	hash := t.Hasher(key, uintptr(h.hash0))
+
+	// Set hashWriting after calling t.hasher, since t.hasher may panic,
+	// in which case we have not actually done a write (delete).
+	h.flags ^= hashWriting
+
+	if tab, g, i, ok := h.find(t, hash, key); ok {
+		h.deleteSlot(t, tab, g, i)
+	}
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
+}
+
+// mapiterinit initializes the hiter struct used for ranging over maps.
+// The hiter struct pointed to by 'it' is allocated on the stack
+// by the compilers order pass or on the heap by reflect_mapiterinit.
+// Both need to have zeroed hiter since the struct contains pointers.
+func mapiterinit(t *maptype, h *hmap, it *hiter) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapiterinit))
+	}
+
+	it.t = t
+	if h == nil || h.count == 0 {
+		return
+	}
+
+	if unsafe.Sizeof(hiter{})/goarch.PtrSize != 12 {
+		throw("hash_iter size incorrect") // see cmd/compile/internal/reflectdata/reflect.go
+	}
+	it.h = h
+	it.clearSeq = h.clearSeq
+
+	// decide where to start
+	it.offsets = uintptr(rand())
+	if h.dirLen == 0 {
+		it.small = true
+		it.groups = h.groups
+	} else {
+		it.globalDepth = h.globalDepth
+	}
+
+	mapiternext(it)
+}
+
+func mapiternext(it *hiter) {
+	h := it.h
+	if raceenabled {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapiternext))
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map iteration and map write")
+	}
+	if it.clearSeq != h.clearSeq {
+		// The map has been cleared, deleting every entry that
+		// existed when the iteration started.
+		it.key = nil
+		it.elem = nil
+		return
+	}
+	t := it.t
+
+	if it.small {
+		g := (*bmap)(it.groups)
+		replaced := h.dirLen != 0 || h.groups != it.groups
+		for ; it.entryIdx < groupSlots; it.entryIdx++ {
+			i := (it.entryIdx + it.offsets) & (groupSlots - 1)
+			if g.ctrl[i]&ctrlFull == 0 {
+				continue
+			}
+			if !it.setEntry(g, i, replaced) {
+				continue
+			}
+			it.entryIdx++
+			return
+		}
+		it.key = nil
+		it.elem = nil
+		return
+	}
+
+	if it.globalDepth != h.globalDepth {
+		// The directory has grown since the last call. Scale our
+		// position in it, which is aligned to the start of a table.
+		orders := h.globalDepth - it.globalDepth
+		it.dirIdx <<= orders
+		it.setDirOffset(it.dirOffset() << orders)
+		it.globalDepth = h.globalDepth
+	}
+	for ; it.dirIdx < h.dirLen; it.nextTable() {
+		if it.tab == nil {
+			dirIdx := (it.dirIdx + it.dirOffset()) & (h.dirLen - 1)
+			tab := h.directoryAt(dirIdx)
+			if uintptr(tab.index) != dirIdx {
+				// The random starting point is in the middle of the
+				// entries of a table. Move it to the first entry, so
+				// that nextTable skips over all entries of each table.
+				it.setDirOffset(it.dirOffset() - (dirIdx - uintptr(tab.index)))
+			}
+			it.tab = tab
+		}
+		tab := it.tab
+		mask := uintptr(tab.capacity) - 1
+		for ; it.entryIdx <= mask; it.entryIdx++ {
+			slot := (it.entryIdx + it.entryOffset()) & mask
+			g := groupAt(t, tab.groups, slot/groupSlots)
+			i := slot % groupSlots
+			if g.ctrl[i]&ctrlFull == 0 {
+				continue
+			}
+			if !it.setEntry(g, i, tab.index < 0) {
+				continue
+			}
+			it.entryIdx++
+			return
+		}
+	}
+	it.key = nil
+	it.elem = nil
+}
+
+// nextTable moves the iterator past all directory entries of the current table.
+func (it *hiter) nextTable() {
+	it.dirIdx += 1 << (it.globalDepth - it.tab.localDepth)
+	it.tab = nil
+	it.entryIdx = 0
+}
+
+// setEntry sets it.key and it.elem to the full slot i of g. If g has
+// been replaced, the entry is looked up in the current map, and
+// setEntry reports false if it has since been deleted.
+func (it *hiter) setEntry(g *bmap, i uintptr, replaced bool) bool {
+	t := it.t
+	k, e := g.keyAt(t, i), g.elemAt(t, i)
+	if replaced && (t.ReflexiveKey() || t.Key.Equal(k, k)) {
+		// The entry may have been updated or deleted since its
+		// table was replaced.
+		k, e = mapaccessK(t, it.h, k)
+		if k == nil {
+			return false
+		}
+	}
+	// A key that is not equal to itself (a NaN) cannot be updated
+	// or deleted, except by clear, which stops the iteration.
+	it.key = k
+	it.elem = e
+	return true
+}
+
+// mapclear deletes all keys from a map.
+func mapclear(t *maptype, h *hmap) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapclear)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+	}
+
+	if h == nil || h.count == 0 {
+		return
+	}
+
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map writes")
+	}
+
+	h.flags ^= hashWriting
+
+	if h.dirLen == 0 {
+		if t.Bucket.Pointers() {
+			memclrHasPointers(h.groups, t.Bucket.Size_)
+		} else {
+			memclrNoHeapPointers(h.groups, t.Bucket.Size_)
+		}
+	} else {
+		dir := h.directory()
+		for i := 0; i < len(dir); i += 1 << (h.globalDepth - dir[i].localDepth) {
+			dir[i].clear(t)
+		}
+	}
+	h.count = 0
+	h.clearSeq++
+
+	// Reset the hash seed to make it more difficult for attackers to
+	// repeatedly trigger hash collisions. See issue 25237.
+	h.hash0 = uint32(rand())
+
+	if h.flags&hashWriting == 0 {
+		fatal("concurrent map writes")
+	}
+	h.flags &^= hashWriting
+}
+
+// Reflect stubs. Called from ../reflect/asm_*.s
+
+//go:linkname reflect_makemap reflect.makemap
+func reflect_makemap(t *maptype, cap int) *hmap {
+	// Check invariants and reflects math.
+	if t.Key.Equal == nil {
+		throw("runtime.reflect_makemap: unsupported map key type")
+	}
+	if t.Key.Size_ > abi.MapMaxKeyBytes && (!t.IndirectKey() || t.KeySize != uint8(goarch.PtrSize)) ||
+		t.Key.Size_ <= abi.MapMaxKeyBytes && (t.IndirectKey() || t.KeySize != uint8(t.Key.Size_)) {
+		throw("key size wrong")
+	}
+	if t.Elem.Size_ > abi.MapMaxElemBytes && (!t.IndirectElem() || t.ValueSize != uint8(goarch.PtrSize)) ||
+		t.Elem.Size_ <= abi.MapMaxElemBytes && (t.IndirectElem() || t.ValueSize != uint8(t.Elem.Size_)) {
+		throw("elem size wrong")
+	}
+	if t.Key.Align_ > abi.MapBucketCount {
+		throw("key align too big")
+	}
+	if t.Elem.Align_ > abi.MapBucketCount {
+		throw("elem align too big")
+	}
+	if t.Key.Size_%uintptr(t.Key.Align_) != 0 {
+		throw("key size not a multiple of key align")
+	}
+	if t.Elem.Size_%uintptr(t.Elem.Align_) != 0 {
+		throw("elem size not a multiple of elem align")
+	}
+	if abi.MapBucketCount < 8 {
+		throw("bucketsize too small for proper alignment")
+	}
+	if dataOffset%uintptr(t.Key.Align_) != 0 {
+		throw("need padding in bucket (key)")
+	}
+	if dataOffset%uintptr(t.Elem.Align_) != 0 {
+		throw("need padding in bucket (elem)")
+	}
+
+	return makemap(t, cap, nil)
+}
+
+//go:linkname reflect_mapaccess reflect.mapaccess
+func reflect_mapaccess(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	elem, ok := mapaccess2(t, h, key)
+	if !ok {
+		// reflect wants nil for a missing element
+		elem = nil
+	}
+	return elem
+}
+
+//go:linkname reflect_mapaccess_faststr reflect.mapaccess_faststr
+func reflect_mapaccess_faststr(t *maptype, h *hmap, key string) unsafe.Pointer {
+	elem, ok := mapaccess2_faststr(t, h, key)
+	if !ok {
+		// reflect wants nil for a missing element
+		elem = nil
+	}
+	return elem
+}
+
+//go:linkname reflect_mapassign reflect.mapassign0
+func reflect_mapassign(t *maptype, h *hmap, key unsafe.Pointer, elem unsafe.Pointer) {
+	p := mapassign(t, h, key)
+	typedmemmove(t.Elem, p, elem)
+}
+
+//go:linkname reflect_mapassign_faststr reflect.mapassign_faststr0
+func reflect_mapassign_faststr(t *maptype, h *hmap, key string, elem unsafe.Pointer) {
+	p := mapassign_faststr(t, h, key)
+	typedmemmove(t.Elem, p, elem)
+}
+
+//go:linkname reflect_mapdelete reflect.mapdelete
+func reflect_mapdelete(t *maptype, h *hmap, key unsafe.Pointer) {
+	mapdelete(t, h, key)
+}
+
+//go:linkname reflect_mapdelete_faststr reflect.mapdelete_faststr
+func reflect_mapdelete_faststr(t *maptype, h *hmap, key string) {
+	mapdelete_faststr(t, h, key)
+}
+
+//go:linkname reflect_mapiterinit reflect.mapiterinit
+func reflect_mapiterinit(t *maptype, h *hmap, it *hiter) {
+	mapiterinit(t, h, it)
+}
+
+//go:linkname reflect_mapiternext reflect.mapiternext
+func reflect_mapiternext(it *hiter) {
+	mapiternext(it)
+}
+
+//go:linkname reflect_mapiterkey reflect.mapiterkey
+func reflect_mapiterkey(it *hiter) unsafe.Pointer {
+	return it.key
+}
+
+//go:linkname reflect_mapiterelem reflect.mapiterelem
+func reflect_mapiterelem(it *hiter) unsafe.Pointer {
+	return it.elem
+}
+
+//go:linkname reflect_maplen reflect.maplen
+func reflect_maplen(h *hmap) int {
+	if h == nil {
+		return 0
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(reflect_maplen))
+	}
+	return h.count
+}
+
+//go:linkname reflect_mapclear reflect.mapclear
+func reflect_mapclear(t *maptype, h *hmap) {
+	mapclear(t, h)
+}
+
+//go:linkname reflectlite_maplen internal/reflectlite.maplen
+func reflectlite_maplen(h *hmap) int {
+	if h == nil {
+		return 0
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(reflect_maplen))
+	}
+	return h.count
+}
+
+var zeroVal [abi.ZeroValSize]byte
+
+// mapinitnoop is a no-op function known the Go linker; if a given global
+// map (of the right size) is determined to be dead, the linker will
+// rewrite the relocation (from the package init func) from the outlined
+// map init function to this symbol. Defined in assembly so as to avoid
+// complications with instrumentation (coverage, etc).
+func mapinitnoop()
+
+// mapclone for implementing maps.Clone
+//
+//go:linkname mapclone maps.clone
+func mapclone(m any) any {
+	e := efaceOf(&m)
+	e.data = unsafe.Pointer(mapclone2((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data)))
+	return m
+}
+
+func mapclone2(t *maptype, src *hmap) *hmap {
+	dst := makemap(t, src.count, nil)
+	// flags do not need to be copied here, just like a new map has no flags.
+
+	if src.count == 0 {
+		return dst
+	}
+
+	if src.flags&hashWriting != 0 {
+		fatal("concurrent map clone and map write")
+	}
+
+	if src.dirLen == 0 && !t.IndirectKey() && !t.IndirectElem() {
+		// Quick copy for small maps. The control bytes depend on the seed.
+		dst.hash0 = src.hash0
+		dst.groups = newobject(t.Bucket)
+		dst.count = src.count
+		typedmemmove(t.Bucket, dst.groups, src.groups)
+		return dst
+	}
+
+	copyGroup := func(g *bmap) {
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			k := g.keyAt(t, i)
+			// Keys that are not equal to themselves are inserted
+			// as new entries, as they are never found by assign.
+			elem := dst.assign(t, t.Hasher(k, uintptr(dst.hash0)), k)
+			if t.IndirectElem() {
+				elem = *((*unsafe.Pointer)(elem))
+			}
+			typedmemmove(t.Elem, elem, g.elemAt(t, i))
+		}
+	}
+	if src.dirLen == 0 {
+		copyGroup((*bmap)(src.groups))
+		return dst
+	}
+	dir := src.directory()
+	for i := 0; i < len(dir); i += 1 << (src.globalDepth - dir[i].localDepth) {
+		tab := dir[i]
+		for gi := uintptr(0); gi <= tab.groupMask(); gi++ {
+			copyGroup(groupAt(t, tab.groups, gi))
+		}
+	}
+	return dst
+}
+
+// keys for implementing maps.keys
+//
+//go:linkname keys maps.keys
+func keys(m any, p unsafe.Pointer) {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+
+	if h == nil || h.count == 0 {
+		return
+	}
+	s := (*slice)(p)
+	var it hiter
+	for mapiterinit(t, h, &it); it.key != nil; mapiternext(&it) {
+		if s.len >= s.cap {
+			fatal("concurrent map read and map write")
+		}
+		typedmemmove(t.Key, add(s.array, uintptr(s.len)*uintptr(t.Key.Size())), it.key)
+		s.len++
+	}
+}
+
+// values for implementing maps.values
+//
+//go:linkname values maps.values
+func values(m any, p unsafe.Pointer) {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+
+	if h == nil || h.count == 0 {
+		return
+	}
+	s := (*slice)(p)
+	var it hiter
+	for mapiterinit(t, h, &it); it.key != nil; mapiternext(&it) {
+		if s.len >= s.cap {
+			fatal("concurrent map read and map write")
+		}
+		typedmemmove(t.Elem, add(s.array, uintptr(s.len)*uintptr(t.Elem.Size())), it.elem)
+		s.len++
+	}
+}
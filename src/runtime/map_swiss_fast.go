@@ -0,0 +1,292 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.swissmap
+
+package runtime
+
+// Specialized map operations for 32-bit, 64-bit and string keys,
+// used in place of map_fast*.go when GOEXPERIMENT=swissmap is set.
+// Lookups compare keys directly instead of calling t.Key.Equal, and
+// lookups in small maps compare the keys of all full slots without
+// hashing. Keys and elems of these maps are never indirect.
+
+import (
+	"internal/abi"
+	"unsafe"
+)
+
+func (h *hmap) find32(t *maptype, key uint32) (*bmap, uintptr, bool) {
+	if h.dirLen == 0 {
+		g := (*bmap)(h.groups)
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*uint32)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		return nil, 0, false
+	}
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
+	tab := h.tableFor(hash)
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		w := g.ctrlWord()
+		for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*uint32)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return nil, 0, false
+		}
+	}
+}
+
+func (h *hmap) find64(t *maptype, key uint64) (*bmap, uintptr, bool) {
+	if h.dirLen == 0 {
+		g := (*bmap)(h.groups)
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*uint64)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		return nil, 0, false
+	}
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
+	tab := h.tableFor(hash)
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		w := g.ctrlWord()
+		for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*uint64)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return nil, 0, false
+		}
+	}
+}
+
+func (h *hmap) findStr(t *maptype, key string) (*bmap, uintptr, bool) {
+	if h.dirLen == 0 {
+		g := (*bmap)(h.groups)
+		for m := matchFull(g.ctrlWord()); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*string)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		return nil, 0, false
+	}
+	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
+	tab := h.tableFor(hash)
+	for seq := makeProbeSeq(hash, tab.groupMask()); ; seq = seq.next() {
+		g := groupAt(t, tab.groups, seq.offset)
+		w := g.ctrlWord()
+		for m := matchH2(w, h2(hash)); m != 0; m &= m - 1 {
+			i := firstMatch(m)
+			if *(*string)(g.key(t, i)) == key {
+				return g, i, true
+			}
+		}
+		if matchEmpty(w) != 0 {
+			return nil, 0, false
+		}
+	}
+}
+
+func mapaccess1_fast32(t *maptype, h *hmap, key uint32) unsafe.Pointer {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess1_fast32))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0])
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.find32(t, key); ok {
+		return g.elem(t, i)
+	}
+	return unsafe.Pointer(&zeroVal[0])
+}
+
+func mapaccess2_fast32(t *maptype, h *hmap, key uint32) (unsafe.Pointer, bool) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess2_fast32))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0]), false
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.find32(t, key); ok {
+		return g.elem(t, i), true
+	}
+	return unsafe.Pointer(&zeroVal[0]), false
+}
+
+func mapassign_fast32(t *maptype, h *hmap, key uint32) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast32))
+	}
+	return h.assignKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapassign_fast32ptr(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast32ptr))
+	}
+	return h.assignKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapdelete_fast32(t *maptype, h *hmap, key uint32) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapdelete_fast32))
+	}
+	if h == nil || h.count == 0 {
+		return
+	}
+	h.deleteKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapaccess1_fast64(t *maptype, h *hmap, key uint64) unsafe.Pointer {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess1_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0])
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.find64(t, key); ok {
+		return g.elem(t, i)
+	}
+	return unsafe.Pointer(&zeroVal[0])
+}
+
+func mapaccess2_fast64(t *maptype, h *hmap, key uint64) (unsafe.Pointer, bool) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess2_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0]), false
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.find64(t, key); ok {
+		return g.elem(t, i), true
+	}
+	return unsafe.Pointer(&zeroVal[0]), false
+}
+
+func mapassign_fast64(t *maptype, h *hmap, key uint64) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast64))
+	}
+	return h.assignKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapassign_fast64ptr(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast64ptr))
+	}
+	return h.assignKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapdelete_fast64(t *maptype, h *hmap, key uint64) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapdelete_fast64))
+	}
+	if h == nil || h.count == 0 {
+		return
+	}
+	h.deleteKey(t, noescape(unsafe.Pointer(&key)))
+}
+
+func mapaccess1_faststr(t *maptype, h *hmap, ky string) unsafe.Pointer {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess1_faststr))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0])
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.findStr(t, ky); ok {
+		return g.elem(t, i)
+	}
+	return unsafe.Pointer(&zeroVal[0])
+}
+
+func mapaccess2_faststr(t *maptype, h *hmap, ky string) (unsafe.Pointer, bool) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapaccess2_faststr))
+	}
+	if h == nil || h.count == 0 {
+		return unsafe.Pointer(&zeroVal[0]), false
+	}
+	if h.flags&hashWriting != 0 {
+		fatal("concurrent map read and map write")
+	}
+	if g, i, ok := h.findStr(t, ky); ok {
+		return g.elem(t, i), true
+	}
+	return unsafe.Pointer(&zeroVal[0]), false
+}
+
+func mapassign_faststr(t *maptype, h *hmap, s string) unsafe.Pointer {
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_faststr))
+	}
+	return h.assignKey(t, noescape(unsafe.Pointer(&s)))
+}
+
+func mapdelete_faststr(t *maptype, h *hmap, ky string) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapdelete_faststr))
+	}
+	if h == nil || h.count == 0 {
+		return
+	}
+	h.deleteKey(t, noescape(unsafe.Pointer(&ky)))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"internal/abi"
 	"internal/goarch"
+	"internal/goexperiment"
 	"math"
 	"reflect"
 	"runtime"
@@ -713,6 +714,9 @@ var mapBucketTests = [...]struct {
 }
 
 func TestMapBuckets(t *testing.T) {
+	if goexperiment.SwissMap {
+		t.Skip("Swiss table maps are not made of buckets")
+	}
 	// Test that maps of different sizes have the right number of buckets.
 	// Non-escaping maps with small buckets (like map[int]int) never
 	// have a nil bucket pointer due to starting with preallocated buckets
@@ -1421,6 +1425,9 @@ func TestEmptyMapWithInterfaceKey(t *testing.T) {
 }
 
 func TestLoadFactor(t *testing.T) {
+	if goexperiment.SwissMap {
+		t.Skip("Swiss table maps have no overLoadFactor")
+	}
 	for b := uint8(0); b < 20; b++ {
 		count := 13 * (1 << b) / 2 // 6.5
 		if b == 0 {
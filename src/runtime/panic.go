@@ -296,11 +296,30 @@ func deferproc(fn func()) {
 	// been set and must not be clobbered.
 }
 
-var rangeExitError = error(errorString("range function continued iteration after exit"))
-
+var rangeDoneError = error(errorString("range function continued iteration after function for loop body returned false"))
+var rangePanicError = error(errorString("range function continued iteration after loop body panic"))
+var rangeExhaustedError = error(errorString("range function continued iteration after whole loop exit"))
+var rangeMissingPanicError = error(errorString("range function recovered a loop body panic and did not resume panicking"))
+
+// panicrangestate is called by the code the compiler generates for a
+// range-over-func loop when the iterator function misbehaves. state
+// is the abi.RF_State of the loop body at the time: the state in which
+// the iterator wrongly called the loop body, or abi.RF_MISSING_PANIC
+// if the iterator returned normally after the loop body panicked.
+//
 //go:noinline
-func panicrangeexit() {
-	panic(rangeExitError)
+func panicrangestate(state int) {
+	switch abi.RF_State(state) {
+	case abi.RF_DONE:
+		panic(rangeDoneError)
+	case abi.RF_PANIC:
+		panic(rangePanicError)
+	case abi.RF_EXHAUSTED:
+		panic(rangeExhaustedError)
+	case abi.RF_MISSING_PANIC:
+		panic(rangeMissingPanicError)
+	}
+	throw("unexpected state passed to panicrangestate")
 }
 
 // deferrangefunc is called by functions that are about to
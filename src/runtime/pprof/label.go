@@ -7,7 +7,7 @@ package pprof
 import (
 	"context"
 	"fmt"
-	"sort"
+	"slices"
 	"strings"
 )
 
@@ -27,15 +27,20 @@ type labelContextKey struct{}
 func labelValue(ctx context.Context) labelMap {
 	labels, _ := ctx.Value(labelContextKey{}).(*labelMap)
 	if labels == nil {
-		return labelMap(nil)
+		return labelMap{}
 	}
 	return *labels
 }
 
 // labelMap is the representation of the label set held in the context type.
-// This is an initial implementation, but it will be replaced with something
-// that admits incremental immutable modification more efficiently.
-type labelMap map[string]string
+// The labels are sorted by key and keys are unique.
+//
+// The runtime reads this structure directly when printing goroutine
+// tracebacks and writing execution traces (see runtime/proflabel.go),
+// so its layout must be kept in sync with runtime.profLabels.
+type labelMap struct {
+	LabelSet
+}
 
 // String satisfies Stringer and returns key, value pairs in a consistent
 // order.
@@ -43,14 +48,12 @@ func (l *labelMap) String() string {
 	if l == nil {
 		return ""
 	}
-	keyVals := make([]string, 0, len(*l))
+	keyVals := make([]string, 0, len(l.list))
 
-	for k, v := range *l {
-		keyVals = append(keyVals, fmt.Sprintf("%q:%q", k, v))
+	for _, lbl := range l.list {
+		keyVals = append(keyVals, fmt.Sprintf("%q:%q", lbl.key, lbl.value))
 	}
 
-	sort.Strings(keyVals)
-
 	return "{" + strings.Join(keyVals, ", ") + "}"
 }
 
@@ -58,24 +61,67 @@ func (l *labelMap) String() string {
 // A label overwrites a prior label with the same key.
 func WithLabels(ctx context.Context, labels LabelSet) context.Context {
 	parentLabels := labelValue(ctx)
-	childLabels := make(labelMap, len(parentLabels))
-	// TODO(matloob): replace the map implementation with something
-	// more efficient so creating a child context WithLabels doesn't need
-	// to clone the map.
-	for k, v := range parentLabels {
-		childLabels[k] = v
+	return context.WithValue(ctx, labelContextKey{}, &labelMap{mergeLabelSets(parentLabels.LabelSet, labels)})
+}
+
+// mergeLabelSets returns the union of left and right, which is sorted by
+// key. left must already be sorted; right may be in any order. Labels in
+// right take precedence over labels in left, and later labels in right
+// over earlier ones.
+func mergeLabelSets(left, right LabelSet) LabelSet {
+	if len(right.list) == 0 {
+		return left
+	}
+	if !slices.IsSortedFunc(right.list, compareLabelKeys) {
+		// Sort stably, so that for duplicate keys the last label
+		// provided is the last one in its run.
+		list := slices.Clone(right.list)
+		slices.SortStableFunc(list, compareLabelKeys)
+		right = LabelSet{list: list}
 	}
-	for _, label := range labels.list {
-		childLabels[label.key] = label.value
+
+	list := make([]label, 0, len(left.list)+len(right.list))
+	i, j := 0, 0
+	for i < len(left.list) || j < len(right.list) {
+		var next label
+		switch {
+		case j == len(right.list):
+			next = left.list[i]
+			i++
+		case i == len(left.list):
+			next = right.list[j]
+			j++
+		case left.list[i].key < right.list[j].key:
+			next = left.list[i]
+			i++
+		case left.list[i].key > right.list[j].key:
+			next = right.list[j]
+			j++
+		default:
+			// Same key: the right-hand label wins.
+			next = right.list[j]
+			i++
+			j++
+		}
+		if n := len(list); n > 0 && list[n-1].key == next.key {
+			list[n-1] = next
+		} else {
+			list = append(list, next)
+		}
 	}
-	return context.WithValue(ctx, labelContextKey{}, &childLabels)
+	return LabelSet{list: list}
+}
+
+func compareLabelKeys(a, b label) int {
+	return strings.Compare(a.key, b.key)
 }
 
 // Labels takes an even number of strings representing key-value pairs
 // and makes a [LabelSet] containing them.
 // A label overwrites a prior label with the same key.
-// Currently only the CPU and goroutine profiles utilize any labels
-// information.
+// Labels set on a goroutine are recorded in the CPU and goroutine profiles
+// and in execution traces (see [runtime/trace]). Setting GODEBUG=tracebacklabels=1
+// also prints them in goroutine tracebacks.
 // See https://golang.org/issue/23458 for details.
 func Labels(args ...string) LabelSet {
 	if len(args)%2 != 0 {
@@ -92,16 +138,21 @@ func Labels(args ...string) LabelSet {
 // whether that label exists.
 func Label(ctx context.Context, key string) (string, bool) {
 	ctxLabels := labelValue(ctx)
-	v, ok := ctxLabels[key]
-	return v, ok
+	i, ok := slices.BinarySearchFunc(ctxLabels.list, key, func(l label, key string) int {
+		return strings.Compare(l.key, key)
+	})
+	if !ok {
+		return "", false
+	}
+	return ctxLabels.list[i].value, true
 }
 
 // ForLabels invokes f with each label set on the context.
 // The function f should return true to continue iteration or false to stop iteration early.
 func ForLabels(ctx context.Context, f func(key, value string) bool) {
 	ctxLabels := labelValue(ctx)
-	for k, v := range ctxLabels {
-		if !f(k, v) {
+	for _, lbl := range ctxLabels.list {
+		if !f(lbl.key, lbl.value) {
 			break
 		}
 	}
@@ -96,18 +96,16 @@ func TestLabelMapStringer(t *testing.T) {
 			},
 			expected: "{}",
 		}, {
-			m: labelMap{
-				"foo": "bar",
-			},
+			m:        labelMap{Labels("foo", "bar")},
 			expected: `{"foo":"bar"}`,
 		}, {
-			m: labelMap{
-				"foo":             "bar",
-				"key1":            "value1",
-				"key2":            "value2",
-				"key3":            "value3",
-				"key4WithNewline": "\nvalue4",
-			},
+			m: labelMap{Labels(
+				"foo", "bar",
+				"key1", "value1",
+				"key2", "value2",
+				"key3", "value3",
+				"key4WithNewline", "\nvalue4",
+			)},
 			expected: `{"foo":"bar", "key1":"value1", "key2":"value2", "key3":"value3", "key4WithNewline":"\nvalue4"}`,
 		},
 	} {
@@ -116,3 +114,19 @@ func TestLabelMapStringer(t *testing.T) {
 		}
 	}
 }
+
+func TestLabelsDuplicateKeys(t *testing.T) {
+	ctx := WithLabels(context.Background(), Labels("b", "1", "a", "2", "b", "3"))
+	ctx = WithLabels(ctx, Labels("c", "4", "a", "5", "a", "6"))
+	got := labelsSorted(ctx)
+	want := []label{{"a", "6"}, {"b", "3"}, {"c", "4"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("labels on context: got %v, want %v", got, want)
+	}
+	if v, ok := Label(ctx, "b"); !ok || v != "3" {
+		t.Errorf(`Label(ctx, "b") = %q, %v; want "3", true`, v, ok)
+	}
+	if v, ok := Label(ctx, "d"); ok {
+		t.Errorf(`Label(ctx, "d") = %q, %v; want "", false`, v, ok)
+	}
+}
@@ -701,6 +701,10 @@ func cpuinit(env string) {
 		x86HasPOPCNT = cpu.X86.HasPOPCNT
 		x86HasSSE41 = cpu.X86.HasSSE41
 		x86HasFMA = cpu.X86.HasFMA
+		x86HasAVX = cpu.X86.HasAVX
+		x86HasAVX2 = cpu.X86.HasAVX2
+		x86HasAVX512F = cpu.X86.HasAVX512F
+		x86HasAVX512BW = cpu.X86.HasAVX512BW
 
 	case "arm":
 		armHasVFPv4 = cpu.ARM.HasVFPv4
@@ -31,10 +31,70 @@ func runtime_setProfLabel(labels unsafe.Pointer) {
 	if raceenabled {
 		racereleasemerge(unsafe.Pointer(&labelSync))
 	}
-	getg().labels = labels
+	gp := getg()
+	gp.labels = labels
+
+	// Record the new labels in the execution trace. Goroutines that
+	// inherit labels from their creator have them recorded when they
+	// start running; see traceLocker.GoStart.
+	if labels != nil {
+		trace := traceAcquire()
+		if trace.ok() {
+			trace.GoroutineLabels(labels)
+			traceRelease(trace)
+		}
+	}
 }
 
 //go:linkname runtime_getProfLabel runtime/pprof.runtime_getProfLabel
 func runtime_getProfLabel() unsafe.Pointer {
 	return getg().labels
 }
+
+// profLabels mirrors the layout of runtime/pprof.labelMap, which is what
+// g.labels points to when it is set by package runtime/pprof. The labels
+// are sorted by key, and keys are unique.
+type profLabels struct {
+	list []profLabel
+}
+
+type profLabel struct {
+	key   string
+	value string
+}
+
+// printProfLabels prints l in the same format as runtime/pprof's
+// labelMap.String, except that keys and values are not escaped.
+func printProfLabels(l *profLabels) {
+	print("{")
+	for i, lbl := range l.list {
+		if i > 0 {
+			print(", ")
+		}
+		print("\"", lbl.key, "\":\"", lbl.value, "\"")
+	}
+	print("}")
+}
+
+// appendProfLabels is like printProfLabels, but appends to buf. It stops
+// early, without allocating, if buf runs out of capacity.
+func appendProfLabels(buf []byte, l *profLabels) []byte {
+	buf = appendTrunc(buf, "{")
+	for i, lbl := range l.list {
+		if i > 0 {
+			buf = appendTrunc(buf, ", ")
+		}
+		buf = appendTrunc(buf, "\"")
+		buf = appendTrunc(buf, lbl.key)
+		buf = appendTrunc(buf, "\":\"")
+		buf = appendTrunc(buf, lbl.value)
+		buf = appendTrunc(buf, "\"")
+	}
+	return appendTrunc(buf, "}")
+}
+
+// appendTrunc appends as much of s to buf as fits in its capacity.
+func appendTrunc(buf []byte, s string) []byte {
+	n := copy(buf[len(buf):cap(buf)], s)
+	return buf[:len(buf)+n]
+}
@@ -324,6 +324,7 @@ var debug struct {
 	scheddetail              int32
 	schedtrace               int32
 	tracebackancestors       int32
+	tracebacklabels          int32
 	asyncpreemptoff          int32
 	harddecommit             int32
 	adaptivestackstart       int32
@@ -380,6 +381,7 @@ var dbgvars = []*dbgVar{
 	{name: "traceadvanceperiod", value: &debug.traceadvanceperiod},
 	{name: "tracecheckstackownership", value: &debug.traceCheckStackOwnership},
 	{name: "tracebackancestors", value: &debug.tracebackancestors},
+	{name: "tracebacklabels", value: &debug.tracebacklabels},
 	{name: "tracefpunwindoff", value: &debug.tracefpunwindoff},
 }
 
@@ -1061,83 +1061,89 @@ type ancestorInfo struct {
 type waitReason uint8
 
 const (
-	waitReasonZero                  waitReason = iota // ""
-	waitReasonGCAssistMarking                         // "GC assist marking"
-	waitReasonIOWait                                  // "IO wait"
-	waitReasonChanReceiveNilChan                      // "chan receive (nil chan)"
-	waitReasonChanSendNilChan                         // "chan send (nil chan)"
-	waitReasonDumpingHeap                             // "dumping heap"
-	waitReasonGarbageCollection                       // "garbage collection"
-	waitReasonGarbageCollectionScan                   // "garbage collection scan"
-	waitReasonPanicWait                               // "panicwait"
-	waitReasonSelect                                  // "select"
-	waitReasonSelectNoCases                           // "select (no cases)"
-	waitReasonGCAssistWait                            // "GC assist wait"
-	waitReasonGCSweepWait                             // "GC sweep wait"
-	waitReasonGCScavengeWait                          // "GC scavenge wait"
-	waitReasonChanReceive                             // "chan receive"
-	waitReasonChanSend                                // "chan send"
-	waitReasonFinalizerWait                           // "finalizer wait"
-	waitReasonForceGCIdle                             // "force gc (idle)"
-	waitReasonSemacquire                              // "semacquire"
-	waitReasonSleep                                   // "sleep"
-	waitReasonSyncCondWait                            // "sync.Cond.Wait"
-	waitReasonSyncMutexLock                           // "sync.Mutex.Lock"
-	waitReasonSyncRWMutexRLock                        // "sync.RWMutex.RLock"
-	waitReasonSyncRWMutexLock                         // "sync.RWMutex.Lock"
-	waitReasonTraceReaderBlocked                      // "trace reader (blocked)"
-	waitReasonWaitForGCCycle                          // "wait for GC cycle"
-	waitReasonGCWorkerIdle                            // "GC worker (idle)"
-	waitReasonGCWorkerActive                          // "GC worker (active)"
-	waitReasonPreempted                               // "preempted"
-	waitReasonDebugCall                               // "debug call"
-	waitReasonGCMarkTermination                       // "GC mark termination"
-	waitReasonStoppingTheWorld                        // "stopping the world"
-	waitReasonFlushProcCaches                         // "flushing proc caches"
-	waitReasonTraceGoroutineStatus                    // "trace goroutine status"
-	waitReasonTraceProcStatus                         // "trace proc status"
-	waitReasonPageTraceFlush                          // "page trace flush"
-	waitReasonCoroutine                               // "coroutine"
+	waitReasonZero                    waitReason = iota // ""
+	waitReasonGCAssistMarking                           // "GC assist marking"
+	waitReasonIOWait                                    // "IO wait"
+	waitReasonChanReceiveNilChan                        // "chan receive (nil chan)"
+	waitReasonChanSendNilChan                           // "chan send (nil chan)"
+	waitReasonDumpingHeap                               // "dumping heap"
+	waitReasonGarbageCollection                         // "garbage collection"
+	waitReasonGarbageCollectionScan                     // "garbage collection scan"
+	waitReasonPanicWait                                 // "panicwait"
+	waitReasonSelect                                    // "select"
+	waitReasonSelectNoCases                             // "select (no cases)"
+	waitReasonGCAssistWait                              // "GC assist wait"
+	waitReasonGCSweepWait                               // "GC sweep wait"
+	waitReasonGCScavengeWait                            // "GC scavenge wait"
+	waitReasonChanReceive                               // "chan receive"
+	waitReasonChanSend                                  // "chan send"
+	waitReasonFinalizerWait                             // "finalizer wait"
+	waitReasonForceGCIdle                               // "force gc (idle)"
+	waitReasonSemacquire                                // "semacquire"
+	waitReasonSleep                                     // "sleep"
+	waitReasonSyncCondWait                              // "sync.Cond.Wait"
+	waitReasonSyncMutexLock                             // "sync.Mutex.Lock"
+	waitReasonSyncRWMutexRLock                          // "sync.RWMutex.RLock"
+	waitReasonSyncRWMutexLock                           // "sync.RWMutex.Lock"
+	waitReasonTraceReaderBlocked                        // "trace reader (blocked)"
+	waitReasonWaitForGCCycle                            // "wait for GC cycle"
+	waitReasonGCWorkerIdle                              // "GC worker (idle)"
+	waitReasonGCWorkerActive                            // "GC worker (active)"
+	waitReasonPreempted                                 // "preempted"
+	waitReasonDebugCall                                 // "debug call"
+	waitReasonGCMarkTermination                         // "GC mark termination"
+	waitReasonStoppingTheWorld                          // "stopping the world"
+	waitReasonFlushProcCaches                           // "flushing proc caches"
+	waitReasonTraceGoroutineStatus                      // "trace goroutine status"
+	waitReasonTraceProcStatus                           // "trace proc status"
+	waitReasonPageTraceFlush                            // "page trace flush"
+	waitReasonCoroutine                                 // "coroutine"
+	waitReasonCtxsyncCondWait                           // "sync/ctxsync.Cond.Wait"
+	waitReasonCtxsyncMutexLock                          // "sync/ctxsync.Mutex.Lock"
+	waitReasonCtxsyncSemaphoreAcquire                   // "sync/ctxsync.Semaphore.Acquire"
 )
 
 var waitReasonStrings = [...]string{
-	waitReasonZero:                  "",
-	waitReasonGCAssistMarking:       "GC assist marking",
-	waitReasonIOWait:                "IO wait",
-	waitReasonChanReceiveNilChan:    "chan receive (nil chan)",
-	waitReasonChanSendNilChan:       "chan send (nil chan)",
-	waitReasonDumpingHeap:           "dumping heap",
-	waitReasonGarbageCollection:     "garbage collection",
-	waitReasonGarbageCollectionScan: "garbage collection scan",
-	waitReasonPanicWait:             "panicwait",
-	waitReasonSelect:                "select",
-	waitReasonSelectNoCases:         "select (no cases)",
-	waitReasonGCAssistWait:          "GC assist wait",
-	waitReasonGCSweepWait:           "GC sweep wait",
-	waitReasonGCScavengeWait:        "GC scavenge wait",
-	waitReasonChanReceive:           "chan receive",
-	waitReasonChanSend:              "chan send",
-	waitReasonFinalizerWait:         "finalizer wait",
-	waitReasonForceGCIdle:           "force gc (idle)",
-	waitReasonSemacquire:            "semacquire",
-	waitReasonSleep:                 "sleep",
-	waitReasonSyncCondWait:          "sync.Cond.Wait",
-	waitReasonSyncMutexLock:         "sync.Mutex.Lock",
-	waitReasonSyncRWMutexRLock:      "sync.RWMutex.RLock",
-	waitReasonSyncRWMutexLock:       "sync.RWMutex.Lock",
-	waitReasonTraceReaderBlocked:    "trace reader (blocked)",
-	waitReasonWaitForGCCycle:        "wait for GC cycle",
-	waitReasonGCWorkerIdle:          "GC worker (idle)",
-	waitReasonGCWorkerActive:        "GC worker (active)",
-	waitReasonPreempted:             "preempted",
-	waitReasonDebugCall:             "debug call",
-	waitReasonGCMarkTermination:     "GC mark termination",
-	waitReasonStoppingTheWorld:      "stopping the world",
-	waitReasonFlushProcCaches:       "flushing proc caches",
-	waitReasonTraceGoroutineStatus:  "trace goroutine status",
-	waitReasonTraceProcStatus:       "trace proc status",
-	waitReasonPageTraceFlush:        "page trace flush",
-	waitReasonCoroutine:             "coroutine",
+	waitReasonZero:                    "",
+	waitReasonGCAssistMarking:         "GC assist marking",
+	waitReasonIOWait:                  "IO wait",
+	waitReasonChanReceiveNilChan:      "chan receive (nil chan)",
+	waitReasonChanSendNilChan:         "chan send (nil chan)",
+	waitReasonDumpingHeap:             "dumping heap",
+	waitReasonGarbageCollection:       "garbage collection",
+	waitReasonGarbageCollectionScan:   "garbage collection scan",
+	waitReasonPanicWait:               "panicwait",
+	waitReasonSelect:                  "select",
+	waitReasonSelectNoCases:           "select (no cases)",
+	waitReasonGCAssistWait:            "GC assist wait",
+	waitReasonGCSweepWait:             "GC sweep wait",
+	waitReasonGCScavengeWait:          "GC scavenge wait",
+	waitReasonChanReceive:             "chan receive",
+	waitReasonChanSend:                "chan send",
+	waitReasonFinalizerWait:           "finalizer wait",
+	waitReasonForceGCIdle:             "force gc (idle)",
+	waitReasonSemacquire:              "semacquire",
+	waitReasonSleep:                   "sleep",
+	waitReasonSyncCondWait:            "sync.Cond.Wait",
+	waitReasonSyncMutexLock:           "sync.Mutex.Lock",
+	waitReasonSyncRWMutexRLock:        "sync.RWMutex.RLock",
+	waitReasonSyncRWMutexLock:         "sync.RWMutex.Lock",
+	waitReasonTraceReaderBlocked:      "trace reader (blocked)",
+	waitReasonWaitForGCCycle:          "wait for GC cycle",
+	waitReasonGCWorkerIdle:            "GC worker (idle)",
+	waitReasonGCWorkerActive:          "GC worker (active)",
+	waitReasonPreempted:               "preempted",
+	waitReasonDebugCall:               "debug call",
+	waitReasonGCMarkTermination:       "GC mark termination",
+	waitReasonStoppingTheWorld:        "stopping the world",
+	waitReasonFlushProcCaches:         "flushing proc caches",
+	waitReasonTraceGoroutineStatus:    "trace goroutine status",
+	waitReasonTraceProcStatus:         "trace proc status",
+	waitReasonPageTraceFlush:          "page trace flush",
+	waitReasonCoroutine:               "coroutine",
+	waitReasonCtxsyncCondWait:         "sync/ctxsync.Cond.Wait",
+	waitReasonCtxsyncMutexLock:        "sync/ctxsync.Mutex.Lock",
+	waitReasonCtxsyncSemaphoreAcquire: "sync/ctxsync.Semaphore.Acquire",
 }
 
 func (w waitReason) String() string {
@@ -1150,7 +1156,8 @@ func (w waitReason) String() string {
 func (w waitReason) isMutexWait() bool {
 	return w == waitReasonSyncMutexLock ||
 		w == waitReasonSyncRWMutexRLock ||
-		w == waitReasonSyncRWMutexLock
+		w == waitReasonSyncRWMutexLock ||
+		w == waitReasonCtxsyncMutexLock
 }
 
 func (w waitReason) isWaitingForGC() bool {
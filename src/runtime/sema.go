@@ -87,6 +87,26 @@ func sync_runtime_SemacquireRWMutex(addr *uint32, lifo bool, skipframes int) {
 	semacquire1(addr, lifo, semaBlockProfile|semaMutexProfile, skipframes, waitReasonSyncRWMutexLock)
 }
 
+//go:linkname ctxsync_runtime_SemacquireMutex sync/ctxsync.runtime_SemacquireMutex
+func ctxsync_runtime_SemacquireMutex(addr *uint32, skipframes int) {
+	semacquire1(addr, false, semaBlockProfile|semaMutexProfile, skipframes, waitReasonCtxsyncMutexLock)
+}
+
+//go:linkname ctxsync_runtime_SemacquireSemaphore sync/ctxsync.runtime_SemacquireSemaphore
+func ctxsync_runtime_SemacquireSemaphore(addr *uint32, skipframes int) {
+	semacquire1(addr, false, semaBlockProfile, skipframes, waitReasonCtxsyncSemaphoreAcquire)
+}
+
+//go:linkname ctxsync_runtime_SemacquireCond sync/ctxsync.runtime_SemacquireCond
+func ctxsync_runtime_SemacquireCond(addr *uint32, skipframes int) {
+	semacquire1(addr, false, semaBlockProfile, skipframes, waitReasonCtxsyncCondWait)
+}
+
+//go:linkname ctxsync_runtime_Semrelease sync/ctxsync.runtime_Semrelease
+func ctxsync_runtime_Semrelease(addr *uint32, handoff bool, skipframes int) {
+	semrelease1(addr, handoff, skipframes)
+}
+
 //go:linkname poll_runtime_Semrelease internal/poll.runtime_Semrelease
 func poll_runtime_Semrelease(addr *uint32) {
 	semrelease(addr)
@@ -863,6 +863,20 @@ func TestTracebackAncestors(t *testing.T) {
 	}
 }
 
+func TestTracebackLabels(t *testing.T) {
+	const labels = `{"id":"7", "worker":"fetch"}`
+	for _, setting := range []string{"0", "1"} {
+		output := runTestProg(t, "testprog", "TracebackLabels", "GODEBUG=tracebacklabels="+setting)
+		want := "[chan receive] " + labels + ":\n"
+		if got := strings.Contains(output, want); got != (setting == "1") {
+			t.Errorf("with tracebacklabels=%s, output contains %q = %v:\n%s", setting, want, got, output)
+		}
+		if setting == "1" && strings.Count(output, labels) != 1 {
+			t.Errorf("with tracebacklabels=%s, want only the labeled goroutine to print labels:\n%s", setting, output)
+		}
+	}
+}
+
 // Test that defer closure is correctly scanned when the stack is scanned.
 func TestDeferLiveness(t *testing.T) {
 	output := runTestProg(t, "testprog", "DeferLiveness", "GODEBUG=clobberfree=1")
@@ -0,0 +1,39 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+)
+
+func init() {
+	register("TracebackLabels", TracebackLabels)
+}
+
+// TracebackLabels prints the stacks of all goroutines while a goroutine
+// started with profiler labels, and one that inherited them, are blocked.
+func TracebackLabels() {
+	var wg sync.WaitGroup
+	block := make(chan struct{})
+	started := make(chan struct{})
+	pprof.Do(context.Background(), pprof.Labels("worker", "fetch", "id", "7"), func(context.Context) {
+		wg.Go(func() {
+			started <- struct{}{}
+			<-block
+		})
+	})
+	<-started
+
+	buf := make([]byte, 1<<16)
+	n := runtime.Stack(buf, true)
+	fmt.Print(string(buf[:n]))
+
+	close(block)
+	wg.Wait()
+}
@@ -1667,6 +1667,10 @@ func (_ traceLocker) GoStart() {
 	}
 }
 
+// GoroutineLabels is a no-op: this trace format has no event for
+// attaching profiler labels to a goroutine.
+func (_ traceLocker) GoroutineLabels(labels unsafe.Pointer) {}
+
 func (_ traceLocker) GoEnd() {
 	traceEvent(traceEvGoEnd, -1)
 }
@@ -10,7 +10,7 @@ package runtime
 
 import (
 	"internal/runtime/atomic"
-	_ "unsafe" // for go:linkname
+	"unsafe"
 )
 
 // gTraceState is per-G state for the tracer.
@@ -404,14 +404,38 @@ func (tl traceLocker) GoCreate(newg *g, pc uintptr, blocked bool) {
 func (tl traceLocker) GoStart() {
 	gp := getg().m.curg
 	pp := gp.m.p
+	var label traceArg
+	if pp.ptr().gcMarkWorkerMode != gcMarkWorkerNotWorker {
+		label = trace.markWorkerLabels[tl.gen%2][pp.ptr().gcMarkWorkerMode]
+	} else if gp.labels != nil {
+		label = tl.profLabels(gp.labels)
+	}
 	w := tl.eventWriter(traceGoRunnable, traceProcRunning)
 	w = w.write(traceEvGoStart, traceArg(gp.goid), gp.trace.nextSeq(tl.gen))
-	if pp.ptr().gcMarkWorkerMode != gcMarkWorkerNotWorker {
-		w = w.write(traceEvGoLabel, trace.markWorkerLabels[tl.gen%2][pp.ptr().gcMarkWorkerMode])
+	if label != 0 {
+		w = w.write(traceEvGoLabel, label)
 	}
 	w.end()
 }
 
+// GoroutineLabels emits a GoLabel event for the current goroutine, recording
+// the profiler labels it was just given by runtime/pprof.
+func (tl traceLocker) GoroutineLabels(labels unsafe.Pointer) {
+	id := tl.profLabels(labels)
+	tl.eventWriter(traceGoRunning, traceProcRunning).commit(traceEvGoLabel, id)
+}
+
+// profLabels returns a traceArg for the string form of the profiler labels
+// pointed to by labels, which must be a *profLabels.
+func (tl traceLocker) profLabels(labels unsafe.Pointer) traceArg {
+	// The string table copies the string before put returns,
+	// so the buffer can stay on the stack.
+	var buf [256]byte
+	p := (*[256]byte)(noescape(unsafe.Pointer(&buf)))
+	b := appendProfLabels(p[:0], (*profLabels)(labels))
+	return tl.string(slicebytetostringtmp(&p[0], len(b)))
+}
+
 // GoEnd emits a GoDestroy event.
 //
 // TODO(mknyszek): Rename this to GoDestroy.
@@ -1228,7 +1228,12 @@ func goroutineheader(gp *g) {
 	if gp.lockedm != 0 {
 		print(", locked to thread")
 	}
-	print("]:\n")
+	print("]")
+	if debug.tracebacklabels != 0 && gp.labels != nil {
+		print(" ")
+		printProfLabels((*profLabels)(gp.labels))
+	}
+	print(":\n")
 }
 
 func tracebackothers(me *g) {
@@ -0,0 +1,219 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// This program is run via "go generate" (via a directive in simd.go)
+// to generate the vector types and their methods in zvectors.go, and
+// their tests in zvectors_test.go.
+//
+// The method names must match the operations that
+// cmd/compile/internal/ssagen registers as intrinsics.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+type elem struct {
+	name  string // element type
+	title string // capitalized element type, as in the vector type names
+	kind  byte   // 'u' for unsigned, 'i' for signed, 'f' for floating-point
+	bits  int
+}
+
+var elems = []elem{
+	{"uint8", "Uint8", 'u', 8},
+	{"int32", "Int32", 'i', 32},
+	{"float32", "Float32", 'f', 32},
+	{"float64", "Float64", 'f', 64},
+}
+
+type method struct {
+	name  string
+	kinds string // element kinds that have the method
+	form  string // "binary", "unary", "fma", "mask" or "broadcast"
+	doc   string
+	// expr returns the expression computing element i of the
+	// result for elements of type e.
+	expr func(e elem) string
+}
+
+func format1(s string) func(elem) string {
+	return func(elem) string { return s }
+}
+
+var methods = []method{
+	{"Broadcast", "uif", "broadcast", "sets every element of z to e and returns z.", format1("e")},
+	{"Add", "uif", "binary", "sets z[i] = x[i] + y[i] for each i and returns z.", format1("x[i] + y[i]")},
+	{"Sub", "uif", "binary", "sets z[i] = x[i] - y[i] for each i and returns z.", format1("x[i] - y[i]")},
+	{"Mul", "if", "binary", "sets z[i] = x[i] * y[i] for each i and returns z.", format1("x[i] * y[i]")},
+	{"Div", "f", "binary", "sets z[i] = x[i] / y[i] for each i and returns z.", format1("x[i] / y[i]")},
+	{"Min", "uif", "binary", "sets z[i] = min(x[i], y[i]) for each i and returns z.", format1("min(x[i], y[i])")},
+	{"Max", "uif", "binary", "sets z[i] = max(x[i], y[i]) for each i and returns z.", format1("max(x[i], y[i])")},
+	{"And", "ui", "binary", "sets z[i] = x[i] & y[i] for each i and returns z.", format1("x[i] & y[i]")},
+	{"Or", "ui", "binary", "sets z[i] = x[i] | y[i] for each i and returns z.", format1("x[i] | y[i]")},
+	{"Xor", "ui", "binary", "sets z[i] = x[i] ^ y[i] for each i and returns z.", format1("x[i] ^ y[i]")},
+	{"AndNot", "ui", "binary", "sets z[i] = x[i] &^ y[i] for each i and returns z.", format1("x[i] &^ y[i]")},
+	{"Equal", "ui", "binary", "sets z[i] to all ones if x[i] == y[i] and to zero otherwise,\n// for each i, and returns z.",
+		func(e elem) string { return fmt.Sprintf("mask%d(x[i] == y[i])", e.bits) }},
+	{"Greater", "i", "binary", "sets z[i] to all ones if x[i] > y[i] and to zero otherwise,\n// for each i, and returns z.",
+		func(e elem) string { return fmt.Sprintf("mask%d(x[i] > y[i])", e.bits) }},
+	{"MulAdd", "f", "fma", "sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one\n// rounding, and returns z.",
+		func(e elem) string {
+			if e.bits == 32 {
+				return "fma32(x[i], y[i], z[i])"
+			}
+			return "math.FMA(x[i], y[i], z[i])"
+		}},
+	{"Sqrt", "f", "unary", "sets z[i] to the square root of x[i] for each i and returns z.",
+		func(e elem) string {
+			if e.bits == 32 {
+				return "float32(math.Sqrt(float64(x[i])))"
+			}
+			return "math.Sqrt(x[i])"
+		}},
+	{"Mask", "u", "mask", "returns a bit mask in which bit i is the high bit of x[i].\n// Applied to the result of Equal, it reports which elements are equal.", nil},
+}
+
+const header = `// Code generated by gen.go; DO NOT EDIT.
+
+//go:build goexperiment.simd
+
+`
+
+func main() {
+	var src, test bytes.Buffer
+	fmt.Fprintf(&src, "%spackage simd\n\nimport \"math\"\n", header)
+	fmt.Fprintf(&test, "%spackage simd_test\n\nimport (\n\t\"math\"\n\t\"simd\"\n\t\"testing\"\n)\n", header)
+	for _, e := range elems {
+		for _, width := range []int{128, 256, 512} {
+			genType(&src, &test, e, width/e.bits)
+		}
+	}
+	write("zvectors.go", src.Bytes())
+	write("zvectors_test.go", test.Bytes())
+}
+
+func write(file string, b []byte) {
+	b, err := format.Source(b)
+	if err != nil {
+		log.Fatalf("formatting %s: %v", file, err)
+	}
+	if err := os.WriteFile(file, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func genType(src, test *bytes.Buffer, e elem, n int) {
+	t := fmt.Sprintf("%sx%d", e.title, n)
+	fmt.Fprintf(src, "\n// %s is a vector of %d %s values.\n", t, n, e.name)
+	fmt.Fprintf(src, "type %s [%d]%s\n", t, n, e.name)
+	for _, m := range methods {
+		if !strings.ContainsRune(m.kinds, rune(e.kind)) {
+			continue
+		}
+		if m.name == "Min" || m.name == "Max" {
+			if e.kind == 'f' {
+				fmt.Fprintf(src, "\n// %s %s\n// If either element is a NaN, or both are zeros, the result\n// depends on the platform.\n", m.name, m.doc)
+			} else {
+				fmt.Fprintf(src, "\n// %s %s\n", m.name, m.doc)
+			}
+		} else {
+			fmt.Fprintf(src, "\n// %s %s\n", m.name, m.doc)
+		}
+		switch m.form {
+		case "broadcast":
+			fmt.Fprintf(src, "func (z *%s) %s(e %s) *%s {\n", t, m.name, e.name, t)
+			fmt.Fprintf(src, "\tfor i := range z {\n\t\tz[i] = e\n\t}\n\treturn z\n}\n")
+		case "unary":
+			fmt.Fprintf(src, "func (z *%s) %s(x *%s) *%s {\n", t, m.name, t, t)
+			fmt.Fprintf(src, "\tfor i := range z {\n\t\tz[i] = %s\n\t}\n\treturn z\n}\n", m.expr(e))
+		case "binary", "fma":
+			fmt.Fprintf(src, "func (z *%s) %s(x, y *%s) *%s {\n", t, m.name, t, t)
+			fmt.Fprintf(src, "\tfor i := range z {\n\t\tz[i] = %s\n\t}\n\treturn z\n}\n", m.expr(e))
+		case "mask":
+			fmt.Fprintf(src, "func (x *%s) %s() uint%d {\n", t, m.name, n)
+			fmt.Fprintf(src, "\tvar m uint%d\n\tfor i, e := range x {\n\t\tm |= uint%d(e>>7) << i\n\t}\n\treturn m\n}\n", n, n)
+		}
+	}
+	genTest(test, e, t, n)
+}
+
+// genTest generates a test of the methods of vector type t. The test
+// compares each method with a scalar computation of its result, on
+// both separate and aliased operands. The inputs come from the
+// helpers in simd_test.go, and are chosen so that floating-point
+// results are exact and comparable.
+func genTest(test *bytes.Buffer, e elem, t string, n int) {
+	fmt.Fprintf(test, "\nfunc Test%s(t *testing.T) {\n", t)
+	fmt.Fprintf(test, "\tfor iter := 0; iter < iterations; iter++ {\n")
+	fmt.Fprintf(test, "\t\tvar x, y, z, want simd.%s\n", t)
+	fmt.Fprintf(test, "\t\tfill%s(x[:])\n\t\tfill%s(y[:])\n\t\tfill%s(z[:])\n", e.title, e.title, e.title)
+	for _, m := range methods {
+		if !strings.ContainsRune(m.kinds, rune(e.kind)) {
+			continue
+		}
+		switch m.form {
+		case "broadcast":
+			fmt.Fprintf(test, "\t\tfor i := range want {\n\t\t\twant[i] = y[0]\n\t\t}\n")
+			fmt.Fprintf(test, "\t\tcheck%s(t, %q, new(simd.%s).%s(y[0])[:], want[:])\n", e.title, m.name, t, m.name)
+		case "unary":
+			fmt.Fprintf(test, "\t\tfor i := range want {\n\t\t\twant[i] = %s\n\t\t}\n", testExpr(m, e))
+			fmt.Fprintf(test, "\t\tcheck%s(t, %q, new(simd.%s).%s(&x)[:], want[:])\n", e.title, m.name, t, m.name)
+			fmt.Fprintf(test, "\t\tif a := x; a.%s(&a) != &a || a != want {\n", m.name)
+			fmt.Fprintf(test, "\t\t\tt.Errorf(\"%s aliased: got %%v, want %%v\", a, want)\n\t\t}\n", m.name)
+		case "binary":
+			fmt.Fprintf(test, "\t\tfor i := range want {\n\t\t\twant[i] = %s\n\t\t}\n", testExpr(m, e))
+			fmt.Fprintf(test, "\t\tcheck%s(t, %q, new(simd.%s).%s(&x, &y)[:], want[:])\n", e.title, m.name, t, m.name)
+			fmt.Fprintf(test, "\t\tif a := x; a.%s(&a, &y) != &a || a != want {\n", m.name)
+			fmt.Fprintf(test, "\t\t\tt.Errorf(\"%s aliased: got %%v, want %%v\", a, want)\n\t\t}\n", m.name)
+		case "fma":
+			fmt.Fprintf(test, "\t\tfor i := range want {\n\t\t\twant[i] = %s\n\t\t}\n", testExpr(m, e))
+			fmt.Fprintf(test, "\t\tif a := z; a.%s(&x, &y) != &a || a != want {\n", m.name)
+			fmt.Fprintf(test, "\t\t\tt.Errorf(\"%s: got %%v, want %%v\", a, want)\n\t\t}\n", m.name)
+		case "mask":
+			fmt.Fprintf(test, "\t\tvar wantMask uint%d\n", n)
+			fmt.Fprintf(test, "\t\tfor i := range x {\n\t\t\tif x[i] >= 0x80 {\n\t\t\t\twantMask |= 1 << i\n\t\t\t}\n\t\t}\n")
+			fmt.Fprintf(test, "\t\tif got := x.Mask(); got != wantMask {\n")
+			fmt.Fprintf(test, "\t\t\tt.Errorf(\"Mask(%%v) = %%#x, want %%#x\", x, got, wantMask)\n\t\t}\n")
+		}
+	}
+	fmt.Fprintf(test, "\t}\n}\n")
+}
+
+// testExpr returns the scalar expression computing element i of the
+// result of method m in the tests. It is written independently of the
+// generated implementation where that is practical.
+func testExpr(m method, e elem) string {
+	all := "uint8(0xFF)"
+	if e.kind == 'i' {
+		all = "int32(-1)"
+	}
+	switch m.name {
+	case "Min":
+		return "min(x[i], y[i])"
+	case "Max":
+		return "max(x[i], y[i])"
+	case "Equal":
+		return fmt.Sprintf("choose(x[i] == y[i], %s, 0)", all)
+	case "Greater":
+		return fmt.Sprintf("choose(x[i] > y[i], %s, 0)", all)
+	case "MulAdd":
+		// The test inputs are small enough that x*y + z is exact.
+		return "x[i]*y[i] + z[i]"
+	case "Sqrt":
+		if e.bits == 32 {
+			return "float32(math.Sqrt(float64(x[i])))"
+		}
+		return "math.Sqrt(x[i])"
+	}
+	return m.expr(e)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+/*
+Package simd provides fixed-size vector types and element-wise
+operations on them that the compiler translates directly into vector
+instructions.
+
+This package is experimental. It is only available when building with
+GOEXPERIMENT=simd, and its API may change or be removed.
+
+Each vector type is an array of 128, 256 or 512 bits of uint8, int32,
+float32 or float64 elements, such as [Float32x8], which is a [8]float32.
+Being arrays, vectors can be declared, indexed and copied like any other
+array, and a slice can be viewed as a vector without copying by
+converting it to an array pointer:
+
+	for len(a) >= 8 {
+		v := (*simd.Float32x8)(a)
+		v.Mul(v, &scale)
+		a = a[8:]
+	}
+
+Operations are methods on pointers, in the style of math/big: the
+receiver is set to the result, which is also returned so calls can be
+chained. Results may alias the operands, which are not modified.
+
+On amd64, the methods are compiled to AVX (128-bit vectors), AVX2
+(256-bit vectors) and AVX-512 (512-bit vectors) instructions, guarded
+by a run-time check for the instruction set extension unless the
+GOAMD64 level guarantees it. On arm64, they are compiled to Advanced
+SIMD instructions, processing wider vectors 128 bits at a time.
+Elsewhere, and when the CPU lacks the required extension, the methods
+run portable Go code with the same results, except as noted for the
+floating-point [Float32x4.Min] and [Float32x4.Max] operations.
+Builds using the race detector or sanitizers always use the portable
+code.
+
+The methods are only translated into vector instructions when they are
+called directly. Calls through method values or interfaces run the
+portable code.
+*/
+package simd
+
+import "math"
+
+//go:generate go run gen.go
+
+// mask8 returns the uint8 with all bits set to b.
+func mask8(b bool) uint8 {
+	if b {
+		return 0xFF
+	}
+	return 0
+}
+
+// mask32 returns the int32 with all bits set to b.
+func mask32(b bool) int32 {
+	if b {
+		return -1
+	}
+	return 0
+}
+
+// fma32 returns x*y + z computed with only one rounding.
+func fma32(x, y, z float32) float32 {
+	// The product is exact in float64. Round the sum to odd, so that
+	// rounding it to float32 gives the correctly rounded result.
+	p := float64(x) * float64(y)
+	s := p + float64(z)
+	if math.IsInf(s, 0) || math.IsNaN(s) {
+		return float32(s)
+	}
+	// err is the rounding error of s.
+	bp := s - float64(z)
+	err := (p - bp) + (float64(z) - (s - bp))
+	if err != 0 && math.Float64bits(s)&1 == 0 {
+		s = math.Nextafter(s, math.Copysign(math.Inf(1), err))
+	}
+	return float32(s)
+}
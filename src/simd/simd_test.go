@@ -0,0 +1,138 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+package simd_test
+
+import (
+	"internal/testenv"
+	"math"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"simd"
+	"strings"
+	"testing"
+)
+
+const iterations = 100
+
+// fillUint8 fills s with bytes from a small alphabet, so that
+// comparisons find equal elements.
+func fillUint8(s []uint8) {
+	for i := range s {
+		s[i] = "\x00\x01\x7f\x80\xfe\xff\"\\"[rand.IntN(8)]
+	}
+}
+
+func fillInt32(s []int32) {
+	for i := range s {
+		switch rand.IntN(4) {
+		case 0:
+			s[i] = int32(rand.IntN(5) - 2)
+		case 1:
+			s[i] = math.MaxInt32 - int32(rand.IntN(2))
+		case 2:
+			s[i] = math.MinInt32 + int32(rand.IntN(2))
+		default:
+			s[i] = int32(rand.Uint32())
+		}
+	}
+}
+
+// fillFloat32 and fillFloat64 fill s with positive multiples of 1/8
+// that are small enough for sums, differences and products to be
+// exact, and whose square roots are never NaN.
+func fillFloat32(s []float32) {
+	for i := range s {
+		s[i] = float32(rand.IntN(1000)+1) / 8
+	}
+}
+
+func fillFloat64(s []float64) {
+	for i := range s {
+		s[i] = float64(rand.IntN(1000)+1) / 8
+	}
+}
+
+func choose[T any](c bool, a, b T) T {
+	if c {
+		return a
+	}
+	return b
+}
+
+func check[T comparable](t *testing.T, name string, got, want []T) {
+	t.Helper()
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func checkUint8(t *testing.T, name string, got, want []uint8) {
+	t.Helper()
+	check(t, name, got, want)
+}
+
+func checkInt32(t *testing.T, name string, got, want []int32) {
+	t.Helper()
+	check(t, name, got, want)
+}
+
+func checkFloat32(t *testing.T, name string, got, want []float32) {
+	t.Helper()
+	check(t, name, got, want)
+}
+
+func checkFloat64(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	check(t, name, got, want)
+}
+
+// TestPortable reruns the tests with the vector extensions disabled,
+// so that the methods run their portable code.
+func TestPortable(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("vector extensions are only checked at run time on amd64")
+	}
+	if os.Getenv("GO_SIMD_PORTABLE") != "" {
+		t.Skip("already running without vector extensions")
+	}
+	testenv.MustHaveExec(t)
+	cmd := testenv.Command(t, os.Args[0], "-test.run=^Test[A-Z][a-z]+[0-9]+x[0-9]+$")
+	cmd.Env = append(os.Environ(), "GO_SIMD_PORTABLE=1", "GODEBUG=cpu.avx=off,cpu.avx2=off,cpu.avx512f=off,cpu.fma=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(out), "PASS") {
+		t.Fatalf("%v: %v\n%s", cmd, err, out)
+	}
+}
+
+func TestFMA32(t *testing.T) {
+	tests := []struct {
+		x, y, z, want float32
+	}{
+		{1, 2, 3, 5},
+		{0.1, 10, -1, 1.4901161e-08},
+		// 1+2^-12 squared is 1+2^-11+2^-24, which rounds to 1+2^-11
+		// in float32 unless -1 is added first.
+		{1 + 0x1p-12, 1 + 0x1p-12, -1, 0x1p-11 + 0x1p-24},
+		// Rounding x*y+z to float64 first would give a tie, which
+		// rounds to even; rounding once rounds up.
+		{1 + 0x1p-23, 1 + 0x1p-23, 0x1p-60, 1 + 0x1p-22},
+		{float32(math.Inf(1)), 1, 1, float32(math.Inf(1))},
+	}
+	for _, tt := range tests {
+		var x, y, z simd.Float32x4
+		x.Broadcast(tt.x)
+		y.Broadcast(tt.y)
+		z.Broadcast(tt.z)
+		if z.MulAdd(&x, &y); z[0] != tt.want || z[3] != tt.want {
+			t.Errorf("MulAdd(%v, %v, %v) = %v, want %v", tt.x, tt.y, tt.z, z, tt.want)
+		}
+	}
+}
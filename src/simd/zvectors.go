@@ -0,0 +1,1072 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+//go:build goexperiment.simd
+
+package simd
+
+import "math"
+
+// Uint8x16 is a vector of 16 uint8 values.
+type Uint8x16 [16]uint8
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Uint8x16) Broadcast(e uint8) *Uint8x16 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Uint8x16) Add(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Uint8x16) Sub(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Uint8x16) Min(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Uint8x16) Max(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Uint8x16) And(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Uint8x16) Or(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Uint8x16) Xor(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Uint8x16) AndNot(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Uint8x16) Equal(x, y *Uint8x16) *Uint8x16 {
+	for i := range z {
+		z[i] = mask8(x[i] == y[i])
+	}
+	return z
+}
+
+// Mask returns a bit mask in which bit i is the high bit of x[i].
+// Applied to the result of Equal, it reports which elements are equal.
+func (x *Uint8x16) Mask() uint16 {
+	var m uint16
+	for i, e := range x {
+		m |= uint16(e>>7) << i
+	}
+	return m
+}
+
+// Uint8x32 is a vector of 32 uint8 values.
+type Uint8x32 [32]uint8
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Uint8x32) Broadcast(e uint8) *Uint8x32 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Uint8x32) Add(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Uint8x32) Sub(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Uint8x32) Min(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Uint8x32) Max(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Uint8x32) And(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Uint8x32) Or(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Uint8x32) Xor(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Uint8x32) AndNot(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Uint8x32) Equal(x, y *Uint8x32) *Uint8x32 {
+	for i := range z {
+		z[i] = mask8(x[i] == y[i])
+	}
+	return z
+}
+
+// Mask returns a bit mask in which bit i is the high bit of x[i].
+// Applied to the result of Equal, it reports which elements are equal.
+func (x *Uint8x32) Mask() uint32 {
+	var m uint32
+	for i, e := range x {
+		m |= uint32(e>>7) << i
+	}
+	return m
+}
+
+// Uint8x64 is a vector of 64 uint8 values.
+type Uint8x64 [64]uint8
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Uint8x64) Broadcast(e uint8) *Uint8x64 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Uint8x64) Add(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Uint8x64) Sub(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Uint8x64) Min(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Uint8x64) Max(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Uint8x64) And(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Uint8x64) Or(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Uint8x64) Xor(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Uint8x64) AndNot(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Uint8x64) Equal(x, y *Uint8x64) *Uint8x64 {
+	for i := range z {
+		z[i] = mask8(x[i] == y[i])
+	}
+	return z
+}
+
+// Mask returns a bit mask in which bit i is the high bit of x[i].
+// Applied to the result of Equal, it reports which elements are equal.
+func (x *Uint8x64) Mask() uint64 {
+	var m uint64
+	for i, e := range x {
+		m |= uint64(e>>7) << i
+	}
+	return m
+}
+
+// Int32x4 is a vector of 4 int32 values.
+type Int32x4 [4]int32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Int32x4) Broadcast(e int32) *Int32x4 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Int32x4) Add(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Int32x4) Sub(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Int32x4) Mul(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Int32x4) Min(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Int32x4) Max(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Int32x4) And(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Int32x4) Or(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Int32x4) Xor(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Int32x4) AndNot(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x4) Equal(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = mask32(x[i] == y[i])
+	}
+	return z
+}
+
+// Greater sets z[i] to all ones if x[i] > y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x4) Greater(x, y *Int32x4) *Int32x4 {
+	for i := range z {
+		z[i] = mask32(x[i] > y[i])
+	}
+	return z
+}
+
+// Int32x8 is a vector of 8 int32 values.
+type Int32x8 [8]int32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Int32x8) Broadcast(e int32) *Int32x8 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Int32x8) Add(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Int32x8) Sub(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Int32x8) Mul(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Int32x8) Min(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Int32x8) Max(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Int32x8) And(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Int32x8) Or(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Int32x8) Xor(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Int32x8) AndNot(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x8) Equal(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = mask32(x[i] == y[i])
+	}
+	return z
+}
+
+// Greater sets z[i] to all ones if x[i] > y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x8) Greater(x, y *Int32x8) *Int32x8 {
+	for i := range z {
+		z[i] = mask32(x[i] > y[i])
+	}
+	return z
+}
+
+// Int32x16 is a vector of 16 int32 values.
+type Int32x16 [16]int32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Int32x16) Broadcast(e int32) *Int32x16 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Int32x16) Add(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Int32x16) Sub(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Int32x16) Mul(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+func (z *Int32x16) Min(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+func (z *Int32x16) Max(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// And sets z[i] = x[i] & y[i] for each i and returns z.
+func (z *Int32x16) And(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z[i] = x[i] | y[i] for each i and returns z.
+func (z *Int32x16) Or(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z[i] = x[i] ^ y[i] for each i and returns z.
+func (z *Int32x16) Xor(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// AndNot sets z[i] = x[i] &^ y[i] for each i and returns z.
+func (z *Int32x16) AndNot(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = x[i] &^ y[i]
+	}
+	return z
+}
+
+// Equal sets z[i] to all ones if x[i] == y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x16) Equal(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = mask32(x[i] == y[i])
+	}
+	return z
+}
+
+// Greater sets z[i] to all ones if x[i] > y[i] and to zero otherwise,
+// for each i, and returns z.
+func (z *Int32x16) Greater(x, y *Int32x16) *Int32x16 {
+	for i := range z {
+		z[i] = mask32(x[i] > y[i])
+	}
+	return z
+}
+
+// Float32x4 is a vector of 4 float32 values.
+type Float32x4 [4]float32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float32x4) Broadcast(e float32) *Float32x4 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float32x4) Add(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float32x4) Sub(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float32x4) Mul(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float32x4) Div(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x4) Min(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x4) Max(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float32x4) MulAdd(x, y *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = fma32(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float32x4) Sqrt(x *Float32x4) *Float32x4 {
+	for i := range z {
+		z[i] = float32(math.Sqrt(float64(x[i])))
+	}
+	return z
+}
+
+// Float32x8 is a vector of 8 float32 values.
+type Float32x8 [8]float32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float32x8) Broadcast(e float32) *Float32x8 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float32x8) Add(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float32x8) Sub(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float32x8) Mul(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float32x8) Div(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x8) Min(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x8) Max(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float32x8) MulAdd(x, y *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = fma32(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float32x8) Sqrt(x *Float32x8) *Float32x8 {
+	for i := range z {
+		z[i] = float32(math.Sqrt(float64(x[i])))
+	}
+	return z
+}
+
+// Float32x16 is a vector of 16 float32 values.
+type Float32x16 [16]float32
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float32x16) Broadcast(e float32) *Float32x16 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float32x16) Add(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float32x16) Sub(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float32x16) Mul(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float32x16) Div(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x16) Min(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float32x16) Max(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float32x16) MulAdd(x, y *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = fma32(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float32x16) Sqrt(x *Float32x16) *Float32x16 {
+	for i := range z {
+		z[i] = float32(math.Sqrt(float64(x[i])))
+	}
+	return z
+}
+
+// Float64x2 is a vector of 2 float64 values.
+type Float64x2 [2]float64
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float64x2) Broadcast(e float64) *Float64x2 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float64x2) Add(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float64x2) Sub(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float64x2) Mul(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float64x2) Div(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x2) Min(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x2) Max(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float64x2) MulAdd(x, y *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = math.FMA(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float64x2) Sqrt(x *Float64x2) *Float64x2 {
+	for i := range z {
+		z[i] = math.Sqrt(x[i])
+	}
+	return z
+}
+
+// Float64x4 is a vector of 4 float64 values.
+type Float64x4 [4]float64
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float64x4) Broadcast(e float64) *Float64x4 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float64x4) Add(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float64x4) Sub(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float64x4) Mul(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float64x4) Div(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x4) Min(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x4) Max(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float64x4) MulAdd(x, y *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = math.FMA(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float64x4) Sqrt(x *Float64x4) *Float64x4 {
+	for i := range z {
+		z[i] = math.Sqrt(x[i])
+	}
+	return z
+}
+
+// Float64x8 is a vector of 8 float64 values.
+type Float64x8 [8]float64
+
+// Broadcast sets every element of z to e and returns z.
+func (z *Float64x8) Broadcast(e float64) *Float64x8 {
+	for i := range z {
+		z[i] = e
+	}
+	return z
+}
+
+// Add sets z[i] = x[i] + y[i] for each i and returns z.
+func (z *Float64x8) Add(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = x[i] + y[i]
+	}
+	return z
+}
+
+// Sub sets z[i] = x[i] - y[i] for each i and returns z.
+func (z *Float64x8) Sub(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = x[i] - y[i]
+	}
+	return z
+}
+
+// Mul sets z[i] = x[i] * y[i] for each i and returns z.
+func (z *Float64x8) Mul(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = x[i] * y[i]
+	}
+	return z
+}
+
+// Div sets z[i] = x[i] / y[i] for each i and returns z.
+func (z *Float64x8) Div(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = x[i] / y[i]
+	}
+	return z
+}
+
+// Min sets z[i] = min(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x8) Min(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = min(x[i], y[i])
+	}
+	return z
+}
+
+// Max sets z[i] = max(x[i], y[i]) for each i and returns z.
+// If either element is a NaN, or both are zeros, the result
+// depends on the platform.
+func (z *Float64x8) Max(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = max(x[i], y[i])
+	}
+	return z
+}
+
+// MulAdd sets z[i] = x[i]*y[i] + z[i] for each i, computed with only one
+// rounding, and returns z.
+func (z *Float64x8) MulAdd(x, y *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = math.FMA(x[i], y[i], z[i])
+	}
+	return z
+}
+
+// Sqrt sets z[i] to the square root of x[i] for each i and returns z.
+func (z *Float64x8) Sqrt(x *Float64x8) *Float64x8 {
+	for i := range z {
+		z[i] = math.Sqrt(x[i])
+	}
+	return z
+}
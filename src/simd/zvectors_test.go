@@ -0,0 +1,944 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+//go:build goexperiment.simd
+
+package simd_test
+
+import (
+	"math"
+	"simd"
+	"testing"
+)
+
+func TestUint8x16(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Uint8x16
+		fillUint8(x[:])
+		fillUint8(y[:])
+		fillUint8(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkUint8(t, "Broadcast", new(simd.Uint8x16).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkUint8(t, "Add", new(simd.Uint8x16).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkUint8(t, "Sub", new(simd.Uint8x16).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkUint8(t, "Min", new(simd.Uint8x16).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkUint8(t, "Max", new(simd.Uint8x16).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkUint8(t, "And", new(simd.Uint8x16).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkUint8(t, "Or", new(simd.Uint8x16).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkUint8(t, "Xor", new(simd.Uint8x16).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkUint8(t, "AndNot", new(simd.Uint8x16).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], uint8(0xFF), 0)
+		}
+		checkUint8(t, "Equal", new(simd.Uint8x16).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		var wantMask uint16
+		for i := range x {
+			if x[i] >= 0x80 {
+				wantMask |= 1 << i
+			}
+		}
+		if got := x.Mask(); got != wantMask {
+			t.Errorf("Mask(%v) = %#x, want %#x", x, got, wantMask)
+		}
+	}
+}
+
+func TestUint8x32(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Uint8x32
+		fillUint8(x[:])
+		fillUint8(y[:])
+		fillUint8(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkUint8(t, "Broadcast", new(simd.Uint8x32).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkUint8(t, "Add", new(simd.Uint8x32).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkUint8(t, "Sub", new(simd.Uint8x32).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkUint8(t, "Min", new(simd.Uint8x32).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkUint8(t, "Max", new(simd.Uint8x32).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkUint8(t, "And", new(simd.Uint8x32).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkUint8(t, "Or", new(simd.Uint8x32).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkUint8(t, "Xor", new(simd.Uint8x32).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkUint8(t, "AndNot", new(simd.Uint8x32).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], uint8(0xFF), 0)
+		}
+		checkUint8(t, "Equal", new(simd.Uint8x32).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		var wantMask uint32
+		for i := range x {
+			if x[i] >= 0x80 {
+				wantMask |= 1 << i
+			}
+		}
+		if got := x.Mask(); got != wantMask {
+			t.Errorf("Mask(%v) = %#x, want %#x", x, got, wantMask)
+		}
+	}
+}
+
+func TestUint8x64(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Uint8x64
+		fillUint8(x[:])
+		fillUint8(y[:])
+		fillUint8(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkUint8(t, "Broadcast", new(simd.Uint8x64).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkUint8(t, "Add", new(simd.Uint8x64).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkUint8(t, "Sub", new(simd.Uint8x64).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkUint8(t, "Min", new(simd.Uint8x64).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkUint8(t, "Max", new(simd.Uint8x64).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkUint8(t, "And", new(simd.Uint8x64).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkUint8(t, "Or", new(simd.Uint8x64).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkUint8(t, "Xor", new(simd.Uint8x64).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkUint8(t, "AndNot", new(simd.Uint8x64).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], uint8(0xFF), 0)
+		}
+		checkUint8(t, "Equal", new(simd.Uint8x64).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		var wantMask uint64
+		for i := range x {
+			if x[i] >= 0x80 {
+				wantMask |= 1 << i
+			}
+		}
+		if got := x.Mask(); got != wantMask {
+			t.Errorf("Mask(%v) = %#x, want %#x", x, got, wantMask)
+		}
+	}
+}
+
+func TestInt32x4(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Int32x4
+		fillInt32(x[:])
+		fillInt32(y[:])
+		fillInt32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkInt32(t, "Broadcast", new(simd.Int32x4).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkInt32(t, "Add", new(simd.Int32x4).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkInt32(t, "Sub", new(simd.Int32x4).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkInt32(t, "Mul", new(simd.Int32x4).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkInt32(t, "Min", new(simd.Int32x4).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkInt32(t, "Max", new(simd.Int32x4).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkInt32(t, "And", new(simd.Int32x4).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkInt32(t, "Or", new(simd.Int32x4).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkInt32(t, "Xor", new(simd.Int32x4).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkInt32(t, "AndNot", new(simd.Int32x4).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Equal", new(simd.Int32x4).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] > y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Greater", new(simd.Int32x4).Greater(&x, &y)[:], want[:])
+		if a := x; a.Greater(&a, &y) != &a || a != want {
+			t.Errorf("Greater aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestInt32x8(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Int32x8
+		fillInt32(x[:])
+		fillInt32(y[:])
+		fillInt32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkInt32(t, "Broadcast", new(simd.Int32x8).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkInt32(t, "Add", new(simd.Int32x8).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkInt32(t, "Sub", new(simd.Int32x8).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkInt32(t, "Mul", new(simd.Int32x8).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkInt32(t, "Min", new(simd.Int32x8).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkInt32(t, "Max", new(simd.Int32x8).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkInt32(t, "And", new(simd.Int32x8).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkInt32(t, "Or", new(simd.Int32x8).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkInt32(t, "Xor", new(simd.Int32x8).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkInt32(t, "AndNot", new(simd.Int32x8).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Equal", new(simd.Int32x8).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] > y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Greater", new(simd.Int32x8).Greater(&x, &y)[:], want[:])
+		if a := x; a.Greater(&a, &y) != &a || a != want {
+			t.Errorf("Greater aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestInt32x16(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Int32x16
+		fillInt32(x[:])
+		fillInt32(y[:])
+		fillInt32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkInt32(t, "Broadcast", new(simd.Int32x16).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkInt32(t, "Add", new(simd.Int32x16).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkInt32(t, "Sub", new(simd.Int32x16).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkInt32(t, "Mul", new(simd.Int32x16).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkInt32(t, "Min", new(simd.Int32x16).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkInt32(t, "Max", new(simd.Int32x16).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] & y[i]
+		}
+		checkInt32(t, "And", new(simd.Int32x16).And(&x, &y)[:], want[:])
+		if a := x; a.And(&a, &y) != &a || a != want {
+			t.Errorf("And aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] | y[i]
+		}
+		checkInt32(t, "Or", new(simd.Int32x16).Or(&x, &y)[:], want[:])
+		if a := x; a.Or(&a, &y) != &a || a != want {
+			t.Errorf("Or aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] ^ y[i]
+		}
+		checkInt32(t, "Xor", new(simd.Int32x16).Xor(&x, &y)[:], want[:])
+		if a := x; a.Xor(&a, &y) != &a || a != want {
+			t.Errorf("Xor aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] &^ y[i]
+		}
+		checkInt32(t, "AndNot", new(simd.Int32x16).AndNot(&x, &y)[:], want[:])
+		if a := x; a.AndNot(&a, &y) != &a || a != want {
+			t.Errorf("AndNot aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] == y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Equal", new(simd.Int32x16).Equal(&x, &y)[:], want[:])
+		if a := x; a.Equal(&a, &y) != &a || a != want {
+			t.Errorf("Equal aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = choose(x[i] > y[i], int32(-1), 0)
+		}
+		checkInt32(t, "Greater", new(simd.Int32x16).Greater(&x, &y)[:], want[:])
+		if a := x; a.Greater(&a, &y) != &a || a != want {
+			t.Errorf("Greater aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat32x4(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float32x4
+		fillFloat32(x[:])
+		fillFloat32(y[:])
+		fillFloat32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat32(t, "Broadcast", new(simd.Float32x4).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat32(t, "Add", new(simd.Float32x4).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat32(t, "Sub", new(simd.Float32x4).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat32(t, "Mul", new(simd.Float32x4).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat32(t, "Div", new(simd.Float32x4).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat32(t, "Min", new(simd.Float32x4).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat32(t, "Max", new(simd.Float32x4).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = float32(math.Sqrt(float64(x[i])))
+		}
+		checkFloat32(t, "Sqrt", new(simd.Float32x4).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat32x8(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float32x8
+		fillFloat32(x[:])
+		fillFloat32(y[:])
+		fillFloat32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat32(t, "Broadcast", new(simd.Float32x8).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat32(t, "Add", new(simd.Float32x8).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat32(t, "Sub", new(simd.Float32x8).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat32(t, "Mul", new(simd.Float32x8).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat32(t, "Div", new(simd.Float32x8).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat32(t, "Min", new(simd.Float32x8).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat32(t, "Max", new(simd.Float32x8).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = float32(math.Sqrt(float64(x[i])))
+		}
+		checkFloat32(t, "Sqrt", new(simd.Float32x8).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat32x16(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float32x16
+		fillFloat32(x[:])
+		fillFloat32(y[:])
+		fillFloat32(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat32(t, "Broadcast", new(simd.Float32x16).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat32(t, "Add", new(simd.Float32x16).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat32(t, "Sub", new(simd.Float32x16).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat32(t, "Mul", new(simd.Float32x16).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat32(t, "Div", new(simd.Float32x16).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat32(t, "Min", new(simd.Float32x16).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat32(t, "Max", new(simd.Float32x16).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = float32(math.Sqrt(float64(x[i])))
+		}
+		checkFloat32(t, "Sqrt", new(simd.Float32x16).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat64x2(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float64x2
+		fillFloat64(x[:])
+		fillFloat64(y[:])
+		fillFloat64(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat64(t, "Broadcast", new(simd.Float64x2).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat64(t, "Add", new(simd.Float64x2).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat64(t, "Sub", new(simd.Float64x2).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat64(t, "Mul", new(simd.Float64x2).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat64(t, "Div", new(simd.Float64x2).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat64(t, "Min", new(simd.Float64x2).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat64(t, "Max", new(simd.Float64x2).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = math.Sqrt(x[i])
+		}
+		checkFloat64(t, "Sqrt", new(simd.Float64x2).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat64x4(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float64x4
+		fillFloat64(x[:])
+		fillFloat64(y[:])
+		fillFloat64(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat64(t, "Broadcast", new(simd.Float64x4).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat64(t, "Add", new(simd.Float64x4).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat64(t, "Sub", new(simd.Float64x4).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat64(t, "Mul", new(simd.Float64x4).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat64(t, "Div", new(simd.Float64x4).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat64(t, "Min", new(simd.Float64x4).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat64(t, "Max", new(simd.Float64x4).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = math.Sqrt(x[i])
+		}
+		checkFloat64(t, "Sqrt", new(simd.Float64x4).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
+
+func TestFloat64x8(t *testing.T) {
+	for iter := 0; iter < iterations; iter++ {
+		var x, y, z, want simd.Float64x8
+		fillFloat64(x[:])
+		fillFloat64(y[:])
+		fillFloat64(z[:])
+		for i := range want {
+			want[i] = y[0]
+		}
+		checkFloat64(t, "Broadcast", new(simd.Float64x8).Broadcast(y[0])[:], want[:])
+		for i := range want {
+			want[i] = x[i] + y[i]
+		}
+		checkFloat64(t, "Add", new(simd.Float64x8).Add(&x, &y)[:], want[:])
+		if a := x; a.Add(&a, &y) != &a || a != want {
+			t.Errorf("Add aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] - y[i]
+		}
+		checkFloat64(t, "Sub", new(simd.Float64x8).Sub(&x, &y)[:], want[:])
+		if a := x; a.Sub(&a, &y) != &a || a != want {
+			t.Errorf("Sub aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] * y[i]
+		}
+		checkFloat64(t, "Mul", new(simd.Float64x8).Mul(&x, &y)[:], want[:])
+		if a := x; a.Mul(&a, &y) != &a || a != want {
+			t.Errorf("Mul aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i] / y[i]
+		}
+		checkFloat64(t, "Div", new(simd.Float64x8).Div(&x, &y)[:], want[:])
+		if a := x; a.Div(&a, &y) != &a || a != want {
+			t.Errorf("Div aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = min(x[i], y[i])
+		}
+		checkFloat64(t, "Min", new(simd.Float64x8).Min(&x, &y)[:], want[:])
+		if a := x; a.Min(&a, &y) != &a || a != want {
+			t.Errorf("Min aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = max(x[i], y[i])
+		}
+		checkFloat64(t, "Max", new(simd.Float64x8).Max(&x, &y)[:], want[:])
+		if a := x; a.Max(&a, &y) != &a || a != want {
+			t.Errorf("Max aliased: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = x[i]*y[i] + z[i]
+		}
+		if a := z; a.MulAdd(&x, &y) != &a || a != want {
+			t.Errorf("MulAdd: got %v, want %v", a, want)
+		}
+		for i := range want {
+			want[i] = math.Sqrt(x[i])
+		}
+		checkFloat64(t, "Sqrt", new(simd.Float64x8).Sqrt(&x)[:], want[:])
+		if a := x; a.Sqrt(&a) != &a || a != want {
+			t.Errorf("Sqrt aliased: got %v, want %v", a, want)
+		}
+	}
+}
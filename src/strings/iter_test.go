@@ -0,0 +1,123 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings_test
+
+import (
+	"iter"
+	. "strings"
+	"testing"
+	"unicode"
+)
+
+func collect(seq iter.Seq[string]) []string {
+	a := []string{}
+	for s := range seq {
+		a = append(a, s)
+	}
+	return a
+}
+
+var linesTests = []struct {
+	s string
+	a []string
+}{
+	{"", []string{}},
+	{"\n", []string{"\n"}},
+	{"abc", []string{"abc"}},
+	{"abc\n", []string{"abc\n"}},
+	{"abc\ndef", []string{"abc\n", "def"}},
+	{"abc\n\ndef\n", []string{"abc\n", "\n", "def\n"}},
+	{"a\r\nb\r\n", []string{"a\r\n", "b\r\n"}},
+}
+
+func TestLines(t *testing.T) {
+	for _, tt := range linesTests {
+		a := collect(Lines(tt.s))
+		if !eq(a, tt.a) {
+			t.Errorf("Lines(%q) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
+
+func TestSplitSeq(t *testing.T) {
+	for _, tt := range splittests {
+		if tt.n >= 0 {
+			continue
+		}
+		a := collect(SplitSeq(tt.s, tt.sep))
+		if !eq(a, tt.a) {
+			t.Errorf("SplitSeq(%q, %q) = %q; want %q", tt.s, tt.sep, a, tt.a)
+		}
+	}
+}
+
+func TestSplitAfterSeq(t *testing.T) {
+	for _, tt := range splitaftertests {
+		if tt.n >= 0 {
+			continue
+		}
+		a := collect(SplitAfterSeq(tt.s, tt.sep))
+		if !eq(a, tt.a) {
+			t.Errorf("SplitAfterSeq(%q, %q) = %q; want %q", tt.s, tt.sep, a, tt.a)
+		}
+	}
+}
+
+func TestFieldsSeq(t *testing.T) {
+	for _, tt := range fieldstests {
+		a := collect(FieldsSeq(tt.s))
+		if !eq(a, tt.a) {
+			t.Errorf("FieldsSeq(%q) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
+
+func TestFieldsFuncSeq(t *testing.T) {
+	for _, tt := range fieldstests {
+		a := collect(FieldsFuncSeq(tt.s, unicode.IsSpace))
+		if !eq(a, tt.a) {
+			t.Errorf("FieldsFuncSeq(%q, unicode.IsSpace) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+	pred := func(c rune) bool { return c == 'X' }
+	for _, tt := range FieldsFuncTests {
+		a := collect(FieldsFuncSeq(tt.s, pred))
+		if !eq(a, tt.a) {
+			t.Errorf("FieldsFuncSeq(%q) = %q; want %q", tt.s, a, tt.a)
+		}
+	}
+}
+
+func TestSeqBreak(t *testing.T) {
+	seqs := map[string]iter.Seq[string]{
+		"Lines":         Lines("a\nb\nc\n"),
+		"SplitSeq":      SplitSeq("a,b,c", ","),
+		"SplitSeq/rune": SplitSeq("abc", ""),
+		"SplitAfterSeq": SplitAfterSeq("a,b,c", ","),
+		"FieldsSeq":     FieldsSeq("a b c"),
+		"FieldsFuncSeq": FieldsFuncSeq("aXbXc", func(r rune) bool { return r == 'X' }),
+	}
+	for name, seq := range seqs {
+		n := 0
+		for range seq {
+			n++
+			if n == 2 {
+				break
+			}
+		}
+		if n != 2 {
+			t.Errorf("%s: got %d values before break, want 2", name, n)
+		}
+	}
+}
+
+func BenchmarkSplitSeq(b *testing.B) {
+	s := Repeat("a,", 100)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range SplitSeq(s, ",") {
+		}
+	}
+}
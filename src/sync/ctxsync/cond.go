@@ -0,0 +1,110 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"sync"
+)
+
+// Cond implements a condition variable whose Wait method can be abandoned
+// when a Context is done. It is otherwise like [sync.Cond].
+//
+// Each Cond has an associated Locker L (often a [*sync.Mutex] or
+// [*sync.RWMutex]), which must be held when changing the condition and
+// when calling the Wait method.
+//
+// A Cond must not be copied after first use.
+//
+// In the terminology of the Go memory model, Cond arranges that a call to
+// Broadcast or Signal “synchronizes before” any Wait call that it unblocks.
+type Cond struct {
+	_ noCopy
+
+	// L is held while observing or changing the condition
+	L sync.Locker
+
+	mu      sync.Mutex
+	waiters waitQueue
+}
+
+// NewCond returns a new Cond with Locker l.
+func NewCond(l sync.Locker) *Cond {
+	return &Cond{L: l}
+}
+
+// Wait atomically unlocks c.L and suspends execution of the calling
+// goroutine until it is woken by Signal or Broadcast, or ctx is done.
+// Wait locks c.L again before returning in either case.
+//
+// Wait returns nil if the goroutine was woken by Signal or Broadcast, and
+// ctx.Err() otherwise. A goroutine that returns because ctx is done does
+// not consume a Signal: the next waiting goroutine is woken instead.
+// If ctx is already done, Wait returns ctx.Err() without unlocking c.L.
+//
+// As with [sync.Cond.Wait], the caller typically cannot assume that the
+// condition is true when Wait returns, and should loop:
+//
+//	c.L.Lock()
+//	for !condition() {
+//	    if err := c.Wait(ctx); err != nil {
+//	        c.L.Unlock()
+//	        return err
+//	    }
+//	}
+//	... make use of condition ...
+//	c.L.Unlock()
+func (c *Cond) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	w := &waiter{}
+	c.waiters.pushBack(w)
+	stop := w.watch(ctx, &c.mu, &c.waiters, nil)
+	c.mu.Unlock()
+
+	c.L.Unlock()
+	granted := w.sleep(&c.mu, stop, runtime_SemacquireCond)
+	c.L.Lock()
+	if !granted {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// Signal wakes the goroutine that has been waiting on c the longest,
+// if there is any.
+//
+// It is allowed but not required for the caller to hold c.L
+// during the call.
+func (c *Cond) Signal() {
+	c.mu.Lock()
+	w := c.waiters.front()
+	if w != nil {
+		c.waiters.remove(w)
+	}
+	c.mu.Unlock()
+	if w != nil {
+		w.wake(false)
+	}
+}
+
+// Broadcast wakes all goroutines waiting on c.
+//
+// It is allowed but not required for the caller to hold c.L
+// during the call.
+func (c *Cond) Broadcast() {
+	c.mu.Lock()
+	var woken []*waiter
+	for w := c.waiters.front(); w != nil; w = c.waiters.front() {
+		c.waiters.remove(w)
+		woken = append(woken, w)
+	}
+	c.mu.Unlock()
+	for _, w := range woken {
+		w.wake(false)
+	}
+}
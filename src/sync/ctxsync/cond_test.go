@@ -0,0 +1,148 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCondSignalFIFO(t *testing.T) {
+	var mu sync.Mutex
+	c := NewCond(&mu)
+	ctx := context.Background()
+
+	const n = 5
+	woken := make(chan int, n)
+	for i := range n {
+		go func() {
+			mu.Lock()
+			if err := c.Wait(ctx); err != nil {
+				t.Error(err)
+			}
+			mu.Unlock()
+			woken <- i
+		}()
+		waitForWaiters(&c.mu, &c.waiters, i+1)
+	}
+	for i := range n {
+		c.Signal()
+		if got := <-woken; got != i {
+			t.Fatalf("Signal woke waiter %d, want %d", got, i)
+		}
+	}
+}
+
+func TestCondBroadcast(t *testing.T) {
+	var mu sync.Mutex
+	c := NewCond(&mu)
+	ctx := context.Background()
+
+	const n = 5
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			if err := c.Wait(ctx); err != nil {
+				t.Error(err)
+			}
+			mu.Unlock()
+		}()
+		waitForWaiters(&c.mu, &c.waiters, i+1)
+	}
+	c.Broadcast()
+	wg.Wait()
+}
+
+func TestCondCancel(t *testing.T) {
+	var mu sync.Mutex
+	c := NewCond(&mu)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		mu.Lock()
+		err := c.Wait(ctx)
+		mu.Unlock()
+		done <- err
+	}()
+	waitForWaiters(&c.mu, &c.waiters, 1)
+
+	woken := make(chan struct{})
+	go func() {
+		mu.Lock()
+		c.Wait(context.Background())
+		mu.Unlock()
+		close(woken)
+	}()
+	waitForWaiters(&c.mu, &c.waiters, 2)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait with canceled context = %v, want %v", err, context.Canceled)
+	}
+
+	// The canceled waiter is gone, so this signal must not be lost.
+	waitForWaiters(&c.mu, &c.waiters, 1)
+	c.Signal()
+	<-woken
+
+	mu.Lock()
+	if err := c.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait with done context = %v, want %v", err, context.Canceled)
+	}
+	// Wait must return with the lock held.
+	if mu.TryLock() {
+		t.Fatalf("Wait returned without the lock held")
+	}
+	mu.Unlock()
+}
+
+func TestCondSignalRace(t *testing.T) {
+	// Every Signal must wake a goroutine that reports success,
+	// even while others are canceled concurrently.
+	var mu sync.Mutex
+	c := NewCond(&mu)
+	ready := 0
+	consumed := 0
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			if i%2 == 0 {
+				cancel()
+			}
+			defer cancel()
+			mu.Lock()
+			for ready == 0 {
+				if c.Wait(ctx) != nil {
+					// Retry without cancellation.
+					ctx = context.Background()
+				}
+			}
+			ready--
+			consumed++
+			mu.Unlock()
+		}()
+	}
+	for range n {
+		mu.Lock()
+		ready++
+		mu.Unlock()
+		c.Signal()
+	}
+	wg.Wait()
+	if consumed != n {
+		t.Fatalf("consumed %d, want %d", consumed, n)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ctxsync provides synchronization primitives whose blocking
+// operations can be abandoned when a [context.Context] is done.
+//
+// A goroutine blocked in [sync.Mutex.Lock] or [sync.Cond.Wait] stays blocked
+// until the lock is released or the condition is signaled, even if the
+// request it is serving has long been canceled. The [Mutex], [Semaphore]
+// and [Cond] types in this package take a Context in the operations that
+// block and return early, with the Context's error, when it is done.
+//
+// # Fairness
+//
+// All three types queue blocked goroutines in first-in, first-out order
+// and never let a newly arriving goroutine overtake one that is already
+// waiting:
+//
+//   - [Mutex.Unlock] hands the lock directly to the goroutine that has
+//     been waiting longest, and [Mutex.Lock] does not acquire an unlocked
+//     Mutex while other goroutines are waiting for it.
+//   - [Semaphore.Release] grants waiting requests in arrival order. A
+//     request for more weight than is currently available blocks the
+//     requests queued behind it, even smaller ones that would fit, so
+//     large requests are not starved.
+//   - [Cond.Signal] wakes the goroutine that has been waiting longest.
+//
+// A goroutine that gives up because its Context is done leaves the queue
+// without consuming a grant or a signal meant for it: the next waiter in
+// line receives it instead.
+//
+// Blocked goroutines sleep on the runtime's semaphores, the same mechanism
+// used by package sync, and appear in tracebacks and in the block and
+// mutex profiles as such. Waiting for cancellation uses
+// [context.AfterFunc] and does not start a goroutine unless the Context
+// is actually canceled while the operation is blocked.
+package ctxsync
+
+import (
+	"context"
+	"sync"
+)
+
+// A waiter is a goroutine blocked in one of the types in this package.
+type waiter struct {
+	sema uint32 // the waiter sleeps on this runtime semaphore
+
+	// The following fields are guarded by the mutex of the type
+	// the waiter is queued on.
+	n          int64 // weight requested from a Semaphore
+	queued     bool  // the waiter is in a waitQueue
+	canceled   bool  // the waiter was removed from the queue by cancellation
+	prev, next *waiter
+}
+
+// A waitQueue is a FIFO list of waiters.
+type waitQueue struct {
+	head, tail *waiter
+	len        int
+}
+
+func (q *waitQueue) pushBack(w *waiter) {
+	w.prev, w.next = q.tail, nil
+	if q.tail != nil {
+		q.tail.next = w
+	} else {
+		q.head = w
+	}
+	q.tail = w
+	w.queued = true
+	q.len++
+}
+
+func (q *waitQueue) front() *waiter {
+	return q.head
+}
+
+func (q *waitQueue) remove(w *waiter) {
+	if w.prev != nil {
+		w.prev.next = w.next
+	} else {
+		q.head = w.next
+	}
+	if w.next != nil {
+		w.next.prev = w.prev
+	} else {
+		q.tail = w.prev
+	}
+	w.prev, w.next = nil, nil
+	w.queued = false
+	q.len--
+}
+
+// watch arranges for w to be removed from q and woken when ctx is done,
+// unless it has been dequeued by then. mu guards q. If w is canceled,
+// onCancel, if non-nil, is called with mu held and reports whether w was
+// at the front of the queue. The returned function must be passed to
+// sleep.
+func (w *waiter) watch(ctx context.Context, mu *sync.Mutex, q *waitQueue, onCancel func(wasFront bool)) (stop func() bool) {
+	if ctx.Done() == nil {
+		return nil
+	}
+	return context.AfterFunc(ctx, func() {
+		mu.Lock()
+		if !w.queued {
+			// Already granted.
+			mu.Unlock()
+			return
+		}
+		wasFront := q.front() == w
+		q.remove(w)
+		w.canceled = true
+		if onCancel != nil {
+			onCancel(wasFront)
+		}
+		mu.Unlock()
+		runtime_Semrelease(&w.sema, false, 0)
+	})
+}
+
+// sleep blocks until w has been dequeued, either by a grant or because
+// its Context is done, and reports whether it was granted. acquire is the
+// runtime semaphore function to sleep with. mu must not be held.
+func (w *waiter) sleep(mu *sync.Mutex, stop func() bool, acquire func(*uint32, int)) bool {
+	acquire(&w.sema, 2)
+	if stop != nil {
+		stop()
+	}
+	// The semaphore carries no happens-before edge for the race
+	// detector; mu does.
+	mu.Lock()
+	canceled := w.canceled
+	mu.Unlock()
+	return !canceled
+}
+
+// wake wakes a waiter that has been dequeued by a grant.
+func (w *waiter) wake(handoff bool) {
+	runtime_Semrelease(&w.sema, handoff, 1)
+}
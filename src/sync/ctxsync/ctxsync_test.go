@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"runtime"
+	"sync"
+)
+
+// waitForWaiters blocks until q, guarded by mu, holds n waiters.
+func waitForWaiters(mu *sync.Mutex, q *waitQueue, n int) {
+	for {
+		mu.Lock()
+		l := q.len
+		mu.Unlock()
+		if l == n {
+			return
+		}
+		runtime.Gosched()
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/ctxsync"
+	"time"
+)
+
+func ExampleMutex() {
+	var mu ctxsync.Mutex
+	mu.Lock(context.Background())
+
+	// The lock is held, so a request with a deadline gives up.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := mu.Lock(ctx); err != nil {
+		fmt.Println("Lock:", err)
+	}
+	mu.Unlock()
+	// Output:
+	// Lock: context deadline exceeded
+}
+
+func ExampleSemaphore() {
+	// Allow at most 2 workers at a time.
+	sem := ctxsync.NewSemaphore(2)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+			results[i] = i * i
+		}()
+	}
+	wg.Wait()
+	fmt.Println(results)
+	// Output:
+	// [0 1 4 9 16]
+}
+
+func ExampleCond() {
+	var mu sync.Mutex
+	c := ctxsync.NewCond(&mu)
+	queue := []string{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Wait for an item that never arrives.
+	mu.Lock()
+	for len(queue) == 0 {
+		if err := c.Wait(ctx); err != nil {
+			fmt.Println("Wait:", err)
+			break
+		}
+	}
+	mu.Unlock()
+	// Output:
+	// Wait: context deadline exceeded
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"sync"
+)
+
+// A Mutex is a mutual exclusion lock whose Lock method can be abandoned
+// when a Context is done.
+// The zero value for a Mutex is an unlocked mutex.
+//
+// Unlike [sync.Mutex], a Mutex is strictly fair: see the package
+// documentation. The price of fairness is throughput under heavy
+// contention, since every contended hand-off requires waking a goroutine.
+//
+// A Mutex must not be copied after first use.
+//
+// In the terminology of the Go memory model, the n'th call to Unlock
+// “synchronizes before” the m'th successful call to Lock for any n < m,
+// and a successful call to TryLock is equivalent to a successful call
+// to Lock.
+type Mutex struct {
+	_ noCopy
+
+	mu      sync.Mutex
+	locked  bool
+	waiters waitQueue
+}
+
+// Lock locks m. If the lock is already in use, the calling goroutine
+// blocks until the mutex is available or ctx is done.
+//
+// Lock returns nil if it acquired the lock, and ctx.Err() otherwise.
+// If ctx is already done, Lock fails without blocking, even if the
+// mutex is unlocked.
+func (m *Mutex) Lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	if !m.locked && m.waiters.len == 0 {
+		m.locked = true
+		m.mu.Unlock()
+		return nil
+	}
+	w := &waiter{}
+	m.waiters.pushBack(w)
+	stop := w.watch(ctx, &m.mu, &m.waiters, nil)
+	m.mu.Unlock()
+
+	if !w.sleep(&m.mu, stop, runtime_SemacquireMutex) {
+		return ctx.Err()
+	}
+	// Unlock handed the lock to us; m.locked is still set.
+	return nil
+}
+
+// TryLock tries to lock m and reports whether it succeeded.
+// It does not succeed while other goroutines are waiting in Lock.
+func (m *Mutex) TryLock() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked || m.waiters.len != 0 {
+		return false
+	}
+	m.locked = true
+	return true
+}
+
+// Unlock unlocks m, handing it to the goroutine that has been waiting
+// longest in Lock, if any.
+// It is a run-time error if m is not locked on entry to Unlock.
+//
+// As with [sync.Mutex], a locked Mutex is not associated with a particular
+// goroutine.
+func (m *Mutex) Unlock() {
+	m.mu.Lock()
+	if !m.locked {
+		m.mu.Unlock()
+		panic("ctxsync: unlock of unlocked mutex")
+	}
+	w := m.waiters.front()
+	if w == nil {
+		m.locked = false
+		m.mu.Unlock()
+		return
+	}
+	m.waiters.remove(w)
+	m.mu.Unlock()
+	w.wake(true)
+}
+
+// noCopy may be added to structs which must not be copied
+// after the first use.
+//
+// See https://golang.org/issues/8005#issuecomment-190753527
+// for details.
+//
+// Note that it must not be embedded, due to the Lock and Unlock methods.
+type noCopy struct{}
+
+// Lock is a no-op used by -copylocks checker from `go vet`.
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
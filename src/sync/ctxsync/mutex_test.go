@@ -0,0 +1,174 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMutex(t *testing.T) {
+	var m Mutex
+	ctx := context.Background()
+	if err := m.Lock(ctx); err != nil {
+		t.Fatalf("Lock of unlocked mutex = %v", err)
+	}
+	if m.TryLock() {
+		t.Fatalf("TryLock of locked mutex succeeded")
+	}
+	m.Unlock()
+	if !m.TryLock() {
+		t.Fatalf("TryLock of unlocked mutex failed")
+	}
+	m.Unlock()
+
+	const n = 10
+	var wg sync.WaitGroup
+	counter := 0
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				if err := m.Lock(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+				counter++
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != n*1000 {
+		t.Errorf("counter = %d, want %d", counter, n*1000)
+	}
+}
+
+func TestMutexFIFO(t *testing.T) {
+	var m Mutex
+	ctx := context.Background()
+	m.Lock(ctx)
+
+	const n = 8
+	order := make(chan int, n)
+	for i := range n {
+		go func() {
+			m.Lock(ctx)
+			order <- i
+			m.Unlock()
+		}()
+		waitForWaiters(&m.mu, &m.waiters, i+1)
+	}
+	// Arriving now must not overtake the queued goroutines.
+	if m.TryLock() {
+		t.Fatalf("TryLock succeeded with goroutines waiting")
+	}
+	m.Unlock()
+	for i := range n {
+		if got := <-order; got != i {
+			t.Fatalf("waiter %d acquired the lock in position %d", got, i)
+		}
+	}
+}
+
+func TestMutexCancel(t *testing.T) {
+	var m Mutex
+	m.Lock(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Lock(ctx) }()
+	waitForWaiters(&m.mu, &m.waiters, 1)
+
+	// A second waiter must get the lock when the first gives up.
+	acquired := make(chan struct{})
+	go func() {
+		m.Lock(context.Background())
+		close(acquired)
+	}()
+	waitForWaiters(&m.mu, &m.waiters, 2)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Lock with canceled context = %v, want %v", err, context.Canceled)
+	}
+	waitForWaiters(&m.mu, &m.waiters, 1)
+	m.Unlock()
+	<-acquired
+	m.Unlock()
+
+	// An already-done context fails even if the mutex is free.
+	if err := m.Lock(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Lock with done context = %v, want %v", err, context.Canceled)
+	}
+	if !m.TryLock() {
+		t.Fatalf("mutex left locked by failed Lock")
+	}
+	m.Unlock()
+}
+
+func TestMutexTimeoutRace(t *testing.T) {
+	// Stress the race between cancellation and Unlock: each Lock
+	// either fails or must be paired with exactly one Unlock.
+	var m Mutex
+	var wg sync.WaitGroup
+	holders := 0
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+				if m.Lock(ctx) == nil {
+					holders++
+					if holders != 1 {
+						t.Errorf("%d holders of mutex", holders)
+					}
+					holders--
+					m.Unlock()
+				}
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if !m.TryLock() {
+		t.Fatalf("mutex left locked")
+	}
+}
+
+func TestMutexUnlockPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Unlock of unlocked mutex did not panic")
+		}
+	}()
+	var m Mutex
+	m.Unlock()
+}
+
+func BenchmarkMutexUncontended(b *testing.B) {
+	var m Mutex
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		m.Lock(ctx)
+		m.Unlock()
+	}
+}
+
+func BenchmarkMutex(b *testing.B) {
+	var m Mutex
+	ctx := context.Background()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Lock(ctx)
+			m.Unlock()
+		}
+	})
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+// defined in package runtime
+
+// runtime_Semacquire{Mutex,Semaphore,Cond} wait until *s > 0 and then
+// atomically decrement it. They differ only in how the runtime presents
+// the reason for waiting in a backtrace and which profiles record the
+// delay. skipframes is the number of frames to omit during tracing,
+// counting from the caller.
+func runtime_SemacquireMutex(s *uint32, skipframes int)
+func runtime_SemacquireSemaphore(s *uint32, skipframes int)
+func runtime_SemacquireCond(s *uint32, skipframes int)
+
+// runtime_Semrelease atomically increments *s and notifies a waiting
+// goroutine if one is blocked in one of the runtime_Semacquire functions.
+// If handoff is true, pass count directly to the first waiter.
+func runtime_Semrelease(s *uint32, handoff bool, skipframes int)
@@ -0,0 +1,120 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"sync"
+)
+
+// A Semaphore is a weighted semaphore: it bounds access to a resource of
+// a fixed total size, with each holder claiming some portion of it.
+//
+// A Semaphore must be created with [NewSemaphore] and must not be copied
+// after first use.
+type Semaphore struct {
+	_ noCopy
+
+	size    int64
+	mu      sync.Mutex
+	cur     int64
+	waiters waitQueue
+}
+
+// NewSemaphore returns a new Semaphore with the given maximum combined
+// weight for concurrent access.
+func NewSemaphore(n int64) *Semaphore {
+	return &Semaphore{size: n}
+}
+
+// Acquire acquires the semaphore with a weight of n, blocking until
+// resources are available or ctx is done.
+//
+// Acquire returns nil on success. On failure it returns ctx.Err() and
+// leaves the semaphore unchanged. If ctx is already done, Acquire fails
+// without blocking, even if the resources are available.
+//
+// A request for more than the semaphore's size can never succeed;
+// Acquire blocks until ctx is done without holding up other requests.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.len == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	if n > s.size {
+		// Don't make other Acquire calls block on one that's doomed to fail.
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	w := &waiter{n: n}
+	s.waiters.pushBack(w)
+	stop := w.watch(ctx, &s.mu, &s.waiters, func(wasFront bool) {
+		// If w was at the front and there's extra capacity,
+		// the requests queued behind it may now be satisfiable.
+		if wasFront && s.size > s.cur {
+			s.grantWaiters()
+		}
+	})
+	s.mu.Unlock()
+
+	if !w.sleep(&s.mu, stop, runtime_SemacquireSemaphore) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// TryAcquire acquires the semaphore with a weight of n without blocking.
+// On success, it returns true. On failure, it returns false and leaves
+// the semaphore unchanged. It does not succeed while other goroutines
+// are waiting in Acquire.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.len == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release releases the semaphore with a weight of n, granting the
+// resources to waiting Acquire calls in the order they arrived.
+// It is a run-time error to release more than is held.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	s.cur -= n
+	if s.cur < 0 {
+		s.mu.Unlock()
+		panic("ctxsync: released more than held")
+	}
+	s.grantWaiters()
+	s.mu.Unlock()
+}
+
+// grantWaiters grants waiting requests in order until the one at the
+// front of the queue does not fit. s.mu must be held.
+func (s *Semaphore) grantWaiters() {
+	for {
+		w := s.waiters.front()
+		if w == nil || s.size-s.cur < w.n {
+			// Not enough resources for the next waiter.
+			//
+			// We could keep going and grant smaller requests further
+			// back, but a steady stream of those could starve w
+			// indefinitely.
+			return
+		}
+		s.cur += w.n
+		s.waiters.remove(w)
+		w.wake(false)
+	}
+}
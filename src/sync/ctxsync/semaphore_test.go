@@ -0,0 +1,137 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ctxsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSemaphore(t *testing.T) {
+	const size = 5
+	s := NewSemaphore(size)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	held := int64(0)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := int64(i%size + 1)
+			for range 100 {
+				if err := s.Acquire(ctx, n); err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				held += n
+				if held > size {
+					t.Errorf("%d of %d held", held, size)
+				}
+				held -= n
+				mu.Unlock()
+				s.Release(n)
+			}
+		}()
+	}
+	wg.Wait()
+	if !s.TryAcquire(size) {
+		t.Fatalf("semaphore not fully released")
+	}
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	s := NewSemaphore(4)
+	ctx := context.Background()
+	s.Acquire(ctx, 4)
+
+	// A large request at the front blocks smaller ones behind it.
+	order := make(chan int64, 3)
+	for i, n := range []int64{3, 1, 1} {
+		go func() {
+			s.Acquire(ctx, n)
+			order <- n
+		}()
+		waitForWaiters(&s.mu, &s.waiters, i+1)
+	}
+
+	s.Release(2)
+	if s.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1) overtook queued Acquire(3)")
+	}
+	select {
+	case n := <-order:
+		t.Fatalf("Acquire(%d) overtook queued Acquire(3)", n)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Each release now makes room for exactly the next request in line.
+	for _, want := range []int64{3, 1, 1} {
+		s.Release(1)
+		if got := <-order; got != want {
+			t.Fatalf("Acquire(%d) granted, want Acquire(%d)", got, want)
+		}
+	}
+}
+
+func TestSemaphoreCancel(t *testing.T) {
+	s := NewSemaphore(2)
+	s.Acquire(context.Background(), 1)
+
+	// Canceling the request at the front must let the ones behind it
+	// proceed if they fit.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Acquire(ctx, 2) }()
+	waitForWaiters(&s.mu, &s.waiters, 1)
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire(context.Background(), 1)
+		close(acquired)
+	}()
+	waitForWaiters(&s.mu, &s.waiters, 2)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire with canceled context = %v, want %v", err, context.Canceled)
+	}
+	<-acquired
+	s.Release(2)
+
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire with done context = %v, want %v", err, context.Canceled)
+	}
+	if !s.TryAcquire(2) {
+		t.Fatalf("failed Acquire changed the semaphore")
+	}
+}
+
+func TestSemaphoreTooLarge(t *testing.T) {
+	s := NewSemaphore(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire(2) of size 1 = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !s.TryAcquire(1) {
+		t.Fatalf("oversized Acquire blocked other requests")
+	}
+}
+
+func TestSemaphoreReleasePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Release of more than held did not panic")
+		}
+	}()
+	s := NewSemaphore(1)
+	s.Release(1)
+}
@@ -0,0 +1,219 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package errgroup provides synchronization, error propagation, and Context
+// cancellation for groups of goroutines working on subtasks of a common task.
+//
+// A [Group] is a [sync.WaitGroup] that also collects the first error
+// returned by its goroutines, cancels a derived [context.Context] when
+// that error occurs, optionally bounds the number of goroutines running at
+// once, and re-raises in [Group.Wait] any panic that occurred in one of
+// its goroutines.
+package errgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+type token struct{}
+
+// A Group is a collection of goroutines working on subtasks that are part of
+// the same overall task.
+//
+// A zero Group is valid, has no limit on the number of active goroutines,
+// and does not cancel on error.
+//
+// A Group must not be copied after first use.
+type Group struct {
+	cancel func(error)
+
+	wg sync.WaitGroup
+
+	sem chan token
+
+	errOnce sync.Once
+	err     error
+
+	mu       sync.Mutex
+	panicErr *PanicError // first panic recovered from a goroutine
+	goexited bool        // a goroutine called runtime.Goexit
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx.
+//
+// The derived Context is canceled the first time a function passed to Go
+// returns a non-nil error or panics, or the first time Wait returns,
+// whichever occurs first. The error or panic that caused the cancellation
+// is recorded as the Context's cause and can be retrieved with
+// [context.Cause].
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Wait blocks until all function calls from the Go method have returned,
+// then returns the first non-nil error (if any) from them.
+//
+// If any of the functions panicked, Wait panics with a [PanicError]
+// holding the first recovered value and the stack of the goroutine that
+// panicked. If any of the functions called [runtime.Goexit] and none
+// panicked, Wait calls runtime.Goexit too.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	if g.panicErr != nil {
+		panic(g.panicErr)
+	}
+	if g.goexited {
+		runtime.Goexit()
+	}
+	return g.err
+}
+
+// Go calls the given function in a new goroutine.
+//
+// The first call to Go must happen before a Wait.
+// It blocks until the new goroutine can be added without the number of
+// active goroutines in the group exceeding the configured limit.
+//
+// The first call to return a non-nil error cancels the group's context, if the
+// group was created by calling WithContext. The error will be returned by Wait.
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- token{}
+	}
+	g.start(f)
+}
+
+// TryGo calls the given function in a new goroutine only if the number of
+// active goroutines in the group is currently below the configured limit.
+//
+// The return value reports whether the goroutine was started.
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- token{}:
+			// Note: this allows barging iff channels in general allow barging.
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+func (g *Group) start(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		normalReturn := false
+		defer func() {
+			if normalReturn {
+				return
+			}
+			// A panic always has a non-nil recovered value,
+			// so nil means that f called runtime.Goexit.
+			if v := recover(); v != nil {
+				g.setPanic(&PanicError{Value: v, Stack: stack()})
+			} else {
+				g.setGoexit()
+			}
+		}()
+
+		err := f()
+		normalReturn = true
+		if err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(g.err)
+				}
+			})
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+func (g *Group) setPanic(p *PanicError) {
+	g.mu.Lock()
+	if g.panicErr == nil {
+		g.panicErr = p
+	}
+	g.mu.Unlock()
+	if g.cancel != nil {
+		g.cancel(p)
+	}
+}
+
+func (g *Group) setGoexit() {
+	g.mu.Lock()
+	g.goexited = true
+	g.mu.Unlock()
+	if g.cancel != nil {
+		g.cancel(errGoexit)
+	}
+}
+
+var errGoexit = errors.New("errgroup: goroutine called runtime.Goexit")
+
+// SetLimit limits the number of active goroutines in this group to at most n.
+// A negative value indicates no limit.
+// A limit of zero will prevent any new goroutines from being added.
+//
+// Any subsequent call to the Go method will block until it can add an active
+// goroutine without exceeding the configured limit.
+//
+// The limit must not be modified while any goroutines in the group are active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("errgroup: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan token, n)
+}
+
+// A PanicError is the value that [Group.Wait] panics with when a function
+// passed to Go or TryGo panicked. It is also recorded as the cause of the
+// Group's Context.
+type PanicError struct {
+	Value any    // the value recovered from the panic
+	Stack []byte // the stack of the panicking goroutine, as formatted by runtime.Stack
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("errgroup: goroutine panicked: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the recovered value if it is an error, and nil otherwise.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// stack returns the stack of the calling goroutine.
+func stack() []byte {
+	buf := make([]byte, 1024)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
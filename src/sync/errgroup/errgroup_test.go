@@ -0,0 +1,296 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"sync/errgroup"
+	"testing"
+	"time"
+)
+
+func TestZeroGroup(t *testing.T) {
+	err1 := errors.New("errgroup_test: 1")
+	err2 := errors.New("errgroup_test: 2")
+
+	cases := []struct {
+		errs []error
+	}{
+		{errs: []error{}},
+		{errs: []error{nil}},
+		{errs: []error{err1}},
+		{errs: []error{err1, nil}},
+		{errs: []error{err1, nil, err2}},
+	}
+
+	for _, tc := range cases {
+		g := new(errgroup.Group)
+
+		var firstErr error
+		for i, err := range tc.errs {
+			g.Go(func() error { return err })
+
+			if firstErr == nil && err != nil {
+				firstErr = err
+			}
+
+			if gErr := g.Wait(); gErr != firstErr {
+				t.Errorf("after %T.Go(func() error { return err }) for err in %v\n"+
+					"g.Wait() = %v; want %v",
+					g, tc.errs[:i+1], err, firstErr)
+			}
+		}
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	errDoom := errors.New("group_test: doomed")
+
+	cases := []struct {
+		errs []error
+		want error
+	}{
+		{want: nil},
+		{errs: []error{nil}, want: nil},
+		{errs: []error{errDoom}, want: errDoom},
+		{errs: []error{errDoom, nil}, want: errDoom},
+	}
+
+	for _, tc := range cases {
+		g, ctx := errgroup.WithContext(context.Background())
+
+		for _, err := range tc.errs {
+			g.Go(func() error { return err })
+		}
+
+		if err := g.Wait(); err != tc.want {
+			t.Errorf("after %T.Go(func() error { return err }) for err in %v\n"+
+				"g.Wait() = %v; want %v",
+				g, tc.errs, err, tc.want)
+		}
+
+		canceled := false
+		select {
+		case <-ctx.Done():
+			canceled = true
+		default:
+		}
+		if !canceled {
+			t.Errorf("after %T.Go(func() error { return err }) for err in %v\n"+
+				"ctx.Done() was not closed",
+				g, tc.errs)
+		}
+	}
+}
+
+func TestCancelCause(t *testing.T) {
+	errDoom := errors.New("group_test: doomed")
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error { return errDoom })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := g.Wait(); err != errDoom {
+		t.Errorf("g.Wait() = %v; want %v", err, errDoom)
+	}
+	if cause := context.Cause(ctx); cause != errDoom {
+		t.Errorf("context.Cause(ctx) = %v; want %v", cause, errDoom)
+	}
+
+	// When nothing fails, Wait cancels the context with the usual error.
+	g, ctx = errgroup.WithContext(context.Background())
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Errorf("g.Wait() = %v; want nil", err)
+	}
+	if cause := context.Cause(ctx); cause != context.Canceled {
+		t.Errorf("context.Cause(ctx) = %v; want %v", cause, context.Canceled)
+	}
+}
+
+func TestTryGo(t *testing.T) {
+	g := &errgroup.Group{}
+	n := 42
+	g.SetLimit(42)
+	ch := make(chan struct{})
+	fn := func() error {
+		ch <- struct{}{}
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if !g.TryGo(fn) {
+			t.Fatalf("TryGo should succeed but got fail at %d-th call.", i)
+		}
+	}
+	if g.TryGo(fn) {
+		t.Fatalf("TryGo is expected to fail but succeeded.")
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			<-ch
+		}
+	}()
+	g.Wait()
+
+	if !g.TryGo(fn) {
+		t.Fatalf("TryGo should success but got fail after all goroutines.")
+	}
+	go func() { <-ch }()
+	g.Wait()
+
+	// Switch limit.
+	g.SetLimit(1)
+	if !g.TryGo(fn) {
+		t.Fatalf("TryGo should success but got failed.")
+	}
+	if g.TryGo(fn) {
+		t.Fatalf("TryGo should fail but succeeded.")
+	}
+	go func() { <-ch }()
+	g.Wait()
+
+	// Block all calls.
+	g.SetLimit(0)
+	for i := 0; i < 1<<10; i++ {
+		if g.TryGo(fn) {
+			t.Fatalf("TryGo should fail but got succeeded.")
+		}
+	}
+	g.Wait()
+}
+
+func TestGoLimit(t *testing.T) {
+	const limit = 10
+
+	g := &errgroup.Group{}
+	g.SetLimit(limit)
+	var active int32
+	for i := 0; i <= 1<<10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&active, 1)
+			if n > limit {
+				return fmt.Errorf("saw %d active goroutines; want ≤ %d", n, limit)
+			}
+			time.Sleep(1 * time.Microsecond) // Give other goroutines a chance to increment active.
+			atomic.AddInt32(&active, -1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetLimitWhileActive(t *testing.T) {
+	g := &errgroup.Group{}
+	g.SetLimit(1)
+	release := make(chan struct{})
+	g.Go(func() error {
+		<-release
+		return nil
+	})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("SetLimit with active goroutines did not panic")
+			}
+		}()
+		g.SetLimit(2)
+	}()
+	close(release)
+	g.Wait()
+}
+
+//go:noinline
+func panickingSubtask() error {
+	panic("errgroup_test: boom")
+}
+
+func TestPanic(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error { return nil })
+	g.Go(panickingSubtask)
+
+	p := func() (p any) {
+		defer func() { p = recover() }()
+		g.Wait()
+		return nil
+	}()
+
+	pe, ok := p.(*errgroup.PanicError)
+	if !ok {
+		t.Fatalf("Wait panicked with %T(%v); want *errgroup.PanicError", p, p)
+	}
+	if pe.Value != "errgroup_test: boom" {
+		t.Errorf("PanicError.Value = %v; want %q", pe.Value, "errgroup_test: boom")
+	}
+	if !strings.Contains(string(pe.Stack), "panickingSubtask") {
+		t.Errorf("PanicError.Stack does not mention the panicking function:\n%s", pe.Stack)
+	}
+	if cause := context.Cause(ctx); cause != error(pe) {
+		t.Errorf("context.Cause(ctx) = %v; want the PanicError", cause)
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	errDoom := errors.New("group_test: doomed")
+
+	g := new(errgroup.Group)
+	g.Go(func() error { panic(errDoom) })
+
+	p := func() (p any) {
+		defer func() { p = recover() }()
+		g.Wait()
+		return nil
+	}()
+
+	err, ok := p.(error)
+	if !ok {
+		t.Fatalf("Wait panicked with %T(%v); want an error", p, p)
+	}
+	if !errors.Is(err, errDoom) {
+		t.Errorf("errors.Is(%v, errDoom) = false; want true", err)
+	}
+}
+
+func TestGoexit(t *testing.T) {
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		runtime.Goexit()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	returned := false
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		g.Wait()
+		returned = true
+	}()
+	wg.Wait()
+	if returned {
+		t.Errorf("Wait returned after a goroutine in the group called runtime.Goexit")
+	}
+}
+
+func BenchmarkGo(b *testing.B) {
+	fn := func() {}
+	g := &errgroup.Group{}
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		g.Go(func() error { fn(); return nil })
+	}
+	g.Wait()
+}
@@ -39,6 +39,26 @@ func ExampleWaitGroup() {
 	wg.Wait()
 }
 
+// This example is the same as the WaitGroup example above,
+// using WaitGroup.Go to start the goroutines.
+func ExampleWaitGroup_Go() {
+	var wg sync.WaitGroup
+	var urls = []string{
+		"http://www.golang.org/",
+		"http://www.google.com/",
+		"http://www.example.com/",
+	}
+	for _, url := range urls {
+		// Launch a goroutine to fetch the URL.
+		wg.Go(func() {
+			// Fetch the URL.
+			http.Get(url)
+		})
+	}
+	// Wait for all HTTP fetches to complete.
+	wg.Wait()
+}
+
 func ExampleOnce() {
 	var once sync.Once
 	onceBody := func() {
@@ -4,6 +4,8 @@
 
 package sync
 
+import "unsafe"
+
 // Export for testing.
 var Runtime_Semacquire = runtime_Semacquire
 var Runtime_Semrelease = runtime_Semrelease
@@ -55,3 +57,14 @@ func (c *poolChain) PopHead() (any, bool) {
 func (c *poolChain) PopTail() (any, bool) {
 	return c.popTail()
 }
+
+// NewMapOfWithHasher returns a MapOf that hashes keys with hash instead of
+// the runtime's hash function for K, so that tests can force collisions.
+func NewMapOfWithHasher[K comparable, V any](hash func(K, uintptr) uintptr) *MapOf[K, V] {
+	m := new(MapOf[K, V])
+	m.init()
+	m.keyHash = func(p unsafe.Pointer, seed uintptr) uintptr {
+		return hash(*(*K)(p), seed)
+	}
+	return m
+}
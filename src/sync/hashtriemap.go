@@ -0,0 +1,721 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"internal/abi"
+	"internal/goarch"
+	"iter"
+	"sync/atomic"
+	"unsafe"
+)
+
+// MapOf is like a Go map[K]V but is safe for concurrent use
+// by multiple goroutines without additional locking or coordination.
+// Loads, stores, and deletes run in amortized constant time.
+//
+// MapOf is a type-safe counterpart to [Map]. Unlike Map, it is not
+// specialized for append-only or disjoint-key workloads: it is
+// implemented as a concurrent hash-trie, in which reads never take a
+// lock and writes only lock the single trie node they modify, so
+// writers to different parts of the key space do not contend with one
+// another.
+//
+// The zero MapOf is empty and ready for use. A MapOf must not be copied
+// after first use.
+//
+// In the terminology of [the Go memory model], MapOf arranges that a
+// write operation “synchronizes before” any read operation that
+// observes the effect of the write, with the same definitions of read
+// and write operations as [Map].
+//
+// [the Go memory model]: https://go.dev/ref/mem
+type MapOf[K comparable, V any] struct {
+	_ noCopy
+
+	inited   atomic.Uint32
+	initMu   Mutex
+	root     atomic.Pointer[trieIndirect[K, V]]
+	keyHash  hashFunc
+	valEqual equalFunc
+	seed     uintptr
+}
+
+type hashFunc func(unsafe.Pointer, uintptr) uintptr
+type equalFunc func(unsafe.Pointer, unsafe.Pointer) bool
+
+func (m *MapOf[K, V]) init() {
+	if m.inited.Load() == 0 {
+		m.initSlow()
+	}
+}
+
+//go:noinline
+func (m *MapOf[K, V]) initSlow() {
+	m.initMu.Lock()
+	defer m.initMu.Unlock()
+
+	if m.inited.Load() != 0 {
+		// Someone got to it while we were waiting.
+		return
+	}
+
+	// Borrow the hash and equality functions the runtime uses
+	// for a map[K]V.
+	var mm map[K]V
+	mapType := abi.TypeOf(mm).MapType()
+	m.root.Store(newIndirectNode[K, V](nil))
+	m.keyHash = mapType.Hasher
+	m.valEqual = mapType.Elem.Equal
+	m.seed = uintptr(runtime_rand())
+
+	m.inited.Store(1)
+}
+
+func (m *MapOf[K, V]) hash(key *K) uintptr {
+	return m.keyHash(abi.NoEscape(unsafe.Pointer(key)), m.seed)
+}
+
+// Load returns the value stored in the map for a key, or the zero value if no
+// value is present.
+// The ok result indicates whether value was found in the map.
+func (m *MapOf[K, V]) Load(key K) (value V, ok bool) {
+	m.init()
+	hash := m.hash(&key)
+
+	i := m.root.Load()
+	hashShift := 8 * goarch.PtrSize
+	for hashShift != 0 {
+		hashShift -= nChildrenLog2
+
+		n := i.children[(hash>>hashShift)&nChildrenMask].Load()
+		if n == nil {
+			return *new(V), false
+		}
+		if n.isEntry {
+			return n.entry().lookup(key)
+		}
+		i = n.indirect()
+	}
+	panic("sync.MapOf: ran out of hash bits while iterating")
+}
+
+// Store sets the value for a key.
+func (m *MapOf[K, V]) Store(key K, value V) {
+	_, _ = m.Swap(key, value)
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *MapOf[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	m.init()
+	hash := m.hash(&key)
+
+	var i *trieIndirect[K, V]
+	var hashShift uint
+	var slot *atomic.Pointer[trieNode[K, V]]
+	var n *trieNode[K, V]
+	for {
+		// Find the key or a candidate location for insertion.
+		i = m.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		haveInsertPoint := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil {
+				// We found a nil slot which is a candidate for insertion.
+				haveInsertPoint = true
+				break
+			}
+			if n.isEntry {
+				// We found an existing entry, which is as far as we can go.
+				// If it stays this way, we'll have to replace it with an
+				// indirect node.
+				if v, ok := n.entry().lookup(key); ok {
+					return v, true
+				}
+				haveInsertPoint = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !haveInsertPoint {
+			panic("sync.MapOf: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if (n == nil || n.isEntry) && !i.dead.Load() {
+			// What we saw is still true, so we can continue with the insert.
+			break
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+	// N.B. This lock is held from when we broke out of the outer loop above.
+	defer i.mu.Unlock()
+
+	var oldEntry *trieEntry[K, V]
+	if n != nil {
+		oldEntry = n.entry()
+		if v, ok := oldEntry.lookup(key); ok {
+			// Easy case: by loading again, it turns out exactly what we wanted is here!
+			return v, true
+		}
+	}
+	newEntry := newEntryNode(key, value)
+	if oldEntry == nil {
+		// Easy case: create a new entry and store it.
+		slot.Store(&newEntry.trieNode)
+	} else {
+		// We possibly need to expand the entry already there into one or more new nodes.
+		//
+		// Publish the node last, which will make both oldEntry and newEntry visible. We
+		// don't want readers to be able to observe that oldEntry isn't in the tree.
+		slot.Store(m.expand(oldEntry, newEntry, hash, hashShift, i))
+	}
+	return value, false
+}
+
+// expand takes oldEntry and newEntry whose hashes conflict from the top bit
+// down to hashShift and produces a subtree of indirect nodes to hold the two
+// entries.
+func (m *MapOf[K, V]) expand(oldEntry, newEntry *trieEntry[K, V], newHash uintptr, hashShift uint, parent *trieIndirect[K, V]) *trieNode[K, V] {
+	// Check for a hash collision.
+	oldHash := m.hash(&oldEntry.key)
+	if oldHash == newHash {
+		// Store the old entry in the new entry's overflow list, then store
+		// the new entry.
+		newEntry.overflow.Store(oldEntry)
+		return &newEntry.trieNode
+	}
+	// We have to add an indirect node. Worse still, we may need to add more than one.
+	newIndirect := newIndirectNode(parent)
+	top := newIndirect
+	for {
+		if hashShift == 0 {
+			panic("sync.MapOf: ran out of hash bits while inserting")
+		}
+		hashShift -= nChildrenLog2 // hashShift is for the level parent is at. We need to go deeper.
+		oi := (oldHash >> hashShift) & nChildrenMask
+		ni := (newHash >> hashShift) & nChildrenMask
+		if oi != ni {
+			newIndirect.children[oi].Store(&oldEntry.trieNode)
+			newIndirect.children[ni].Store(&newEntry.trieNode)
+			break
+		}
+		nextIndirect := newIndirectNode(newIndirect)
+		newIndirect.children[oi].Store(&nextIndirect.trieNode)
+		newIndirect = nextIndirect
+	}
+	return &top.trieNode
+}
+
+// Swap swaps the value for a key and returns the previous value if any.
+// The loaded result reports whether the key was present.
+func (m *MapOf[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	m.init()
+	hash := m.hash(&key)
+
+	var i *trieIndirect[K, V]
+	var hashShift uint
+	var slot *atomic.Pointer[trieNode[K, V]]
+	var n *trieNode[K, V]
+	for {
+		// Find the key or a candidate location for insertion.
+		i = m.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		haveInsertPoint := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil || n.isEntry {
+				// We found a nil slot which is a candidate for insertion,
+				// or an existing entry that we'll replace.
+				haveInsertPoint = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !haveInsertPoint {
+			panic("sync.MapOf: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if (n == nil || n.isEntry) && !i.dead.Load() {
+			// What we saw is still true, so we can continue with the insert.
+			break
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+	// N.B. This lock is held from when we broke out of the outer loop above.
+	defer i.mu.Unlock()
+
+	var oldEntry *trieEntry[K, V]
+	if n != nil {
+		// Swap if the keys compare.
+		oldEntry = n.entry()
+		newEntry, old, swapped := oldEntry.swap(key, value)
+		if swapped {
+			slot.Store(&newEntry.trieNode)
+			return old, true
+		}
+	}
+	// The keys didn't compare, so we're doing an insertion.
+	newEntry := newEntryNode(key, value)
+	if oldEntry == nil {
+		// Easy case: create a new entry and store it.
+		slot.Store(&newEntry.trieNode)
+	} else {
+		// We possibly need to expand the entry already there into one or more new nodes.
+		slot.Store(m.expand(oldEntry, newEntry, hash, hashShift, i))
+	}
+	return *new(V), false
+}
+
+// CompareAndSwap swaps the old and new values for key
+// if the value stored in the map is equal to old.
+// The value type V must be comparable, otherwise CompareAndSwap panics.
+func (m *MapOf[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	m.init()
+	if m.valEqual == nil {
+		panic("sync.MapOf: called CompareAndSwap when value is not of comparable type")
+	}
+	hash := m.hash(&key)
+
+	// Find a node with the key and compare with it. n != nil if we found the node.
+	i, _, slot, n := m.find(key, hash, m.valEqual, old)
+	if i != nil {
+		defer i.mu.Unlock()
+	}
+	if n == nil {
+		return false
+	}
+
+	// Try to swap the entry.
+	e, swapped := n.entry().compareAndSwap(key, old, new, m.valEqual)
+	if !swapped {
+		// Nothing was actually swapped, which means the node is no longer there.
+		return false
+	}
+	// Store the entry back because it changed.
+	slot.Store(&e.trieNode)
+	return true
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (m *MapOf[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	m.init()
+	hash := m.hash(&key)
+
+	// Find a node with the key and compare with it. n != nil if we found the node.
+	i, hashShift, slot, n := m.find(key, hash, nil, *new(V))
+	if n == nil {
+		if i != nil {
+			i.mu.Unlock()
+		}
+		return *new(V), false
+	}
+
+	// Try to delete the entry.
+	v, e, loaded := n.entry().loadAndDelete(key)
+	if !loaded {
+		// Nothing was actually deleted, which means the node is no longer there.
+		i.mu.Unlock()
+		return *new(V), false
+	}
+	if e != nil {
+		// We didn't actually delete the whole entry, just one entry in the chain.
+		// Nothing else to do, since the parent is definitely not empty.
+		slot.Store(&e.trieNode)
+		i.mu.Unlock()
+		return v, true
+	}
+	// Delete the entry.
+	slot.Store(nil)
+	m.prune(i, hash, hashShift)
+	return v, true
+}
+
+// Delete deletes the value for a key.
+func (m *MapOf[K, V]) Delete(key K) {
+	_, _ = m.LoadAndDelete(key)
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// The value type V must be comparable, otherwise CompareAndDelete panics.
+//
+// If there is no current value for key in the map, CompareAndDelete
+// returns false (even if the old value is the zero value of V).
+func (m *MapOf[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	m.init()
+	if m.valEqual == nil {
+		panic("sync.MapOf: called CompareAndDelete when value is not of comparable type")
+	}
+	hash := m.hash(&key)
+
+	// Find a node with the key. n != nil if we found the node.
+	i, hashShift, slot, n := m.find(key, hash, nil, *new(V))
+	if n == nil {
+		if i != nil {
+			i.mu.Unlock()
+		}
+		return false
+	}
+
+	// Try to delete the entry.
+	e, deleted := n.entry().compareAndDelete(key, old, m.valEqual)
+	if !deleted {
+		// Nothing was actually deleted, which means the node is no longer there.
+		i.mu.Unlock()
+		return false
+	}
+	if e != nil {
+		// We didn't actually delete the whole entry, just one entry in the chain.
+		// Nothing else to do, since the parent is definitely not empty.
+		slot.Store(&e.trieNode)
+		i.mu.Unlock()
+		return true
+	}
+	// Delete the entry.
+	slot.Store(nil)
+	m.prune(i, hash, hashShift)
+	return true
+}
+
+// prune removes i and any of its ancestors that are left empty after a
+// deletion, stopping at the root. i.mu must be held; prune releases it.
+func (m *MapOf[K, V]) prune(i *trieIndirect[K, V], hash uintptr, hashShift uint) {
+	for i.parent != nil && i.empty() {
+		if hashShift == 8*goarch.PtrSize {
+			panic("sync.MapOf: ran out of hash bits while iterating")
+		}
+		hashShift += nChildrenLog2
+
+		// Delete the current node in the parent.
+		parent := i.parent
+		parent.mu.Lock()
+		i.dead.Store(true)
+		parent.children[(hash>>hashShift)&nChildrenMask].Store(nil)
+		i.mu.Unlock()
+		i = parent
+	}
+	i.mu.Unlock()
+}
+
+// find searches the tree for a node that contains key (hash must be the hash of key).
+// If valEqual != nil, then it will also enforce that the values are equal as well.
+//
+// Returns a non-nil node, which will always be an entry, if found.
+//
+// If i != nil then i.mu is locked, and it is the caller's responsibility to unlock it.
+func (m *MapOf[K, V]) find(key K, hash uintptr, valEqual equalFunc, value V) (i *trieIndirect[K, V], hashShift uint, slot *atomic.Pointer[trieNode[K, V]], n *trieNode[K, V]) {
+	for {
+		// Find the key or return if it's not there.
+		i = m.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		found := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil {
+				// Nothing to compare with. Give up.
+				i = nil
+				return
+			}
+			if n.isEntry {
+				// We found an entry. Check if it matches.
+				if _, ok := n.entry().lookupWithValue(key, value, valEqual); !ok {
+					// No match, comparison failed.
+					i = nil
+					n = nil
+					return
+				}
+				// We've got a match. Prepare to perform an operation on the key.
+				found = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !found {
+			panic("sync.MapOf: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if !i.dead.Load() && (n == nil || n.isEntry) {
+			// Either we've got a valid node or the node is now nil under the lock.
+			// In either case, we're done here.
+			return
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+}
+
+// All returns an iterator over each key and value present in the map.
+//
+// The iterator does not necessarily correspond to any consistent snapshot of the
+// MapOf's contents: no key will be visited more than once, but if the value
+// for any key is stored or deleted concurrently (including by yield), the iterator
+// may reflect any mapping for that key from any point during iteration. The iterator
+// does not block other methods on the receiver; even yield itself may call any
+// method on the MapOf.
+func (m *MapOf[K, V]) All() iter.Seq2[K, V] {
+	m.init()
+	return func(yield func(key K, value V) bool) {
+		m.iter(m.root.Load(), yield)
+	}
+}
+
+// Range calls f sequentially for each key and value present in the map.
+// If f returns false, Range stops the iteration.
+//
+// Range is equivalent to ranging over [MapOf.All] and has the same
+// consistency guarantees.
+func (m *MapOf[K, V]) Range(f func(key K, value V) bool) {
+	m.init()
+	m.iter(m.root.Load(), f)
+}
+
+func (m *MapOf[K, V]) iter(i *trieIndirect[K, V], yield func(key K, value V) bool) bool {
+	for j := range i.children {
+		n := i.children[j].Load()
+		if n == nil {
+			continue
+		}
+		if !n.isEntry {
+			if !m.iter(n.indirect(), yield) {
+				return false
+			}
+			continue
+		}
+		e := n.entry()
+		for e != nil {
+			if !yield(e.key, e.value) {
+				return false
+			}
+			e = e.overflow.Load()
+		}
+	}
+	return true
+}
+
+// Clear deletes all the entries, resulting in an empty MapOf.
+func (m *MapOf[K, V]) Clear() {
+	m.init()
+
+	// It's sufficient to just drop the root on the floor, but the root
+	// must always be non-nil.
+	m.root.Store(newIndirectNode[K, V](nil))
+}
+
+const (
+	// 16 children. This seems to be the sweet spot for
+	// load performance: any smaller and we lose out on
+	// 50% or more in CPU performance. Any larger and the
+	// returns are minuscule (~1% improvement for 32 children).
+	nChildrenLog2 = 4
+	nChildren     = 1 << nChildrenLog2
+	nChildrenMask = nChildren - 1
+)
+
+// trieIndirect is an internal node in the hash-trie.
+type trieIndirect[K comparable, V any] struct {
+	trieNode[K, V]
+	dead     atomic.Bool
+	mu       Mutex // Protects mutation to children and any children that are entry nodes.
+	parent   *trieIndirect[K, V]
+	children [nChildren]atomic.Pointer[trieNode[K, V]]
+}
+
+func newIndirectNode[K comparable, V any](parent *trieIndirect[K, V]) *trieIndirect[K, V] {
+	return &trieIndirect[K, V]{trieNode: trieNode[K, V]{isEntry: false}, parent: parent}
+}
+
+func (i *trieIndirect[K, V]) empty() bool {
+	for j := range i.children {
+		if i.children[j].Load() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// trieEntry is a leaf node in the hash-trie.
+type trieEntry[K comparable, V any] struct {
+	trieNode[K, V]
+	overflow atomic.Pointer[trieEntry[K, V]] // Overflow for hash collisions.
+	key      K
+	value    V
+}
+
+func newEntryNode[K comparable, V any](key K, value V) *trieEntry[K, V] {
+	return &trieEntry[K, V]{
+		trieNode: trieNode[K, V]{isEntry: true},
+		key:      key,
+		value:    value,
+	}
+}
+
+func (e *trieEntry[K, V]) lookup(key K) (V, bool) {
+	for e != nil {
+		if e.key == key {
+			return e.value, true
+		}
+		e = e.overflow.Load()
+	}
+	return *new(V), false
+}
+
+func (e *trieEntry[K, V]) lookupWithValue(key K, value V, valEqual equalFunc) (V, bool) {
+	for e != nil {
+		if e.key == key && (valEqual == nil || valEqual(unsafe.Pointer(&e.value), abi.NoEscape(unsafe.Pointer(&value)))) {
+			return e.value, true
+		}
+		e = e.overflow.Load()
+	}
+	return *new(V), false
+}
+
+// swap replaces an entry in the overflow chain if keys compare equal. Returns the new entry chain,
+// the old value, and whether or not anything was swapped.
+//
+// swap must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) swap(key K, new V) (*trieEntry[K, V], V, bool) {
+	if head.key == key {
+		// Return the new head of the list.
+		e := newEntryNode(key, new)
+		if chain := head.overflow.Load(); chain != nil {
+			e.overflow.Store(chain)
+		}
+		return e, head.value, true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if e.key == key {
+			eNew := newEntryNode(key, new)
+			eNew.overflow.Store(e.overflow.Load())
+			i.Store(eNew)
+			return head, e.value, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	var zero V
+	return head, zero, false
+}
+
+// compareAndSwap replaces an entry in the overflow chain if both the key and value compare
+// equal. Returns the new entry chain and whether or not anything was swapped.
+//
+// compareAndSwap must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) compareAndSwap(key K, old, new V, valEqual equalFunc) (*trieEntry[K, V], bool) {
+	if head.key == key && valEqual(unsafe.Pointer(&head.value), abi.NoEscape(unsafe.Pointer(&old))) {
+		// Return the new head of the list.
+		e := newEntryNode(key, new)
+		if chain := head.overflow.Load(); chain != nil {
+			e.overflow.Store(chain)
+		}
+		return e, true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if e.key == key && valEqual(unsafe.Pointer(&e.value), abi.NoEscape(unsafe.Pointer(&old))) {
+			eNew := newEntryNode(key, new)
+			eNew.overflow.Store(e.overflow.Load())
+			i.Store(eNew)
+			return head, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return head, false
+}
+
+// loadAndDelete deletes an entry in the overflow chain by key. Returns the value for the key, the new
+// entry chain and whether or not anything was loaded (and deleted).
+//
+// loadAndDelete must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) loadAndDelete(key K) (V, *trieEntry[K, V], bool) {
+	if head.key == key {
+		// Drop the head of the list.
+		return head.value, head.overflow.Load(), true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if e.key == key {
+			i.Store(e.overflow.Load())
+			return e.value, head, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return *new(V), head, false
+}
+
+// compareAndDelete deletes an entry in the overflow chain if both the key and value compare
+// equal. Returns the new entry chain and whether or not anything was deleted.
+//
+// compareAndDelete must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) compareAndDelete(key K, value V, valEqual equalFunc) (*trieEntry[K, V], bool) {
+	if head.key == key && valEqual(unsafe.Pointer(&head.value), abi.NoEscape(unsafe.Pointer(&value))) {
+		// Drop the head of the list.
+		return head.overflow.Load(), true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if e.key == key && valEqual(unsafe.Pointer(&e.value), abi.NoEscape(unsafe.Pointer(&value))) {
+			i.Store(e.overflow.Load())
+			return head, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return head, false
+}
+
+// trieNode is the header for a node. It's polymorphic and
+// is actually either a trieEntry or a trieIndirect.
+type trieNode[K comparable, V any] struct {
+	isEntry bool
+}
+
+func (n *trieNode[K, V]) entry() *trieEntry[K, V] {
+	if !n.isEntry {
+		panic("sync.MapOf: called entry on non-entry node")
+	}
+	return (*trieEntry[K, V])(unsafe.Pointer(n))
+}
+
+func (n *trieNode[K, V]) indirect() *trieIndirect[K, V] {
+	if n.isEntry {
+		panic("sync.MapOf: called indirect on entry node")
+	}
+	return (*trieIndirect[K, V])(unsafe.Pointer(n))
+}
+
+// from runtime
+//
+//go:linkname runtime_rand runtime.rand
+func runtime_rand() uint64
@@ -0,0 +1,520 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var _ mapInterface = &sync.MapOf[any, any]{}
+
+func TestMapOf(t *testing.T) {
+	testMapOf(t, func() *sync.MapOf[string, int] {
+		return new(sync.MapOf[string, int])
+	})
+}
+
+func TestMapOfBadHash(t *testing.T) {
+	testMapOf(t, func() *sync.MapOf[string, int] {
+		// Everything collides.
+		return sync.NewMapOfWithHasher[string, int](func(string, uintptr) uintptr {
+			return 0
+		})
+	})
+}
+
+func TestMapOfTruncHash(t *testing.T) {
+	testMapOf(t, func() *sync.MapOf[string, int] {
+		// Only the low bits of the hash are kept, so the trie is forced
+		// to build long chains of indirect nodes before keys diverge.
+		return sync.NewMapOfWithHasher[string, int](func(s string, _ uintptr) uintptr {
+			var h uintptr
+			for i := 0; i < len(s); i++ {
+				h = h*31 + uintptr(s[i])
+			}
+			return h & 0xff
+		})
+	})
+}
+
+func testMapOf(t *testing.T, newMap func() *sync.MapOf[string, int]) {
+	t.Run("LoadEmpty", func(t *testing.T) {
+		m := newMap()
+
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+	})
+	t.Run("LoadOrStore", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoaded(t, s, i)(m.LoadOrStore(s, 0))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoaded(t, s, i)(m.LoadOrStore(s, 0))
+		}
+	})
+	t.Run("All", func(t *testing.T) {
+		m := newMap()
+
+		testAll(t, m, testDataMap(testData[:]), func(_ string, _ int) bool {
+			return true
+		})
+	})
+	t.Run("Clear", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		m.Clear()
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+	})
+	t.Run("Store", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			m.Store(s, i)
+			expectPresent(t, s, i)(m.Load(s))
+			m.Store(s, i+1)
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+	})
+	t.Run("Swap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectNotLoadedFromSwap(t, s, i)(m.Swap(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoadedFromSwap(t, s, i, i+1)(m.Swap(s, i+1))
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectLoadedFromSwap(t, s, i+1, i)(m.Swap(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+		}
+	})
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+			if m.CompareAndSwap(s, i+1, i+2) {
+				t.Errorf("CompareAndSwap(%q, %d, %d) = true, want false", s, i+1, i+2)
+			}
+			if !m.CompareAndSwap(s, i, i+1) {
+				t.Errorf("CompareAndSwap(%q, %d, %d) = false, want true", s, i, i+1)
+			}
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			if !m.CompareAndSwap(s, i+1, i) {
+				t.Errorf("CompareAndSwap(%q, %d, %d) = false, want true", s, i+1, i)
+			}
+			expectPresent(t, s, i)(m.Load(s))
+		}
+		if m.CompareAndSwap("not in map", 0, 1) {
+			t.Errorf("CompareAndSwap on missing key = true, want false")
+		}
+	})
+	t.Run("LoadAndDelete", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoaded(t, s, i)(m.LoadAndDelete(s))
+			expectMissing(t, s, 0)(m.Load(s))
+			expectNotLoaded(t, s, 0)(m.LoadAndDelete(s))
+		}
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+	})
+	t.Run("Delete", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		// Delete in reverse to exercise the removal of entries from
+		// the middle of overflow chains.
+		for i := len(testData) - 1; i >= 0; i-- {
+			s := testData[i]
+			m.Delete(s)
+			expectMissing(t, s, 0)(m.Load(s))
+			for _, s := range testData[:i] {
+				if _, ok := m.Load(s); !ok {
+					t.Fatalf("key %q unexpectedly missing after deleting %q", s, testData[i])
+				}
+			}
+		}
+	})
+	t.Run("CompareAndDelete", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		for i, s := range testData {
+			if m.CompareAndDelete(s, i+1) {
+				t.Errorf("CompareAndDelete(%q, %d) = true, want false", s, i+1)
+			}
+			expectPresent(t, s, i)(m.Load(s))
+			if !m.CompareAndDelete(s, i) {
+				t.Errorf("CompareAndDelete(%q, %d) = false, want true", s, i)
+			}
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+		if m.CompareAndDelete("not in map", 0) {
+			t.Errorf("CompareAndDelete on missing key = true, want false")
+		}
+	})
+	t.Run("ConcurrentLifecycleUnsharedKeys", func(t *testing.T) {
+		m := newMap()
+
+		gmp := runtime.GOMAXPROCS(-1)
+		var wg sync.WaitGroup
+		for i := range gmp {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+
+				makeKey := func(s string) string {
+					return s + "-" + strconv.Itoa(id)
+				}
+				for _, s := range testData {
+					key := makeKey(s)
+					expectMissing(t, key, 0)(m.Load(key))
+					expectStored(t, key, id)(m.LoadOrStore(key, id))
+					expectPresent(t, key, id)(m.Load(key))
+					expectLoaded(t, key, id)(m.LoadOrStore(key, 0))
+				}
+				for _, s := range testData {
+					key := makeKey(s)
+					expectPresent(t, key, id)(m.Load(key))
+					expectLoaded(t, key, id)(m.LoadAndDelete(key))
+					expectMissing(t, key, 0)(m.Load(key))
+				}
+				for _, s := range testData {
+					key := makeKey(s)
+					expectMissing(t, key, 0)(m.Load(key))
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+	t.Run("ConcurrentSharedKeys", func(t *testing.T) {
+		m := newMap()
+
+		// Every goroutine races to increment the same counters with
+		// CompareAndSwap. No increment may be lost.
+		const rounds = 50
+		gmp := runtime.GOMAXPROCS(-1)
+		var wg sync.WaitGroup
+		for range gmp {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for range rounds {
+					for _, s := range testData[:64] {
+						for {
+							v, _ := m.LoadOrStore(s, 0)
+							if m.CompareAndSwap(s, v, v+1) {
+								break
+							}
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		for _, s := range testData[:64] {
+			expectPresent(t, s, gmp*rounds)(m.Load(s))
+		}
+	})
+	t.Run("ConcurrentDeleteDuringAll", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, s := range testData {
+				m.Delete(s)
+			}
+		}()
+		want := testDataMap(testData[:])
+		for k, v := range m.All() {
+			if want[k] != v {
+				t.Errorf("All yielded %q: %d, want %d", k, v, want[k])
+			}
+		}
+		wg.Wait()
+		for range m.All() {
+			t.Fatalf("All yielded an entry after all keys were deleted")
+		}
+	})
+}
+
+func testAll(t *testing.T, m *sync.MapOf[string, int], testData map[string]int, yield func(string, int) bool) {
+	for k, v := range testData {
+		expectStored(t, k, v)(m.LoadOrStore(k, v))
+	}
+	visited := make(map[string]int)
+	m.All()(func(key string, got int) bool {
+		want, ok := testData[key]
+		if !ok {
+			t.Errorf("unexpected key %q in map", key)
+			return false
+		}
+		if got != want {
+			t.Errorf("expected key %q to have value %d, got %d", key, want, got)
+			return false
+		}
+		visited[key]++
+		return yield(key, got)
+	})
+	for key, n := range visited {
+		if n > 1 {
+			t.Errorf("visited key %q more than once", key)
+		}
+	}
+	if len(visited) != len(testData) {
+		t.Errorf("visited %d keys, want %d", len(visited), len(testData))
+	}
+}
+
+func TestMapOfAllBreak(t *testing.T) {
+	var m sync.MapOf[string, int]
+	for i, s := range testData {
+		m.Store(s, i)
+	}
+	n := 0
+	for range m.All() {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("iterated over %d entries before break, want 10", n)
+	}
+}
+
+func TestMapOfNonComparableValue(t *testing.T) {
+	var m sync.MapOf[string, []int]
+	m.Store("a", []int{1})
+
+	for _, tt := range []struct {
+		name string
+		f    func()
+	}{
+		{"CompareAndSwap", func() { m.CompareAndSwap("a", nil, []int{2}) }},
+		{"CompareAndDelete", func() { m.CompareAndDelete("a", nil) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s on a MapOf with non-comparable values did not panic", tt.name)
+				}
+			}()
+			tt.f()
+		})
+	}
+}
+
+func TestMapOfFloatKeys(t *testing.T) {
+	var m sync.MapOf[float64, int]
+	m.Store(0.0, 1)
+	expectPresent(t, math.Copysign(0, -1), 1)(m.Load(math.Copysign(0, -1)))
+
+	// NaN is never equal to itself, so it can be stored but never found.
+	m.Store(math.NaN(), 2)
+	if _, ok := m.Load(math.NaN()); ok {
+		t.Errorf("Load(NaN) found a value")
+	}
+}
+
+func TestMapOfZeroAllocs(t *testing.T) {
+	var m sync.MapOf[string, int]
+	for i, s := range testData {
+		m.Store(s, i)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range testData {
+			m.Load(s)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Load allocated %v times per run, want 0", allocs)
+	}
+}
+
+func expectPresent[K, V comparable](t *testing.T, key K, want V) func(got V, ok bool) {
+	t.Helper()
+	return func(got V, ok bool) {
+		t.Helper()
+
+		if !ok {
+			t.Errorf("expected key %v to be present in map", key)
+		}
+		if ok && got != want {
+			t.Errorf("expected key %v to have value %v, got %v", key, want, got)
+		}
+	}
+}
+
+func expectMissing[K, V comparable](t *testing.T, key K, want V) func(got V, ok bool) {
+	t.Helper()
+	if want != *new(V) {
+		// This is awkward, but the want argument is necessary to smooth over type inference.
+		// Just make sure the want argument always looks the same.
+		panic("expectMissing must always have a zero value variable")
+	}
+	return func(got V, ok bool) {
+		t.Helper()
+
+		if ok {
+			t.Errorf("expected key %v to be missing from map, got value %v", key, got)
+		}
+		if !ok && got != want {
+			t.Errorf("expected missing key %v to be paired with the zero value; got %v", key, got)
+		}
+	}
+}
+
+func expectLoaded[K, V comparable](t *testing.T, key K, want V) func(got V, loaded bool) {
+	t.Helper()
+	return func(got V, loaded bool) {
+		t.Helper()
+
+		if !loaded {
+			t.Errorf("expected key %v to have been loaded, not stored", key)
+		}
+		if got != want {
+			t.Errorf("expected key %v to have value %v, got %v", key, want, got)
+		}
+	}
+}
+
+func expectStored[K, V comparable](t *testing.T, key K, want V) func(got V, loaded bool) {
+	t.Helper()
+	return func(got V, loaded bool) {
+		t.Helper()
+
+		if loaded {
+			t.Errorf("expected inserted key %v to have been stored, not loaded", key)
+		}
+		if got != want {
+			t.Errorf("expected inserted key %v to have value %v, got %v", key, want, got)
+		}
+	}
+}
+
+func expectNotLoaded[K, V comparable](t *testing.T, key K, _ V) func(old V, loaded bool) {
+	t.Helper()
+	return func(old V, loaded bool) {
+		t.Helper()
+
+		if loaded {
+			t.Errorf("expected key %v to not be present in map, got value %v", key, old)
+		}
+	}
+}
+
+func expectLoadedFromSwap[K, V comparable](t *testing.T, key K, want, new V) func(old V, loaded bool) {
+	t.Helper()
+	return func(old V, loaded bool) {
+		t.Helper()
+
+		if !loaded {
+			t.Errorf("expected key %v to be in map and for %v to have been swapped for %v", key, want, new)
+		} else if old != want {
+			t.Errorf("key %v had its value %v swapped for %v, but expected it to have value %v", key, old, new, want)
+		}
+	}
+}
+
+func expectNotLoadedFromSwap[K, V comparable](t *testing.T, key K, new V) func(old V, loaded bool) {
+	t.Helper()
+	return func(old V, loaded bool) {
+		t.Helper()
+
+		if loaded {
+			t.Errorf("expected key %v to not be in map, but found value %v for it", key, old)
+		}
+	}
+}
+
+func testDataMap(data []string) map[string]int {
+	m := make(map[string]int)
+	for i, s := range data {
+		m[s] = i
+	}
+	return m
+}
+
+var testData [128]string
+
+func init() {
+	for i := range testData {
+		testData[i] = fmt.Sprintf("%b", i)
+	}
+}
+
+// The benchmarks below use typed keys and values, which avoids the
+// interface conversions in the mapInterface-based benchmarks.
+
+func BenchmarkMapOfLoad(b *testing.B) {
+	var m sync.MapOf[string, int]
+	keys := make([]string, 1<<10)
+	for i := range keys {
+		keys[i] = strings.Repeat("k", i%8) + strconv.Itoa(i)
+		m.Store(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Load(keys[i&(len(keys)-1)])
+			i++
+		}
+	})
+}
+
+func BenchmarkMapOfStore(b *testing.B) {
+	var m sync.MapOf[int, int]
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Store(i&(1<<10-1), i)
+			i++
+		}
+	})
+}
@@ -11,7 +11,7 @@ import (
 
 // This file contains reference map implementations for unit-tests.
 
-// mapInterface is the interface Map implements.
+// mapInterface is the interface Map and MapOf[any, any] implement.
 type mapInterface interface {
 	Load(key any) (value any, ok bool)
 	Store(key, value any)
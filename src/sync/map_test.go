@@ -133,6 +133,10 @@ func applyDeepCopyMap(calls []mapCall) ([]mapResult, map[any]any) {
 	return applyCalls(new(DeepCopyMap), calls)
 }
 
+func applyMapOf(calls []mapCall) ([]mapResult, map[any]any) {
+	return applyCalls(new(sync.MapOf[any, any]), calls)
+}
+
 func TestMapMatchesRWMutex(t *testing.T) {
 	if err := quick.CheckEqual(applyMap, applyRWMutexMap, nil); err != nil {
 		t.Error(err)
@@ -145,6 +149,12 @@ func TestMapMatchesDeepCopy(t *testing.T) {
 	}
 }
 
+func TestMapOfMatchesRWMutex(t *testing.T) {
+	if err := quick.CheckEqual(applyMapOf, applyRWMutexMap, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestConcurrentRange(t *testing.T) {
 	const mapSize = 1 << 10
 
@@ -15,6 +15,8 @@ import (
 // goroutines to wait for. Then each of the goroutines
 // runs and calls [WaitGroup.Done] when finished. At the same time,
 // [WaitGroup.Wait] can be used to block until all goroutines have finished.
+// [WaitGroup.Go] combines the calls to Add and Done with starting
+// the goroutine.
 //
 // A WaitGroup must not be copied after first use.
 //
@@ -87,6 +89,32 @@ func (wg *WaitGroup) Done() {
 	wg.Add(-1)
 }
 
+// Go calls f in a new goroutine and adds that task to the [WaitGroup].
+// When f returns, the task is removed from the WaitGroup.
+//
+// It is equivalent to
+//
+//	wg.Add(1)
+//	go func() {
+//		defer wg.Done()
+//		f()
+//	}()
+//
+// and is subject to the same rules for calls to Add: the calls to Go
+// must happen before the corresponding call to Wait.
+//
+// Like any goroutine, the new goroutine inherits the profiler labels
+// of the calling goroutine. To name the goroutine in profiles, execution
+// traces, and (with GODEBUG=tracebacklabels=1) tracebacks, call Go from
+// within [runtime/pprof.Do], or call runtime/pprof.Do from within f.
+func (wg *WaitGroup) Go(f func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f()
+	}()
+}
+
 // Wait blocks until the [WaitGroup] counter is zero.
 func (wg *WaitGroup) Wait() {
 	if race.Enabled {
@@ -46,6 +46,20 @@ func TestWaitGroup(t *testing.T) {
 	}
 }
 
+func TestWaitGroupGo(t *testing.T) {
+	wg := &WaitGroup{}
+	var i atomic.Int32
+	for range 10 {
+		wg.Go(func() {
+			i.Add(1)
+		})
+	}
+	wg.Wait()
+	if got := i.Load(); got != 10 {
+		t.Fatalf("got %d goroutines run, want 10", got)
+	}
+}
+
 func TestWaitGroupMisuse(t *testing.T) {
 	defer func() {
 		err := recover()
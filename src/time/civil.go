@@ -0,0 +1,454 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import "errors"
+
+// A CivilDate is a date in the proleptic Gregorian calendar, such as a
+// birthday or the due date of an invoice, with no time of day and no time
+// zone. It does not describe an instant: a CivilDate begins and ends at
+// different instants in different locations, and it need not last 24 hours.
+//
+// A CivilDate is valid if Month is in the range [January, December] and
+// Day is in the range [1, number of days in Month]. The methods that
+// compute new dates accept invalid dates and normalize them as [Date]
+// does, so that October 32 is treated as November 1.
+//
+// CivilDate values are comparable with ==, and can be used as map keys.
+type CivilDate struct {
+	Year  int
+	Month Month
+	Day   int
+}
+
+// A CivilTime is a time of day, such as the opening time of a shop, with
+// no date and no time zone.
+//
+// A CivilTime is valid if its fields are in their usual ranges: Hour in
+// [0, 23], Minute in [0, 59], Second in [0, 59] and Nanosecond in
+// [0, 999999999]. It cannot represent a leap second.
+type CivilTime struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// A CivilDateTime is a date and a time of day, such as the start of a
+// recurring meeting, with no time zone. It is the wall clock reading an
+// event is scheduled for, and it becomes an instant only in a Location;
+// see [CivilDateTime.In].
+type CivilDateTime struct {
+	Date CivilDate
+	Time CivilTime
+}
+
+const (
+	civilTimeLayout     = "15:04:05.999999999"
+	civilDateTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+// CivilDateOf returns the date on which t occurs in its location.
+func CivilDateOf(t Time) CivilDate {
+	var d CivilDate
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// ParseCivilDate parses a date formatted according to layout, as
+// described by [Parse]. Elements of value beyond the date, such as a time
+// of day or a time zone, must be well-formed but are otherwise ignored.
+func ParseCivilDate(layout, value string) (CivilDate, error) {
+	t, err := parse(layout, value, UTC, nil)
+	if err != nil {
+		return CivilDate{}, err
+	}
+	return CivilDateOf(t), nil
+}
+
+// time returns the Time in UTC at d and clock.
+func (d CivilDate) time(clock CivilTime) Time {
+	return Date(d.Year, d.Month, d.Day, clock.Hour, clock.Minute, clock.Second, clock.Nanosecond, UTC)
+}
+
+// String returns d in the format "2006-01-02".
+func (d CivilDate) String() string {
+	return d.Format(DateOnly)
+}
+
+// Format returns a textual representation of d formatted according to
+// layout, as described by [Time.Format]. Elements of the layout for the
+// time of day format as midnight, and those for the time zone as UTC.
+func (d CivilDate) Format(layout string) string {
+	return d.time(CivilTime{}).Format(layout)
+}
+
+// AppendFormat is like [CivilDate.Format] but appends the textual
+// representation to b and returns the extended buffer.
+func (d CivilDate) AppendFormat(b []byte, layout string) []byte {
+	return d.time(CivilTime{}).AppendFormat(b, layout)
+}
+
+// IsValid reports whether d is a valid date.
+func (d CivilDate) IsValid() bool {
+	return January <= d.Month && d.Month <= December && 1 <= d.Day && d.Day <= daysIn(d.Month, d.Year)
+}
+
+// In returns the first instant of d in loc. That is midnight unless a
+// time zone transition skips midnight in loc on that date, in which case
+// it is the instant the skipped interval ends.
+//
+// In panics if loc is nil.
+func (d CivilDate) In(loc *Location) Time {
+	return CivilDateTime{Date: d}.In(loc)
+}
+
+// Weekday returns the day of the week of d.
+func (d CivilDate) Weekday() Weekday {
+	return d.time(CivilTime{}).Weekday()
+}
+
+// YearDay returns the day of the year of d, in the range [1,365] for
+// non-leap years, and [1,366] in leap years.
+func (d CivilDate) YearDay() int {
+	return d.time(CivilTime{}).YearDay()
+}
+
+// AddDays returns the date n days after d. n may be negative.
+func (d CivilDate) AddDays(n int) CivilDate {
+	return CivilDateOf(Date(d.Year, d.Month, d.Day+n, 0, 0, 0, 0, UTC))
+}
+
+// AddMonths returns the date n months after d. n may be negative.
+//
+// If the day of d does not exist in the resulting month, the result is
+// the last day of that month: adding one month to January 31 yields
+// February 28, or February 29 in a leap year. This differs from
+// [Time.AddDate], which overflows into the following month.
+func (d CivilDate) AddMonths(n int) CivilDate {
+	d = CivilDateOf(d.time(CivilTime{}))
+	year, m := norm(d.Year, int(d.Month)-1+n, 12)
+	month := Month(m) + 1
+	return CivilDate{year, month, min(d.Day, daysIn(month, year))}
+}
+
+// AddYears returns the date n years after d. n may be negative.
+// As with [CivilDate.AddMonths], February 29 becomes February 28 if the
+// resulting year is not a leap year.
+func (d CivilDate) AddYears(n int) CivilDate {
+	d = CivilDateOf(d.time(CivilTime{}))
+	year := d.Year + n
+	return CivilDate{year, d.Month, min(d.Day, daysIn(d.Month, year))}
+}
+
+// DaysSince returns the number of days from u to d, which is negative
+// if d is before u.
+func (d CivilDate) DaysSince(u CivilDate) int {
+	return int((d.time(CivilTime{}).Unix() - u.time(CivilTime{}).Unix()) / secondsPerDay)
+}
+
+// Before reports whether d is before u.
+func (d CivilDate) Before(u CivilDate) bool {
+	return d.Compare(u) < 0
+}
+
+// After reports whether d is after u.
+func (d CivilDate) After(u CivilDate) bool {
+	return d.Compare(u) > 0
+}
+
+// Compare compares d and u. If d is before u, it returns -1;
+// if d is after u, it returns +1; if they're the same, it returns 0.
+// Compare is only meaningful for valid dates.
+func (d CivilDate) Compare(u CivilDate) int {
+	if c := compareInt(d.Year, u.Year); c != 0 {
+		return c
+	}
+	if c := compareInt(int(d.Month), int(u.Month)); c != 0 {
+		return c
+	}
+	return compareInt(d.Day, u.Day)
+}
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+// The date is formatted as an RFC 3339 full-date, as by [CivilDate.String].
+// An invalid date, or one whose year is outside of the range [0,9999],
+// is reported as an error.
+func (d CivilDate) MarshalText() ([]byte, error) {
+	if !d.IsValid() {
+		return nil, errors.New("CivilDate.MarshalText: invalid date")
+	}
+	if d.Year < 0 || d.Year > 9999 {
+		return nil, errors.New("CivilDate.MarshalText: year outside of range [0,9999]")
+	}
+	return d.AppendFormat(make([]byte, 0, len(DateOnly)), DateOnly), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+// The date must be an RFC 3339 full-date.
+func (d *CivilDate) UnmarshalText(data []byte) error {
+	var err error
+	*d, err = ParseCivilDate(DateOnly, string(data))
+	return err
+}
+
+// CivilTimeOf returns the time of day at which t occurs in its location.
+func CivilTimeOf(t Time) CivilTime {
+	var c CivilTime
+	c.Hour, c.Minute, c.Second = t.Clock()
+	c.Nanosecond = t.Nanosecond()
+	return c
+}
+
+// ParseCivilTime parses a time of day formatted according to layout, as
+// described by [Parse]. Elements of value beyond the time of day, such as
+// a date or a time zone, must be well-formed but are otherwise ignored.
+func ParseCivilTime(layout, value string) (CivilTime, error) {
+	t, err := parse(layout, value, UTC, nil)
+	if err != nil {
+		return CivilTime{}, err
+	}
+	return CivilTimeOf(t), nil
+}
+
+// String returns t in the format "15:04:05.999999999".
+func (t CivilTime) String() string {
+	return t.Format(civilTimeLayout)
+}
+
+// Format returns a textual representation of t formatted according to
+// layout, as described by [Time.Format]. Elements of the layout for the
+// date format as January 1 of year 0, and those for the time zone as UTC.
+func (t CivilTime) Format(layout string) string {
+	return CivilDate{0, January, 1}.time(t).Format(layout)
+}
+
+// AppendFormat is like [CivilTime.Format] but appends the textual
+// representation to b and returns the extended buffer.
+func (t CivilTime) AppendFormat(b []byte, layout string) []byte {
+	return CivilDate{0, January, 1}.time(t).AppendFormat(b, layout)
+}
+
+// IsValid reports whether t is a valid time of day.
+func (t CivilTime) IsValid() bool {
+	return 0 <= t.Hour && t.Hour < 24 &&
+		0 <= t.Minute && t.Minute < 60 &&
+		0 <= t.Second && t.Second < 60 &&
+		0 <= t.Nanosecond && t.Nanosecond < 1e9
+}
+
+// Before reports whether t is before u.
+func (t CivilTime) Before(u CivilTime) bool {
+	return t.Compare(u) < 0
+}
+
+// After reports whether t is after u.
+func (t CivilTime) After(u CivilTime) bool {
+	return t.Compare(u) > 0
+}
+
+// Compare compares t and u. If t is before u, it returns -1;
+// if t is after u, it returns +1; if they're the same, it returns 0.
+// Compare is only meaningful for valid times of day.
+func (t CivilTime) Compare(u CivilTime) int {
+	if c := compareInt(t.Hour, u.Hour); c != 0 {
+		return c
+	}
+	if c := compareInt(t.Minute, u.Minute); c != 0 {
+		return c
+	}
+	if c := compareInt(t.Second, u.Second); c != 0 {
+		return c
+	}
+	return compareInt(t.Nanosecond, u.Nanosecond)
+}
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+// The time of day is formatted as by [CivilTime.String], which is an
+// RFC 3339 partial-time. An invalid time of day is reported as an error.
+func (t CivilTime) MarshalText() ([]byte, error) {
+	if !t.IsValid() {
+		return nil, errors.New("CivilTime.MarshalText: invalid time of day")
+	}
+	return t.AppendFormat(make([]byte, 0, len(civilTimeLayout)), civilTimeLayout), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+// The time of day must be in the format produced by [CivilTime.MarshalText].
+func (t *CivilTime) UnmarshalText(data []byte) error {
+	var err error
+	*t, err = ParseCivilTime(civilTimeLayout, string(data))
+	return err
+}
+
+// CivilDateTimeOf returns the date and time of day at which t occurs in
+// its location.
+func CivilDateTimeOf(t Time) CivilDateTime {
+	return CivilDateTime{CivilDateOf(t), CivilTimeOf(t)}
+}
+
+// ParseCivilDateTime parses a date and time of day formatted according to
+// layout, as described by [Parse]. A time zone in value must be
+// well-formed but is otherwise ignored: the result is the date and time
+// of day as written.
+func ParseCivilDateTime(layout, value string) (CivilDateTime, error) {
+	t, err := parse(layout, value, UTC, nil)
+	if err != nil {
+		return CivilDateTime{}, err
+	}
+	return CivilDateTimeOf(t), nil
+}
+
+// String returns dt in the format "2006-01-02T15:04:05.999999999".
+func (dt CivilDateTime) String() string {
+	return dt.Format(civilDateTimeLayout)
+}
+
+// Format returns a textual representation of dt formatted according to
+// layout, as described by [Time.Format]. Elements of the layout for the
+// time zone format as UTC.
+func (dt CivilDateTime) Format(layout string) string {
+	return dt.Date.time(dt.Time).Format(layout)
+}
+
+// AppendFormat is like [CivilDateTime.Format] but appends the textual
+// representation to b and returns the extended buffer.
+func (dt CivilDateTime) AppendFormat(b []byte, layout string) []byte {
+	return dt.Date.time(dt.Time).AppendFormat(b, layout)
+}
+
+// IsValid reports whether both the date and the time of day of dt
+// are valid.
+func (dt CivilDateTime) IsValid() bool {
+	return dt.Date.IsValid() && dt.Time.IsValid()
+}
+
+// In returns the instant at which the wall clock in loc reads dt.
+//
+// A time zone transition can skip or repeat wall clock times, and unlike
+// [Date], In resolves both cases deterministically. If dt occurs twice in
+// loc, In returns the earlier of the two instants. If dt does not occur
+// in loc, In moves it forward by the length of the skipped interval: in
+// the United States, March 13, 2011 2:15am, which never occurred, becomes
+// 3:15am EDT.
+//
+// In panics if loc is nil.
+func (dt CivilDateTime) In(loc *Location) Time {
+	if loc == nil {
+		panic("time: missing Location in call to CivilDateTime.In")
+	}
+	wall := dt.Date.time(dt.Time)
+	local, nsec := wall.unixSec(), wall.nsec()
+
+	// A zone that contains dt has an offset of less than a day, so it
+	// is the zone in effect at local, read as UTC, or one next to it.
+	type zone struct {
+		offset     int
+		start, end int64
+	}
+	var zones [3]zone
+	_, offset, start, end, _ := loc.lookup(local)
+	zones[0] = zone{offset, start, end}
+	zones[1] = zones[0]
+	zones[2] = zones[0]
+	if start != alpha {
+		_, offset, start, end, _ := loc.lookup(start - 1)
+		zones[0] = zone{offset, start, end}
+	}
+	if end != omega {
+		_, offset, start, end, _ := loc.lookup(end)
+		zones[2] = zone{offset, start, end}
+	}
+
+	// Zones are in chronological order, so the first match is the
+	// earliest.
+	unix, found := int64(0), false
+	for _, z := range zones {
+		if u := local - int64(z.offset); z.start <= u && u < z.end {
+			unix, found = u, true
+			break
+		}
+	}
+	if !found {
+		// dt falls in a gap between two zones. Read it with the offset
+		// of the earlier one, which lands after the gap.
+		unix = local - int64(offset)
+		for i := 0; i < len(zones)-1; i++ {
+			if u := local - int64(zones[i].offset); u >= zones[i].end && local-int64(zones[i+1].offset) < zones[i+1].start {
+				unix = u
+				break
+			}
+		}
+	}
+
+	t := unixTime(unix, nsec)
+	t.setLoc(loc)
+	return t
+}
+
+// AddDays returns dt with its date moved n days later, keeping its time
+// of day. n may be negative.
+func (dt CivilDateTime) AddDays(n int) CivilDateTime {
+	return CivilDateTime{dt.Date.AddDays(n), dt.Time}
+}
+
+// AddMonths returns dt with its date moved n months later, keeping its
+// time of day, and clamping the day as [CivilDate.AddMonths] does.
+// n may be negative.
+func (dt CivilDateTime) AddMonths(n int) CivilDateTime {
+	return CivilDateTime{dt.Date.AddMonths(n), dt.Time}
+}
+
+// Before reports whether dt is before u.
+func (dt CivilDateTime) Before(u CivilDateTime) bool {
+	return dt.Compare(u) < 0
+}
+
+// After reports whether dt is after u.
+func (dt CivilDateTime) After(u CivilDateTime) bool {
+	return dt.Compare(u) > 0
+}
+
+// Compare compares dt and u. If dt is before u, it returns -1;
+// if dt is after u, it returns +1; if they're the same, it returns 0.
+// Compare is only meaningful for valid values.
+func (dt CivilDateTime) Compare(u CivilDateTime) int {
+	if c := dt.Date.Compare(u.Date); c != 0 {
+		return c
+	}
+	return dt.Time.Compare(u.Time)
+}
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+// The value is formatted as by [CivilDateTime.String], which is an
+// RFC 3339 date-time without a time zone offset. An invalid value, or
+// one whose year is outside of the range [0,9999], is reported as an error.
+func (dt CivilDateTime) MarshalText() ([]byte, error) {
+	if !dt.IsValid() {
+		return nil, errors.New("CivilDateTime.MarshalText: invalid date or time of day")
+	}
+	if dt.Date.Year < 0 || dt.Date.Year > 9999 {
+		return nil, errors.New("CivilDateTime.MarshalText: year outside of range [0,9999]")
+	}
+	return dt.AppendFormat(make([]byte, 0, len(civilDateTimeLayout)), civilDateTimeLayout), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+// The value must be in the format produced by [CivilDateTime.MarshalText].
+func (dt *CivilDateTime) UnmarshalText(data []byte) error {
+	var err error
+	*dt, err = ParseCivilDateTime(civilDateTimeLayout, string(data))
+	return err
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return +1
+	}
+	return 0
+}
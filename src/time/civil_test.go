@@ -0,0 +1,344 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"encoding/json"
+	"testing"
+	. "time"
+)
+
+func TestCivilOf(t *testing.T) {
+	loc := FixedZone("", -5*60*60)
+	tm := Date(2024, March, 10, 23, 30, 15, 500, loc)
+	if got, want := CivilDateOf(tm), (CivilDate{2024, March, 10}); got != want {
+		t.Errorf("CivilDateOf(%v) = %v, want %v", tm, got, want)
+	}
+	if got, want := CivilTimeOf(tm), (CivilTime{23, 30, 15, 500}); got != want {
+		t.Errorf("CivilTimeOf(%v) = %v, want %v", tm, got, want)
+	}
+	dt := CivilDateTimeOf(tm)
+	if got := dt.In(loc); !got.Equal(tm) {
+		t.Errorf("CivilDateTimeOf(%v).In(loc) = %v", tm, got)
+	}
+	// The same instant falls on the next day in UTC.
+	if got, want := CivilDateOf(tm.UTC()), (CivilDate{2024, March, 11}); got != want {
+		t.Errorf("CivilDateOf(%v) = %v, want %v", tm.UTC(), got, want)
+	}
+}
+
+var civilDateArithmeticTests = []struct {
+	d      CivilDate
+	days   int
+	months int
+	want   CivilDate
+}{
+	{CivilDate{2024, January, 31}, 1, 0, CivilDate{2024, February, 1}},
+	{CivilDate{2024, February, 28}, 1, 0, CivilDate{2024, February, 29}},
+	{CivilDate{2023, December, 31}, 1, 0, CivilDate{2024, January, 1}},
+	{CivilDate{2024, March, 1}, -1, 0, CivilDate{2024, February, 29}},
+	{CivilDate{2024, January, 1}, 366, 0, CivilDate{2025, January, 1}},
+	{CivilDate{2024, October, 32}, 0, 0, CivilDate{2024, November, 1}},
+	{CivilDate{2024, January, 31}, 0, 1, CivilDate{2024, February, 29}},
+	{CivilDate{2023, January, 31}, 0, 1, CivilDate{2023, February, 28}},
+	{CivilDate{2024, March, 31}, 0, -1, CivilDate{2024, February, 29}},
+	{CivilDate{2024, May, 31}, 0, 1, CivilDate{2024, June, 30}},
+	{CivilDate{2024, December, 15}, 0, 1, CivilDate{2025, January, 15}},
+	{CivilDate{2024, January, 15}, 0, -13, CivilDate{2022, December, 15}},
+	{CivilDate{2024, August, 31}, 0, 30, CivilDate{2027, February, 28}},
+	{CivilDate{2024, October, 32}, 0, 1, CivilDate{2024, December, 1}},
+}
+
+func TestCivilDateArithmetic(t *testing.T) {
+	for _, tt := range civilDateArithmeticTests {
+		var got CivilDate
+		if tt.months != 0 {
+			got = tt.d.AddMonths(tt.months)
+		} else {
+			got = tt.d.AddDays(tt.days)
+		}
+		if got != tt.want {
+			t.Errorf("%v + %d days, %d months = %v, want %v", tt.d, tt.days, tt.months, got, tt.want)
+		}
+		if !got.IsValid() {
+			t.Errorf("%v + %d days, %d months = %v, which is not valid", tt.d, tt.days, tt.months, got)
+		}
+	}
+
+	leap := CivilDate{2024, February, 29}
+	if got, want := leap.AddYears(1), (CivilDate{2025, February, 28}); got != want {
+		t.Errorf("%v.AddYears(1) = %v, want %v", leap, got, want)
+	}
+	if got, want := leap.AddYears(4), (CivilDate{2028, February, 29}); got != want {
+		t.Errorf("%v.AddYears(4) = %v, want %v", leap, got, want)
+	}
+
+	for _, tt := range []struct {
+		d, u CivilDate
+		want int
+	}{
+		{CivilDate{2024, March, 1}, CivilDate{2024, February, 1}, 29},
+		{CivilDate{2023, March, 1}, CivilDate{2023, February, 1}, 28},
+		{CivilDate{1969, December, 31}, CivilDate{1970, January, 1}, -1},
+		{CivilDate{2000, January, 1}, CivilDate{1900, January, 1}, 36524},
+		{CivilDate{-1, January, 1}, CivilDate{0, January, 1}, -365},
+	} {
+		if got := tt.d.DaysSince(tt.u); got != tt.want {
+			t.Errorf("%v.DaysSince(%v) = %d, want %d", tt.d, tt.u, got, tt.want)
+		}
+		if got := tt.u.AddDays(tt.want); got != tt.d {
+			t.Errorf("%v.AddDays(%d) = %v, want %v", tt.u, tt.want, got, tt.d)
+		}
+	}
+
+	d := CivilDate{2024, March, 1}
+	if got, want := d.Weekday(), Friday; got != want {
+		t.Errorf("%v.Weekday() = %v, want %v", d, got, want)
+	}
+	if got, want := d.YearDay(), 31+29+1; got != want {
+		t.Errorf("%v.YearDay() = %d, want %d", d, got, want)
+	}
+}
+
+func TestCivilIsValid(t *testing.T) {
+	for _, tt := range []struct {
+		d    CivilDate
+		want bool
+	}{
+		{CivilDate{2024, February, 29}, true},
+		{CivilDate{2023, February, 29}, false},
+		{CivilDate{2024, April, 31}, false},
+		{CivilDate{2024, 0, 1}, false},
+		{CivilDate{2024, 13, 1}, false},
+		{CivilDate{2024, January, 0}, false},
+		{CivilDate{}, false},
+	} {
+		if got := tt.d.IsValid(); got != tt.want {
+			t.Errorf("%#v.IsValid() = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		c    CivilTime
+		want bool
+	}{
+		{CivilTime{}, true},
+		{CivilTime{23, 59, 59, 999999999}, true},
+		{CivilTime{24, 0, 0, 0}, false},
+		{CivilTime{0, 60, 0, 0}, false},
+		{CivilTime{0, 0, 60, 0}, false},
+		{CivilTime{0, 0, 0, 1e9}, false},
+		{CivilTime{-1, 0, 0, 0}, false},
+	} {
+		if got := tt.c.IsValid(); got != tt.want {
+			t.Errorf("%#v.IsValid() = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestCivilCompare(t *testing.T) {
+	dates := []CivilDate{{2023, December, 31}, {2024, January, 1}, {2024, January, 2}, {2024, February, 1}}
+	times := []CivilTime{{0, 0, 0, 0}, {0, 0, 0, 1}, {0, 0, 1, 0}, {0, 1, 0, 0}, {1, 0, 0, 0}}
+	for i, d := range dates {
+		for j, u := range dates {
+			if got, want := d.Compare(u), compareInts(i, j); got != want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", d, u, got, want)
+			}
+			if d.Before(u) != (i < j) || d.After(u) != (i > j) {
+				t.Errorf("%v.Before/After(%v) inconsistent", d, u)
+			}
+		}
+	}
+	for i, c := range times {
+		for j, u := range times {
+			if got, want := c.Compare(u), compareInts(i, j); got != want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", c, u, got, want)
+			}
+			if c.Before(u) != (i < j) || c.After(u) != (i > j) {
+				t.Errorf("%v.Before/After(%v) inconsistent", c, u)
+			}
+		}
+	}
+	late := CivilDateTime{dates[0], times[len(times)-1]}
+	early := CivilDateTime{dates[1], times[0]}
+	if !late.Before(early) || !early.After(late) || late.Compare(late) != 0 {
+		t.Errorf("CivilDateTime comparison of %v and %v is wrong", late, early)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return +1
+	}
+	return 0
+}
+
+func TestCivilFormat(t *testing.T) {
+	d := CivilDate{2024, March, 1}
+	c := CivilTime{15, 4, 5, 120000000}
+	dt := CivilDateTime{d, c}
+	for _, tt := range []struct {
+		got, want string
+	}{
+		{d.String(), "2024-03-01"},
+		{c.String(), "15:04:05.12"},
+		{CivilTime{9, 0, 0, 0}.String(), "09:00:00"},
+		{dt.String(), "2024-03-01T15:04:05.12"},
+		{d.Format("Monday, January 2, 2006"), "Friday, March 1, 2024"},
+		{d.Format(RFC3339), "2024-03-01T00:00:00Z"},
+		{c.Format(Kitchen), "3:04PM"},
+		{dt.Format(DateTime), "2024-03-01 15:04:05"},
+		{string(dt.AppendFormat([]byte("at "), Stamp)), "at Mar  1 15:04:05"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseCivil(t *testing.T) {
+	for _, tt := range []struct {
+		layout, value string
+		want          CivilDateTime
+	}{
+		{DateTime, "2024-03-01 15:04:05", CivilDateTime{CivilDate{2024, March, 1}, CivilTime{15, 4, 5, 0}}},
+		{RFC3339, "2024-03-01T15:04:05.5+05:00", CivilDateTime{CivilDate{2024, March, 1}, CivilTime{15, 4, 5, 500000000}}},
+		{RFC1123, "Fri, 01 Mar 2024 15:04:05 GMT+3", CivilDateTime{CivilDate{2024, March, 1}, CivilTime{15, 4, 5, 0}}},
+		{RFC1123, "Fri, 01 Mar 2024 23:04:05 PST", CivilDateTime{CivilDate{2024, March, 1}, CivilTime{23, 4, 5, 0}}},
+	} {
+		got, err := ParseCivilDateTime(tt.layout, tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseCivilDateTime(%q, %q) = %v, %v, want %v", tt.layout, tt.value, got, err, tt.want)
+		}
+		d, err := ParseCivilDate(tt.layout, tt.value)
+		if err != nil || d != tt.want.Date {
+			t.Errorf("ParseCivilDate(%q, %q) = %v, %v, want %v", tt.layout, tt.value, d, err, tt.want.Date)
+		}
+		c, err := ParseCivilTime(tt.layout, tt.value)
+		if err != nil || c != tt.want.Time {
+			t.Errorf("ParseCivilTime(%q, %q) = %v, %v, want %v", tt.layout, tt.value, c, err, tt.want.Time)
+		}
+	}
+
+	if c, err := ParseCivilTime(Kitchen, "3:04PM"); err != nil || c != (CivilTime{15, 4, 0, 0}) {
+		t.Errorf("ParseCivilTime(Kitchen, %q) = %v, %v", "3:04PM", c, err)
+	}
+	for _, value := range []string{"2023-02-29", "2024-13-01", "2024-3-1", "2024-03-01x"} {
+		if d, err := ParseCivilDate(DateOnly, value); err == nil {
+			t.Errorf("ParseCivilDate(DateOnly, %q) = %v, want error", value, d)
+		}
+	}
+	if c, err := ParseCivilTime(TimeOnly, "24:00:00"); err == nil {
+		t.Errorf("ParseCivilTime(TimeOnly, %q) = %v, want error", "24:00:00", c)
+	}
+}
+
+func TestCivilJSON(t *testing.T) {
+	type event struct {
+		Date CivilDate
+		Time CivilTime
+		At   CivilDateTime
+	}
+	e := event{
+		CivilDate{2024, March, 1},
+		CivilTime{9, 30, 0, 0},
+		CivilDateTime{CivilDate{1999, December, 31}, CivilTime{23, 59, 59, 999000000}},
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"Date":"2024-03-01","Time":"09:30:00","At":"1999-12-31T23:59:59.999"}`
+	if string(b) != want {
+		t.Errorf("json.Marshal = %s, want %s", b, want)
+	}
+	var got event
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != e {
+		t.Errorf("json.Unmarshal = %v, want %v", got, e)
+	}
+
+	for _, v := range []any{
+		CivilDate{2023, February, 29},
+		CivilDate{10000, January, 1},
+		CivilDate{-1, January, 1},
+		CivilTime{24, 0, 0, 0},
+		CivilDateTime{CivilDate{2024, March, 1}, CivilTime{0, 0, 0, -1}},
+		CivilDateTime{CivilDate{10000, March, 1}, CivilTime{}},
+	} {
+		if b, err := json.Marshal(v); err == nil {
+			t.Errorf("json.Marshal(%#v) = %s, want error", v, b)
+		}
+	}
+	for _, data := range []string{`"2024-03-01T00:00:00"`, `"2024-02-30"`, `""`} {
+		var d CivilDate
+		if err := json.Unmarshal([]byte(data), &d); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want error", data, d)
+		}
+	}
+}
+
+func TestCivilIn(t *testing.T) {
+	undo := DisablePlatformSources()
+	defer undo()
+
+	ny, err := LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saoPaulo, err := LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		dt   CivilDateTime
+		loc  *Location
+		want string
+	}{
+		// Ordinary times.
+		{CivilDateTime{CivilDate{2011, July, 4}, CivilTime{12, 0, 0, 0}}, ny, "2011-07-04T12:00:00-04:00"},
+		{CivilDateTime{CivilDate{2011, January, 4}, CivilTime{12, 0, 0, 5}}, ny, "2011-01-04T12:00:00.000000005-05:00"},
+		// Skipped: 2am-3am on March 13, 2011.
+		{CivilDateTime{CivilDate{2011, March, 13}, CivilTime{2, 15, 0, 0}}, ny, "2011-03-13T03:15:00-04:00"},
+		{CivilDateTime{CivilDate{2011, March, 13}, CivilTime{1, 59, 59, 0}}, ny, "2011-03-13T01:59:59-05:00"},
+		{CivilDateTime{CivilDate{2011, March, 13}, CivilTime{3, 0, 0, 0}}, ny, "2011-03-13T03:00:00-04:00"},
+		// Repeated: 1am-2am on November 6, 2011.
+		{CivilDateTime{CivilDate{2011, November, 6}, CivilTime{1, 15, 0, 0}}, ny, "2011-11-06T01:15:00-04:00"},
+		{CivilDateTime{CivilDate{2011, November, 6}, CivilTime{2, 0, 0, 0}}, ny, "2011-11-06T02:00:00-05:00"},
+		// Midnight skipped on November 4, 2018.
+		{CivilDateTime{Date: CivilDate{2018, November, 4}}, saoPaulo, "2018-11-04T01:00:00-02:00"},
+		// A fixed zone.
+		{CivilDateTime{CivilDate{2024, March, 1}, CivilTime{8, 0, 0, 0}}, FixedZone("", 5*60*60+30*60), "2024-03-01T08:00:00+05:30"},
+		{CivilDateTime{CivilDate{2024, March, 1}, CivilTime{8, 0, 0, 0}}, UTC, "2024-03-01T08:00:00Z"},
+	} {
+		got := tt.dt.In(tt.loc)
+		if s := got.Format(RFC3339Nano); s != tt.want {
+			t.Errorf("%v.In(%v) = %s, want %s", tt.dt, tt.loc, s, tt.want)
+		}
+		if got.Location() != tt.loc && tt.loc != UTC {
+			t.Errorf("%v.In(%v) has location %v", tt.dt, tt.loc, got.Location())
+		}
+	}
+
+	d := CivilDate{2018, November, 4}
+	if got, want := d.In(saoPaulo).Format(RFC3339), "2018-11-04T01:00:00-02:00"; got != want {
+		t.Errorf("%v.In(%v) = %s, want %s", d, saoPaulo, got, want)
+	}
+	// A day across a transition is not 24 hours long.
+	if got, want := d.AddDays(1).In(saoPaulo).Sub(d.In(saoPaulo)), 23*Hour; got != want {
+		t.Errorf("length of %v in %v = %v, want %v", d, saoPaulo, got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("In(nil) did not panic")
+		}
+	}()
+	d.In(nil)
+}
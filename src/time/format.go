@@ -1029,6 +1029,10 @@ func ParseInLocation(layout, value string, loc *Location) (Time, error) {
 	return parse(layout, value, loc, loc)
 }
 
+// parse parses value according to layout. If local is nil, any time zone
+// information in value is checked but otherwise ignored, and parse returns
+// the date and time of day exactly as written, in UTC; this is how civil
+// dates and times are parsed.
 func parse(layout, value string, defaultLocation, local *Location) (Time, error) {
 	alayout, avalue := layout, value
 	rangeErrString := "" // set if a value is out of range
@@ -1351,6 +1355,10 @@ func parse(layout, value string, defaultLocation, local *Location) (Time, error)
 		return Time{}, newParseError(alayout, avalue, "", value, ": day out of range")
 	}
 
+	if local == nil {
+		return Date(year, Month(month), day, hour, min, sec, nsec, UTC), nil
+	}
+
 	if z != nil {
 		return Date(year, Month(month), day, hour, min, sec, nsec, z), nil
 	}
@@ -0,0 +1,339 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import "sync"
+
+// A Wheel manages a large number of coarse-grained, func-based timers,
+// such as the idle timeouts of many network connections.
+//
+// Every [Timer] is scheduled individually by the runtime, and starting,
+// stopping or resetting it costs a logarithmic-time update of a timer heap.
+// A Wheel instead sorts its timers into a hierarchical timing wheel
+// with a fixed granularity, its tick: starting, stopping and resetting a
+// [WheelTimer] take constant time and allocate nothing beyond the
+// WheelTimer itself, and the whole Wheel occupies a single runtime timer.
+// The price is precision. A WheelTimer fires on a tick boundary, no earlier
+// than requested and up to about one tick late.
+//
+// Like [Timer], a Wheel measures time using the monotonic clock and is
+// unaffected by changes to the wall clock.
+//
+// A Wheel must be created with [NewWheel]. It is safe for concurrent use
+// by multiple goroutines.
+type Wheel struct {
+	tick  int64 // granularity in nanoseconds
+	start int64 // runtimeNano() at tick 0
+
+	mu      sync.Mutex
+	now     int64 // last tick processed
+	len     int   // number of pending timers
+	len0    int   // number of pending timers in level 0
+	next    int64 // tick the driver is set to fire at; 0 if stopped
+	driver  *Timer
+	buckets [wheelLevels][wheelSlots]wheelBucket
+}
+
+// A wheel has wheelLevels levels of wheelSlots buckets. A bucket at level
+// k spans wheelSlots^k ticks, so that the whole wheel spans
+// wheelSlots^wheelLevels = 2^48 ticks.
+const (
+	wheelBits   = 6
+	wheelSlots  = 1 << wheelBits
+	wheelMask   = wheelSlots - 1
+	wheelLevels = 8
+	wheelSpan   = 1 << (wheelBits * wheelLevels)
+)
+
+// A wheelBucket is a doubly-linked list of the timers due within
+// the bucket's span of ticks.
+type wheelBucket struct {
+	head *WheelTimer
+}
+
+// A WheelTimer is a timer managed by a [Wheel].
+// A WheelTimer must be created with [Wheel.AfterFunc].
+type WheelTimer struct {
+	w      *Wheel
+	f      func()
+	when   int64        // tick at which the timer fires
+	bucket *wheelBucket // nil if not pending
+	level  int
+	prev   *WheelTimer
+	next   *WheelTimer
+}
+
+// NewWheel returns a new Wheel whose timers fire on multiples of tick.
+// NewWheel panics if tick is not positive.
+func NewWheel(tick Duration) *Wheel {
+	if tick <= 0 {
+		panic("time: non-positive tick for NewWheel")
+	}
+	return &Wheel{tick: int64(tick), start: runtimeNano()}
+}
+
+// Tick returns the granularity of w's timers.
+func (w *Wheel) Tick() Duration {
+	return Duration(w.tick)
+}
+
+// Len returns the number of pending timers in w.
+func (w *Wheel) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.len
+}
+
+// AfterFunc waits for at least the duration d to elapse and then calls f
+// in its own goroutine. It returns a [WheelTimer] that can be used to
+// cancel the call using its Stop method, or reschedule it using its
+// Reset method.
+func (w *Wheel) AfterFunc(d Duration, f func()) *WheelTimer {
+	t := &WheelTimer{w: w, f: f}
+	w.mu.Lock()
+	t.when = w.whenTick(d)
+	w.startDriver(w.insert(t))
+	w.mu.Unlock()
+	return t
+}
+
+// Stop prevents t from firing.
+// It returns true if the call stops the timer, false if the timer has
+// already expired or been stopped.
+//
+// As with [Timer.Stop] for timers created with [AfterFunc], if Stop
+// returns false then the function has already been started in its own
+// goroutine, and Stop does not wait for it to complete.
+func (t *WheelTimer) Stop() bool {
+	if t.w == nil {
+		panic("time: Stop called on uninitialized WheelTimer")
+	}
+	w := t.w
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t.bucket == nil {
+		return false
+	}
+	w.remove(t)
+	return true
+}
+
+// Reset changes t to expire after duration d.
+// It returns true if the timer had been active, false if the timer had
+// expired or been stopped. In the latter case the function is scheduled
+// to run again, with the same caveats as for [Timer.Reset].
+//
+// Resetting a pending timer does not allocate and takes constant time.
+// Pushing it further into the future, as is typical of an idle timeout,
+// is cheaper still.
+func (t *WheelTimer) Reset(d Duration) bool {
+	if t.w == nil {
+		panic("time: Reset called on uninitialized WheelTimer")
+	}
+	w := t.w
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	when := w.whenTick(d)
+	if t.bucket == nil {
+		t.when = when
+		w.startDriver(w.insert(t))
+		return false
+	}
+	if when >= t.when {
+		// Leave t in its bucket, which is now reached early;
+		// run moves t on from there.
+		t.when = when
+		return true
+	}
+	w.remove(t)
+	t.when = when
+	w.startDriver(w.insert(t))
+	return true
+}
+
+// whenTick returns the tick at which a timer started now should fire
+// after duration d.
+// w.mu must be held.
+func (w *Wheel) whenTick(d Duration) int64 {
+	now := runtimeNano() - w.start
+	if w.len == 0 {
+		// The driver is not running, so w.now may be arbitrarily
+		// far behind. Nothing needs processing: catch up.
+		w.now = now / w.tick
+	}
+	if d < 0 {
+		d = 0
+	}
+	// Round up, so that the timer never fires early.
+	end := now + int64(d)
+	if end < now {
+		end = 1<<63 - 1 // math.MaxInt64
+	}
+	when := end / w.tick
+	if end%w.tick != 0 {
+		when++
+	}
+	// Ticks up to w.now have already been processed.
+	return max(when, w.now+1)
+}
+
+// insert adds t to the bucket for t.when, relative to w.now.
+// It returns the tick at which that bucket is processed.
+// w.mu must be held.
+func (w *Wheel) insert(t *WheelTimer) int64 {
+	when := t.when
+	delta := when - w.now
+	if delta >= wheelSpan {
+		// Park the timer in the farthest bucket; it is
+		// re-inserted from there when that bucket is reached.
+		when = w.now + wheelSpan - 1
+		delta = wheelSpan - 1
+	}
+	level := 0
+	for delta >= wheelSlots {
+		delta >>= wheelBits
+		level++
+	}
+	shift := wheelBits * level
+	b := &w.buckets[level][(when>>shift)&wheelMask]
+	t.bucket = b
+	t.level = level
+	t.prev = nil
+	t.next = b.head
+	if b.head != nil {
+		b.head.prev = t
+	}
+	b.head = t
+	w.len++
+	if level == 0 {
+		w.len0++
+	}
+	return when >> shift << shift
+}
+
+// remove removes t from its bucket.
+// w.mu must be held.
+func (w *Wheel) remove(t *WheelTimer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		t.bucket.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+	t.bucket = nil
+	t.prev = nil
+	t.next = nil
+	w.len--
+	if t.level == 0 {
+		w.len0--
+	}
+}
+
+// startDriver makes sure the driver fires no later than tick when.
+// w.mu must be held.
+func (w *Wheel) startDriver(when int64) {
+	if w.next != 0 && w.next <= when {
+		return
+	}
+	w.next = when
+	d := Duration(1<<63 - 1)
+	if when < (1<<63-1-w.start)/w.tick {
+		d = Duration(w.start + when*w.tick - runtimeNano())
+	}
+	if w.driver == nil {
+		w.driver = AfterFunc(d, w.run)
+	} else {
+		w.driver.Reset(d)
+	}
+}
+
+// run advances w to the current tick, starting the functions of the
+// timers that have expired, and sets the driver for the next tick that
+// needs processing.
+func (w *Wheel) run() {
+	w.mu.Lock()
+	now := (runtimeNano() - w.start) / w.tick
+	var expired []func()
+	for {
+		// Skip straight to the next tick with work to do.
+		next := w.nextTick()
+		if next == 0 || next > now {
+			break
+		}
+		w.now = next
+		if next&wheelMask == 0 {
+			w.cascade(1)
+		}
+		b := &w.buckets[0][next&wheelMask]
+		for b.head != nil {
+			t := b.head
+			w.remove(t)
+			if t.when > next {
+				// Reset to a later tick.
+				w.insert(t)
+				continue
+			}
+			expired = append(expired, t.f)
+		}
+	}
+	w.now = now
+
+	w.next = 0
+	if next := w.nextTick(); next != 0 {
+		w.startDriver(next)
+	}
+	w.mu.Unlock()
+
+	for _, f := range expired {
+		go f()
+	}
+}
+
+// cascade re-inserts the timers in the current bucket of the given level
+// into the lower levels, after cascading the levels above it if their
+// current bucket also starts at w.now.
+// w.mu must be held.
+func (w *Wheel) cascade(level int) {
+	if level >= wheelLevels {
+		return
+	}
+	slot := (w.now >> (wheelBits * level)) & wheelMask
+	if slot == 0 {
+		w.cascade(level + 1)
+	}
+	b := &w.buckets[level][slot]
+	for b.head != nil {
+		t := b.head
+		w.remove(t)
+		w.insert(t)
+	}
+}
+
+// nextTick returns the next tick after w.now at which w needs
+// processing, or 0 if w has no pending timers. That is the earliest
+// tick at which a non-empty bucket at any level is reached.
+// w.mu must be held.
+func (w *Wheel) nextTick() int64 {
+	if w.len == 0 {
+		return 0
+	}
+	next := int64(1<<63 - 1)
+	for level := range wheelLevels {
+		if level > 0 && w.len == w.len0 {
+			break
+		}
+		shift := wheelBits * level
+		base := w.now >> shift
+		for i := int64(1); i <= wheelSlots; i++ {
+			if w.buckets[level][(base+i)&wheelMask].head != nil {
+				next = min(next, (base+i)<<shift)
+				break
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,273 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	. "time"
+)
+
+func TestWheelAfterFunc(t *testing.T) {
+	const tick = 200 * Microsecond
+	w := NewWheel(tick)
+	if got := w.Tick(); got != tick {
+		t.Fatalf("Tick() = %v, want %v", got, tick)
+	}
+
+	// Durations within level 0, and ones that need
+	// cascading from levels 1 and 2.
+	delays := []Duration{0, 1, tick, 3*tick + 1, 70 * tick, 200 * tick, 4100 * tick}
+	if testing.Short() {
+		delays = delays[:len(delays)-1]
+	}
+	var wg sync.WaitGroup
+	for _, d := range delays {
+		wg.Add(1)
+		start := Now()
+		w.AfterFunc(d, func() {
+			defer wg.Done()
+			if elapsed := Since(start); elapsed < d {
+				t.Errorf("AfterFunc(%v) fired after %v", d, elapsed)
+			}
+		})
+	}
+	if got := w.Len(); got != len(delays) {
+		t.Errorf("Len() = %d, want %d", got, len(delays))
+	}
+	wg.Wait()
+	if got := w.Len(); got != 0 {
+		t.Errorf("Len() after firing = %d, want 0", got)
+	}
+}
+
+func TestWheelOrder(t *testing.T) {
+	const (
+		tick = Millisecond
+		n    = 300
+	)
+	w := NewWheel(tick)
+	var (
+		mu    sync.Mutex
+		fired []int
+		wg    sync.WaitGroup
+	)
+	// Schedule in reverse order; timers in different ticks must
+	// fire in order of expiry.
+	for i := n - 1; i >= 0; i-- {
+		wg.Add(1)
+		w.AfterFunc(Duration(i)*2*tick, func() {
+			mu.Lock()
+			fired = append(fired, i)
+			mu.Unlock()
+			wg.Done()
+		})
+	}
+	wg.Wait()
+	if len(fired) != n {
+		t.Fatalf("%d timers fired, want %d", len(fired), n)
+	}
+	// Each timer is started in its own goroutine, so neighbors may
+	// race; but no timer may fire long after a later one.
+	for i, v := range fired {
+		if d := v - i; d > 10 || d < -10 {
+			t.Fatalf("timer %d fired in position %d: %v", v, i, fired)
+		}
+	}
+}
+
+func TestWheelStopReset(t *testing.T) {
+	w := NewWheel(Millisecond)
+	var fired atomic.Int32
+	f := func() { fired.Add(1) }
+
+	tm := w.AfterFunc(Hour, f)
+	if !tm.Stop() {
+		t.Errorf("Stop of pending timer = false, want true")
+	}
+	if tm.Stop() {
+		t.Errorf("Stop of stopped timer = true, want false")
+	}
+	if tm.Reset(Hour) {
+		t.Errorf("Reset of stopped timer = true, want false")
+	}
+	if !tm.Reset(10 * Millisecond) {
+		t.Errorf("Reset of pending timer = false, want true")
+	}
+	for fired.Load() == 0 {
+		Sleep(Millisecond)
+	}
+	if tm.Stop() {
+		t.Errorf("Stop of expired timer = true, want false")
+	}
+	if tm.Reset(0) {
+		t.Errorf("Reset of expired timer = true, want false")
+	}
+	for fired.Load() == 1 {
+		Sleep(Millisecond)
+	}
+	if w.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", w.Len())
+	}
+
+	// A timer pushed back repeatedly only fires once.
+	fired.Store(0)
+	tm = w.AfterFunc(20*Millisecond, f)
+	for range 10 {
+		Sleep(5 * Millisecond)
+		if !tm.Reset(20 * Millisecond) {
+			t.Fatalf("timer fired despite Reset")
+		}
+	}
+	Sleep(50 * Millisecond)
+	if n := fired.Load(); n != 1 {
+		t.Errorf("timer fired %d times, want 1", n)
+	}
+}
+
+func TestWheelFarFuture(t *testing.T) {
+	// With a 1ns tick, 200 hours is beyond the span of the wheel.
+	w := NewWheel(1)
+	tm := w.AfterFunc(200*Hour, func() { t.Errorf("timer fired") })
+	near := make(chan bool)
+	w.AfterFunc(Millisecond, func() { close(near) })
+	<-near
+	if got := w.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+	if !tm.Stop() {
+		t.Errorf("Stop of far-future timer = false, want true")
+	}
+	w.AfterFunc(1<<63-1, func() { t.Errorf("timer fired") }).Stop()
+}
+
+func TestWheelConcurrent(t *testing.T) {
+	w := NewWheel(Millisecond)
+	// Every scheduled call must either fire or be stopped, once.
+	var scheduled, fired, stopped atomic.Int64
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timers := make([]*WheelTimer, 200)
+			for i := range timers {
+				scheduled.Add(1)
+				timers[i] = w.AfterFunc(Duration(i%20)*Millisecond, func() { fired.Add(1) })
+			}
+			for i, tm := range timers {
+				if i%2 == 0 {
+					if tm.Stop() {
+						stopped.Add(1)
+					}
+				} else if !tm.Reset(Duration(i%7) * Millisecond) {
+					scheduled.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for fired.Load()+stopped.Load() < scheduled.Load() {
+		Sleep(Millisecond)
+	}
+	Sleep(10 * Millisecond)
+	if got, want := fired.Load()+stopped.Load(), scheduled.Load(); got != want {
+		t.Errorf("%d timers fired or stopped, want %d", got, want)
+	}
+	if got := w.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestWheelNonPositiveTick(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewWheel(0) did not panic")
+		}
+	}()
+	NewWheel(0)
+}
+
+// The following benchmarks model the idle timeouts of many connections:
+// each one holds a timer that is pushed back whenever it sees traffic.
+
+const benchmarkConns = 1 << 16
+
+func BenchmarkIdleTimeouts(b *testing.B) {
+	b.Run("impl=Timer", func(b *testing.B) {
+		b.ReportAllocs()
+		timers := make([]*Timer, benchmarkConns)
+		for i := range timers {
+			timers[i] = AfterFunc(Hour, func() {})
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				timers[i%benchmarkConns].Reset(Hour)
+			}
+		})
+		b.StopTimer()
+		for _, t := range timers {
+			t.Stop()
+		}
+	})
+	b.Run("impl=Wheel", func(b *testing.B) {
+		b.ReportAllocs()
+		w := NewWheel(Second)
+		timers := make([]*WheelTimer, benchmarkConns)
+		for i := range timers {
+			timers[i] = w.AfterFunc(Hour, func() {})
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				timers[i%benchmarkConns].Reset(Hour)
+			}
+		})
+		b.StopTimer()
+		for _, t := range timers {
+			t.Stop()
+		}
+	})
+}
+
+func BenchmarkStartStopTimeouts(b *testing.B) {
+	b.Run("impl=Timer", func(b *testing.B) {
+		b.ReportAllocs()
+		timers := make([]*Timer, benchmarkConns)
+		for i := range timers {
+			timers[i] = AfterFunc(Hour, nil)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// A connection closes and another one opens.
+			timers[i%benchmarkConns].Stop()
+			timers[i%benchmarkConns] = AfterFunc(Hour, nil)
+		}
+		b.StopTimer()
+		for _, t := range timers {
+			t.Stop()
+		}
+	})
+	b.Run("impl=Wheel", func(b *testing.B) {
+		b.ReportAllocs()
+		w := NewWheel(Second)
+		timers := make([]*WheelTimer, benchmarkConns)
+		for i := range timers {
+			timers[i] = w.AfterFunc(Hour, nil)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			timers[i%benchmarkConns].Stop()
+			timers[i%benchmarkConns] = w.AfterFunc(Hour, nil)
+		}
+		b.StopTimer()
+		for _, t := range timers {
+			t.Stop()
+		}
+	})
+}
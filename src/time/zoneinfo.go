@@ -267,6 +267,58 @@ func (l *Location) firstZoneUsed() bool {
 	return false
 }
 
+// LoadLocationFromTZString returns a Location with the given name whose
+// time zones and daylight saving time rules are described by tz, a string
+// in the format of the TZ environment variable specified by POSIX, such as
+// "EST5EDT,M3.2.0,M11.1.0" or "<+0330>-3:30". The extensions used in the
+// IANA Time Zone database, such as negative or large hours in rule times,
+// are accepted too. A tz starting with a colon, which POSIX leaves to
+// the implementation, is rejected.
+//
+// LoadLocationFromTZString does not consult any time zone database and
+// is safe to use with untrusted input.
+func LoadLocationFromTZString(name, tz string) (*Location, error) {
+	zones, ok := tzsetZones(tz)
+	if !ok {
+		return nil, errors.New("time: invalid TZ string " + quote(tz))
+	}
+	// The extend string applies after the last transition,
+	// so a single transition covers all time.
+	l := &Location{
+		name:   name,
+		zone:   zones,
+		tx:     []zoneTrans{{when: alpha, index: 0}},
+		extend: tz,
+	}
+	l.initCache()
+	return l, nil
+}
+
+// tzsetZones returns the standard and, if present, daylight saving time
+// zones named in the tzset string s, and reports whether s is valid.
+func tzsetZones(s string) ([]zone, bool) {
+	if len(s) == 0 || s[0] == ':' {
+		return nil, false
+	}
+	if _, _, _, _, _, ok := tzset(s, alpha, 0); !ok {
+		return nil, false
+	}
+	// tzset has validated s, so the parses below succeed.
+	stdName, s, _ := tzsetName(s)
+	stdOffset, s, _ := tzsetOffset(s)
+	zones := []zone{{name: stdName, offset: -stdOffset}}
+	if len(s) == 0 || s[0] == ',' {
+		return zones, true
+	}
+	dstName, s, _ := tzsetName(s)
+	dstOffset := -stdOffset + secondsPerHour
+	if len(s) > 0 && s[0] != ',' {
+		dstOffset, _, _ = tzsetOffset(s)
+		dstOffset = -dstOffset
+	}
+	return append(zones, zone{name: dstName, offset: dstOffset, isDST: true}), true
+}
+
 // tzset takes a timezone string like the one found in the TZ environment
 // variable, the time of the last time zone transition expressed as seconds
 // since January 1, 1970 00:00:00 UTC, and a time expressed the same way.
@@ -357,10 +409,14 @@ func tzset(s string, lastTxSec, sec int64) (name string, offset int, start, end
 	// close to a daylight savings transition, but are otherwise
 	// just the start and end of the year. That suffices for
 	// the only caller that cares, which is Date.
+	yearEnd := abs + 365*secondsPerDay
+	if isLeap(year) {
+		yearEnd += secondsPerDay
+	}
 	if ysec < startSec {
 		return stdName, stdOffset, abs, startSec + abs, stdIsDST, true
 	} else if ysec >= endSec {
-		return stdName, stdOffset, endSec + abs, abs + 365*secondsPerDay, stdIsDST, true
+		return stdName, stdOffset, endSec + abs, yearEnd, stdIsDST, true
 	} else {
 		return dstName, dstOffset, startSec + abs, endSec + abs, dstIsDST, true
 	}
@@ -670,9 +726,7 @@ func LoadLocation(name string) (*Location, error) {
 	if name == "Local" {
 		return Local, nil
 	}
-	if containsDotDot(name) || name[0] == '/' || name[0] == '\\' {
-		// No valid IANA Time Zone name contains a single dot,
-		// much less dot dot. Likewise, none begin with a slash.
+	if !validZoneName(name) {
 		return nil, errLocation
 	}
 	zoneinfoOnce.Do(func() {
@@ -698,6 +752,14 @@ func LoadLocation(name string) (*Location, error) {
 	return nil, firstErr
 }
 
+// validZoneName reports whether name, which is not empty, may be
+// the name of a zone in the IANA Time Zone database.
+func validZoneName(name string) bool {
+	// No valid IANA Time Zone name contains a single dot,
+	// much less dot dot. Likewise, none begin with a slash.
+	return !containsDotDot(name) && name[0] != '/' && name[0] != '\\'
+}
+
 // containsDotDot reports whether s contains "..".
 func containsDotDot(s string) bool {
 	if len(s) < 2 {
@@ -159,7 +159,9 @@ func LoadLocationFromTZData(name string, data []byte) (*Location, error) {
 		if !ok {
 			return nil, errBadData
 		}
-		if uint32(int(nn)) != nn {
+		// Every entry takes up at least a byte. Rejecting larger
+		// counts keeps the size computations below from overflowing.
+		if uint32(int(nn)) != nn || int(nn) > len(data) {
 			return nil, errBadData
 		}
 		n[i] = int(nn)
@@ -192,7 +194,7 @@ func LoadLocationFromTZData(name string, data []byte) (*Location, error) {
 			if !ok {
 				return nil, errBadData
 			}
-			if uint32(int(nn)) != nn {
+			if uint32(int(nn)) != nn || int(nn) > len(data) {
 				return nil, errBadData
 			}
 			n[i] = int(nn)
@@ -314,10 +316,15 @@ func LoadLocationFromTZData(name string, data []byte) (*Location, error) {
 
 	// Committed to succeed.
 	l := &Location{zone: zones, tx: tx, name: name, extend: extend}
+	l.initCache()
+	return l, nil
+}
 
-	// Fill in the cache with information about right now,
-	// since that will be the most common lookup.
+// initCache fills in the cache with information about right now,
+// since that will be the most common lookup.
+func (l *Location) initCache() {
 	sec, _, _ := now()
+	tx := l.tx
 	for i := range tx {
 		if tx[i].when <= sec && (i+1 == len(tx) || sec < tx[i+1].when) {
 			l.cacheStart = tx[i].when
@@ -346,8 +353,6 @@ func LoadLocationFromTZData(name string, data []byte) (*Location, error) {
 			break
 		}
 	}
-
-	return l, nil
 }
 
 func findZone(zones []zone, name string, offset int, isDST bool) int {
@@ -505,6 +510,123 @@ func loadTzinfoFromZip(zipfile, name string) ([]byte, error) {
 	return nil, syscall.ENOENT
 }
 
+var errBadZip = errors.New("malformed time zone archive")
+
+// walkZipData calls f with the name and contents of each file in the
+// uncompressed zip archive data, in the order of the archive's central
+// directory, until f returns false. The contents alias data.
+// It reports an error if data is not a well-formed uncompressed zip archive;
+// unlike loadTzinfoFromZip, it does not trust any of the offsets and sizes
+// recorded in it.
+func walkZipData(data []byte, f func(name string, content []byte) bool) error {
+	const (
+		zecheader = 0x06054b50
+		zcheader  = 0x02014b50
+		ztailsize = 22
+
+		zheadersize = 30
+		zheader     = 0x04034b50
+	)
+
+	if len(data) < ztailsize {
+		return errBadZip
+	}
+	tail := data[len(data)-ztailsize:]
+	if get4(tail) != zecheader {
+		return errBadZip
+	}
+	n := get2(tail[10:])
+	size := get4(tail[12:])
+	off := get4(tail[16:])
+	if off < 0 || size < 0 || off > len(data) || size > len(data)-off {
+		return errBadZip
+	}
+	buf := data[off : off+size]
+
+	// See loadTzinfoFromZip for the layout of the headers.
+	for i := 0; i < n; i++ {
+		if len(buf) < 46 || get4(buf) != zcheader {
+			return errBadZip
+		}
+		meth := get2(buf[10:])
+		size := get4(buf[24:])
+		namelen := get2(buf[28:])
+		xlen := get2(buf[30:])
+		fclen := get2(buf[32:])
+		off := get4(buf[42:])
+		if 46+namelen+xlen+fclen > len(buf) {
+			return errBadZip
+		}
+		zname := string(buf[46 : 46+namelen])
+		buf = buf[46+namelen+xlen+fclen:]
+		if meth != 0 {
+			return errors.New("unsupported compression for " + zname + " in time zone archive")
+		}
+
+		if off < 0 || size < 0 || off > len(data)-zheadersize {
+			return errBadZip
+		}
+		hdr := data[off:]
+		if get4(hdr) != zheader ||
+			get2(hdr[8:]) != meth ||
+			get2(hdr[26:]) != namelen {
+			return errBadZip
+		}
+		start := zheadersize + namelen + get2(hdr[28:])
+		if start > len(hdr) || size > len(hdr)-start ||
+			string(hdr[zheadersize:zheadersize+namelen]) != zname {
+			return errBadZip
+		}
+
+		if !f(zname, hdr[start:start+size]) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// loadTzinfoFromZipData returns the contents of the file with the given name
+// in the uncompressed zip archive data.
+func loadTzinfoFromZipData(data []byte, name string) ([]byte, error) {
+	var ret []byte
+	err := walkZipData(data, func(zname string, content []byte) bool {
+		if zname == name {
+			ret = content
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, syscall.ENOENT
+	}
+	return ret, nil
+}
+
+// LoadLocationFromZip returns the Location with the given name from
+// data, an uncompressed zip archive of IANA Time Zone database-formatted
+// files such as $GOROOT/lib/time/zoneinfo.zip.
+// The archive is read from memory, so an updated database can be loaded
+// at run time, from any source, without installing it on the system.
+func LoadLocationFromZip(name string, data []byte) (*Location, error) {
+	if name == "" || name == "UTC" {
+		return UTC, nil
+	}
+	if !validZoneName(name) {
+		return nil, errLocation
+	}
+	zoneData, err := loadTzinfoFromZipData(data, name)
+	if err == syscall.ENOENT {
+		return nil, errors.New("unknown time zone " + name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return LoadLocationFromTZData(name, zoneData)
+}
+
 // loadTzinfoFromTzdata returns the time zone information of the time zone
 // with the given name, from a tzdata database file as they are typically
 // found on android.
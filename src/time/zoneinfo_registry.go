@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// A LocationRegistry is a time zone database that can be replaced while
+// it is in use, so that a long-running program can pick up a new release
+// of the IANA Time Zone database without restarting.
+//
+// The zero value is ready to use and loads locations as [LoadLocation]
+// does. [LocationRegistry.UpdateFromZip] and
+// [LocationRegistry.UpdateFromSource] atomically replace the database:
+// LoadLocation calls that start after an update returns see the new
+// database, never a mix of the two. Locations loaded before an update are
+// not affected by it; they keep the zones and rules they were loaded with.
+//
+// A LocationRegistry is safe for concurrent use by multiple goroutines.
+// It must not be copied after first use.
+type LocationRegistry struct {
+	db atomic.Pointer[map[string]*Location]
+}
+
+// LoadLocation returns the Location with the given name from r's database.
+// The names "", "UTC" and "Local" are interpreted as by [LoadLocation].
+// If r has not been updated, or has been reset, LoadLocation is
+// equivalent to the top-level LoadLocation function.
+//
+// The returned Location may be shared with other callers.
+func (r *LocationRegistry) LoadLocation(name string) (*Location, error) {
+	db := r.db.Load()
+	if db == nil || name == "" || name == "UTC" || name == "Local" {
+		return LoadLocation(name)
+	}
+	if l, ok := (*db)[name]; ok {
+		return l, nil
+	}
+	if !validZoneName(name) {
+		return nil, errLocation
+	}
+	return nil, errors.New("unknown time zone " + name)
+}
+
+// UpdateFromZip replaces r's database with the time zones in data, an
+// uncompressed zip archive of IANA Time Zone database-formatted files such
+// as $GOROOT/lib/time/zoneinfo.zip. Every file in the archive is validated
+// first: if any of them is malformed, UpdateFromZip returns an error and
+// leaves r unchanged.
+func (r *LocationRegistry) UpdateFromZip(data []byte) error {
+	db := make(map[string]*Location)
+	var err error
+	zerr := walkZipData(data, func(name string, content []byte) bool {
+		if len(name) > 0 && name[len(name)-1] == '/' {
+			return true // directory
+		}
+		var l *Location
+		if l, err = LoadLocationFromTZData(name, content); err != nil {
+			err = errors.New("time: time zone " + name + " in archive: " + err.Error())
+			return false
+		}
+		db[name] = l
+		return true
+	})
+	if zerr != nil {
+		return zerr
+	}
+	if err != nil {
+		return err
+	}
+	r.db.Store(&db)
+	return nil
+}
+
+// UpdateFromSource replaces r's database with the time zones compiled
+// from src, the text of one or more source files of the IANA Time Zone
+// database, as for [LoadLocationFromTZSource]. Every zone and link in src
+// is compiled first: if any of them is malformed, UpdateFromSource returns
+// an error and leaves r unchanged.
+func (r *LocationRegistry) UpdateFromSource(src []byte) error {
+	ts, err := parseTZSource(src)
+	if err != nil {
+		return err
+	}
+	db := make(map[string]*Location, len(ts.names))
+	compiled := make(map[string]*Location)
+	for _, name := range ts.names {
+		target := name
+		if t, ok := ts.links[name]; ok {
+			target = t
+		}
+		l, ok := compiled[target]
+		if !ok {
+			if l, err = ts.location(target); err != nil {
+				return err
+			}
+			compiled[target] = l
+		}
+		if l.name != name {
+			// A link shares the compiled zone under its own name.
+			link := *l
+			link.name = name
+			l = &link
+		}
+		db[name] = l
+	}
+	r.db.Store(&db)
+	return nil
+}
+
+// Reset discards r's database, so that r loads locations as
+// [LoadLocation] does.
+func (r *LocationRegistry) Reset() {
+	r.db.Store(nil)
+}
@@ -0,0 +1,831 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Compile time zone information from the source files of the IANA Time
+// Zone database, such as "northamerica" and "europe".
+// The format is the input format of zic(8); see
+// https://data.iana.org/time-zones/tz-link.html and zic.8 in the
+// tz distribution.
+
+package time
+
+import (
+	"errors"
+	"internal/bytealg"
+)
+
+// A tzSource is a parsed set of tz source files.
+type tzSource struct {
+	rules map[string][]tzRule
+	zones map[string][]tzZoneLine
+	links map[string]string // link name to target
+	names []string          // zone and link names, in order of appearance
+}
+
+// A tzRule is a Rule line.
+type tzRule struct {
+	from, to int // years, inclusive
+	month    Month
+	day      tzDay
+	at       int  // seconds since midnight
+	atKind   byte // 'w' (wall clock), 's' (standard time) or 'u' (UTC)
+	save     int  // seconds added to standard time
+	isDST    bool
+	letter   string
+}
+
+// A tzDay is the ON field of a Rule line, or the day of an UNTIL field.
+type tzDay struct {
+	kind    byte // 0 for a fixed day, 'l' for lastSun, '>' for Sun>=8, '<' for Sun<=25
+	day     int
+	weekday Weekday
+}
+
+// A tzZoneLine is a Zone line or one of its continuation lines.
+type tzZoneLine struct {
+	stdoff int    // seconds east of UTC
+	rules  string // name of the rules in effect; "" if none
+	save   int    // seconds added to standard time if rules is ""
+	isDST  bool
+	format string
+
+	hasUntil   bool
+	untilYear  int
+	untilMonth Month
+	untilDay   tzDay
+	untilAt    int
+	untilKind  byte
+}
+
+// The years "min" and "max" in Rule lines, and the range of years for
+// which we generate transitions. Like zic, we rely on the extend string,
+// if there is one, for times after tzLastYear.
+const (
+	tzMinYear   = -1 << 31
+	tzMaxYear   = 1<<31 - 1
+	tzFirstYear = 1800
+	tzLastYear  = 2037
+)
+
+// A tzSourceError reports a problem in tz source.
+type tzSourceError struct {
+	line int
+	msg  string
+}
+
+func (e *tzSourceError) Error() string {
+	return "time: tz source line " + string(appendInt(nil, e.line, 0)) + ": " + e.msg
+}
+
+// parseTZSource parses the tz source in src.
+func parseTZSource(src []byte) (*tzSource, error) {
+	ts := &tzSource{
+		rules: make(map[string][]tzRule),
+		zones: make(map[string][]tzZoneLine),
+		links: make(map[string]string),
+	}
+	var zoneName string // name of the zone being continued, if any
+	for lineno := 1; len(src) > 0; lineno++ {
+		line := src
+		if i := bytealg.IndexByte(src, '\n'); i >= 0 {
+			line, src = src[:i], src[i+1:]
+		} else {
+			src = nil
+		}
+		f := tzFields(line)
+		if len(f) == 0 {
+			continue
+		}
+		fail := func(msg string) error {
+			return &tzSourceError{lineno, msg}
+		}
+
+		if zoneName != "" {
+			// Continuation line: the fields of a Zone line
+			// after the name.
+			zl, ok := parseTZZoneLine(f)
+			if !ok {
+				return nil, fail("malformed zone continuation line")
+			}
+			ts.zones[zoneName] = append(ts.zones[zoneName], zl)
+			if !zl.hasUntil {
+				zoneName = ""
+			}
+			continue
+		}
+
+		switch {
+		case tzKeyword(f[0], "Rule"):
+			if len(f) != 10 {
+				return nil, fail("wrong number of fields in Rule line")
+			}
+			r, ok := parseTZRule(f[2:])
+			if !ok {
+				return nil, fail("malformed Rule line")
+			}
+			ts.rules[f[1]] = append(ts.rules[f[1]], r)
+		case tzKeyword(f[0], "Zone"):
+			if len(f) < 5 || len(f) > 9 {
+				return nil, fail("wrong number of fields in Zone line")
+			}
+			name := f[1]
+			if _, dup := ts.zones[name]; dup {
+				return nil, fail("duplicate zone name " + name)
+			}
+			if _, dup := ts.links[name]; dup {
+				return nil, fail("duplicate zone name " + name)
+			}
+			zl, ok := parseTZZoneLine(f[2:])
+			if !ok {
+				return nil, fail("malformed Zone line")
+			}
+			ts.zones[name] = []tzZoneLine{zl}
+			ts.names = append(ts.names, name)
+			if zl.hasUntil {
+				zoneName = name
+			}
+		case tzKeyword(f[0], "Link"):
+			if len(f) != 3 {
+				return nil, fail("wrong number of fields in Link line")
+			}
+			name := f[2]
+			if _, dup := ts.zones[name]; dup {
+				return nil, fail("duplicate zone name " + name)
+			}
+			if _, dup := ts.links[name]; dup {
+				return nil, fail("duplicate zone name " + name)
+			}
+			ts.links[name] = f[1]
+			ts.names = append(ts.names, name)
+		default:
+			return nil, fail("unknown line type " + quote(f[0]))
+		}
+	}
+	if zoneName != "" {
+		return nil, errors.New("time: tz source ends in the middle of zone " + zoneName)
+	}
+	for _, lines := range ts.zones {
+		for _, zl := range lines {
+			if _, ok := ts.rules[zl.rules]; zl.rules != "" && !ok {
+				return nil, errors.New("time: tz source has no rules named " + zl.rules)
+			}
+		}
+	}
+	return ts, nil
+}
+
+// tzFields splits a line of tz source into its fields,
+// discarding any comment.
+func tzFields(line []byte) []string {
+	var f []string
+	for i := 0; i < len(line); {
+		c := line[i]
+		if c == '#' {
+			break
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && line[j] != ' ' && line[j] != '\t' && line[j] != '\r' &&
+			line[j] != '\f' && line[j] != '\v' && line[j] != '#' {
+			j++
+		}
+		f = append(f, string(line[i:j]))
+		i = j
+	}
+	return f
+}
+
+// tzKeyword reports whether s is an abbreviation of the keyword kw,
+// ignoring case, as zic accepts.
+func tzKeyword(s, kw string) bool {
+	return len(s) > 0 && len(s) <= len(kw) && match(s, kw[:len(s)])
+}
+
+// tzLookupName returns the index of the unique name in tab that s
+// abbreviates, ignoring case.
+func tzLookupName(tab []string, s string) (int, bool) {
+	found := -1
+	for i, name := range tab {
+		if tzKeyword(s, name) {
+			if found >= 0 {
+				return 0, false // ambiguous
+			}
+			found = i
+		}
+	}
+	return found, found >= 0
+}
+
+// parseTZRule parses the fields of a Rule line after the name:
+// FROM TO - IN ON AT SAVE LETTER/S.
+func parseTZRule(f []string) (tzRule, bool) {
+	var r tzRule
+	var ok bool
+	switch {
+	case tzKeyword(f[0], "minimum") && len(f[0]) >= 2:
+		r.from = tzMinYear
+	case tzKeyword(f[0], "maximum") && len(f[0]) >= 2:
+		r.from = tzMaxYear
+	default:
+		if r.from, ok = tzYear(f[0]); !ok {
+			return tzRule{}, false
+		}
+	}
+	switch {
+	case tzKeyword(f[1], "only"):
+		r.to = r.from
+	case tzKeyword(f[1], "minimum") && len(f[1]) >= 2:
+		r.to = tzMinYear
+	case tzKeyword(f[1], "maximum") && len(f[1]) >= 2:
+		r.to = tzMaxYear
+	default:
+		if r.to, ok = tzYear(f[1]); !ok {
+			return tzRule{}, false
+		}
+	}
+	if r.to < r.from || f[2] != "-" {
+		return tzRule{}, false
+	}
+	m, ok := tzLookupName(longMonthNames, f[3])
+	if !ok {
+		return tzRule{}, false
+	}
+	r.month = Month(m + 1)
+	if r.day, ok = parseTZDay(f[4]); !ok {
+		return tzRule{}, false
+	}
+	if r.at, r.atKind, ok = parseTZTime(f[5]); !ok {
+		return tzRule{}, false
+	}
+	if r.save, r.isDST, ok = parseTZSave(f[6]); !ok {
+		return tzRule{}, false
+	}
+	if f[7] != "-" {
+		r.letter = f[7]
+	}
+	return r, true
+}
+
+// tzYear parses a year.
+func tzYear(s string) (int, bool) {
+	neg := len(s) > 0 && s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+	y, rest, ok := tzsetNum(s, 0, 1<<31-1)
+	if !ok || rest != "" {
+		return 0, false
+	}
+	if neg {
+		y = -y
+	}
+	return y, true
+}
+
+// parseTZDay parses the ON field of a Rule line: 5, lastSun, Sun>=8 or Sun<=25.
+func parseTZDay(s string) (tzDay, bool) {
+	if len(s) > 4 && match(s[:4], "last") {
+		wd, ok := tzLookupName(longDayNames, s[4:])
+		return tzDay{kind: 'l', weekday: Weekday(wd)}, ok
+	}
+	for i := 0; i+1 < len(s); i++ {
+		if (s[i] == '>' || s[i] == '<') && s[i+1] == '=' {
+			wd, ok := tzLookupName(longDayNames, s[:i])
+			if !ok {
+				return tzDay{}, false
+			}
+			d, rest, ok := tzsetNum(s[i+2:], 1, 31)
+			if !ok || rest != "" {
+				return tzDay{}, false
+			}
+			return tzDay{kind: s[i], day: d, weekday: Weekday(wd)}, true
+		}
+	}
+	d, rest, ok := tzsetNum(s, 1, 31)
+	if !ok || rest != "" {
+		return tzDay{}, false
+	}
+	return tzDay{day: d}, true
+}
+
+// parseTZTime parses an AT field, a time of day such as 2:00 or 1:00u,
+// returning the time in seconds and the kind of time it is.
+func parseTZTime(s string) (int, byte, bool) {
+	kind := byte('w')
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'w':
+			s = s[:n-1]
+		case 's':
+			kind = 's'
+			s = s[:n-1]
+		case 'u', 'g', 'z':
+			kind = 'u'
+			s = s[:n-1]
+		}
+	}
+	secs, ok := parseTZHMS(s)
+	return secs, kind, ok
+}
+
+// parseTZSave parses a SAVE field or the RULES field of a Zone line
+// giving a fixed amount of saved time, returning the amount in seconds
+// and whether it denotes daylight saving time.
+func parseTZSave(s string) (int, bool, bool) {
+	dst := byte(0)
+	if n := len(s); n > 0 && (s[n-1] == 's' || s[n-1] == 'd') {
+		dst = s[n-1]
+		s = s[:n-1]
+	}
+	secs, ok := parseTZHMS(s)
+	if dst == 0 {
+		return secs, secs != 0, ok
+	}
+	return secs, dst == 'd', ok
+}
+
+// parseTZHMS parses a signed duration in the form [-]hh[:mm[:ss[.fff]]]
+// or "-", which means zero. Fractions of a second are ignored.
+func parseTZHMS(s string) (int, bool) {
+	if s == "-" {
+		return 0, true
+	}
+	if i := bytealg.IndexByteString(s, '.'); i >= 0 {
+		if _, rest, ok := tzsetNum(s[i+1:], 0, 1<<31-1); !ok || rest != "" {
+			return 0, false
+		}
+		s = s[:i]
+	}
+	off, rest, ok := tzsetOffset(s)
+	return off, ok && rest == ""
+}
+
+// parseTZZoneLine parses the fields of a Zone line after the name:
+// STDOFF RULES FORMAT [UNTIL].
+func parseTZZoneLine(f []string) (tzZoneLine, bool) {
+	if len(f) < 3 || len(f) > 7 {
+		return tzZoneLine{}, false
+	}
+	var zl tzZoneLine
+	var ok bool
+	if zl.stdoff, ok = parseTZHMS(f[0]); !ok {
+		return tzZoneLine{}, false
+	}
+	if r := f[1]; r != "-" {
+		if c := r[0]; c == '-' || '0' <= c && c <= '9' {
+			if zl.save, zl.isDST, ok = parseTZSave(r); !ok {
+				return tzZoneLine{}, false
+			}
+		} else {
+			zl.rules = r
+		}
+	}
+	zl.format = f[2]
+	if f = f[3:]; len(f) == 0 {
+		return zl, true
+	}
+	zl.hasUntil = true
+	zl.untilMonth = January
+	zl.untilDay = tzDay{day: 1}
+	zl.untilKind = 'w'
+	if zl.untilYear, ok = tzYear(f[0]); !ok {
+		return tzZoneLine{}, false
+	}
+	if len(f) > 1 {
+		m, ok := tzLookupName(longMonthNames, f[1])
+		if !ok {
+			return tzZoneLine{}, false
+		}
+		zl.untilMonth = Month(m + 1)
+	}
+	if len(f) > 2 {
+		if zl.untilDay, ok = parseTZDay(f[2]); !ok {
+			return tzZoneLine{}, false
+		}
+	}
+	if len(f) > 3 {
+		if zl.untilAt, zl.untilKind, ok = parseTZTime(f[3]); !ok {
+			return tzZoneLine{}, false
+		}
+	}
+	return zl, true
+}
+
+// tzLocal returns the given local date and time of day as seconds since
+// January 1, 1970 00:00:00 in the local time scale.
+func tzLocal(year int, month Month, day tzDay, at int) int64 {
+	d := day.day
+	switch day.kind {
+	case 'l':
+		d = daysIn(month, year)
+		wd := Date(year, month, d, 0, 0, 0, 0, UTC).Weekday()
+		d -= int(wd-day.weekday+7) % 7
+	case '>':
+		wd := Date(year, month, d, 0, 0, 0, 0, UTC).Weekday()
+		d += int(day.weekday-wd+7) % 7
+	case '<':
+		wd := Date(year, month, d, 0, 0, 0, 0, UTC).Weekday()
+		d -= int(wd-day.weekday+7) % 7
+	}
+	return Date(year, month, d, 0, 0, 0, 0, UTC).Unix() + int64(at)
+}
+
+// tzUTC converts a local time of the given kind to UTC, given the standard
+// offset and the saved time in effect.
+func tzUTC(local int64, kind byte, stdoff, save int) int64 {
+	switch kind {
+	case 's':
+		return local - int64(stdoff)
+	case 'u':
+		return local
+	}
+	return local - int64(stdoff+save)
+}
+
+// tzFormat returns the abbreviation produced by the FORMAT field of a
+// Zone line.
+func tzFormat(format string, stdoff, save int, isDST bool, letter string) string {
+	for i := 0; i < len(format); i++ {
+		if format[i] == '/' {
+			if isDST {
+				return format[i+1:]
+			}
+			return format[:i]
+		}
+	}
+	var b []byte
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b = append(b, format[i])
+			continue
+		}
+		i++
+		switch format[i] {
+		case 's':
+			b = append(b, letter...)
+		case 'z':
+			off := stdoff + save
+			if off < 0 {
+				b = append(b, '-')
+				off = -off
+			} else {
+				b = append(b, '+')
+			}
+			h, m, s := off/secondsPerHour, off/secondsPerMinute%60, off%60
+			b = appendInt(b, h, 2)
+			if m != 0 || s != 0 {
+				b = appendInt(b, m, 2)
+			}
+			if s != 0 {
+				b = appendInt(b, s, 2)
+			}
+		default:
+			b = append(b, '%', format[i])
+		}
+	}
+	return string(b)
+}
+
+// A tzEvent is an occurrence of a rule in a particular year.
+type tzEvent struct {
+	local int64 // local time, in the time scale of r.atKind
+	r     *tzRule
+}
+
+// tzCompiler accumulates the zones and transitions of a Location.
+type tzCompiler struct {
+	zones []zone
+	tx    []zoneTrans
+}
+
+// emit records that zone z is in effect from when on, replacing any
+// transitions at or after when.
+func (c *tzCompiler) emit(when int64, z zone) error {
+	idx := findZone(c.zones, z.name, z.offset, z.isDST)
+	if idx < 0 {
+		if len(c.zones) == 256 {
+			return errors.New("time: too many time zone types")
+		}
+		idx = len(c.zones)
+		c.zones = append(c.zones, z)
+	}
+	for len(c.tx) > 0 && c.tx[len(c.tx)-1].when >= when {
+		c.tx = c.tx[:len(c.tx)-1]
+	}
+	if len(c.tx) > 0 && int(c.tx[len(c.tx)-1].index) == idx {
+		return nil
+	}
+	c.tx = append(c.tx, zoneTrans{when: when, index: uint8(idx)})
+	return nil
+}
+
+// location returns the Location with the given name, following links.
+func (ts *tzSource) location(name string) (*Location, error) {
+	target := name
+	for i := 0; ; i++ {
+		next, ok := ts.links[target]
+		if !ok {
+			break
+		}
+		if i == 16 {
+			return nil, errors.New("time: too many links for " + name + " in tz source")
+		}
+		target = next
+	}
+	lines, ok := ts.zones[target]
+	if !ok {
+		return nil, errors.New("unknown time zone " + name)
+	}
+
+	var c tzCompiler
+	start := int64(alpha) // start of the current line's period, in UTC
+	for _, zl := range lines {
+		save, isDST, letter := zl.save, zl.isDST, ""
+		zoneAt := func() zone {
+			return zone{
+				name:   tzFormat(zl.format, zl.stdoff, save, isDST, letter),
+				offset: zl.stdoff + save,
+				isDST:  isDST,
+			}
+		}
+		until := func() int64 {
+			if !zl.hasUntil {
+				return omega
+			}
+			local := tzLocal(zl.untilYear, zl.untilMonth, zl.untilDay, zl.untilAt)
+			return tzUTC(local, zl.untilKind, zl.stdoff, save)
+		}
+
+		if zl.rules == "" {
+			if err := c.emit(start, zoneAt()); err != nil {
+				return nil, err
+			}
+			start = until()
+			continue
+		}
+
+		rules := ts.rules[zl.rules]
+		events := tzEvents(rules, start, zl)
+		// Until a rule takes effect, use the letters of the
+		// first rule for standard time, as zic does.
+		save, isDST = 0, false
+		for i := range rules {
+			if !rules[i].isDST {
+				letter = rules[i].letter
+				break
+			}
+		}
+		started := false
+		for _, ev := range events {
+			t := tzUTC(ev.local, ev.r.atKind, zl.stdoff, save)
+			if t >= until() {
+				break
+			}
+			if t > start && !started {
+				if err := c.emit(start, zoneAt()); err != nil {
+					return nil, err
+				}
+				started = true
+			}
+			save, isDST, letter = ev.r.save, ev.r.isDST, ev.r.letter
+			if started {
+				if err := c.emit(t, zoneAt()); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !started {
+			if err := c.emit(start, zoneAt()); err != nil {
+				return nil, err
+			}
+		}
+		start = until()
+	}
+
+	l := &Location{name: name, zone: c.zones, tx: c.tx}
+	if last := lines[len(lines)-1]; last.rules != "" {
+		l.extend = tzExtend(last, ts.rules[last.rules])
+	}
+	l.initCache()
+	return l, nil
+}
+
+// tzEvents returns the occurrences of rules during the period of zl,
+// which starts at start, in order.
+func tzEvents(rules []tzRule, start int64, zl tzZoneLine) []tzEvent {
+	y0 := tzMaxYear
+	for i := range rules {
+		y0 = min(y0, rules[i].from)
+	}
+	y0 = max(y0, tzFirstYear)
+	if start != alpha {
+		// Include the year before, to find the rule in effect
+		// at the start.
+		y0 = max(y0, Unix(start+int64(zl.stdoff), 0).UTC().Year()-1)
+	}
+	y1 := tzLastYear
+	for i := range rules {
+		if rules[i].to != tzMaxYear {
+			y1 = max(y1, rules[i].to)
+		}
+	}
+	if zl.hasUntil {
+		y1 = min(y1, zl.untilYear)
+	}
+
+	var events []tzEvent
+	for y := y0; y <= y1; y++ {
+		for i := range rules {
+			r := &rules[i]
+			if r.from <= y && y <= r.to {
+				events = append(events, tzEvent{tzLocal(y, r.month, r.day, r.at), r})
+			}
+		}
+		if y == tzMaxYear {
+			break
+		}
+	}
+	// Insertion sort: the rules of each year are nearly in order.
+	// Differences in the kind of time can only reorder events that
+	// are less than a day apart, which tz data does not do.
+	for i := 1; i < len(events); i++ {
+		for j := i; j > 0 && events[j].local < events[j-1].local; j-- {
+			events[j], events[j-1] = events[j-1], events[j]
+		}
+	}
+	return events
+}
+
+// tzExtend returns the tzset string describing the rules that remain in
+// effect indefinitely at the end of a zone, or "" if there is none.
+// zl is the zone's last line.
+func tzExtend(zl tzZoneLine, rules []tzRule) string {
+	var std, dst *tzRule
+	for i := range rules {
+		r := &rules[i]
+		if r.to != tzMaxYear {
+			continue
+		}
+		switch {
+		case r.save == 0 && !r.isDST && std == nil:
+			std = r
+		case r.save > 0 && r.isDST && dst == nil:
+			dst = r
+		default:
+			// Not expressible: we rely on the
+			// transitions up to tzLastYear.
+			return ""
+		}
+	}
+	if std == nil || dst == nil {
+		// Either no rules stay in effect forever and the last
+		// transition does, or they are not expressible.
+		return ""
+	}
+
+	b := tzsetAppendName(nil, tzFormat(zl.format, zl.stdoff, 0, false, std.letter))
+	b = tzsetAppendOffset(b, -zl.stdoff)
+	b = tzsetAppendName(b, tzFormat(zl.format, zl.stdoff, dst.save, true, dst.letter))
+	if dst.save != secondsPerHour {
+		b = tzsetAppendOffset(b, -(zl.stdoff + dst.save))
+	}
+	var ok bool
+	// The tzset rule times are local wall clock times
+	// before the transition.
+	if b, ok = tzsetAppendRule(append(b, ','), dst, zl.stdoff, 0); !ok {
+		return ""
+	}
+	if b, ok = tzsetAppendRule(append(b, ','), std, zl.stdoff, dst.save); !ok {
+		return ""
+	}
+	s := string(b)
+	if _, _, _, _, _, ok := tzset(s, alpha, 0); !ok {
+		return ""
+	}
+	return s
+}
+
+// tzsetAppendName appends a zone name to a tzset string,
+// in angle brackets if needed.
+func tzsetAppendName(b []byte, name string) []byte {
+	plain := len(name) >= 3
+	for i := 0; i < len(name); i++ {
+		if c := name[i] | ('a' - 'A'); c < 'a' || c > 'z' {
+			plain = false
+		}
+	}
+	if plain {
+		return append(b, name...)
+	}
+	b = append(b, '<')
+	b = append(b, name...)
+	return append(b, '>')
+}
+
+// tzsetAppendOffset appends an offset, in seconds, to a tzset string.
+func tzsetAppendOffset(b []byte, off int) []byte {
+	if off < 0 {
+		b = append(b, '-')
+		off = -off
+	}
+	b = appendInt(b, off/secondsPerHour, 0)
+	if m, s := off/secondsPerMinute%60, off%60; m != 0 || s != 0 {
+		b = append(b, ':')
+		b = appendInt(b, m, 2)
+		if s != 0 {
+			b = append(b, ':')
+			b = appendInt(b, s, 2)
+		}
+	}
+	return b
+}
+
+// tzsetAppendRule appends the date and time of r as a tzset rule,
+// given the standard offset and the time saved before r takes effect.
+// It reports whether r can be expressed as a tzset rule.
+func tzsetAppendRule(b []byte, r *tzRule, stdoff, save int) ([]byte, bool) {
+	at := r.at
+	switch r.atKind {
+	case 's':
+		at += save
+	case 'u':
+		at += stdoff + save
+	}
+
+	switch r.day.kind {
+	case 0:
+		if r.month == February && r.day.day == 29 {
+			return nil, false
+		}
+		b = append(b, 'J')
+		b = appendInt(b, int(daysBefore[r.month-1])+r.day.day, 0)
+	case 'l':
+		b = append(b, 'M')
+		b = appendInt(b, int(r.month), 0)
+		b = append(b, ".5."...)
+		b = appendInt(b, int(r.day.weekday), 0)
+	case '>', '<':
+		first := r.day.day
+		if r.day.kind == '<' {
+			first -= 6
+		}
+		if first < 1 {
+			return nil, false
+		}
+		// A rule such as Fri>=23 is the day after Thu>=22,
+		// which tzset can express as the fourth Thursday.
+		// Find the nearest such equivalent rule.
+		var shift int
+		for shift = 0; shift < 7; shift++ {
+			if (first-1-shift)%7 == 0 && first-shift <= 22 {
+				break
+			}
+			if (first-1+shift)%7 == 0 && first+shift <= 22 {
+				shift = -shift
+				break
+			}
+		}
+		if shift == 7 {
+			return nil, false
+		}
+		first -= shift
+		wd := (int(r.day.weekday) - shift + 7) % 7
+		at += shift * secondsPerDay
+		b = append(b, 'M')
+		b = appendInt(b, int(r.month), 0)
+		b = append(b, '.')
+		b = appendInt(b, (first-1)/7+1, 0)
+		b = append(b, '.')
+		b = appendInt(b, wd, 0)
+	}
+	if at != 2*secondsPerHour {
+		b = append(b, '/')
+		b = tzsetAppendOffset(b, at)
+	}
+	return b, true
+}
+
+// LoadLocationFromTZSource returns the Location with the given name,
+// compiled from src, the text of one or more source files of the IANA Time
+// Zone database, such as "northamerica", as read by zic(8). The source must
+// contain the Zone line for the named zone, or a Link line naming it, and
+// the Rule lines it uses.
+//
+// Transitions are computed through the year 2037. After that the location
+// follows the rules in effect at the end of the zone if they can be
+// expressed as a POSIX TZ string, as zic does, and otherwise the last
+// computed zone.
+func LoadLocationFromTZSource(name string, src []byte) (*Location, error) {
+	if name == "" || name == "UTC" {
+		return UTC, nil
+	}
+	ts, err := parseTZSource(src)
+	if err != nil {
+		return nil, err
+	}
+	return ts.location(name)
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gorootZoneinfoZip returns the contents of $GOROOT/lib/time/zoneinfo.zip.
+func gorootZoneinfoZip(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(testenv.GOROOT(t), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		t.Skip(err)
+	}
+	return data
+}
+
+// checkSameZones checks that got and want are in the same zone
+// throughout [from, to).
+func checkSameZones(t *testing.T, got, want *time.Location, from, to time.Time) {
+	t.Helper()
+	check := func(tm time.Time) {
+		t.Helper()
+		gname, goff := tm.In(got).Zone()
+		wname, woff := tm.In(want).Zone()
+		if gname != wname || goff != woff {
+			t.Fatalf("at %v: got zone %s %d, want %s %d", tm.UTC(), gname, goff, wname, woff)
+		}
+	}
+	// Walk the zone changes of both locations, so that transitions
+	// missing from either are detected.
+	for _, loc := range []*time.Location{got, want} {
+		for tm := from; tm.Before(to); {
+			check(tm)
+			_, end := tm.In(loc).ZoneBounds()
+			if end.IsZero() {
+				break
+			}
+			check(end.Add(-time.Second))
+			tm = end
+		}
+	}
+}
+
+func TestLoadLocationFromTZSource(t *testing.T) {
+	zip := gorootZoneinfoZip(t)
+	src, err := os.ReadFile("testdata/tzsource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name, ref string
+		from      time.Time
+	}{
+		{"America/New_York", "America/New_York", time.Date(1967, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"US/Eastern", "America/New_York", time.Date(1967, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"Australia/Sydney", "Australia/Sydney", time.Date(2008, time.April, 7, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Jerusalem", "Asia/Jerusalem", time.Date(2013, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Dubai", "Asia/Dubai", time.Date(1921, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Muscat", "Asia/Dubai", time.Date(1921, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := time.LoadLocationFromTZSource(test.name, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != test.name {
+				t.Errorf("String() = %q, want %q", got, test.name)
+			}
+			want, err := time.LoadLocationFromZip(test.ref, zip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSameZones(t, got, want, test.from, end)
+		})
+	}
+
+	// Local mean time is kept to the second.
+	ny, err := time.LoadLocationFromTZSource("America/New_York", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, off := time.Date(1880, time.January, 1, 0, 0, 0, 0, ny).Zone()
+	if name != "LMT" || off != -(4*3600+56*60+2) {
+		t.Errorf("zone in 1880 = %s %d, want LMT %d", name, off, -(4*3600 + 56*60 + 2))
+	}
+}
+
+func TestLoadLocationFromTZSourceErrors(t *testing.T) {
+	for _, test := range []struct {
+		name, src, err string
+	}{
+		{"X", "Zone Y 1:00 - CET\n", "unknown time zone X"},
+		{"X", "Zone X 1:00 - CET 2000\n", "ends in the middle of zone X"},
+		{"X", "Zone X 1:00 EU CE%sT\n", "no rules named EU"},
+		{"X", "Zone X 1:00 - CET\nZone X 2:00 - EET\n", "line 2: duplicate zone name X"},
+		{"X", "Zone X 1:00\n", "line 1: wrong number of fields"},
+		{"X", "Zone X 1:00:99 - CET\n", "line 1: malformed Zone line"},
+		{"X", "Rule EU 1981 max - Ma lastSun 1:00u 1:00 S\n", "line 1: malformed Rule line"},
+		{"X", "Rule EU 1981 1980 - Mar lastSun 1:00u 1:00 S\n", "line 1: malformed Rule line"},
+		{"X", "Rule EU 1981 max - Mar lastFoo 1:00u 1:00 S\n", "line 1: malformed Rule line"},
+		{"X", "Leap 2016 Dec 31 23:59:60 + S\n", "line 1: unknown line type"},
+		{"X", "Link X Y\nLink Y X\n", "too many links"},
+	} {
+		_, err := time.LoadLocationFromTZSource(test.name, []byte(test.src))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("LoadLocationFromTZSource(%q, %q) error = %v, want %q", test.name, test.src, err, test.err)
+		}
+	}
+}
+
+func TestLocationRegistry(t *testing.T) {
+	zip := gorootZoneinfoZip(t)
+	src, err := os.ReadFile("testdata/tzsource")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r time.LocationRegistry
+	if l, err := r.LoadLocation("UTC"); err != nil || l != time.UTC {
+		t.Errorf("LoadLocation(UTC) = %v, %v; want UTC", l, err)
+	}
+
+	if err := r.UpdateFromSource(src); err != nil {
+		t.Fatal(err)
+	}
+	l, err := r.LoadLocation("Asia/Muscat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.String() != "Asia/Muscat" {
+		t.Errorf("String() = %q, want Asia/Muscat", l)
+	}
+	if _, err := r.LoadLocation("Europe/Berlin"); err == nil {
+		t.Errorf("LoadLocation(Europe/Berlin) succeeded with a database without it")
+	}
+	if _, err := r.LoadLocation("../x"); err != time.ErrLocation {
+		t.Errorf("LoadLocation(../x) error = %v, want %v", err, time.ErrLocation)
+	}
+
+	// A bad update leaves the registry unchanged.
+	if err := r.UpdateFromSource([]byte("Zone X 1:00 EU CE%sT\n")); err == nil {
+		t.Errorf("UpdateFromSource with bad source succeeded")
+	}
+	if err := r.UpdateFromZip(zip[:len(zip)/2]); err == nil {
+		t.Errorf("UpdateFromZip with truncated archive succeeded")
+	}
+	if l2, err := r.LoadLocation("Asia/Muscat"); err != nil || l2 != l {
+		t.Errorf("LoadLocation after failed updates = %v, %v; want %v", l2, err, l)
+	}
+
+	// Updates are seen by concurrent loads, which never fail.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if _, err := r.LoadLocation("Asia/Dubai"); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	if err := r.UpdateFromZip(zip); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	berlin, err := r.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := time.Date(2024, time.July, 1, 0, 0, 0, 0, berlin).Zone(); name != "CEST" {
+		t.Errorf("zone in Berlin in July = %s, want CEST", name)
+	}
+	// Locations loaded earlier are unchanged.
+	if name, _ := time.Date(2024, time.July, 1, 0, 0, 0, 0, l).Zone(); name != "+04" {
+		t.Errorf("zone of earlier Location = %s, want +04", name)
+	}
+
+	r.Reset()
+	if _, err := r.LoadLocation("Europe/Berlin"); err != nil {
+		t.Errorf("LoadLocation after Reset: %v", err)
+	}
+}
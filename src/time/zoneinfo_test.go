@@ -156,6 +156,97 @@ func TestLoadLocationFromTZData(t *testing.T) {
 	}
 }
 
+func TestLoadLocationFromZip(t *testing.T) {
+	undo := time.DisablePlatformSources()
+	defer undo()
+
+	const locationName = "Asia/Jerusalem"
+	reference, err := time.LoadLocation(locationName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zip := gorootZoneinfoZip(t)
+	sample, err := time.LoadLocationFromZip(locationName, zip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reference, sample) {
+		t.Errorf("return values of LoadLocationFromZip and LoadLocation don't match")
+	}
+
+	if _, err := time.LoadLocationFromZip("Nowhere/Nothing", zip); err == nil {
+		t.Errorf("LoadLocationFromZip of unknown zone succeeded")
+	}
+	if _, err := time.LoadLocationFromZip("../Asia/Jerusalem", zip); err != time.ErrLocation {
+		t.Errorf("LoadLocationFromZip of invalid name: got %v, want %v", err, time.ErrLocation)
+	}
+	// Damaged archives result in an error, not a panic.
+	for _, n := range []int{0, 22, len(zip) / 2, len(zip) - 1} {
+		if _, err := time.LoadLocationFromZip(locationName, zip[:n]); err == nil {
+			t.Errorf("LoadLocationFromZip of archive truncated to %d bytes succeeded", n)
+		}
+	}
+	damaged := append([]byte(nil), zip...)
+	copy(damaged[len(damaged)-6:], "\xff\xff\xff\x7f")
+	if _, err := time.LoadLocationFromZip(locationName, damaged); err == nil {
+		t.Errorf("LoadLocationFromZip of archive with bad directory offset succeeded")
+	}
+}
+
+func TestLoadLocationFromTZString(t *testing.T) {
+	for _, test := range []struct {
+		tz         string
+		date       time.Time
+		wantName   string
+		wantOffset int
+	}{
+		{"EST5EDT,M3.2.0,M11.1.0", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), "EDT", -4 * 60 * 60},
+		{"EST5EDT,M3.2.0,M11.1.0", time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC), "EST", -5 * 60 * 60},
+		{"EST5EDT", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), "EDT", -4 * 60 * 60},
+		{"AEST-10AEDT,M10.1.0,M4.1.0/3", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), "AEDT", 11 * 60 * 60},
+		{"AEST-10AEDT,M10.1.0,M4.1.0/3", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), "AEST", 10 * 60 * 60},
+		{"<+0330>-3:30", time.Date(1900, time.July, 1, 0, 0, 0, 0, time.UTC), "+0330", (3*60 + 30) * 60},
+		{"IST-2IDT,M3.4.4/26,M10.5.0", time.Date(2030, time.April, 1, 0, 0, 0, 0, time.UTC), "IDT", 3 * 60 * 60},
+	} {
+		loc, err := time.LoadLocationFromTZString("Test", test.tz)
+		if err != nil {
+			t.Errorf("LoadLocationFromTZString(%q): %v", test.tz, err)
+			continue
+		}
+		name, offset := test.date.In(loc).Zone()
+		if name != test.wantName || offset != test.wantOffset {
+			t.Errorf("%q: zone at %v = %s %d, want %s %d", test.tz, test.date, name, offset, test.wantName, test.wantOffset)
+		}
+	}
+
+	// The rules agree with the database for future years.
+	undo := time.DisablePlatformSources()
+	defer undo()
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := time.LoadLocationFromTZString("America/New_York", "EST5EDT,M3.2.0,M11.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSameZones(t, loc, ny, time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, tz := range []string{
+		"",
+		":America/New_York",
+		"EST",
+		"EST5EDT,M13.1.0,M11.1.0",
+		"EST5EDT,M3.2.0",
+		"<EST5",
+		"EST5EDT,M3.2.0,M11.1.0x",
+	} {
+		if _, err := time.LoadLocationFromTZString("Test", tz); err == nil {
+			t.Errorf("LoadLocationFromTZString(%q) succeeded, want error", tz)
+		}
+	}
+}
+
 // Issue 30099.
 func TestEarlyLocation(t *testing.T) {
 	undo := time.DisablePlatformSources()
@@ -186,6 +277,23 @@ func TestMalformedTZData(t *testing.T) {
 	}
 }
 
+func FuzzLoadLocationFromTZData(f *testing.F) {
+	for _, test := range slimTests {
+		if data, err := os.ReadFile("testdata/" + test.fileName); err == nil {
+			f.Add(data)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// The goal here is just that malformed tzdata results in an error, not a panic.
+		loc, err := time.LoadLocationFromTZData("Fuzz", data)
+		if err != nil {
+			return
+		}
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, loc).Zone()
+	})
+}
+
 var slimTests = []struct {
 	zoneName   string
 	fileName   string
@@ -272,6 +380,8 @@ func TestTzset(t *testing.T) {
 		{"PST8PDT,M3.2.0,M11.1.0", 0, 2172733200, "PST", -8 * 60 * 60, 2172733200, 2177452800, false, true},
 		{"PST8PDT,M3.2.0,M11.1.0", 0, 2172733201, "PST", -8 * 60 * 60, 2172733200, 2177452800, false, true},
 		{"KST-9", 592333200, 1677246697, "KST", 9 * 60 * 60, 592333200, 1<<63 - 1, false, true},
+		// The year ends after 366 days in a leap year.
+		{"PST8PDT,M3.2.0,M11.1.0", 0, 2240568000, "PST", -8 * 60 * 60, 2235632400, 2240611200, false, true},
 	} {
 		name, off, start, end, isDST, ok := time.Tzset(test.inStr, test.inEnd, test.inSec)
 		if name != test.name || off != test.off || start != test.start || end != test.end || isDST != test.isDST || ok != test.ok {
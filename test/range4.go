@@ -1,4 +1,4 @@
-// run -goexperiment rangefunc
+// run
 
 // Copyright 2023 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
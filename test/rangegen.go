@@ -1,4 +1,4 @@
-// runoutput -goexperiment rangefunc
+// runoutput
 
 // Copyright 2023 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
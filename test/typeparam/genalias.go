@@ -0,0 +1,55 @@
+// run
+
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "fmt"
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+type StringPair[V any] = Pair[string, V]
+
+type List[E any] = []E
+
+type Set[T comparable] = map[T]struct{}
+
+func Sum[E int | float64](l List[E]) (s E) {
+	for _, e := range l {
+		s += e
+	}
+	return s
+}
+
+func Keys[T comparable](s Set[T]) List[T] {
+	var l List[T]
+	for k := range s {
+		l = append(l, k)
+	}
+	return l
+}
+
+func main() {
+	p := StringPair[int]{"a", 1}
+	var q Pair[string, int] = p
+	if got := fmt.Sprintf("%T %v", q, q); got != "main.Pair[string,int] {a 1}" {
+		panic(got)
+	}
+
+	if got := Sum(List[int]{1, 2, 3}); got != 6 {
+		panic(got)
+	}
+	if got := Sum(List[float64]{0.5, 0.25}); got != 0.75 {
+		panic(got)
+	}
+
+	s := Set[string]{"x": {}}
+	if got := Keys(s); len(got) != 1 || got[0] != "x" {
+		panic(fmt.Sprint(got))
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+type Pair[K comparable, V any] struct {
+	Key K
+	Val V
+}
+
+func (p Pair[K, V]) String() string { return "pair" }
+
+type StringPair[V any] = Pair[string, V]
+
+type Set[T comparable] = map[T]struct{}
+
+func Make[V any](k string, v V) StringPair[V] {
+	return StringPair[V]{k, v}
+}
+
+func Len[T comparable](s Set[T]) int {
+	return len(s)
+}
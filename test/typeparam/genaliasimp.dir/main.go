@@ -0,0 +1,23 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "./a"
+
+type Local[V any] = a.StringPair[V]
+
+func main() {
+	var p a.StringPair[int] = a.Make("k", 42)
+	var q a.Pair[string, int] = p
+	var r Local[int] = q
+	if r.Key != "k" || r.Val != 42 || r.String() != "pair" {
+		panic("bad pair")
+	}
+
+	s := a.Set[int]{1: {}, 2: {}}
+	if a.Len(s) != 2 {
+		panic("bad set")
+	}
+}